@@ -0,0 +1,519 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"slices"
+	"strings"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/overloads"
+	"cel.dev/cel-go/common/types"
+)
+
+// ReferencedAttributes returns the attribute paths which may be read during the evaluation of an
+// expression, sorted by their string representation.
+//
+// Each path begins with a variable and is followed by the field selections and constant indices
+// applied to the variable. A path stops at the first non-constant index, e.g. `request.items[i]`
+// is reported as `request.items`. Paths which extend another reported path are omitted since
+// loading the shorter path makes the longer one available as well.
+//
+// The attribute paths may be used to load only the data an expression needs prior to evaluation.
+// For checked ASTs, qualified variable names are resolved using the checker references.
+func ReferencedAttributes(a *Ast) []*types.AttributeTrail {
+	if a == nil {
+		return nil
+	}
+	c := &attributeCollector{ast: a.NativeRep()}
+	c.visit(a.NativeRep().Expr())
+	return normalizeAttributes(c.attrs)
+}
+
+// UnknownAttributes returns the attribute paths responsible for an unknown evaluation result,
+// grouped by variable name.
+//
+// The attribute trails within an unknown value only reflect the portion of the attribute which
+// matched an unknown AttributePattern. The AST which produced the unknown is used to extend each
+// trail to the full field path selected by the expression, e.g. a pattern on `request.resource`
+// together with the expression `request.resource.labels['env']` produces the path
+// `request.resource.labels.env`.
+//
+// Paths are de-duplicated and sorted, and paths which extend another reported path are omitted.
+// Unknowns which are not associated with a variable, such as those produced by unknown functions,
+// are not included.
+func UnknownAttributes(a *Ast, unk *types.Unknown) map[string][]*types.AttributeTrail {
+	if a == nil || unk == nil {
+		return nil
+	}
+	root := ast.NavigateAST(a.NativeRep())
+	c := &attributeCollector{ast: a.NativeRep()}
+	var attrs []*types.AttributeTrail
+	for _, id := range unk.IDs() {
+		trails, _ := unk.GetAttributeTrails(id)
+		var expr ast.NavigableExpr
+		if matches := ast.MatchDescendants(root, idMatcher(id)); len(matches) != 0 {
+			expr = outermostAttribute(matches[0])
+		}
+		for _, trail := range trails {
+			if trail.Variable() == "" {
+				continue
+			}
+			if expr != nil {
+				trail = c.extendTrail(trail, expr)
+			}
+			attrs = append(attrs, trail)
+		}
+	}
+	grouped := map[string][]*types.AttributeTrail{}
+	for _, attr := range normalizeAttributes(attrs) {
+		grouped[attr.Variable()] = append(grouped[attr.Variable()], attr)
+	}
+	return grouped
+}
+
+func idMatcher(id int64) ast.ExprMatcher {
+	return func(e ast.NavigableExpr) bool {
+		return e.ID() == id
+	}
+}
+
+// outermostAttribute returns the outermost selection or constant index expression which
+// qualifies the input expression.
+func outermostAttribute(e ast.NavigableExpr) ast.NavigableExpr {
+	for {
+		parent, found := e.Parent()
+		if !found || !qualifiesOperand(parent, e.ID()) {
+			return e
+		}
+		e = parent
+	}
+}
+
+// qualifiesOperand returns whether the expression is a field selection or constant index applied
+// to the operand with the given id.
+func qualifiesOperand(e ast.Expr, operandID int64) bool {
+	switch e.Kind() {
+	case ast.SelectKind:
+		return e.AsSelect().Operand().ID() == operandID
+	case ast.CallKind:
+		call := e.AsCall()
+		if !isQualifierCall(call) {
+			return false
+		}
+		_, isConst := attributeQualifier(call.Args()[1])
+		return isConst && call.Args()[0].ID() == operandID
+	}
+	return false
+}
+
+func isQualifierCall(call ast.CallExpr) bool {
+	switch call.FunctionName() {
+	case operators.Index, operators.OptIndex, operators.OptSelect:
+		return len(call.Args()) == 2
+	}
+	return false
+}
+
+// attributeQualifier returns the qualifier value for a constant index or field name.
+func attributeQualifier(e ast.Expr) (any, bool) {
+	if e.Kind() != ast.LiteralKind {
+		return nil, false
+	}
+	switch v := e.AsLiteral().(type) {
+	case types.Bool:
+		return bool(v), true
+	case types.Int:
+		return int64(v), true
+	case types.String:
+		return string(v), true
+	case types.Uint:
+		return uint64(v), true
+	}
+	return nil, false
+}
+
+type attributeCollector struct {
+	ast   *ast.AST
+	attrs []*types.AttributeTrail
+	// exprIDs contains the id of the outermost expression for each collected attribute.
+	exprIDs []int64
+	// scopes maps the comprehension variables in scope to the attribute paths they may refer to.
+	scopes []map[string][]*attributePath
+	// localAttrs contains the attribute paths reached through comprehension variables, and
+	// localExprIDs the id of the outermost expression for each of them.
+	localAttrs   []*attributePath
+	localExprIDs []int64
+	// localRefs maps the id of each comprehension variable reference to the attribute paths the
+	// variable may refer to.
+	localRefs map[int64][]*attributePath
+	// computed contains the ids of the expressions which are qualified by a field selection or
+	// index and whose value is not read from a variable, such as list elements, function results,
+	// and comprehension variables.
+	computed map[int64]bool
+}
+
+// attributePath is a variable followed by field selections and indices, where anyQualifier
+// stands in for an index which may take any value.
+type attributePath struct {
+	variable string
+	quals    []any
+}
+
+// anyQualifier matches any qualifier within an attributePath.
+type anyQualifier struct{}
+
+// qualify returns a copy of the path extended with the input qualifiers.
+func (p *attributePath) qualify(quals ...any) *attributePath {
+	return &attributePath{variable: p.variable, quals: append(slices.Clone(p.quals), quals...)}
+}
+
+// segments returns the path as a flat list of variable name components and qualifiers.
+func (p *attributePath) segments() []any {
+	return variablePath(p.variable, p.quals)
+}
+
+// visit records the attributes referenced within the expression.
+func (c *attributeCollector) visit(e ast.Expr) {
+	if variable, quals, args, ok := c.attribute(e); ok {
+		c.attrs = append(c.attrs, newAttributeTrail(variable, quals))
+		c.exprIDs = append(c.exprIDs, e.ID())
+		for _, arg := range args {
+			c.visit(arg)
+		}
+		return
+	}
+	if root, quals, args, ok := c.qualifiers(e); ok {
+		if paths, found := c.local(root.AsIdent()); found {
+			if c.localRefs == nil {
+				c.localRefs = map[int64][]*attributePath{}
+			}
+			c.localRefs[root.ID()] = paths
+			c.markOperands(e)
+			// Reads of the variable itself are not recorded, only the selections applied to it.
+			if quals = constantPrefix(quals); len(quals) != 0 {
+				for _, p := range paths {
+					c.localAttrs = append(c.localAttrs, p.qualify(quals...))
+					c.localExprIDs = append(c.localExprIDs, e.ID())
+				}
+			}
+			for _, arg := range args {
+				c.visit(arg)
+			}
+			return
+		}
+	}
+	switch e.Kind() {
+	case ast.CallKind:
+		call := e.AsCall()
+		if isQualifierCall(call) {
+			c.markComputed(call.Args()[0])
+		}
+		if call.IsMemberFunction() {
+			c.visit(call.Target())
+		}
+		for _, arg := range call.Args() {
+			c.visit(arg)
+		}
+	case ast.ComprehensionKind:
+		comp := e.AsComprehension()
+		c.visit(comp.IterRange())
+		c.visit(comp.AccuInit())
+		c.scopes = append(c.scopes, c.comprehensionScope(comp))
+		c.visit(comp.LoopCondition())
+		c.visit(comp.LoopStep())
+		c.visit(comp.Result())
+		c.scopes = c.scopes[:len(c.scopes)-1]
+	case ast.ListKind:
+		for _, elem := range e.AsList().Elements() {
+			c.visit(elem)
+		}
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			c.visit(entry.AsMapEntry().Key())
+			c.visit(entry.AsMapEntry().Value())
+		}
+	case ast.SelectKind:
+		c.markComputed(e.AsSelect().Operand())
+		c.visit(e.AsSelect().Operand())
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			c.visit(field.AsStructField().Value())
+		}
+	}
+}
+
+// markComputed records an expression whose value is qualified but not read from a variable, along
+// with the branches of a conditional since each of them becomes the qualified value.
+func (c *attributeCollector) markComputed(e ast.Expr) {
+	if c.computed == nil {
+		c.computed = map[int64]bool{}
+	}
+	c.computed[e.ID()] = true
+	if e.Kind() == ast.CallKind && e.AsCall().FunctionName() == operators.Conditional {
+		args := e.AsCall().Args()
+		c.markComputed(args[1])
+		c.markComputed(args[2])
+	}
+}
+
+// markOperands records the operands of the qualifiers within an attribute expression rooted at a
+// comprehension variable.
+func (c *attributeCollector) markOperands(e ast.Expr) {
+	for {
+		switch e.Kind() {
+		case ast.SelectKind:
+			e = e.AsSelect().Operand()
+		case ast.CallKind:
+			e = e.AsCall().Args()[0]
+		default:
+			return
+		}
+		c.markComputed(e)
+	}
+}
+
+// attribute returns the variable and qualifiers of an attribute expression, along with any
+// non-constant index arguments within the attribute which must be visited separately.
+func (c *attributeCollector) attribute(e ast.Expr) (string, []any, []ast.Expr, bool) {
+	root, quals, args, ok := c.qualifiers(e)
+	if !ok {
+		return "", nil, nil, false
+	}
+	variable, ok := c.variableName(root)
+	if !ok {
+		return "", nil, nil, false
+	}
+	// The attribute ends at the first non-constant index.
+	return variable, constantPrefix(quals), args, true
+}
+
+// qualifiers returns the identifier at the root of an attribute expression and the qualifiers
+// applied to it, where non-constant indices are represented by anyQualifier and returned as args.
+func (c *attributeCollector) qualifiers(e ast.Expr) (ast.Expr, []any, []ast.Expr, bool) {
+	var quals []any
+	var args []ast.Expr
+	for {
+		switch e.Kind() {
+		case ast.IdentKind:
+			slices.Reverse(quals)
+			return e, quals, args, true
+		case ast.SelectKind:
+			sel := e.AsSelect()
+			quals = append(quals, sel.FieldName())
+			e = sel.Operand()
+		case ast.CallKind:
+			call := e.AsCall()
+			// The dyn function is an identity function and does not affect the attribute path.
+			if call.FunctionName() == overloads.TypeConvertDyn && !call.IsMemberFunction() && len(call.Args()) == 1 {
+				e = call.Args()[0]
+				continue
+			}
+			if !isQualifierCall(call) {
+				return nil, nil, nil, false
+			}
+			if q, isConst := attributeQualifier(call.Args()[1]); isConst {
+				quals = append(quals, q)
+			} else {
+				quals = append(quals, anyQualifier{})
+				args = append(args, call.Args()[1])
+			}
+			e = call.Args()[0]
+		default:
+			return nil, nil, nil, false
+		}
+	}
+}
+
+// constantPrefix returns the qualifiers up to the first non-constant index.
+func constantPrefix(quals []any) []any {
+	if i := slices.Index(quals, any(anyQualifier{})); i >= 0 {
+		return quals[:i]
+	}
+	return quals
+}
+
+// variableName resolves an identifier to a variable name, excluding comprehension variables,
+// constants, and type identifiers.
+func (c *attributeCollector) variableName(e ast.Expr) (string, bool) {
+	name := e.AsIdent()
+	if _, found := c.local(name); found {
+		return "", false
+	}
+	if !c.ast.IsChecked() {
+		return name, true
+	}
+	if ref, found := c.ast.ReferenceMap()[e.ID()]; found {
+		if ref.Value != nil {
+			return "", false
+		}
+		if ref.Name != "" {
+			name = ref.Name
+		}
+	}
+	if c.ast.GetType(e.ID()).Kind() == types.TypeKind {
+		return "", false
+	}
+	return name, true
+}
+
+// local returns the attribute paths of the innermost comprehension variable with the given name.
+func (c *attributeCollector) local(name string) ([]*attributePath, bool) {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if paths, found := c.scopes[i][name]; found {
+			return paths, true
+		}
+	}
+	return nil, false
+}
+
+// comprehensionScope returns the attribute paths which each of the comprehension variables may
+// refer to.
+//
+// The accumulator refers to its initial value, which is how cel.bind introduces local names, and
+// the iteration variable which ranges over values refers to the elements of the range.
+func (c *attributeCollector) comprehensionScope(comp ast.ComprehensionExpr) map[string][]*attributePath {
+	scope := map[string][]*attributePath{comp.AccuVar(): c.valuePaths(comp.AccuInit())}
+	iterRange := comp.IterRange()
+	var elems []*attributePath
+	isMap := false
+	switch iterRange.Kind() {
+	case ast.ListKind:
+		for _, elem := range iterRange.AsList().Elements() {
+			elems = append(elems, c.valuePaths(elem)...)
+		}
+	case ast.MapKind:
+		isMap = true
+		for _, entry := range iterRange.AsMap().Entries() {
+			elems = append(elems, c.valuePaths(entry.AsMapEntry().Value())...)
+		}
+	default:
+		isMap = c.ast.GetType(iterRange.ID()).Kind() == types.MapKind
+		for _, p := range c.valuePaths(iterRange) {
+			elems = append(elems, p.qualify(anyQualifier{}))
+		}
+	}
+	switch {
+	case comp.HasIterVar2():
+		scope[comp.IterVar()] = nil
+		scope[comp.IterVar2()] = elems
+	case isMap:
+		scope[comp.IterVar()] = nil
+	default:
+		scope[comp.IterVar()] = elems
+	}
+	return scope
+}
+
+// valuePaths returns the attribute paths which the value of an expression may refer to.
+func (c *attributeCollector) valuePaths(e ast.Expr) []*attributePath {
+	root, quals, _, ok := c.qualifiers(e)
+	if !ok {
+		return nil
+	}
+	if paths, found := c.local(root.AsIdent()); found {
+		out := make([]*attributePath, len(paths))
+		for i, p := range paths {
+			out[i] = p.qualify(quals...)
+		}
+		return out
+	}
+	variable, ok := c.variableName(root)
+	if !ok {
+		return nil
+	}
+	return []*attributePath{{variable: variable, quals: quals}}
+}
+
+// extendTrail extends an attribute trail with the qualifiers selected by the expression, provided
+// that the trail is a prefix of the expression's attribute path.
+func (c *attributeCollector) extendTrail(trail *types.AttributeTrail, e ast.Expr) *types.AttributeTrail {
+	variable, quals, _, ok := c.attribute(e)
+	if !ok {
+		return trail
+	}
+	// Unchecked expressions represent qualified variable names as field selections.
+	for i := 0; variable != trail.Variable() && i < len(quals); i++ {
+		field, isStr := quals[i].(string)
+		if !isStr {
+			break
+		}
+		variable = variable + "." + field
+		if variable == trail.Variable() {
+			quals = quals[i+1:]
+		}
+	}
+	if variable != trail.Variable() || !isQualifierPrefix(trail.QualifierPath(), quals) {
+		return trail
+	}
+	return newAttributeTrail(variable, quals)
+}
+
+func newAttributeTrail(variable string, quals []any) *types.AttributeTrail {
+	trail := types.NewAttributeTrail(variable)
+	for _, q := range quals {
+		switch q := q.(type) {
+		case bool:
+			trail = types.QualifyAttribute(trail, q)
+		case int64:
+			trail = types.QualifyAttribute(trail, q)
+		case string:
+			trail = types.QualifyAttribute(trail, q)
+		case uint64:
+			trail = types.QualifyAttribute(trail, q)
+		}
+	}
+	return trail
+}
+
+func isQualifierPrefix(prefix, quals []any) bool {
+	if len(prefix) > len(quals) {
+		return false
+	}
+	for i, q := range prefix {
+		if q != quals[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeAttributes sorts the attributes and removes duplicates as well as attributes which
+// extend other attributes in the list.
+func normalizeAttributes(attrs []*types.AttributeTrail) []*types.AttributeTrail {
+	slices.SortFunc(attrs, func(a, b *types.AttributeTrail) int {
+		if c := strings.Compare(a.Variable(), b.Variable()); c != 0 {
+			return c
+		}
+		if c := len(a.QualifierPath()) - len(b.QualifierPath()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.String(), b.String())
+	})
+	var out []*types.AttributeTrail
+	for _, attr := range attrs {
+		covered := slices.ContainsFunc(out, func(o *types.AttributeTrail) bool {
+			return o.Variable() == attr.Variable() && isQualifierPrefix(o.QualifierPath(), attr.QualifierPath())
+		})
+		if !covered {
+			out = append(out, attr)
+		}
+	}
+	slices.SortFunc(out, func(a, b *types.AttributeTrail) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return out
+}
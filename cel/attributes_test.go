@@ -0,0 +1,199 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"reflect"
+	"testing"
+
+	"cel.dev/cel-go/common/types"
+)
+
+func TestReferencedAttributes(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{
+			expr: `request.resource.labels['env'] == 'prod' && request.size > 10`,
+			want: []string{`request.resource.labels.env`, `request.size`},
+		},
+		{
+			expr: `request.items[x].name == 'a' && request.items[0].name == 'b'`,
+			want: []string{`request.items`, `x`},
+		},
+		{
+			expr: `has(request.auth.claims) && request.auth.claims['group-id'] == 1`,
+			want: []string{`request.auth.claims`},
+		},
+		{
+			expr: `request.items.exists(i, i.name == request.user && x > 0)`,
+			want: []string{`request.items`, `request.user`, `x`},
+		},
+		{
+			expr: `[request.a, {'k': request.b}, request.c.?d.orValue(1), request.e[?1]]`,
+			want: []string{`request.a`, `request.b`, `request.c.d`, `request.e[1]`},
+		},
+		{
+			expr: `dyn(request).a.b == 1 && dyn(x) == 1`,
+			want: []string{`request.a.b`, `x`},
+		},
+		{
+			expr: `type(x) == int && ns.flag`,
+			want: []string{`ns.flag`, `x`},
+		},
+		{
+			expr: `request == null || request.size > 0`,
+			want: []string{`request`},
+		},
+		{
+			expr: `1 + 2 == 3`,
+		},
+	}
+	env, err := NewEnv(
+		OptionalTypes(),
+		Variable("request", DynType),
+		Variable("x", IntType),
+		Variable("ns.flag", BoolType),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			checked, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			if got := attributeStrings(ReferencedAttributes(checked)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ReferencedAttributes() got %v, wanted %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnknownAttributes(t *testing.T) {
+	tests := []struct {
+		expr     string
+		patterns []*AttributePatternType
+		want     map[string][]string
+	}{
+		{
+			expr:     `request.resource.labels['env'] == 'prod' && request.size > 1`,
+			patterns: []*AttributePatternType{AttributePattern("request")},
+			want: map[string][]string{
+				"request": {`request.resource.labels.env`, `request.size`},
+			},
+		},
+		{
+			expr:     `request.resource.labels['env'] == 'prod' && request.size > 1`,
+			patterns: []*AttributePatternType{AttributePattern("request").QualString("resource")},
+			want: map[string][]string{
+				"request": {`request.resource.labels.env`},
+			},
+		},
+		{
+			expr: `request.a[x] == 1 || request.resource.labels['env'] == 'prod' || ns.flag`,
+			patterns: []*AttributePatternType{
+				AttributePattern("request").QualString("resource"),
+				AttributePattern("request").QualString("a"),
+				AttributePattern("ns.flag"),
+			},
+			want: map[string][]string{
+				"request": {`request.a`, `request.resource.labels.env`},
+				"ns.flag": {`ns.flag`},
+			},
+		},
+		{
+			expr:     `request.items.exists(i, i.name == 'a')`,
+			patterns: []*AttributePatternType{AttributePattern("request")},
+			want: map[string][]string{
+				"request": {`request.items`},
+			},
+		},
+	}
+	env, err := NewEnv(
+		Variable("request", DynType),
+		Variable("x", IntType),
+		Variable("ns.flag", BoolType),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			checked, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			parsed, iss := env.Parse(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Parse(%q) failed: %v", tc.expr, iss.Err())
+			}
+			for _, a := range []*Ast{checked, parsed} {
+				prg, err := env.Program(a, EvalOptions(OptPartialEval))
+				if err != nil {
+					t.Fatalf("env.Program() failed: %v", err)
+				}
+				vars, err := PartialVars(map[string]any{
+					"x": 1,
+					"request": map[string]any{
+						"size":     2,
+						"a":        map[int]int{1: 1},
+						"resource": map[string]any{"labels": map[string]string{}},
+						"items":    []any{},
+					},
+				}, tc.patterns...)
+				if err != nil {
+					t.Fatalf("PartialVars() failed: %v", err)
+				}
+				out, _, err := prg.Eval(vars)
+				if err != nil {
+					t.Fatalf("prg.Eval() failed: %v", err)
+				}
+				unk, isUnk := out.(*types.Unknown)
+				if !isUnk {
+					t.Fatalf("prg.Eval() got %v, wanted unknown", out)
+				}
+				got := map[string][]string{}
+				for v, attrs := range UnknownAttributes(a, unk) {
+					got[v] = attributeStrings(attrs)
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("UnknownAttributes(checked=%t) got %v, wanted %v", a.IsChecked(), got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownAttributesNil(t *testing.T) {
+	if UnknownAttributes(nil, nil) != nil {
+		t.Error("UnknownAttributes(nil, nil) got non-nil result")
+	}
+	if ReferencedAttributes(nil) != nil {
+		t.Error("ReferencedAttributes(nil) got non-nil result")
+	}
+}
+
+func attributeStrings(attrs []*types.AttributeTrail) []string {
+	var out []string
+	for _, attr := range attrs {
+		out = append(out, attr.String())
+	}
+	return out
+}
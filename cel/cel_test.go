@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
@@ -37,6 +39,8 @@ import (
 	"cel.dev/cel-go/common/env"
 	"cel.dev/cel-go/common/operators"
 	"cel.dev/cel-go/common/overloads"
+	"cel.dev/cel-go/common/schema/arrow"
+	"cel.dev/cel-go/common/schema/avro"
 	"cel.dev/cel-go/common/types"
 	"cel.dev/cel-go/common/types/ref"
 	"cel.dev/cel-go/common/types/traits"
@@ -4650,3 +4654,369 @@ func TestOptionalOperatorsLegacyEval(t *testing.T) {
 		})
 	}
 }
+
+func TestFlowSensitiveTyping(t *testing.T) {
+	env, err := NewEnv(
+		OptionalTypes(),
+		FlowSensitiveTyping(true),
+		Variable("x", DynType),
+		Variable("o", OptionalType(IntType)),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	tests := []struct {
+		expr    string
+		vars    map[string]any
+		want    ref.Val
+		outType *Type
+	}{
+		{
+			expr: `type(x) == string && x.startsWith('a')`,
+			vars: map[string]any{"x": "abc", "o": types.OptionalNone},
+			want: types.True,
+		},
+		{
+			expr: `type(x) == string && x.startsWith('a')`,
+			vars: map[string]any{"x": 1, "o": types.OptionalNone},
+			want: types.False,
+		},
+		{
+			expr: `o.hasValue() ? o + 1 : 0`,
+			vars: map[string]any{"x": 1, "o": types.OptionalOf(types.Int(1))},
+			want: types.Int(2),
+		},
+		{
+			expr: `o.hasValue() && o.orValue(0) == o`,
+			vars: map[string]any{"x": 1, "o": types.OptionalOf(types.Int(1))},
+			want: types.True,
+		},
+		{
+			expr: `!o.hasValue() || o > 0`,
+			vars: map[string]any{"x": 1, "o": types.OptionalNone},
+			want: types.True,
+		},
+		{
+			expr: `o.hasValue() && type(o) == optional_type`,
+			vars: map[string]any{"x": 1, "o": types.OptionalOf(types.Int(2))},
+			want: types.True,
+		},
+		{
+			expr:    `o.hasValue() ? optional.of(o) : optional.none()`,
+			vars:    map[string]any{"x": 1, "o": types.OptionalOf(types.Int(2))},
+			want:    types.OptionalOf(types.OptionalOf(types.Int(2))),
+			outType: OptionalType(OptionalType(IntType)),
+		},
+		{
+			expr: `o.hasValue() ? o == optional.of(2) : false`,
+			vars: map[string]any{"x": 1, "o": types.OptionalOf(types.Int(2))},
+			want: types.True,
+		},
+		{
+			expr: `o.hasValue() && [o][0] == optional.of(2)`,
+			vars: map[string]any{"x": 1, "o": types.OptionalOf(types.Int(2))},
+			want: types.True,
+		},
+		{
+			expr:    `o.hasValue() ? o : 0`,
+			vars:    map[string]any{"x": 1, "o": types.OptionalOf(types.Int(2))},
+			want:    types.Int(2),
+			outType: IntType,
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			if tc.outType != nil && !ast.OutputType().IsExactType(tc.outType) {
+				t.Errorf("env.Compile(%q) got output type %v, wanted %v", tc.expr, ast.OutputType(), tc.outType)
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			out, _, err := prg.Eval(tc.vars)
+			if err != nil {
+				t.Fatalf("prg.Eval() failed: %v", err)
+			}
+			if out.Equal(tc.want) != types.True {
+				t.Errorf("prg.Eval() got %v, wanted %v", out, tc.want)
+			}
+		})
+	}
+	if _, iss := env.Compile(`type(x) == int && x.startsWith('a')`); iss.Err() == nil {
+		t.Error("env.Compile() succeeded, wanted no matching overload error")
+	}
+}
+
+func TestUnionAndLiteralTypes(t *testing.T) {
+	env, err := NewEnv(
+		Variable("method", StringLiteralType("GET", "POST")),
+		Variable("id", UnionType(StringType, IntType)),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	ast, iss := env.Compile(`method == 'GET' && (id == 1 || id == 'abc')`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	out, _, err := prg.Eval(map[string]any{"method": "GET", "id": "abc"})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if out != types.True {
+		t.Errorf("prg.Eval() got %v, wanted true", out)
+	}
+	if _, iss := env.Compile(`method == 'PUT'`); iss.Err() == nil ||
+		!strings.Contains(iss.Err().Error(), `value "PUT" is not permitted by type '"GET" | "POST"'`) {
+		t.Errorf("env.Compile() got %v, wanted literal value error", iss.Err())
+	}
+	if _, iss := env.Compile(`id + 1`); iss.Err() == nil {
+		t.Error("env.Compile() succeeded, wanted overload error for union operand")
+	}
+
+	// Union and literal types survive a round trip through the environment config.
+	conf, err := env.ToConfig("unions")
+	if err != nil {
+		t.Fatalf("env.ToConfig() failed: %v", err)
+	}
+	env2, err := NewEnv(FromConfig(conf))
+	if err != nil {
+		t.Fatalf("NewEnv(FromConfig()) failed: %v", err)
+	}
+	if _, iss := env2.Compile(`method == 'PUT'`); iss.Err() == nil {
+		t.Error("env2.Compile() succeeded, wanted literal value error")
+	}
+	if _, iss := env2.Compile(`id == 1 || id == 'abc'`); iss.Err() != nil {
+		t.Errorf("env2.Compile() failed: %v", iss.Err())
+	}
+}
+
+func TestSchemaTypes(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "crontab.yaml")
+	err := os.WriteFile(schemaPath, []byte(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: stable.example.com
+  names:
+    kind: CronTab
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required: [schedule]
+              properties:
+                schedule:
+                  type: string
+                  maxLength: 64
+                replicas:
+                  type: integer
+                policy:
+                  type: string
+                  enum: [Allow, Forbid]
+                args:
+                  type: array
+                  maxItems: 4
+                  items:
+                    type: string
+                    maxLength: 16
+`), 0600)
+	if err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	env, err := NewEnv(
+		SchemaTypes(schemaPath),
+		Variable("self", ObjectType("stable.example.com.v1.CronTab")),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	self := map[string]any{
+		"spec": map[string]any{
+			"schedule": "*/5 * * * *",
+			"replicas": float64(2),
+			"args":     []any{"-v"},
+		},
+	}
+	tests := []struct {
+		expr string
+		want ref.Val
+	}{
+		{expr: `self.spec.replicas + 1`, want: types.Int(3)},
+		{expr: `has(self.spec.policy) ? self.spec.policy == 'Allow' : true`, want: types.True},
+		{expr: `self.spec.schedule.startsWith('*/5') && self.spec.args.size() == 1`, want: types.True},
+		{expr: `type(self.spec) == stable.example.com.v1.CronTab.spec`, want: types.True},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			out, _, err := prg.Eval(map[string]any{"self": self})
+			if err != nil {
+				t.Fatalf("prg.Eval() failed: %v", err)
+			}
+			if out.Equal(tc.want) != types.True {
+				t.Errorf("prg.Eval() got %v, wanted %v", out, tc.want)
+			}
+		})
+	}
+	if _, iss := env.Compile(`self.spec.policy == 'Deny'`); iss.Err() == nil {
+		t.Error("env.Compile() succeeded, wanted literal value error")
+	}
+	if _, iss := env.Compile(`self.spec.undefined`); iss.Err() == nil {
+		t.Error("env.Compile() succeeded, wanted undefined field error")
+	}
+
+	// The maxLength and maxItems bounds limit the estimated cost of string and list operations.
+	ast, iss := env.Compile(`self.spec.args.exists(a, a == self.spec.schedule)`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	est, err := env.EstimateCost(ast, testCostEstimator{})
+	if err != nil {
+		t.Fatalf("env.EstimateCost() failed: %v", err)
+	}
+	if est.Max == math.MaxUint64 || est.Max > 100 {
+		t.Errorf("env.EstimateCost() got %v, wanted a bounded estimate", est)
+	}
+
+	// The schema paths are preserved within the environment config.
+	conf, err := env.ToConfig("schemas")
+	if err != nil {
+		t.Fatalf("env.ToConfig() failed: %v", err)
+	}
+	if !reflect.DeepEqual(conf.Schemas, []string{schemaPath}) {
+		t.Errorf("env.ToConfig() got schemas %v, wanted [%s]", conf.Schemas, schemaPath)
+	}
+	env2, err := NewEnv(FromConfig(conf))
+	if err != nil {
+		t.Fatalf("NewEnv(FromConfig()) failed: %v", err)
+	}
+	if _, iss := env2.Compile(`self.spec.replicas > 1`); iss.Err() != nil {
+		t.Errorf("env2.Compile() failed: %v", iss.Err())
+	}
+	if _, err := NewEnv(SchemaTypes(filepath.Join(t.TempDir(), "missing.yaml"))); err == nil {
+		t.Error("NewEnv(SchemaTypes(missing)) succeeded, wanted error")
+	}
+}
+
+func TestAvroAndArrowTypes(t *testing.T) {
+	avroSchema, err := avro.Parse([]byte(`{
+		"type": "record",
+		"name": "Click",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "user", "type": ["null", "string"]},
+			{"name": "at", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("avro.Parse() failed: %v", err)
+	}
+	avroTypes, err := avro.NewProvider(avroSchema)
+	if err != nil {
+		t.Fatalf("avro.NewProvider() failed: %v", err)
+	}
+	arrowTypes, err := arrow.NewProvider("Event", &arrow.Schema{
+		Fields: []arrow.Field{
+			{Name: "id", Type: arrow.Int64},
+			{Name: "name", Type: arrow.String, Nullable: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("arrow.NewProvider() failed: %v", err)
+	}
+	var objTypes []any
+	for _, typ := range append(avroTypes.Types(), arrowTypes.Types()...) {
+		objTypes = append(objTypes, typ)
+	}
+	env, err := NewEnv(
+		Types(objTypes...),
+		Variable("click", ObjectType("com.example.Click")),
+		Variable("event", ObjectType("Event")),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	batch := &testArrowBatch{columns: []arrow.Column{
+		testArrowColumn{int64(1), int64(2)},
+		testArrowColumn{"login", nil},
+	}}
+	at := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		vars map[string]any
+		want ref.Val
+	}{
+		{
+			expr: `has(click.user) && click.user == 'ada' && click.at > timestamp('2026-01-01T00:00:00Z')`,
+			vars: map[string]any{"click": avroTypes.NewRecord("com.example.Click", []any{"ada", at.UnixMilli()})},
+			want: types.True,
+		},
+		{
+			expr: `has(click.user)`,
+			vars: map[string]any{"click": avroTypes.NewRecord("com.example.Click", []any{nil, at.UnixMilli()})},
+			want: types.False,
+		},
+		{
+			expr: `has(event.name) ? event.name : 'event-' + string(event.id)`,
+			vars: map[string]any{"event": arrowTypes.NewRow(batch, 0)},
+			want: types.String("login"),
+		},
+		{
+			expr: `has(event.name) ? event.name : 'event-' + string(event.id)`,
+			vars: map[string]any{"event": arrowTypes.NewRow(batch, 1)},
+			want: types.String("event-2"),
+		},
+	}
+	for _, tc := range tests {
+		ast, iss := env.Compile(tc.expr)
+		if iss.Err() != nil {
+			t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("env.Program() failed: %v", err)
+		}
+		out, _, err := prg.Eval(tc.vars)
+		if err != nil {
+			t.Fatalf("prg.Eval(%q) failed: %v", tc.expr, err)
+		}
+		if out.Equal(tc.want) != types.True {
+			t.Errorf("prg.Eval(%q) got %v, wanted %v", tc.expr, out, tc.want)
+		}
+	}
+}
+
+type testArrowBatch struct {
+	columns []arrow.Column
+}
+
+func (b *testArrowBatch) NumRows() int              { return b.columns[0].Len() }
+func (b *testArrowBatch) Column(i int) arrow.Column { return b.columns[i] }
+
+type testArrowColumn []any
+
+func (c testArrowColumn) Len() int          { return len(c) }
+func (c testArrowColumn) IsNull(i int) bool { return c[i] == nil }
+func (c testArrowColumn) Value(i int) any   { return c[i] }
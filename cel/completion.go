@@ -0,0 +1,357 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cel.dev/cel-go/checker"
+	"cel.dev/cel-go/common"
+	celast "cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/containers"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/parser"
+)
+
+// CompletionKind describes the syntactic position of the cursor within an expression.
+type CompletionKind = parser.CompletionKind
+
+const (
+	// IdentifierCompletion indicates the cursor is at an identifier prefix, possibly empty.
+	IdentifierCompletion = parser.IdentifierCompletion
+
+	// MemberCompletion indicates the cursor follows a '.' applied to an operand expression.
+	MemberCompletion = parser.MemberCompletion
+
+	// CallArgumentCompletion indicates the cursor is at the start of a call argument.
+	CallArgumentCompletion = parser.CallArgumentCompletion
+)
+
+// CandidateKind indicates the kind of declaration a completion candidate refers to.
+type CandidateKind int
+
+const (
+	// VariableCandidate refers to a variable declared within the environment.
+	VariableCandidate CandidateKind = iota + 1
+
+	// FunctionCandidate refers to a function declared within the environment.
+	FunctionCandidate
+
+	// MacroCandidate refers to a macro configured within the environment.
+	MacroCandidate
+
+	// FieldCandidate refers to a field of a struct type.
+	FieldCandidate
+
+	// NamespaceCandidate refers to a segment of a qualified variable or function name.
+	NamespaceCandidate
+)
+
+// String returns a human-readable name for the candidate kind.
+func (k CandidateKind) String() string {
+	switch k {
+	case VariableCandidate:
+		return "variable"
+	case FunctionCandidate:
+		return "function"
+	case MacroCandidate:
+		return "macro"
+	case FieldCandidate:
+		return "field"
+	case NamespaceCandidate:
+		return "namespace"
+	default:
+		return "unknown"
+	}
+}
+
+// CompletionCandidate is a suggested name to insert at the cursor.
+type CompletionCandidate struct {
+	// Name is the text which completes the prefix at the cursor.
+	Name string
+
+	// Kind indicates the kind of declaration the candidate refers to.
+	Kind CandidateKind
+
+	// Type is the type of a variable or field candidate, nil otherwise.
+	Type *Type
+
+	// Description is the documentation associated with the declaration, if any.
+	Description string
+}
+
+// Completion describes the context of a cursor within a possibly incomplete expression along with
+// the candidates which may be inserted at the cursor.
+type Completion struct {
+	// Kind indicates the syntactic position of the cursor.
+	Kind CompletionKind
+
+	// Prefix is the portion of the identifier typed before the cursor, if any.
+	Prefix string
+
+	// OperandType is the type of the expression to the left of the '.' for a MemberCompletion.
+	//
+	// The type is nil if the operand is a namespace rather than a value, e.g. `math.`, or if the
+	// operand could not be type-checked.
+	OperandType *Type
+
+	// Function is the name of the innermost call whose argument list encloses the cursor, if any.
+	Function string
+
+	// ArgIndex is the zero-based index of the argument containing the cursor within the call.
+	ArgIndex int
+
+	// TargetType is the type of the receiver of the enclosing member call, if any.
+	TargetType *Type
+
+	// Candidates contains the names which complete the prefix, sorted by name.
+	Candidates []CompletionCandidate
+}
+
+// Complete reports the completion context at the code point `offset` within the expression text.
+//
+// The expression may be incomplete, e.g. `request.user.` while typing. It is parsed in an error
+// tolerant mode and type-checked against the environment's declarations in order to determine the
+// type of any operand at the cursor. The candidates are drawn from the environment's variables,
+// functions, macros, and the fields of struct types known to the type provider.
+//
+// A nil Completion is returned when the offset falls within a literal or comment.
+func (e *Env) Complete(txt string, offset int) (*Completion, error) {
+	src, err := common.NewTextSourceWithLimit(txt, e.configuredExpressionSizeLimit())
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || offset > len([]rune(txt)) {
+		return nil, fmt.Errorf("completion offset out of range: %d", offset)
+	}
+	prsr, err := e.initTolerantParser()
+	if err != nil {
+		return nil, err
+	}
+	parsed, _ := prsr.Parse(src)
+	cc := parser.Completion(parsed, src, offset)
+	if cc == nil {
+		return nil, nil
+	}
+	var typeMap map[int64]*types.Type
+	if parsed != nil {
+		chk, err := e.initChecker()
+		if err != nil {
+			return nil, err
+		}
+		// Type-checking errors are expected for incomplete expressions, so only the types which
+		// could be inferred are consulted.
+		checked, _ := checker.Check(parsed, src, chk)
+		typeMap = checked.TypeMap()
+	}
+	typeOf := func(expr celast.Expr) *Type {
+		if expr == nil {
+			return nil
+		}
+		if t, found := typeMap[expr.ID()]; found && t.Kind() != types.ErrorKind {
+			return t
+		}
+		return nil
+	}
+
+	out := &Completion{Kind: cc.Kind, Prefix: cc.Prefix}
+	if cc.Call != nil {
+		out.Function = cc.Call.Function
+		out.ArgIndex = cc.Call.ArgIndex
+		if cc.Call.IsMemberFunction {
+			// Qualified global functions, e.g. `math.greatest(`, parse as member calls.
+			if qual, found := qualifiedName(cc.Call.Target); found && e.HasFunction(qual+"."+cc.Call.Function) {
+				out.Function = qual + "." + cc.Call.Function
+			} else {
+				out.TargetType = typeOf(cc.Call.Target)
+			}
+		}
+	}
+
+	var candidates []CompletionCandidate
+	switch cc.Kind {
+	case MemberCompletion:
+		out.OperandType = typeOf(cc.Operand)
+		if qual, found := qualifiedName(cc.Operand); found {
+			candidates = append(candidates, e.qualifiedCandidates(qual+".")...)
+		}
+		if out.OperandType != nil || len(candidates) == 0 {
+			candidates = append(candidates, e.memberCandidates(out.OperandType)...)
+		}
+	default:
+		candidates = e.identCandidates()
+	}
+	out.Candidates = filterCandidates(candidates, cc.Prefix)
+	return out, nil
+}
+
+// identCandidates returns the variables, global functions, and global macros within the environment.
+func (e *Env) identCandidates() []CompletionCandidate {
+	var candidates []CompletionCandidate
+	for _, v := range e.variables {
+		candidates = append(candidates, CompletionCandidate{
+			Name:        v.Name(),
+			Kind:        VariableCandidate,
+			Type:        v.Type(),
+			Description: v.Description(),
+		})
+	}
+	for name, fn := range e.functions {
+		if fn.IsDeclarationDisabled() || !isCompletableName(name) {
+			continue
+		}
+		for _, o := range fn.OverloadDecls() {
+			if !o.IsMemberFunction() {
+				candidates = append(candidates, CompletionCandidate{
+					Name:        name,
+					Kind:        FunctionCandidate,
+					Description: fn.Description(),
+				})
+				break
+			}
+		}
+	}
+	for _, m := range e.macros {
+		if !m.IsReceiverStyle() && isCompletableName(m.Function()) {
+			candidates = append(candidates, CompletionCandidate{Name: m.Function(), Kind: MacroCandidate})
+		}
+	}
+	return candidates
+}
+
+// memberCandidates returns the fields and member functions applicable to an operand of the given type.
+//
+// When the operand type is unknown or dynamic, all member functions are considered applicable.
+func (e *Env) memberCandidates(operandType *Type) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	dynOperand := operandType == nil || operandType.Kind() == types.DynKind || operandType.Kind() == types.AnyKind
+	if operandType != nil && operandType.Kind() == types.StructKind {
+		fieldNames, _ := e.provider.FindStructFieldNames(operandType.TypeName())
+		for _, name := range fieldNames {
+			c := CompletionCandidate{Name: name, Kind: FieldCandidate}
+			if ft, found := e.provider.FindStructFieldType(operandType.TypeName(), name); found {
+				c.Type = ft.Type
+			}
+			candidates = append(candidates, c)
+		}
+	}
+	for name, fn := range e.functions {
+		if fn.IsDeclarationDisabled() || !isCompletableName(name) {
+			continue
+		}
+		for _, o := range fn.OverloadDecls() {
+			if o.IsMemberFunction() && (dynOperand || o.ArgTypes()[0].IsAssignableType(operandType)) {
+				candidates = append(candidates, CompletionCandidate{
+					Name:        name,
+					Kind:        FunctionCandidate,
+					Description: fn.Description(),
+				})
+				break
+			}
+		}
+	}
+	macroOperand := dynOperand
+	if operandType != nil {
+		switch operandType.Kind() {
+		case types.ListKind, types.MapKind, types.OpaqueKind:
+			macroOperand = true
+		}
+	}
+	if macroOperand {
+		for _, m := range e.macros {
+			if m.IsReceiverStyle() {
+				candidates = append(candidates, CompletionCandidate{Name: m.Function(), Kind: MacroCandidate})
+			}
+		}
+	}
+	return candidates
+}
+
+// qualifiedCandidates returns the next name segment of variables, functions, and macros whose names
+// begin with the qualified prefix, e.g. `math.` yields `greatest` and `least`.
+func (e *Env) qualifiedCandidates(qual string) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	add := func(name string, kind CandidateKind, t *Type, desc string) {
+		rest, found := strings.CutPrefix(name, qual)
+		if !found || rest == "" {
+			return
+		}
+		if seg, _, nested := strings.Cut(rest, "."); nested {
+			candidates = append(candidates, CompletionCandidate{Name: seg, Kind: NamespaceCandidate})
+			return
+		}
+		candidates = append(candidates, CompletionCandidate{Name: rest, Kind: kind, Type: t, Description: desc})
+	}
+	for _, v := range e.variables {
+		add(v.Name(), VariableCandidate, v.Type(), v.Description())
+	}
+	for name, fn := range e.functions {
+		if !fn.IsDeclarationDisabled() {
+			add(name, FunctionCandidate, nil, fn.Description())
+		}
+	}
+	for _, m := range e.macros {
+		if !m.IsReceiverStyle() {
+			add(m.Function(), MacroCandidate, nil, "")
+		}
+	}
+	return candidates
+}
+
+// filterCandidates removes candidates which do not match the prefix along with duplicates, and sorts
+// the remainder by name and kind.
+func filterCandidates(candidates []CompletionCandidate, prefix string) []CompletionCandidate {
+	seen := make(map[string]bool, len(candidates))
+	var out []CompletionCandidate
+	for _, c := range candidates {
+		key := fmt.Sprintf("%s/%d", c.Name, c.Kind)
+		if !strings.HasPrefix(c.Name, prefix) || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// qualifiedName returns the dot-qualified name of an ident or select chain.
+func qualifiedName(expr celast.Expr) (string, bool) {
+	if expr == nil {
+		return "", false
+	}
+	return containers.ToQualifiedName(expr)
+}
+
+// isCompletableName indicates whether a function or macro name may be written as an identifier,
+// excluding operators such as `_+_` and internal functions such as `@in`.
+func isCompletableName(name string) bool {
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
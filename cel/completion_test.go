@@ -0,0 +1,230 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	proto3pb "cel.dev/cel-go/test/proto3pb"
+)
+
+func TestComplete(t *testing.T) {
+	env, err := NewEnv(
+		Types(&proto3pb.TestAllTypes{}),
+		Variable("msg", ObjectType("google.expr.proto3.test.TestAllTypes")),
+		Variable("names", ListType(StringType)),
+		Variable("request.auth", MapType(StringType, DynType)),
+		Function("math.greatest",
+			Overload("math_greatest_int_int", []*Type{IntType, IntType}, IntType)),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	tests := []struct {
+		expr        string
+		offset      int
+		kind        CompletionKind
+		prefix      string
+		operandType *Type
+		function    string
+		argIndex    int
+		targetType  *Type
+		contains    []string
+		excludes    []string
+		candidates  []string
+	}{
+		{
+			expr:        `msg.single_i`,
+			kind:        MemberCompletion,
+			prefix:      "single_i",
+			operandType: ObjectType("google.expr.proto3.test.TestAllTypes"),
+			candidates:  []string{"single_int32", "single_int32_wrapper", "single_int64", "single_int64_wrapper"},
+		},
+		{
+			expr:        `msg.single_nested_message.`,
+			kind:        MemberCompletion,
+			operandType: ObjectType("google.expr.proto3.test.TestAllTypes.NestedMessage"),
+			candidates:  []string{"bb"},
+		},
+		{
+			expr:        `names.`,
+			kind:        MemberCompletion,
+			operandType: ListType(StringType),
+			contains:    []string{"all", "exists", "filter", "map", "size"},
+			excludes:    []string{"startsWith", "getHours"},
+		},
+		{
+			expr:        `names.exists(n, n.starts`,
+			kind:        MemberCompletion,
+			prefix:      "starts",
+			operandType: StringType,
+			function:    "exists",
+			argIndex:    1,
+			candidates:  []string{"startsWith"},
+		},
+		{
+			expr:       `request.`,
+			kind:       MemberCompletion,
+			candidates: []string{"auth"},
+		},
+		{
+			expr:       `math.`,
+			kind:       MemberCompletion,
+			candidates: []string{"greatest"},
+		},
+		{
+			expr:     `math.greatest(1, `,
+			kind:     CallArgumentCompletion,
+			function: "math.greatest",
+			argIndex: 1,
+			contains: []string{"msg", "names", "request.auth", "size", "has"},
+			excludes: []string{"_+_", "@in", "startsWith"},
+		},
+		{
+			expr:       `names.contains(`,
+			kind:       CallArgumentCompletion,
+			function:   "contains",
+			targetType: ListType(StringType),
+			contains:   []string{"msg"},
+		},
+		{
+			expr:       `msg.single_int64 > 0 && na`,
+			kind:       IdentifierCompletion,
+			prefix:     "na",
+			candidates: []string{"names"},
+		},
+		{
+			expr:       `size(n) > 0`,
+			offset:     6,
+			kind:       IdentifierCompletion,
+			prefix:     "n",
+			function:   "size",
+			candidates: []string{"names"},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			offset := tc.offset
+			if offset == 0 {
+				offset = len(tc.expr)
+			}
+			c, err := env.Complete(tc.expr, offset)
+			if err != nil {
+				t.Fatalf("Complete(%q, %d) failed: %v", tc.expr, offset, err)
+			}
+			if c == nil {
+				t.Fatalf("Complete(%q, %d) returned nil", tc.expr, offset)
+			}
+			if c.Kind != tc.kind || c.Prefix != tc.prefix {
+				t.Errorf("Complete(%q, %d) got kind %v, prefix %q, wanted kind %v, prefix %q",
+					tc.expr, offset, c.Kind, c.Prefix, tc.kind, tc.prefix)
+			}
+			if !reflect.DeepEqual(c.OperandType, tc.operandType) {
+				t.Errorf("Complete(%q, %d).OperandType got %v, wanted %v", tc.expr, offset, c.OperandType, tc.operandType)
+			}
+			if c.Function != tc.function || c.ArgIndex != tc.argIndex {
+				t.Errorf("Complete(%q, %d) got function %q, arg %d, wanted function %q, arg %d",
+					tc.expr, offset, c.Function, c.ArgIndex, tc.function, tc.argIndex)
+			}
+			if !reflect.DeepEqual(c.TargetType, tc.targetType) {
+				t.Errorf("Complete(%q, %d).TargetType got %v, wanted %v", tc.expr, offset, c.TargetType, tc.targetType)
+			}
+			var names []string
+			for _, cand := range c.Candidates {
+				names = append(names, cand.Name)
+			}
+			if tc.candidates != nil && !reflect.DeepEqual(names, tc.candidates) {
+				t.Errorf("Complete(%q, %d).Candidates got %v, wanted %v", tc.expr, offset, names, tc.candidates)
+			}
+			for _, name := range tc.contains {
+				if !slices.Contains(names, name) {
+					t.Errorf("Complete(%q, %d).Candidates got %v, wanted to contain %s", tc.expr, offset, names, name)
+				}
+			}
+			for _, name := range tc.excludes {
+				if slices.Contains(names, name) {
+					t.Errorf("Complete(%q, %d).Candidates got %v, wanted to exclude %s", tc.expr, offset, names, name)
+				}
+			}
+		})
+	}
+}
+
+func TestCompleteCandidateDetails(t *testing.T) {
+	env, err := NewEnv(
+		Types(&proto3pb.TestAllTypes{}),
+		Variable("msg", ObjectType("google.expr.proto3.test.TestAllTypes")),
+		VariableWithDoc("count", IntType, "number of items"),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	c, err := env.Complete("msg.single_string", 8)
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	want := CompletionCandidate{Name: "single_string", Kind: FieldCandidate, Type: StringType}
+	if !slices.Contains(c.Candidates, want) {
+		t.Errorf("Complete() got %v, wanted to contain %v", c.Candidates, want)
+	}
+	c, err = env.Complete("cou", 3)
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	want = CompletionCandidate{Name: "count", Kind: VariableCandidate, Type: IntType, Description: "number of items"}
+	if len(c.Candidates) != 1 || !reflect.DeepEqual(c.Candidates[0], want) {
+		t.Errorf("Complete() got %v, wanted [%v]", c.Candidates, want)
+	}
+}
+
+func TestCompleteErrors(t *testing.T) {
+	env, err := NewEnv()
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	if _, err := env.Complete("a.b", 4); err == nil {
+		t.Error("Complete() with an out of range offset succeeded, wanted error")
+	}
+	c, err := env.Complete("'abc", 4)
+	if err != nil || c != nil {
+		t.Errorf("Complete() within a literal got %v, %v, wanted nil, nil", c, err)
+	}
+}
+
+func TestCompleteTolerantParserInit(t *testing.T) {
+	env, err := NewEnv(Variable("request", MapType(StringType, DynType)))
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	ext, err := env.Extend(Variable("resource", DynType))
+	if err != nil {
+		t.Fatalf("env.Extend() failed: %v", err)
+	}
+	if env.tolerantPrsr != nil || ext.tolerantPrsr != nil {
+		t.Fatal("NewEnv() created the error tolerant parser, wanted it created on first use")
+	}
+	if _, err := ext.Complete("req", 3); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+	if ext.tolerantPrsr == nil {
+		t.Error("Complete() did not create the error tolerant parser")
+	}
+	if env.tolerantPrsr != nil {
+		t.Error("Complete() on an extended environment created the parent's error tolerant parser")
+	}
+}
@@ -81,6 +81,9 @@ const (
 
 	// UintKind represents a uint type.
 	UintKind = types.UintKind
+
+	// UnionKind represents a type which is assignable from any of its member types.
+	UnionKind = types.UnionKind
 )
 
 var (
@@ -127,6 +130,12 @@ var (
 	ObjectType = types.NewObjectType
 	// TypeParamType creates a parameterized type instance.
 	TypeParamType = types.NewTypeParamType
+	// UnionType creates a type which is assignable from any of its member types.
+	UnionType = types.NewUnionType
+	// StringLiteralType creates a string type which permits only the given values.
+	StringLiteralType = types.NewStringLiteralType
+	// IntLiteralType creates an int type which permits only the given values.
+	IntLiteralType = types.NewIntLiteralType
 )
 
 // Type holds a reference to a runtime type with an optional type-checked set of type parameters.
@@ -389,6 +398,13 @@ func OverloadOperandTrait(trait int) OverloadOpt {
 	return decls.OverloadOperandTrait(trait)
 }
 
+// OverloadIsCrossTypeNumericComparison marks the overload as a comparison between numeric values
+// of different types which is only available to the type-checker when CrossTypeNumericComparisons
+// is enabled.
+func OverloadIsCrossTypeNumericComparison() OverloadOpt {
+	return decls.OverloadIsCrossTypeNumericComparison()
+}
+
 // TypeToExprType converts a CEL-native type representation to a protobuf CEL Type representation.
 func TypeToExprType(t *Type) (*exprpb.Type, error) {
 	return types.TypeToExprType(t)
@@ -150,6 +150,7 @@ type Env struct {
 	libraries       map[string]SingletonLibrary
 	validators      []ASTValidator
 	costOptions     []checker.CostOption
+	schemaPaths     []string
 
 	// Flags for copy-on-write behavior with env.Extend.
 	funcsShared           bool
@@ -174,6 +175,12 @@ type Env struct {
 	prsr     *parser.Parser
 	prsrOpts []parser.Option
 
+	// Error tolerant parser used to support code completion, created on first use
+	tolerantPrsr     *parser.PrattParser
+	tolerantPrsrOpts []parser.Option
+	tolerantPrsrErr  error
+	tolerantPrsrOnce sync.Once
+
 	// Internal checker representation
 	chkMutex sync.Mutex
 	chk      *checker.Env
@@ -310,6 +317,8 @@ func (e *Env) ToConfig(name string) (*env.Config, error) {
 		}
 	}
 
+	conf.AddSchemas(e.schemaPaths...)
+
 	for id, val := range e.limits {
 		limitName, found := limitNameByID(id)
 		if !found || val == 0 {
@@ -589,6 +598,7 @@ func (e *Env) Extend(opts ...EnvOption) (*Env, error) {
 		chkOpts:         chkOptsCopy,
 		prsrOpts:        prsrOptsCopy,
 		costOptions:     costOptsCopy,
+		schemaPaths:     slices.Clone(e.schemaPaths),
 		// Copy-on-write flags.
 		funcsShared:           true,
 		featuresShared:        true,
@@ -942,6 +952,7 @@ func (e *Env) configure(opts []EnvOption) (*Env, error) {
 	if err != nil {
 		return nil, err
 	}
+	e.tolerantPrsrOpts = append(prsrOpts[:len(prsrOpts):len(prsrOpts)], parser.ErrorTolerant(true))
 
 	// Enable JSON field names is using a proto-based *types.Registry
 	if e.HasFeature(featureJSONFieldNames) {
@@ -966,6 +977,15 @@ func (e *Env) configure(opts []EnvOption) (*Env, error) {
 	return e, nil
 }
 
+// initTolerantParser creates the error tolerant parser on first use, since it is only required for
+// code completion.
+func (e *Env) initTolerantParser() (*parser.PrattParser, error) {
+	e.tolerantPrsrOnce.Do(func() {
+		e.tolerantPrsr, e.tolerantPrsrErr = parser.NewPrattParser(e.tolerantPrsrOpts...)
+	})
+	return e.tolerantPrsr, e.tolerantPrsrErr
+}
+
 func (e *Env) initChecker() (*checker.Env, error) {
 	e.chkOnce.Do(func() {
 		chkOpts := []checker.Option{}
@@ -975,6 +995,8 @@ func (e *Env) initChecker() (*checker.Env, error) {
 				e.HasFeature(featureCrossTypeNumericComparisons)))
 		chkOpts = append(chkOpts,
 			checker.JSONFieldNames(e.HasFeature(featureJSONFieldNames)))
+		chkOpts = append(chkOpts,
+			checker.FlowSensitiveTyping(e.HasFeature(featureFlowSensitiveTyping)))
 
 		if e.parent != nil && e.funcsShared {
 			parentChk, err := e.parent.initChecker()
@@ -570,8 +570,10 @@ func TestEnvToConfig(t *testing.T) {
 			opts: []EnvOption{
 				DefaultUTCTimeZone(false),
 				EnableMacroCallTracking(),
+				FlowSensitiveTyping(true),
 			},
 			want: env.NewConfig("feature flags").AddFeatures(
+				env.NewFeature("cel.feature.flow_sensitive_typing", true),
 				env.NewFeature("cel.feature.macro_call_tracking", true),
 			),
 		},
@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"sort"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/debug"
+	"cel.dev/cel-go/common/operators"
+)
+
+// SemanticallyEquivalent reports whether two expressions are equivalent after normalizing away
+// differences which cannot affect the result of evaluation.
+//
+// Both ASTs are type-checked against the environment if they have not been already, and are then
+// normalized as follows before being compared structurally with ast.Diff:
+//
+// - Constant subexpressions are folded, e.g. `x + (1 + 2)` becomes `x + 3`.
+// - The operands of chained `&&` and `||` operators are flattened, sorted, and deduplicated, as
+// CEL's logical operators are commutative.
+// - The elements of list literals on the right-hand side of the `in` operator are sorted and
+// deduplicated.
+//
+// Compiled policies may be compared in the same way, making it possible to determine whether a
+// refactoring of a policy changed its behavior. A result of false does not prove the expressions
+// differ in behavior, only that the normalized forms differ.
+func SemanticallyEquivalent(env *Env, a, b *Ast) (bool, error) {
+	normA, err := normalizeForEquivalence(env, a)
+	if err != nil {
+		return false, err
+	}
+	normB, err := normalizeForEquivalence(env, b)
+	if err != nil {
+		return false, err
+	}
+	return len(ast.Diff(normA, normB)) == 0, nil
+}
+
+func normalizeForEquivalence(env *Env, a *Ast) (*ast.AST, error) {
+	if !a.IsChecked() {
+		checked, iss := env.Check(a)
+		if iss.Err() != nil {
+			return nil, iss.Err()
+		}
+		a = checked
+	}
+	if blockVars := BlockIndexVariables(a); len(blockVars) != 0 {
+		var err error
+		if env, err = env.Extend(blockVars...); err != nil {
+			return nil, err
+		}
+	}
+	folder, err := NewConstantFoldingOptimizer()
+	if err != nil {
+		return nil, err
+	}
+	opt, err := NewStaticOptimizer(folder)
+	if err != nil {
+		return nil, err
+	}
+	// The optimizer returns a copy, so the normalization below does not modify the input.
+	folded, iss := opt.Optimize(env, a)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	normalized := folded.NativeRep()
+	fac := ast.NewExprFactory()
+	ast.PostOrderVisit(normalized.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		normalizeCommutative(fac, e)
+	}))
+	return normalized, nil
+}
+
+// normalizeCommutative rewrites logical operator chains and `in` list literals into a canonical order.
+//
+// The visit is post-order, so the subexpressions of the input have already been normalized.
+func normalizeCommutative(fac ast.ExprFactory, e ast.Expr) {
+	if e.Kind() != ast.CallKind {
+		return
+	}
+	call := e.AsCall()
+	switch call.FunctionName() {
+	case operators.LogicalAnd, operators.LogicalOr:
+		fn := call.FunctionName()
+		terms := canonicalOrder(logicalTerms(e, fn))
+		out := terms[0]
+		for _, term := range terms[1:] {
+			out = fac.NewCall(0, fn, out, term)
+		}
+		e.SetKindCase(out)
+	case operators.In:
+		list := call.Args()[1]
+		if list.Kind() != ast.ListKind || len(list.AsList().OptionalIndices()) != 0 {
+			return
+		}
+		elems := canonicalOrder(list.AsList().Elements())
+		e.SetKindCase(fac.NewCall(0, operators.In, call.Args()[0], fac.NewList(list.ID(), elems, nil)))
+	}
+}
+
+// logicalTerms flattens a chain of like logical operators into its terms.
+func logicalTerms(e ast.Expr, fn string) []ast.Expr {
+	if e.Kind() != ast.CallKind || e.AsCall().FunctionName() != fn {
+		return []ast.Expr{e}
+	}
+	var terms []ast.Expr
+	for _, arg := range e.AsCall().Args() {
+		terms = append(terms, logicalTerms(arg, fn)...)
+	}
+	return terms
+}
+
+// canonicalOrder sorts expressions by their debug representation and removes duplicates.
+func canonicalOrder(exprs []ast.Expr) []ast.Expr {
+	keys := make(map[ast.Expr]string, len(exprs))
+	for _, e := range exprs {
+		keys[e] = debug.ToDebugString(e)
+	}
+	sorted := make([]ast.Expr, len(exprs))
+	copy(sorted, exprs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i]] < keys[sorted[j]]
+	})
+	var out []ast.Expr
+	for _, e := range sorted {
+		if len(out) > 0 && ast.ExprEquals(out[len(out)-1], e) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
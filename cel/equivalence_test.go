@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"testing"
+)
+
+func TestSemanticallyEquivalent(t *testing.T) {
+	tests := []struct {
+		a          string
+		b          string
+		equivalent bool
+	}{
+		{a: `a && b`, b: `b && a`, equivalent: true},
+		{a: `(a && b) && c`, b: `c && (b && a)`, equivalent: true},
+		{a: `a || (b || a)`, b: `b || a`, equivalent: true},
+		{a: `a && (b || c)`, b: `(c || b) && a`, equivalent: true},
+		{a: `x + (1 + 2)`, b: `x + 3`, equivalent: true},
+		{a: `a && 1 < 2`, b: `a`, equivalent: true},
+		{a: `x in [1, 2, 3]`, b: `x in [3, 1, 2, 1]`, equivalent: true},
+		{a: `[1, 2].exists(i, i == x)`, b: `[1, 2].exists(j, j == x)`, equivalent: false},
+		{a: `a && b`, b: `a || b`, equivalent: false},
+		{a: `x - 1`, b: `1 - x`, equivalent: false},
+		{a: `x in [1, 2]`, b: `x in [1, 3]`, equivalent: false},
+	}
+	env, err := NewEnv(
+		Variable("a", BoolType),
+		Variable("b", BoolType),
+		Variable("c", BoolType),
+		Variable("x", IntType),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.a+" vs "+tc.b, func(t *testing.T) {
+			a, iss := env.Compile(tc.a)
+			if iss.Err() != nil {
+				t.Fatalf("Compile(%q) failed: %v", tc.a, iss.Err())
+			}
+			// Exercise the type-checking of parsed ASTs.
+			b, iss := env.Parse(tc.b)
+			if iss.Err() != nil {
+				t.Fatalf("Parse(%q) failed: %v", tc.b, iss.Err())
+			}
+			equiv, err := SemanticallyEquivalent(env, a, b)
+			if err != nil {
+				t.Fatalf("SemanticallyEquivalent() failed: %v", err)
+			}
+			if equiv != tc.equivalent {
+				t.Errorf("SemanticallyEquivalent(%q, %q) got %t, wanted %t", tc.a, tc.b, equiv, tc.equivalent)
+			}
+		})
+	}
+}
+
+func TestSemanticallyEquivalentErrors(t *testing.T) {
+	env, err := NewEnv(Variable("a", BoolType))
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	a, iss := env.Compile(`a`)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	b, iss := env.Parse(`undeclared`)
+	if iss.Err() != nil {
+		t.Fatalf("Parse() failed: %v", iss.Err())
+	}
+	if _, err := SemanticallyEquivalent(env, a, b); err == nil {
+		t.Error("SemanticallyEquivalent() with an undeclared reference succeeded, wanted error")
+	}
+}
@@ -0,0 +1,779 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"fmt"
+	"strings"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/env"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/interpreter"
+	"cel.dev/cel-go/parser"
+)
+
+// ValidateFieldAccess denies access to the given field paths within expressions.
+//
+// A field path is written as a CEL selection expression and is either rooted at a variable, e.g.
+// `request.auth.credentials.secret`, or at a message type, e.g.
+// `google.rpc.context.AttributeContext.Request.headers['authorization']`. A path is treated as
+// rooted at a message type when its leading components name a struct type known to the
+// environment, in which case the path applies to every value of that type regardless of how the
+// value was reached.
+//
+// Expressions which select a denied field, or any field nested within it, are rejected at compile
+// time when the selection can be resolved from the checked AST. Programs created from an
+// environment with this validator also guard attribute resolution at runtime, which covers
+// selections on `dyn` values and indices which are only known during evaluation; a denied access
+// at runtime results in an evaluation error.
+//
+// Selections on comprehension variables and cel.bind locals are checked against the paths the
+// local may refer to, e.g. `[request].exists(r, r.auth.credentials.secret == 'x')` is denied in
+// the same way as a direct selection on `request`.
+//
+// Field selections and indices applied to computed values, such as list elements, map values,
+// optional values, and function or comprehension results, are denied when the type of the value
+// could be the type of a prefix of a denied path and the qualifiers complete the path, e.g.
+// `[request][0].auth.credentials.secret`. Since the path of a computed value is not tracked, this
+// also rejects unrelated values of the same type, e.g. `{'secret': 'x'}.secret` when the type of
+// `request.auth.credentials` is `dyn`.
+//
+// Reads of a value which contains a denied field, e.g. `request.auth` as a whole, are not
+// restricted.
+func ValidateFieldAccess(deniedFields ...string) ASTValidator {
+	v := fieldAccessValidator{deniedFields: deniedFields}
+	v.paths, v.err = parseFieldPaths(deniedFields)
+	return v
+}
+
+type fieldAccessValidator struct {
+	deniedFields []string
+	paths        []*fieldPathPattern
+	err          error
+}
+
+// Name returns the name of the field access validator.
+func (v fieldAccessValidator) Name() string {
+	return fieldAccessValidatorName
+}
+
+// ToConfig converts the ASTValidator to an env.Validator specifying the validator name and the
+// denied field paths as a string list: {"fields": []string}
+func (v fieldAccessValidator) ToConfig() *env.Validator {
+	return env.NewValidator(v.Name()).SetConfig(map[string]any{"fields": v.deniedFields})
+}
+
+// Validate implements the ASTValidator interface method.
+func (v fieldAccessValidator) Validate(e *Env, _ ValidatorConfig, a *ast.AST, iss *Issues) {
+	if v.err != nil {
+		iss.ReportErrorAtID(a.Expr().ID(), "%v", v.err)
+		return
+	}
+	rules := newFieldAccessRules(e, v.paths)
+
+	// Check the attribute paths rooted at variables.
+	c := &attributeCollector{ast: a}
+	c.visit(a.Expr())
+	for i, attr := range c.attrs {
+		path := variablePath(attr.Variable(), attr.QualifierPath())
+		if denied := rules.deniedVariablePath(path); denied != nil {
+			iss.ReportErrorAtID(c.exprIDs[i], "access to field %s is denied", denied.text)
+		}
+	}
+	// Check the attribute paths reached through comprehension variables, including cel.bind.
+	for i, path := range c.localAttrs {
+		if denied := rules.deniedVariablePath(path.segments()); denied != nil {
+			iss.ReportErrorAtID(c.localExprIDs[i], "access to field %s is denied", denied.text)
+		}
+	}
+
+	// Check the qualifiers applied to computed values which may alias a prefix of a denied path.
+	root := ast.NavigateAST(a)
+	reported := map[int64]bool{}
+	for _, q := range ast.MatchDescendants(root, qualifierMatcher) {
+		operand := qualifierOperand(q)
+		if !c.computed[operand.ID()] {
+			continue
+		}
+		qual, isConst := selectionQualifier(q)
+		if !isConst {
+			continue
+		}
+		exprs := []ast.NavigableExpr{q}
+		quals := []any{qual}
+		for e := q; ; {
+			parent, found := e.Parent()
+			if !found || !qualifiesOperand(parent, e.ID()) {
+				break
+			}
+			q, _ := selectionQualifier(parent)
+			exprs = append(exprs, parent)
+			quals = append(quals, q)
+			e = parent
+		}
+		if denied, n := rules.deniedAlias(a.GetType(operand.ID()), quals); denied != nil {
+			if id := exprs[n-1].ID(); !reported[id] {
+				reported[id] = true
+				iss.ReportErrorAtID(id, "access to field %s is denied", denied.text)
+			}
+		}
+	}
+
+	// Check the field selections on message types.
+	if !a.IsChecked() || len(rules.typeRules) == 0 {
+		return
+	}
+	for _, sel := range ast.MatchDescendants(root, selectionMatcher) {
+		operand, field := selectionOperand(sel)
+		opType := a.GetType(operand.ID())
+		if opType.Kind() != types.StructKind {
+			continue
+		}
+		var quals []any
+		for e := sel; ; {
+			parent, found := e.Parent()
+			if !found || !qualifiesOperand(parent, e.ID()) {
+				break
+			}
+			q, _ := selectionQualifier(parent)
+			quals = append(quals, q)
+			e = parent
+		}
+		if denied := rules.deniedTypeField(opType.TypeName(), field, quals); denied != nil {
+			iss.ReportErrorAtID(sel.ID(), "access to field %s is denied", denied.text)
+		}
+	}
+}
+
+// selectionMatcher matches field selections, including optional field selections.
+func selectionMatcher(e ast.NavigableExpr) bool {
+	switch e.Kind() {
+	case ast.SelectKind:
+		return true
+	case ast.CallKind:
+		call := e.AsCall()
+		return call.FunctionName() == "_?._" && len(call.Args()) == 2
+	}
+	return false
+}
+
+// qualifierMatcher matches field selections and indices.
+func qualifierMatcher(e ast.NavigableExpr) bool {
+	return e.Kind() == ast.SelectKind || e.Kind() == ast.CallKind && isQualifierCall(e.AsCall())
+}
+
+func qualifierOperand(e ast.Expr) ast.Expr {
+	if e.Kind() == ast.SelectKind {
+		return e.AsSelect().Operand()
+	}
+	return e.AsCall().Args()[0]
+}
+
+func selectionOperand(e ast.Expr) (ast.Expr, string) {
+	if e.Kind() == ast.SelectKind {
+		return e.AsSelect().Operand(), e.AsSelect().FieldName()
+	}
+	field, _ := attributeQualifier(e.AsCall().Args()[1])
+	return e.AsCall().Args()[0], field.(string)
+}
+
+func selectionQualifier(e ast.Expr) (any, bool) {
+	if e.Kind() == ast.SelectKind {
+		return e.AsSelect().FieldName(), true
+	}
+	return attributeQualifier(e.AsCall().Args()[1])
+}
+
+// fieldPathPattern is a parsed field path consisting of string field names and constant indices.
+type fieldPathPattern struct {
+	text     string
+	segments []any
+}
+
+func parseFieldPaths(paths []string) ([]*fieldPathPattern, error) {
+	p, err := parser.NewParser()
+	if err != nil {
+		return nil, err
+	}
+	patterns := make([]*fieldPathPattern, 0, len(paths))
+	for _, path := range paths {
+		parsed, iss := p.Parse(common.NewTextSource(path))
+		if len(iss.GetErrors()) != 0 {
+			return nil, fmt.Errorf("invalid field path %q: %s", path, iss.ToDisplayString())
+		}
+		c := &attributeCollector{ast: parsed}
+		variable, quals, args, ok := c.attribute(parsed.Expr())
+		if !ok || len(args) != 0 {
+			return nil, fmt.Errorf("invalid field path %q: must be a selection with constant indices", path)
+		}
+		patterns = append(patterns, &fieldPathPattern{text: path, segments: variablePath(variable, quals)})
+	}
+	return patterns, nil
+}
+
+// variablePath flattens a possibly qualified variable name and its qualifiers into a single path.
+func variablePath(variable string, quals []any) []any {
+	var path []any
+	for _, part := range strings.Split(variable, ".") {
+		path = append(path, part)
+	}
+	return append(path, quals...)
+}
+
+// fieldAccessRules holds the denied field paths resolved against an environment.
+type fieldAccessRules struct {
+	variableRules []*fieldPathPattern
+	typeRules     []*typeFieldRule
+	aliases       []*aliasPrefix
+}
+
+// aliasPrefix is a prefix of a denied field path which a computed value may alias, together with
+// the qualifiers which reach the denied field from the prefix.
+type aliasPrefix struct {
+	rule *fieldPathPattern
+	// typ is the type of the prefix, or nil if the type is not known.
+	typ  *types.Type
+	rest []any
+}
+
+// typeFieldRule denies access to a field, and optionally the constant indices applied to it, on
+// values of a given message type.
+type typeFieldRule struct {
+	*fieldPathPattern
+	typeName string
+	field    string
+	rest     []any
+}
+
+func newFieldAccessRules(e *Env, paths []*fieldPathPattern) *fieldAccessRules {
+	rules := &fieldAccessRules{}
+	provider := e.CELTypeProvider()
+	for _, p := range paths {
+		if rule := findTypeFieldRule(provider, p); rule != nil {
+			rules.typeRules = append(rules.typeRules, rule)
+			// Values of the message type itself are covered by the type rule, so only the values
+			// nested within the denied field may be aliased.
+			var t *types.Type
+			if ft, found := provider.FindStructFieldType(rule.typeName, rule.field); found {
+				t = ft.Type
+			}
+			rules.addAliases(provider, p, t, rule.rest)
+			continue
+		}
+		rules.variableRules = append(rules.variableRules, p)
+		t, start := variablePathType(e, p)
+		rules.addAliases(provider, p, t, p.segments[start:])
+	}
+	return rules
+}
+
+// variablePathType returns the declared type of the variable at the root of the path, or nil if the
+// variable is not declared, along with the number of path segments which name the variable.
+func variablePathType(e *Env, p *fieldPathPattern) (*types.Type, int) {
+	for i := len(p.segments) - 1; i > 0; i-- {
+		parts := make([]string, 0, i)
+		for _, s := range p.segments[:i] {
+			if str, isStr := s.(string); isStr {
+				parts = append(parts, str)
+			}
+		}
+		if len(parts) != i {
+			continue
+		}
+		name := strings.Join(parts, ".")
+		for _, v := range e.variables {
+			if v.Name() == name {
+				return v.Type(), i
+			}
+		}
+	}
+	return nil, 1
+}
+
+// addAliases records each prefix of a denied path starting from a value of the given type and
+// followed by the input qualifiers.
+func (r *fieldAccessRules) addAliases(provider types.Provider, p *fieldPathPattern, t *types.Type, quals []any) {
+	for i, q := range quals {
+		r.aliases = append(r.aliases, &aliasPrefix{rule: p, typ: t, rest: quals[i:]})
+		t = qualifiedType(provider, t, q)
+	}
+}
+
+// qualifiedType returns the type of the value produced by applying the qualifier to a value of the
+// given type, or nil if the type is not known.
+func qualifiedType(provider types.Provider, t *types.Type, qual any) *types.Type {
+	t = unwrapOptionalType(t)
+	switch t.Kind() {
+	case types.StructKind:
+		if field, isStr := qual.(string); isStr {
+			if ft, found := provider.FindStructFieldType(t.TypeName(), field); found {
+				return ft.Type
+			}
+		}
+	case types.MapKind:
+		return t.Parameters()[1]
+	case types.ListKind:
+		return t.Parameters()[0]
+	}
+	return nil
+}
+
+func unwrapOptionalType(t *types.Type) *types.Type {
+	if t.Kind() == types.OpaqueKind && t.TypeName() == "optional_type" {
+		return t.Parameters()[0]
+	}
+	return t
+}
+
+// isDynamicType returns whether the type is unknown or may hold values of any type.
+func isDynamicType(t *types.Type) bool {
+	switch t.Kind() {
+	case types.UnspecifiedKind, types.AnyKind, types.DynKind, types.ErrorKind, types.TypeParamKind:
+		return true
+	}
+	return false
+}
+
+// couldAlias returns whether a value of the given type may be a value of the prefix type.
+func couldAlias(prefix, t *types.Type) bool {
+	prefix, t = unwrapOptionalType(prefix), unwrapOptionalType(t)
+	if isDynamicType(prefix) || isDynamicType(t) {
+		return true
+	}
+	return prefix.IsAssignableType(t) || t.IsAssignableType(prefix)
+}
+
+// findTypeFieldRule returns a type rule if the leading components of the path name a struct type.
+func findTypeFieldRule(provider types.Provider, p *fieldPathPattern) *typeFieldRule {
+	for i := len(p.segments) - 1; i > 0; i-- {
+		field, isStr := p.segments[i].(string)
+		if !isStr {
+			continue
+		}
+		var parts []string
+		for _, s := range p.segments[:i] {
+			str, isStr := s.(string)
+			if !isStr {
+				break
+			}
+			parts = append(parts, str)
+		}
+		if len(parts) != i {
+			continue
+		}
+		typeName := strings.Join(parts, ".")
+		if t, found := provider.FindStructType(typeName); found && t.Kind() == types.TypeKind {
+			return &typeFieldRule{fieldPathPattern: p, typeName: typeName, field: field, rest: p.segments[i+1:]}
+		}
+	}
+	return nil
+}
+
+// deniedVariablePath returns the first rule whose path is a prefix of the input path, where an
+// anyQualifier within the input path matches any rule segment.
+func (r *fieldAccessRules) deniedVariablePath(path []any) *fieldPathPattern {
+	for _, rule := range r.variableRules {
+		if len(rule.segments) > len(path) {
+			continue
+		}
+		matches := true
+		for i, seg := range rule.segments {
+			if path[i] != seg && path[i] != any(anyQualifier{}) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return rule
+		}
+	}
+	return nil
+}
+
+// deniedTypeField returns the first rule which denies the selection of the field on the given
+// type followed by the input qualifiers.
+func (r *fieldAccessRules) deniedTypeField(typeName, field string, quals []any) *fieldPathPattern {
+	for _, rule := range r.typeRules {
+		if rule.typeName == typeName && rule.field == field && isQualifierPrefix(rule.rest, quals) {
+			return rule.fieldPathPattern
+		}
+	}
+	return nil
+}
+
+// deniedAlias returns the first rule which is reached by applying the qualifiers to a computed
+// value of the given type, along with the number of qualifiers needed to reach the denied field.
+func (r *fieldAccessRules) deniedAlias(t *types.Type, quals []any) (*fieldPathPattern, int) {
+	for _, p := range r.aliases {
+		if couldAlias(p.typ, t) && isQualifierPrefix(p.rest, quals) {
+			return p.rule, len(p.rest)
+		}
+	}
+	return nil, 0
+}
+
+// fieldAccessRulesFor returns the field access rules configured on the environment, if any.
+func fieldAccessRulesFor(e *Env) (*fieldAccessRules, error) {
+	for _, v := range e.validators {
+		if fav, ok := v.(fieldAccessValidator); ok {
+			if fav.err != nil {
+				return nil, fav.err
+			}
+			return newFieldAccessRules(e, fav.paths), nil
+		}
+	}
+	return nil, nil
+}
+
+// newFieldAccessAttributeFactory wraps an attribute factory such that the attributes it produces
+// are checked against the field access rules when they are resolved.
+//
+// The AST being planned determines the attribute paths which comprehension variables refer to,
+// as well as the checked types of the values being qualified.
+func newFieldAccessAttributeFactory(fac interpreter.AttributeFactory, adapter types.Adapter, rules *fieldAccessRules, a *ast.AST) interpreter.AttributeFactory {
+	c := &attributeCollector{ast: a}
+	c.visit(a.Expr())
+	return &fieldAccessAttributeFactory{
+		AttributeFactory: fac,
+		adapter:          adapter,
+		rules:            rules,
+		locals:           c.localRefs,
+		computed:         c.computed,
+		typeMap:          a.TypeMap(),
+	}
+}
+
+type fieldAccessAttributeFactory struct {
+	interpreter.AttributeFactory
+	adapter types.Adapter
+	rules   *fieldAccessRules
+	// locals maps comprehension variable references to the attribute paths they may refer to.
+	locals map[int64][]*attributePath
+	// computed contains the ids of qualified expressions whose value is not read from a variable.
+	computed map[int64]bool
+	typeMap  map[int64]*types.Type
+}
+
+// staticTypeName returns the type name of the expression with the given id if the checked type
+// of the expression is not dynamic.
+func (f *fieldAccessAttributeFactory) staticTypeName(id int64) (string, bool) {
+	t := unwrapOptionalType(f.typeMap[id])
+	if isDynamicType(t) {
+		return "", false
+	}
+	return t.TypeName(), true
+}
+
+// AbsoluteAttribute implements the interpreter.AttributeFactory interface method.
+func (f *fieldAccessAttributeFactory) AbsoluteAttribute(id int64, names ...string) interpreter.NamespacedAttribute {
+	candidates := make([]string, len(names))
+	for i, name := range names {
+		candidates[i] = strings.TrimPrefix(name, ".")
+	}
+	baseNames := append([]string{}, names...)
+	attr := f.AttributeFactory.AbsoluteAttribute(id, names...)
+	return &guardedNamespacedAttribute{
+		guardedAttribute: &guardedAttribute{
+			Attribute: attr,
+			fac:       f,
+			id:        id,
+			names:     candidates,
+			locals:    f.locals[id],
+			computed:  f.computed[id],
+			base: func() interpreter.Attribute {
+				return f.AttributeFactory.AbsoluteAttribute(id, append([]string{}, baseNames...)...)
+			},
+		},
+		ns: attr,
+	}
+}
+
+// MaybeAttribute implements the interpreter.AttributeFactory interface method.
+func (f *fieldAccessAttributeFactory) MaybeAttribute(id int64, name string) interpreter.Attribute {
+	return &guardedAttribute{
+		Attribute: f.AttributeFactory.MaybeAttribute(id, name),
+		fac:       f,
+		id:        id,
+		names:     []string{strings.TrimPrefix(name, ".")},
+		locals:    f.locals[id],
+		computed:  f.computed[id],
+		base: func() interpreter.Attribute {
+			return f.AttributeFactory.MaybeAttribute(id, name)
+		},
+	}
+}
+
+// RelativeAttribute implements the interpreter.AttributeFactory interface method.
+func (f *fieldAccessAttributeFactory) RelativeAttribute(id int64, operand interpreter.Interpretable) interpreter.Attribute {
+	return &guardedAttribute{
+		Attribute: f.AttributeFactory.RelativeAttribute(id, operand),
+		fac:       f,
+		id:        id,
+		computed:  f.computed[id],
+		base: func() interpreter.Attribute {
+			return f.AttributeFactory.RelativeAttribute(id, operand)
+		},
+	}
+}
+
+// guardedAttribute checks the concrete field path of an attribute against the field access rules
+// prior to resolving the attribute.
+type guardedAttribute struct {
+	interpreter.Attribute
+	fac *fieldAccessAttributeFactory
+	// id is the id of the expression which the first qualifier is applied to.
+	id int64
+	// names contains the candidate variable names of the attribute, if any.
+	names []string
+	// locals contains the attribute paths a comprehension variable attribute may refer to.
+	locals []*attributePath
+	// computed indicates whether the attribute qualifies a value which is not read from a variable.
+	computed bool
+	// base creates a new unqualified instance of the attribute, used to determine the type of
+	// intermediate values when checking type rules.
+	base  func() interpreter.Attribute
+	quals []interpreter.Qualifier
+}
+
+// AddQualifier implements the interpreter.Attribute interface method.
+func (a *guardedAttribute) AddQualifier(qual interpreter.Qualifier) (interpreter.Attribute, error) {
+	attr, err := a.Attribute.AddQualifier(qual)
+	if err != nil {
+		return nil, err
+	}
+	a.Attribute = attr
+	a.quals = append(a.quals, qual)
+	return a, nil
+}
+
+// Resolve implements the interpreter.Attribute interface method.
+func (a *guardedAttribute) Resolve(vars interpreter.Activation) (any, error) {
+	if err := a.checkAccess(vars); err != nil {
+		return nil, err
+	}
+	return a.Attribute.Resolve(vars)
+}
+
+// Qualify implements the interpreter.Qualifier interface method.
+func (a *guardedAttribute) Qualify(vars interpreter.Activation, obj any) (any, error) {
+	if err := a.checkAccess(vars); err != nil {
+		return nil, err
+	}
+	return a.Attribute.Qualify(vars, obj)
+}
+
+// QualifyIfPresent implements the interpreter.Qualifier interface method.
+func (a *guardedAttribute) QualifyIfPresent(vars interpreter.Activation, obj any, presenceOnly bool) (any, bool, error) {
+	if err := a.checkAccess(vars); err != nil {
+		return nil, false, err
+	}
+	return a.Attribute.QualifyIfPresent(vars, obj, presenceOnly)
+}
+
+// String returns the string representation of the guarded attribute.
+func (a *guardedAttribute) String() string {
+	return fmt.Sprintf("%v", a.Attribute)
+}
+
+func (a *guardedAttribute) checkAccess(vars interpreter.Activation) error {
+	if len(a.quals) == 0 {
+		return nil
+	}
+	values := a.qualifierValues(vars)
+	rules := a.fac.rules
+	for _, name := range a.names {
+		if denied := rules.deniedVariablePath(variablePath(name, values)); denied != nil {
+			return fmt.Errorf("access to field %s is denied", denied.text)
+		}
+	}
+	for _, local := range a.locals {
+		if denied := rules.deniedVariablePath(local.qualify(values...).segments()); denied != nil {
+			return fmt.Errorf("access to field %s is denied", denied.text)
+		}
+	}
+	if a.computed {
+		for i := range values {
+			operandID := a.id
+			if i > 0 {
+				operandID = a.quals[i-1].ID()
+			}
+			if denied, _ := rules.deniedAlias(a.fac.typeMap[operandID], values[i:]); denied != nil {
+				return fmt.Errorf("access to field %s is denied", denied.text)
+			}
+		}
+	}
+	var typeNames []string
+	for i, v := range values {
+		field, isStr := v.(string)
+		if !isStr {
+			continue
+		}
+		for _, rule := range rules.typeRules {
+			if rule.field != field || !isQualifierPrefix(rule.rest, values[i+1:]) {
+				continue
+			}
+			if typeNames == nil {
+				typeNames = a.operandTypeNames(vars, len(values))
+			}
+			if typeNames[i] == rule.typeName {
+				return fmt.Errorf("access to field %s is denied", rule.text)
+			}
+		}
+	}
+	return nil
+}
+
+// qualifierValues returns the values of the qualifiers up to the first qualifier whose value
+// cannot be determined.
+func (a *guardedAttribute) qualifierValues(vars interpreter.Activation) []any {
+	values := make([]any, 0, len(a.quals))
+	for _, q := range a.quals {
+		var val ref.Val
+		switch q := q.(type) {
+		case interpreter.ConstantQualifier:
+			val = q.Value()
+		case interpreter.Attribute:
+			v, err := q.Resolve(vars)
+			if err != nil {
+				return values
+			}
+			val = a.fac.adapter.NativeToValue(v)
+		default:
+			return values
+		}
+		qual, ok := refQualifier(val)
+		if !ok {
+			return values
+		}
+		values = append(values, qual)
+	}
+	return values
+}
+
+// operandTypeNames returns the type names of the values which the first n qualifiers are applied
+// to.
+//
+// Checked types are used when they are not dynamic. Otherwise, the attribute is resolved up to the
+// first qualifier with a dynamic operand and the remaining qualifiers are applied to the resolved
+// value, so the attribute operand is evaluated at most once.
+func (a *guardedAttribute) operandTypeNames(vars interpreter.Activation, n int) []string {
+	names := make([]string, n)
+	var obj ref.Val
+	applied := -1
+	for i := 0; i < n; i++ {
+		operandID := a.id
+		if i > 0 {
+			operandID = a.quals[i-1].ID()
+		}
+		if name, found := a.fac.staticTypeName(operandID); found {
+			names[i] = name
+			continue
+		}
+		if applied < 0 {
+			attr := a.base()
+			for _, q := range a.quals[:i] {
+				var err error
+				if attr, err = attr.AddQualifier(q); err != nil {
+					return names
+				}
+			}
+			val, err := attr.Resolve(vars)
+			if err != nil {
+				return names
+			}
+			obj, applied = a.fac.adapter.NativeToValue(val), i
+		}
+		for ; applied < i; applied++ {
+			var ok bool
+			if obj, ok = a.qualifyOperand(vars, a.quals[applied], obj); !ok {
+				return names
+			}
+		}
+		if opt, isOpt := obj.(*types.Optional); isOpt {
+			if !opt.HasValue() {
+				return names
+			}
+			obj = opt.GetValue()
+		}
+		names[i] = obj.Type().TypeName()
+	}
+	return names
+}
+
+// qualifyOperand applies a qualifier to a resolved value, returning false if the qualifier
+// cannot be applied.
+func (a *guardedAttribute) qualifyOperand(vars interpreter.Activation, q interpreter.Qualifier, obj ref.Val) (ref.Val, bool) {
+	var val any
+	var err error
+	if _, isOpt := obj.(*types.Optional); isOpt || q.IsOptional() {
+		var present bool
+		val, present, err = q.QualifyIfPresent(vars, obj, false)
+		if !present {
+			return nil, false
+		}
+	} else {
+		val, err = q.Qualify(vars, obj)
+	}
+	if err != nil {
+		return nil, false
+	}
+	out := a.fac.adapter.NativeToValue(val)
+	if types.IsError(out) {
+		return nil, false
+	}
+	return out, true
+}
+
+func refQualifier(val ref.Val) (any, bool) {
+	switch v := val.(type) {
+	case types.Bool:
+		return bool(v), true
+	case types.Int:
+		return int64(v), true
+	case types.String:
+		return string(v), true
+	case types.Uint:
+		return uint64(v), true
+	}
+	return nil, false
+}
+
+// guardedNamespacedAttribute is a guardedAttribute which also implements the
+// interpreter.NamespacedAttribute interface.
+type guardedNamespacedAttribute struct {
+	*guardedAttribute
+	ns interpreter.NamespacedAttribute
+}
+
+// AddQualifier implements the interpreter.Attribute interface method.
+func (a *guardedNamespacedAttribute) AddQualifier(qual interpreter.Qualifier) (interpreter.Attribute, error) {
+	if _, err := a.guardedAttribute.AddQualifier(qual); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// CandidateVariableNames implements the interpreter.NamespacedAttribute interface method.
+func (a *guardedNamespacedAttribute) CandidateVariableNames() []string {
+	return a.ns.CandidateVariableNames()
+}
+
+// Qualifiers implements the interpreter.NamespacedAttribute interface method.
+func (a *guardedNamespacedAttribute) Qualifiers() []interpreter.Qualifier {
+	return a.ns.Qualifiers()
+}
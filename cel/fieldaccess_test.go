@@ -0,0 +1,310 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/env"
+	"cel.dev/cel-go/common/types"
+
+	proto3pb "cel.dev/cel-go/test/proto3pb"
+)
+
+func TestValidateFieldAccess(t *testing.T) {
+	tests := []struct {
+		expr    string
+		compile string
+		eval    string
+	}{
+		{expr: `request.auth.principal == 'alice'`},
+		{expr: `has(request.auth) && size(request.auth) > 0`},
+		{
+			expr:    `request.auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `request.auth.credentials['secret'].startsWith('x')`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `request.auth[?'credentials'].?secret.hasValue()`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr: `request.auth.credentials[key] == 'x'`,
+			eval: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `dyn(request).auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `msg.map_string_string['authorization'] == 'x'`,
+			compile: `access to field google.expr.proto3.test.TestAllTypes.map_string_string['authorization'] is denied`,
+		},
+		{
+			expr:    `msg.single_nested_message.bb == 1`,
+			compile: `access to field google.expr.proto3.test.TestAllTypes.NestedMessage.bb is denied`,
+		},
+		{expr: `msg.map_string_string['other'] == 'y'`},
+		{
+			expr: `dyn(msg).map_string_string['authorization'] == 'x'`,
+			eval: `access to field google.expr.proto3.test.TestAllTypes.map_string_string['authorization'] is denied`,
+		},
+		{
+			expr: `dyn(msg).single_nested_message.bb == 1`,
+			eval: `access to field google.expr.proto3.test.TestAllTypes.NestedMessage.bb is denied`,
+		},
+		{expr: `cel.bind(r, request, r.auth.principal == 'alice')`},
+		{expr: `request.auth.credentials.exists(k, k == 'secret')`},
+		{
+			expr:    `cel.bind(r, request, r.auth.credentials.secret == 'x')`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `cel.bind(c, request.auth.credentials, c['secret'] == 'x')`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr: `cel.bind(c, request.auth.credentials, c[key] == 'x')`,
+			eval: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `[request].exists(r, r.auth.credentials.secret == 'x')`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `[request.auth].exists(a, [a.credentials].exists(c, c.secret == 'x'))`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr: `[request].exists(r, r.auth.credentials[key] == 'x')`,
+			eval: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `[msg].exists(m, m.single_nested_message.bb == 1)`,
+			compile: `access to field google.expr.proto3.test.TestAllTypes.NestedMessage.bb is denied`,
+		},
+		{
+			expr: `[dyn(msg)].exists(m, m.single_nested_message.bb == 1)`,
+			eval: `access to field google.expr.proto3.test.TestAllTypes.NestedMessage.bb is denied`,
+		},
+		{
+			expr:    `[request][0].auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `{'x': request}.x.auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `cel.bind(c, request.auth, c.credentials).secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `optional.of(request).value().auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `[request.auth].map(a, a.credentials)[0].secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `(key == 'x' ? {} : request).auth.credentials.secret == 'x'`,
+			compile: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr:    `[msg.map_string_string][0]['authorization'] == 'x'`,
+			compile: `access to field google.expr.proto3.test.TestAllTypes.map_string_string['authorization'] is denied`,
+		},
+		{
+			expr:    `cel.bind(m, msg.map_string_string, m['authorization'] == 'x')`,
+			compile: `access to field google.expr.proto3.test.TestAllTypes.map_string_string['authorization'] is denied`,
+		},
+		{
+			expr: `[request][0].auth.credentials[key] == 'x'`,
+			eval: `access to field request.auth.credentials.secret is denied`,
+		},
+		{
+			expr: `[msg.map_string_string][0]['auth' + 'orization'] == 'x'`,
+			eval: `access to field google.expr.proto3.test.TestAllTypes.map_string_string['authorization'] is denied`,
+		},
+		{expr: `[request][0].auth.principal == 'alice'`},
+		{expr: `[msg.map_string_string][0]['other'] == 'y'`},
+	}
+	env, err := NewEnv(
+		OptionalTypes(),
+		Macros(bindMacro),
+		Types(&proto3pb.TestAllTypes{}),
+		Variable("request", MapType(StringType, DynType)),
+		Variable("key", StringType),
+		Variable("msg", ObjectType("google.expr.proto3.test.TestAllTypes")),
+		ASTValidators(ValidateFieldAccess(
+			"request.auth.credentials.secret",
+			"google.expr.proto3.test.TestAllTypes.map_string_string['authorization']",
+			"google.expr.proto3.test.TestAllTypes.NestedMessage.bb",
+		)),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	vars := map[string]any{
+		"request": map[string]any{
+			"auth": map[string]any{
+				"principal":   "alice",
+				"credentials": map[string]string{"secret": "x"},
+			},
+		},
+		"key": "secret",
+		"msg": &proto3pb.TestAllTypes{
+			MapStringString: map[string]string{"authorization": "x", "other": "y"},
+			NestedType: &proto3pb.TestAllTypes_SingleNestedMessage{
+				SingleNestedMessage: &proto3pb.TestAllTypes_NestedMessage{Bb: 1},
+			},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if tc.compile != "" {
+				if iss.Err() == nil || !strings.Contains(iss.Err().Error(), tc.compile) {
+					t.Fatalf("env.Compile(%q) got %v, wanted error containing %q", tc.expr, iss.Err(), tc.compile)
+				}
+				return
+			}
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			out, _, err := prg.Eval(vars)
+			if tc.eval != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.eval) {
+					t.Fatalf("prg.Eval() got %v, %v, wanted error containing %q", out, err, tc.eval)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("prg.Eval() failed: %v", err)
+			}
+			if out != types.True {
+				t.Errorf("prg.Eval() got %v, wanted true", out)
+			}
+		})
+	}
+}
+
+// bindMacro mirrors the cel.bind macro from the ext package.
+var bindMacro = ReceiverMacro("bind", 3,
+	func(mef MacroExprFactory, target ast.Expr, args []ast.Expr) (ast.Expr, *Error) {
+		if target.Kind() != ast.IdentKind || target.AsIdent() != "cel" {
+			return nil, nil
+		}
+		varName := args[0].AsIdent()
+		return mef.NewComprehension(mef.NewList(), "#unused", varName, args[1],
+			mef.NewLiteral(types.False), mef.NewIdent(varName), args[2]), nil
+	})
+
+func TestValidateFieldAccessParsed(t *testing.T) {
+	env, err := NewEnv(
+		Variable("request", MapType(StringType, DynType)),
+		Variable("key", StringType),
+		ASTValidators(ValidateFieldAccess("request.auth.credentials.secret")),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	exprs := []string{
+		`request.auth.credentials.secret == 'x'`,
+		`[request.auth].exists(a, a.credentials.secret == 'x')`,
+		`[request][0].auth.credentials.secret == 'x'`,
+		`{'x': request}.x.auth.credentials[key] == 'x'`,
+	}
+	for _, expr := range exprs {
+		ast, iss := env.Parse(expr)
+		if iss.Err() != nil {
+			t.Fatalf("env.Parse(%q) failed: %v", expr, iss.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("env.Program() failed: %v", err)
+		}
+		_, _, err = prg.Eval(map[string]any{
+			"request": map[string]any{"auth": map[string]any{"credentials": map[string]any{"secret": "x"}}},
+			"key":     "secret",
+		})
+		if err == nil || !strings.Contains(err.Error(), "access to field request.auth.credentials.secret is denied") {
+			t.Errorf("prg.Eval(%q) got error %v, wanted access denied", expr, err)
+		}
+	}
+}
+
+func TestValidateFieldAccessConfig(t *testing.T) {
+	fields := []string{"request.auth.credentials.secret", "request.headers['authorization']"}
+	env1, err := NewEnv(
+		Variable("request", MapType(StringType, DynType)),
+		ASTValidators(ValidateFieldAccess(fields...)),
+	)
+	if err != nil {
+		t.Fatalf("NewEnv() failed: %v", err)
+	}
+	conf, err := env1.ToConfig("field_access")
+	if err != nil {
+		t.Fatalf("env.ToConfig() failed: %v", err)
+	}
+	var val *env.Validator
+	for _, v := range conf.Validators {
+		if v.Name == fieldAccessValidatorName {
+			val = v
+		}
+	}
+	if val == nil {
+		t.Fatalf("env.ToConfig() missing validator %s: %v", fieldAccessValidatorName, conf.Validators)
+	}
+	if got, _ := val.ConfigValue("fields"); !reflect.DeepEqual(got, fields) {
+		t.Errorf("ToConfig() got fields %v, wanted %v", got, fields)
+	}
+
+	// Config values read from YAML or protobuf are represented as lists of any.
+	env2, err := NewEnv(FromConfig(env.NewConfig("field_access").
+		AddVariables(env.NewVariable("request", env.NewTypeDesc("map", env.NewTypeDesc("string"), env.NewTypeDesc("dyn")))).
+		AddValidators(env.NewValidator(fieldAccessValidatorName).
+			SetConfig(map[string]any{"fields": []any{"request.headers['authorization']"}}))))
+	if err != nil {
+		t.Fatalf("NewEnv(FromConfig()) failed: %v", err)
+	}
+	if _, iss := env2.Compile(`request.headers.authorization == 'x'`); iss.Err() == nil {
+		t.Error("env.Compile() succeeded, wanted access denied")
+	}
+
+	_, err = NewEnv(FromConfig(env.NewConfig("invalid").
+		AddValidators(env.NewValidator(fieldAccessValidatorName).
+			SetConfig(map[string]any{"fields": []any{"request.f(x)"}}))))
+	if err == nil || !strings.Contains(err.Error(), "invalid field path") {
+		t.Errorf("NewEnv(FromConfig()) got error %v, wanted invalid field path", err)
+	}
+	_, err = NewEnv(FromConfig(env.NewConfig("invalid").
+		AddValidators(env.NewValidator(fieldAccessValidatorName))))
+	if err == nil || !strings.Contains(err.Error(), "missing fields") {
+		t.Errorf("NewEnv(FromConfig()) got error %v, wanted missing fields", err)
+	}
+}
@@ -70,6 +70,27 @@ func OptimizeWithSource(source Source) OptimizerOption {
 	}
 }
 
+// BlockIndexVariables returns the declarations of the `@index<N>` variables of a top-level
+// `cel.@block` call within a checked AST, such as those produced by policy composition.
+//
+// Extending an environment with the declarations permits the AST to be optimized, and therefore
+// type-checked, again.
+func BlockIndexVariables(a *Ast) []EnvOption {
+	root := a.NativeRep().Expr()
+	if root.Kind() != ast.CallKind || root.AsCall().FunctionName() != "cel.@block" {
+		return nil
+	}
+	block := root.AsCall()
+	if len(block.Args()) != 2 || block.Args()[0].Kind() != ast.ListKind {
+		return nil
+	}
+	var opts []EnvOption
+	for i, elem := range block.Args()[0].AsList().Elements() {
+		opts = append(opts, Variable(fmt.Sprintf("@index%d", i), a.NativeRep().GetType(elem.ID())))
+	}
+	return opts
+}
+
 // Optimize applies a sequence of optimizations to an Ast within a given environment.
 //
 // If issues are encountered, the Issues.Err() return value will be non-nil.
@@ -17,6 +17,7 @@ package cel
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
@@ -30,6 +31,7 @@ import (
 	"cel.dev/cel-go/common/decls"
 	"cel.dev/cel-go/common/env"
 	"cel.dev/cel-go/common/functions"
+	"cel.dev/cel-go/common/schema"
 	"cel.dev/cel-go/common/types"
 	"cel.dev/cel-go/common/types/pb"
 	"cel.dev/cel-go/common/types/ref"
@@ -75,6 +77,10 @@ const (
 
 	// Enable accessing fields by JSON names within protobuf messages
 	featureJSONFieldNames
+
+	// Enable the narrowing of types within branches guarded by type, presence, and optional
+	// value tests.
+	featureFlowSensitiveTyping
 )
 
 var featureIDsToNames = map[int]string{
@@ -82,6 +88,7 @@ var featureIDsToNames = map[int]string{
 	featureCrossTypeNumericComparisons: "cel.feature.cross_type_numeric_comparisons",
 	featureIdentEscapeSyntax:           "cel.feature.backtick_escape_syntax",
 	featureJSONFieldNames:              "cel.feature.json_field_names",
+	featureFlowSensitiveTyping:         "cel.feature.flow_sensitive_typing",
 }
 
 func featureNameByID(id int) (string, bool) {
@@ -374,6 +381,52 @@ func Types(addTypes ...any) EnvOption {
 	}
 }
 
+// SchemaTypes declares the object types described by JSON Schema, OpenAPI v3, or Kubernetes
+// CustomResourceDefinition documents read from the given paths. Documents may be written in either
+// JSON or YAML, and schemas may refer to schemas in other documents by name.
+//
+// Each named object schema is declared as a struct type whose values are represented at runtime as
+// `map[string]any` values, such as those produced by decoding JSON or YAML. The `maxLength`,
+// `maxItems`, and `maxProperties` bounds of object properties are used by Env.EstimateCost.
+//
+// See schema.Provider for details on how schemas are mapped to CEL types.
+func SchemaTypes(paths ...string) EnvOption {
+	return func(e *Env) (*Env, error) {
+		schemas := map[string]*schema.Schema{}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read schema: %w", err)
+			}
+			parsed, err := schema.Parse(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+			}
+			for name, s := range parsed {
+				if _, found := schemas[name]; found {
+					return nil, fmt.Errorf("failed to parse schema %s: duplicate schema %q", path, name)
+				}
+				schemas[name] = s
+			}
+		}
+		p, err := schema.NewProvider(schemas)
+		if err != nil {
+			return nil, err
+		}
+		objTypes := make([]any, 0, len(p.Types()))
+		for _, t := range p.Types() {
+			objTypes = append(objTypes, t)
+		}
+		e, err = Types(objTypes...)(e)
+		if err != nil {
+			return nil, err
+		}
+		e.costOptions = append(e.costOptions, checker.FieldSizeBounds(p.FieldMaxSize))
+		e.schemaPaths = append(e.schemaPaths, paths...)
+		return e, nil
+	}
+}
+
 // TypeDescs adds type declarations from any protoreflect.FileDescriptor, protoregistry.Files,
 // google.protobuf.FileDescriptorProto or google.protobuf.FileDescriptorSet provided.
 //
@@ -458,6 +511,25 @@ func JSONFieldNames(enabled bool) EnvOption {
 	return features(featureJSONFieldNames, enabled)
 }
 
+// FlowSensitiveTyping narrows the types of variables and field selections within the branches of
+// the `&&`, `||`, and `? :` operators according to the guard expressions which precede them.
+//
+// The supported guards are type tests on dynamically typed values, presence tests on nullable
+// fields, and optional value tests:
+//
+//	type(x) == string && x.startsWith('a')   // x is a string on the right-hand side
+//	has(msg.wrapped) ? msg.wrapped + 1 : 0   // msg.wrapped is an int rather than a nullable int
+//	opt.hasValue() ? opt + 1 : 0             // opt is unwrapped to its value type
+//
+// Narrowed types are recorded in the checked AST type map. Optional values used within a branch
+// guarded by hasValue() keep their optional type unless an overload only resolves with the value
+// type, e.g. `opt + 1`, in which case the use is rewritten to opt.value() so the runtime value
+// agrees with the narrowed type. Uses such as `type(opt)`, `optional.of(opt)`, `opt == other`, and
+// `[opt]` are left unchanged.
+func FlowSensitiveTyping(enabled bool) EnvOption {
+	return features(featureFlowSensitiveTyping, enabled)
+}
+
 // ProgramOption is a functional interface for configuring evaluation bindings and behaviors.
 type ProgramOption func(p *prog) (*prog, error)
 
@@ -550,6 +622,14 @@ func FromConfig(config *env.Config, optFactories ...ConfigOptionFactory) EnvOpti
 		if err := config.Validate(); err != nil {
 			return nil, err
 		}
+		// Schema types must be declared before the variables and functions which refer to them.
+		if len(config.Schemas) != 0 {
+			var err error
+			e, err = SchemaTypes(config.Schemas...)(e)
+			if err != nil {
+				return nil, err
+			}
+		}
 		opts, err := configToEnvOptions(config, e.CELTypeProvider(), optFactories)
 		if err != nil {
 			return nil, err
@@ -791,6 +871,30 @@ func ConcurrentDrainStrategy(strategy async.DrainStrategy) ProgramOption {
 	}
 }
 
+// BatchConcurrency sets the number of workers which evaluate the inputs to an EvalBatch call
+// concurrently. By default, and for values less than two, the inputs are evaluated sequentially on
+// the calling goroutine.
+func BatchConcurrency(workers int) ProgramOption {
+	return func(p *prog) (*prog, error) {
+		p.batchConcurrency = workers
+		return p, nil
+	}
+}
+
+// BatchCostLimit enables cost tracking and bounds the total runtime cost of all evaluations within
+// a single EvalBatch call. The evaluation which exhausts the budget fails with a cost limit error,
+// as do any inputs which have not been evaluated by then.
+//
+// The budget is shared by all batch workers and applies in addition to any per-evaluation limit set
+// with CostLimit.
+func BatchCostLimit(costLimit uint64) ProgramOption {
+	return func(p *prog) (*prog, error) {
+		p.batchCostLimit = &costLimit
+		p.evalOpts |= OptTrackCost
+		return p, nil
+	}
+}
+
 // CostEstimatorOptions configure type-check time options for estimating expression cost.
 func CostEstimatorOptions(costOpts ...checker.CostOption) EnvOption {
 	return func(e *Env) (*Env, error) {
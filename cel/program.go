@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"cel.dev/cel-go/cel/async"
@@ -70,6 +72,22 @@ type Program interface {
 	// their results discarded. Async functions should therefore be free of unwanted side effects
 	// on partial evaluation, or guard them with idempotency/cancellation handling.
 	ConcurrentEval(context.Context, any) <-chan EvalResult
+
+	// EvalBatch evaluates the program against each of the inputs and returns the results in input
+	// order, with one EvalResult per input.
+	//
+	// Each input value may either be an `Activation` or `map[string]any`.
+	//
+	// Execution frames and the per-evaluation trackers attached to them are reused from one input
+	// to the next, so EvalBatch avoids most of the setup cost incurred by calling Eval once per
+	// input. The inputs are evaluated by the number of workers set with BatchConcurrency, and the
+	// total cost of the batch may be bounded with BatchCostLimit. Programs which contain
+	// asynchronous function calls evaluate each input as ConcurrentEval would.
+	//
+	// Cancelling the context interrupts in-progress evaluations when used in conjunction with the
+	// InterruptCheckFrequency() option, and inputs which have not yet been evaluated when the
+	// context is done report the context error.
+	EvalBatch(context.Context, []any) []EvalResult
 }
 
 // Activation used to resolve identifiers by name and references by id.
@@ -141,6 +159,10 @@ type AttributePatternType = interpreter.AttributePattern
 type EvalDetails struct {
 	state       interpreter.EvalState
 	costTracker *interpreter.CostTracker
+
+	// cost records the actual cost of an evaluation whose cost tracker was reused after the
+	// evaluation completed.
+	cost *uint64
 }
 
 // State of the evaluation, non-nil if the OptTrackState or OptExhaustiveEval is specified
@@ -155,7 +177,14 @@ func (ed *EvalDetails) State() interpreter.EvalState {
 // ActualCost returns the tracked cost through the course of execution when `CostTracking` is enabled.
 // Otherwise, returns nil if the cost was not enabled.
 func (ed *EvalDetails) ActualCost() *uint64 {
-	if ed == nil || ed.costTracker == nil {
+	if ed == nil {
+		return nil
+	}
+	if ed.cost != nil {
+		cost := *ed.cost
+		return &cost
+	}
+	if ed.costTracker == nil {
 		return nil
 	}
 	cost := ed.costTracker.ActualCost()
@@ -190,6 +219,13 @@ type prog struct {
 	costOptions       []interpreter.CostTrackerOption
 	costLimit         *uint64
 
+	// costTracker is the template tracker cloned for each evaluation when cost tracking is enabled.
+	costTracker *interpreter.CostTracker
+
+	// Batch evaluation configuration used by EvalBatch.
+	batchConcurrency int
+	batchCostLimit   *uint64
+
 	// hasAsync indicates the planned expression contains an asynchronous function call, which can
 	// only be resolved by ConcurrentEval.
 	hasAsync bool
@@ -276,6 +312,14 @@ func newProgram(e *Env, a *ast.AST, opts []ProgramOption) (Program, error) {
 	} else {
 		attrFactory = interpreter.NewAttributeFactory(e.Container, e.adapter, e.provider, attrFactorOpts...)
 	}
+	// Guard attribute resolution when field access restrictions are configured on the environment.
+	fieldRules, err := fieldAccessRulesFor(e)
+	if err != nil {
+		return nil, err
+	}
+	if fieldRules != nil {
+		attrFactory = newFieldAccessAttributeFactory(attrFactory, e.adapter, fieldRules, a)
+	}
 	interp := interpreter.NewInterpreter(disp, e.Container, e.provider, e.adapter, attrFactory)
 	p.interpreter = interp
 
@@ -332,6 +376,7 @@ func newProgram(e *Env, a *ast.AST, opts []ProgramOption) (Program, error) {
 		if err != nil {
 			return nil, fmt.Errorf("construct cost tracker: %w", err)
 		}
+		p.costTracker = tracker
 		trackerFactory := func() (*interpreter.CostTracker, error) {
 			return tracker.Clone()
 		}
@@ -511,134 +556,277 @@ func (p *prog) ConcurrentEval(ctx context.Context, input any) <-chan EvalResult
 
 	go func() {
 		defer close(resCh)
-		// Ensure concurrent eval handles panic / recovery properly
-		defer func() {
-			if r := recover(); r != nil {
-				switch t := r.(type) {
-				case interpreter.EvalCancelledError:
-					resCh <- EvalResult{Err: t}
-				default:
-					resCh <- EvalResult{Err: fmt.Errorf("internal error: %v", r)}
-				}
-			}
-		}()
+		resCh <- p.concurrentEval(ctx, input, nil)
+	}()
 
-		frame, err := p.newAsyncFrame(ctx, input)
-		if err != nil {
-			resCh <- EvalResult{Err: err}
-			return
-		}
-		defer frame.Close()
+	return resCh
+}
 
-		// Completions are signaled to this channel as async calls finish. The asyncCallState
-		// fan-in also selects on ctx.Done(), so the sender will not leak if this loop returns early.
-		completions := make(chan int64, p.resolveCompletionBufferSize())
-		frame.SetCompletions(completions)
-
-		for {
-			var out ref.Val
-			var det *EvalDetails
-
-			if p.observable != nil {
-				det = &EvalDetails{}
-				out = p.observable.ObserveExec(frame, func(observed any) {
-					switch o := observed.(type) {
-					case interpreter.EvalState:
-						det.state = o
-					case *interpreter.CostTracker:
-						det.costTracker = o
-					}
-				})
-			} else {
-				out = p.interpretable.Exec(frame)
+// concurrentEval evaluates the input to completion, re-evaluating as asynchronous calls complete,
+// and returns the final result. When the budget is non-nil, the cost of the evaluation is charged
+// against it.
+func (p *prog) concurrentEval(ctx context.Context, input any, budget *interpreter.CostBudget) (res EvalResult) {
+	// Ensure concurrent eval handles panic / recovery properly
+	defer func() {
+		if r := recover(); r != nil {
+			switch t := r.(type) {
+			case interpreter.EvalCancelledError:
+				res = EvalResult{Err: t}
+			default:
+				res = EvalResult{Err: fmt.Errorf("internal error: %v", r)}
 			}
+		}
+	}()
 
-			// Communicate errors quickly.
-			if types.IsError(out) {
-				var err error = out.(*types.Err)
-				if errors.Is(err, interpreter.InterruptError{}) {
-					err = fmt.Errorf("%w: %w", err, context.Cause(ctx))
+	frame, err := p.newAsyncFrame(ctx, input)
+	if err != nil {
+		return EvalResult{Err: err}
+	}
+	defer frame.Close()
+	if budget != nil {
+		if err := p.setBudgetTracker(frame, budget); err != nil {
+			return EvalResult{Err: err}
+		}
+	}
+
+	// Completions are signaled to this channel as async calls finish. The asyncCallState
+	// fan-in also selects on ctx.Done(), so the sender will not leak if this loop returns early.
+	completions := make(chan int64, p.resolveCompletionBufferSize())
+	frame.SetCompletions(completions)
+
+	for {
+		var out ref.Val
+		var det *EvalDetails
+
+		if p.observable != nil {
+			det = &EvalDetails{}
+			out = p.observable.ObserveExec(frame, func(observed any) {
+				switch o := observed.(type) {
+				case interpreter.EvalState:
+					det.state = o
+				case *interpreter.CostTracker:
+					det.costTracker = o
 				}
-				resCh <- EvalResult{Val: out, EvalDetails: det, Err: err}
-				return
+			})
+		} else {
+			out = p.interpretable.Exec(frame)
+		}
+
+		// Communicate errors quickly.
+		if types.IsError(out) {
+			var err error = out.(*types.Err)
+			if errors.Is(err, interpreter.InterruptError{}) {
+				err = fmt.Errorf("%w: %w", err, context.Cause(ctx))
 			}
+			return EvalResult{Val: out, EvalDetails: det, Err: err}
+		}
+
+		// A concrete (non-unknown) result is final.
+		unk, isUnknown := out.(*types.Unknown)
+		if !isUnknown || !unk.HasUnknownFunction() {
+			return EvalResult{Val: out, EvalDetails: det, Err: nil}
+		}
+
+		// Post-execution dispatch: launch only the async calls required by the unknown result.
+		frame.DispatchPendingAsyncCalls(unk.IDs())
 
-			// A concrete (non-unknown) result is final.
-			unk, isUnknown := out.(*types.Unknown)
-			if !isUnknown || !unk.HasUnknownFunction() {
-				resCh <- EvalResult{Val: out, EvalDetails: det, Err: nil}
-				return
+		// The result depends on one or more unresolved async calls. Wait for completions and
+		// re-evaluate according to the configured drain strategy.
+		var batch []async.Call
+
+		// Wait for at least one completion (or cancellation).
+		select {
+		case id := <-completions:
+			if call := frame.AsyncCall(id); call != nil {
+				batch = append(batch, call)
 			}
+		case <-ctx.Done():
+			return EvalResult{Val: out, EvalDetails: det, Err: ctx.Err()}
+		}
 
-			// Post-execution dispatch: launch only the async calls required by the unknown result.
-			frame.DispatchPendingAsyncCalls(unk.IDs())
+		// Accumulate completions and consult the strategy.
+		var timer *time.Timer
+		reevaluate := false
+		for !reevaluate {
+			active := frame.ActiveAsyncCalls()
+			action := p.drainStrategy.NextAction(batch, active)
+			if action.Reevaluate {
+				break
+			}
 
-			// The result depends on one or more unresolved async calls. Wait for completions and
-			// re-evaluate according to the configured drain strategy.
-			var batch []async.Call
+			var timeoutCh <-chan time.Time
+			if action.WaitDuration > 0 {
+				if timer == nil {
+					timer = time.NewTimer(action.WaitDuration)
+				} else {
+					if !timer.Stop() {
+						select {
+						case <-timer.C:
+						default:
+						}
+					}
+					timer.Reset(action.WaitDuration)
+				}
+				timeoutCh = timer.C
+			}
 
-			// Wait for at least one completion (or cancellation).
 			select {
 			case id := <-completions:
 				if call := frame.AsyncCall(id); call != nil {
 					batch = append(batch, call)
 				}
+			case <-timeoutCh:
+				reevaluate = true
 			case <-ctx.Done():
-				resCh <- EvalResult{Val: out, EvalDetails: det, Err: ctx.Err()}
-				return
-			}
-
-			// Accumulate completions and consult the strategy.
-			var timer *time.Timer
-			reevaluate := false
-			for !reevaluate {
-				active := frame.ActiveAsyncCalls()
-				action := p.drainStrategy.NextAction(batch, active)
-				if action.Reevaluate {
-					break
+				if timer != nil {
+					timer.Stop()
 				}
+				return EvalResult{Val: out, EvalDetails: det, Err: ctx.Err()}
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
 
-				var timeoutCh <-chan time.Time
-				if action.WaitDuration > 0 {
-					if timer == nil {
-						timer = time.NewTimer(action.WaitDuration)
-					} else {
-						if !timer.Stop() {
-							select {
-							case <-timer.C:
-							default:
-							}
-						}
-						timer.Reset(action.WaitDuration)
-					}
-					timeoutCh = timer.C
-				}
+// EvalBatch implements the Program interface.
+func (p *prog) EvalBatch(ctx context.Context, inputs []any) []EvalResult {
+	results := make([]EvalResult, len(inputs))
+	if ctx == nil {
+		for i := range results {
+			results[i] = EvalResult{Err: errors.New("context can not be nil")}
+		}
+		return results
+	}
+	var budget *interpreter.CostBudget
+	if p.batchCostLimit != nil {
+		budget = interpreter.NewCostBudget(*p.batchCostLimit)
+	}
+	var next atomic.Int64
+	workers := min(max(p.batchConcurrency, 1), len(inputs))
+	if workers <= 1 {
+		p.evalBatchWorker(ctx, inputs, results, &next, budget)
+		return results
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			p.evalBatchWorker(ctx, inputs, results, &next, budget)
+		}()
+	}
+	wg.Wait()
+	return results
+}
 
-				select {
-				case id := <-completions:
-					if call := frame.AsyncCall(id); call != nil {
-						batch = append(batch, call)
-					}
-				case <-timeoutCh:
-					reevaluate = true
-				case <-ctx.Done():
-					if timer != nil {
-						timer.Stop()
-					}
-					resCh <- EvalResult{Val: out, EvalDetails: det, Err: ctx.Err()}
-					return
-				}
-			}
-			if timer != nil {
-				timer.Stop()
-			}
+// evalBatchWorker evaluates inputs claimed from the shared `next` index until none remain,
+// reusing a single execution frame across all synchronous evaluations.
+func (p *prog) evalBatchWorker(ctx context.Context, inputs []any, results []EvalResult,
+	next *atomic.Int64, budget *interpreter.CostBudget) {
+	var frame *interpreter.ExecutionFrame
+	defer func() {
+		if frame != nil {
+			frame.Close()
 		}
 	}()
+	for {
+		i := int(next.Add(1) - 1)
+		if i >= len(inputs) {
+			return
+		}
+		if err := ctx.Err(); err != nil {
+			results[i] = EvalResult{Err: err}
+			continue
+		}
+		if budget != nil && budget.Exceeded() {
+			results[i] = EvalResult{Err: errBatchCostBudgetExceeded}
+			continue
+		}
+		if p.hasAsync {
+			results[i] = p.concurrentEval(ctx, inputs[i], budget)
+			continue
+		}
+		var err error
+		if frame == nil {
+			frame, err = p.newBatchFrame(ctx, inputs[i], budget)
+		} else {
+			err = p.resetExecutionFrame(frame, inputs[i])
+		}
+		if err != nil {
+			results[i] = EvalResult{Err: err}
+			continue
+		}
+		out, det, err := p.Eval(frame)
+		if err != nil && errors.Is(err, interpreter.InterruptError{}) {
+			err = fmt.Errorf("%w: %w", err, context.Cause(ctx))
+		}
+		// The cost tracker is reset for the next input, so record the cost observed for this one.
+		det.snapshotCost()
+		results[i] = EvalResult{Val: out, EvalDetails: det, Err: err}
+	}
+}
 
-	return resCh
+// newBatchFrame creates an ExecutionFrame which is reused for successive inputs within EvalBatch.
+func (p *prog) newBatchFrame(ctx context.Context, input any, budget *interpreter.CostBudget) (*interpreter.ExecutionFrame, error) {
+	frame, err := p.newExecutionFrame(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := frame.SetContext(ctx, p.interruptCheckFrequency); err != nil {
+		frame.Close()
+		return nil, err
+	}
+	if budget != nil {
+		if err := p.setBudgetTracker(frame, budget); err != nil {
+			frame.Close()
+			return nil, err
+		}
+	}
+	return frame, nil
+}
+
+// resetExecutionFrame rebinds a reusable ExecutionFrame to the next input.
+func (p *prog) resetExecutionFrame(frame *interpreter.ExecutionFrame, input any) error {
+	if err := frame.Reset(input); err != nil {
+		return err
+	}
+	if p.defaultVars != nil {
+		frame.Activation = interpreter.NewHierarchicalActivation(p.defaultVars, frame.Activation)
+	}
+	return nil
+}
+
+// setBudgetTracker configures the frame with a cost tracker which charges the shared budget.
+func (p *prog) setBudgetTracker(frame *interpreter.ExecutionFrame, budget *interpreter.CostBudget) error {
+	if p.costTracker == nil {
+		return errors.New("batch cost limit requires cost tracking")
+	}
+	tracker, err := p.costTracker.Clone()
+	if err != nil {
+		return err
+	}
+	tracker.Budget = budget
+	return frame.SetCostTracker(tracker)
+}
+
+// snapshotCost records the actual cost observed so far and detaches the details from the cost
+// tracker so that the tracker may be reused.
+func (ed *EvalDetails) snapshotCost() {
+	if ed == nil || ed.costTracker == nil {
+		return
+	}
+	cost := ed.costTracker.ActualCost()
+	ed.cost = &cost
+	ed.costTracker = nil
 }
 
 // errAsyncRequiresConcurrentEval is returned by the synchronous entry points (Eval, ContextEval)
 // when the expression contains asynchronous function calls, which only ConcurrentEval can resolve.
 var errAsyncRequiresConcurrentEval = errors.New(
 	"expression contains asynchronous function calls; use ConcurrentEval")
+
+// errBatchCostBudgetExceeded is reported for the EvalBatch inputs which are not evaluated because
+// the shared cost budget set with BatchCostLimit has been exhausted.
+var errBatchCostBudgetExceeded = errors.New("operation cancelled: shared cost budget exceeded")
@@ -0,0 +1,272 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cel_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+func TestEvalBatch(t *testing.T) {
+	cases := []struct {
+		name     string
+		expr     string
+		progOpts []cel.ProgramOption
+	}{
+		{
+			name: "sequential",
+			expr: `x * 2 + y.size()`,
+		},
+		{
+			name:     "concurrent",
+			expr:     `x * 2 + y.size()`,
+			progOpts: []cel.ProgramOption{cel.BatchConcurrency(4)},
+		},
+		{
+			name:     "comprehension",
+			expr:     `[1, 2, 3].map(i, i + x).filter(i, i > x)[0] - x + y.size()`,
+			progOpts: []cel.ProgramOption{cel.BatchConcurrency(3)},
+		},
+		{
+			name: "track_state_and_cost",
+			expr: `x * 2 + y.size()`,
+			progOpts: []cel.ProgramOption{
+				cel.BatchConcurrency(2),
+				cel.EvalOptions(cel.OptTrackState, cel.OptTrackCost),
+			},
+		},
+		{
+			name: "globals",
+			expr: `x * 2 + y.size() + z`,
+			progOpts: []cel.ProgramOption{
+				cel.Globals(map[string]any{"z": 0}),
+				cel.BatchConcurrency(2),
+			},
+		},
+	}
+	for _, tst := range cases {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := cel.NewEnv(
+				cel.Variable("x", cel.IntType),
+				cel.Variable("y", cel.StringType),
+				cel.Variable("z", cel.IntType),
+			)
+			if err != nil {
+				t.Fatalf("cel.NewEnv() failed: %v", err)
+			}
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast, tc.progOpts...)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			inputs := make([]any, 100)
+			for i := range inputs {
+				inputs[i] = map[string]any{"x": i, "y": strings.Repeat("a", i%7)}
+			}
+			results := prg.EvalBatch(context.Background(), inputs)
+			if len(results) != len(inputs) {
+				t.Fatalf("EvalBatch() returned %d results, wanted %d", len(results), len(inputs))
+			}
+			for i, res := range results {
+				want, det, err := prg.Eval(inputs[i])
+				if err != nil {
+					t.Fatalf("prg.Eval(inputs[%d]) failed: %v", i, err)
+				}
+				if res.Err != nil {
+					t.Fatalf("EvalBatch() result %d failed: %v", i, res.Err)
+				}
+				if res.Val.Equal(want) != types.True {
+					t.Errorf("EvalBatch() result %d got %v, wanted %v", i, res.Val, want)
+				}
+				if det.ActualCost() != nil {
+					if res.EvalDetails.ActualCost() == nil || *res.EvalDetails.ActualCost() != *det.ActualCost() {
+						t.Errorf("EvalBatch() result %d got cost %v, wanted %d",
+							i, res.EvalDetails.ActualCost(), *det.ActualCost())
+					}
+				}
+				if det != nil && len(det.State().IDs()) != 0 {
+					if len(res.EvalDetails.State().IDs()) != len(det.State().IDs()) {
+						t.Errorf("EvalBatch() result %d got state ids %v, wanted %v",
+							i, res.EvalDetails.State().IDs(), det.State().IDs())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEvalBatchErrors(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("x", cel.IntType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	ast, iss := env.Compile(`10 / x`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	results := prg.EvalBatch(context.Background(), []any{
+		map[string]any{"x": 2},
+		map[string]any{"x": 0},
+		"bad input",
+		map[string]any{"x": 5},
+	})
+	if results[0].Err != nil || results[0].Val != types.Int(5) {
+		t.Errorf("results[0] got %v, %v, wanted 5", results[0].Val, results[0].Err)
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "division by zero") {
+		t.Errorf("results[1] got error %v, wanted division by zero", results[1].Err)
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "invalid input") {
+		t.Errorf("results[2] got error %v, wanted invalid input", results[2].Err)
+	}
+	if results[3].Err != nil || results[3].Val != types.Int(2) {
+		t.Errorf("results[3] got %v, %v, wanted 2", results[3].Val, results[3].Err)
+	}
+
+	//nolint:staticcheck
+	results = prg.EvalBatch(nil, []any{map[string]any{"x": 2}})
+	if results[0].Err == nil {
+		t.Error("EvalBatch(nil, ...) succeeded, wanted error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = prg.EvalBatch(ctx, []any{map[string]any{"x": 2}, map[string]any{"x": 1}})
+	for i, res := range results {
+		if res.Err != context.Canceled {
+			t.Errorf("results[%d] got error %v, wanted %v", i, res.Err, context.Canceled)
+		}
+	}
+}
+
+func TestEvalBatchCostLimit(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("x", cel.ListType(cel.IntType)))
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	ast, iss := env.Compile(`x.all(i, i > 0)`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	inputs := make([]any, 50)
+	for i := range inputs {
+		inputs[i] = map[string]any{"x": []int{1, 2, 3, 4, 5}}
+	}
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers_%d", workers), func(t *testing.T) {
+			prg, err := env.Program(ast, cel.BatchConcurrency(workers), cel.BatchCostLimit(200))
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			results := prg.EvalBatch(context.Background(), inputs)
+			var succeeded, failed int
+			var total uint64
+			for _, res := range results {
+				if res.Err != nil {
+					if !strings.Contains(res.Err.Error(), "cost budget exceeded") {
+						t.Errorf("EvalBatch() got error %v, wanted cost budget exceeded", res.Err)
+					}
+					failed++
+					continue
+				}
+				succeeded++
+				total += *res.EvalDetails.ActualCost()
+			}
+			if succeeded == 0 || failed == 0 {
+				t.Errorf("EvalBatch() got %d successes and %d failures, wanted both", succeeded, failed)
+			}
+			if total > 200 {
+				t.Errorf("EvalBatch() successful evaluations cost %d, wanted at most 200", total)
+			}
+		})
+	}
+}
+
+func TestEvalBatchAsync(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("x", cel.IntType),
+		cel.Function("async_double",
+			cel.Overload("async_double_int", []*cel.Type{cel.IntType}, cel.IntType,
+				cel.AsyncBinding(func(ctx context.Context, args ...ref.Val) ref.Val {
+					time.Sleep(time.Millisecond)
+					return args[0].(types.Int) * 2
+				}),
+			),
+		),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	ast, iss := env.Compile(`async_double(x) + 1`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast, cel.BatchConcurrency(4))
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	inputs := make([]any, 20)
+	for i := range inputs {
+		inputs[i] = map[string]any{"x": i}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i, res := range prg.EvalBatch(ctx, inputs) {
+		if res.Err != nil {
+			t.Fatalf("EvalBatch() result %d failed: %v", i, res.Err)
+		}
+		if res.Val != types.Int(i*2+1) {
+			t.Errorf("EvalBatch() result %d got %v, wanted %d", i, res.Val, i*2+1)
+		}
+	}
+}
+
+func BenchmarkEvalBatch(b *testing.B) {
+	env, err := cel.NewEnv(cel.Variable("x", cel.IntType), cel.Variable("y", cel.StringType))
+	if err != nil {
+		b.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	ast, iss := env.Compile(`x > 10 && y.startsWith('a')`)
+	if iss.Err() != nil {
+		b.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast, cel.EvalOptions(cel.OptOptimize))
+	if err != nil {
+		b.Fatalf("env.Program() failed: %v", err)
+	}
+	inputs := make([]any, 1000)
+	for i := range inputs {
+		inputs[i] = map[string]any{"x": i, "y": "abc"}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prg.EvalBatch(context.Background(), inputs)
+	}
+}
@@ -34,6 +34,7 @@ const (
 	nestingLimitValidatorName          = "cel.validator.comprehension_nesting_limit"
 	bindNestingLimitValidatorName      = "cel.validator.bind_nesting_limit"
 	regexProgramSizeLimitValidatorName = "cel.validator.regex_program_size_limit"
+	fieldAccessValidatorName           = "cel.validator.field_access"
 
 	// HomogeneousAggregateLiteralExemptFunctions is the ValidatorConfig key used to configure
 	// the set of function names which are exempt from homogeneous type checks. The expected type
@@ -68,6 +69,17 @@ var (
 			}
 			return ValidateRegexProgramSizeLimit(limit), nil
 		},
+		fieldAccessValidatorName: func(val *env.Validator) (ASTValidator, error) {
+			fields, err := validatorStringListConfig(val, "fields")
+			if err != nil {
+				return nil, err
+			}
+			v := ValidateFieldAccess(fields...).(fieldAccessValidator)
+			if v.err != nil {
+				return nil, fmt.Errorf("invalid validator: %s, %w", val.Name, v.err)
+			}
+			return v, nil
+		},
 		durationValidatorName: func(*env.Validator) (ASTValidator, error) {
 			return ValidateDurationLiterals(), nil
 		},
@@ -615,3 +627,25 @@ func validatorIntConfig(val *env.Validator, configKey string) (int, error) {
 	}
 	return 0, fmt.Errorf("invalid validator: %s missing %s", val.Name, configKey)
 }
+
+func validatorStringListConfig(val *env.Validator, configKey string) ([]string, error) {
+	list, found := val.ConfigValue(configKey)
+	if !found {
+		return nil, fmt.Errorf("invalid validator: %s missing %s", val.Name, configKey)
+	}
+	switch list := list.(type) {
+	case []string:
+		return list, nil
+	case []any:
+		strs := make([]string, len(list))
+		for i, elem := range list {
+			str, isStr := elem.(string)
+			if !isStr {
+				return nil, fmt.Errorf("invalid validator: %s unsupported %s element type: %v", val.Name, configKey, elem)
+			}
+			strs[i] = str
+		}
+		return strs, nil
+	}
+	return nil, fmt.Errorf("invalid validator: %s unsupported %s type: %v", val.Name, configKey, list)
+}
@@ -38,6 +38,13 @@ type checker struct {
 	errors             *typeErrors
 	mappings           *mapping
 	freeTypeVarCounter int
+	maxID              int64
+	// narrowed contains the narrowed types of variables and field selections within the
+	// current branch when flow-sensitive typing is enabled.
+	narrowed narrowings
+	// narrowedOptionals maps the ids of optional expressions narrowed to their value type, which
+	// are only unwrapped when an overload does not resolve with the optional type.
+	narrowedOptionals map[int64]*types.Type
 }
 
 // Check performs type checking, giving a typed AST.
@@ -95,8 +102,10 @@ func (c *checker) check(e ast.Expr) {
 		}
 	case ast.IdentKind:
 		c.checkIdent(e)
+		c.narrowType(e)
 	case ast.SelectKind:
 		c.checkSelect(e)
+		c.narrowType(e)
 	case ast.CallKind:
 		c.checkCall(e)
 	case ast.ListKind:
@@ -109,6 +118,9 @@ func (c *checker) check(e ast.Expr) {
 		c.checkComprehension(e)
 	default:
 		c.errors.unexpectedASTType(e.ID(), c.location(e), "unspecified", reflect.TypeOf(e).Name())
+		// Partial ASTs produced by error tolerant parsing use unspecified expressions to mark
+		// incomplete input, so assign a type to avoid disrupting the checking of parent nodes.
+		c.setType(e, types.ErrorType)
 	}
 }
 
@@ -210,6 +222,7 @@ func (c *checker) checkOptSelect(e ast.Expr) {
 	resultType := c.checkSelectField(e, operand, fieldName, true)
 	c.setType(e, substitute(c.mappings, resultType, false))
 	c.setReference(e, ast.NewFunctionReference("select_optional_field"))
+	c.narrowType(e)
 }
 
 func (c *checker) checkSelectField(e, operand ast.Expr, field string, optional bool) *types.Type {
@@ -268,9 +281,11 @@ func (c *checker) checkCall(e ast.Expr) {
 	}
 
 	args := call.Args()
-	// Traverse arguments.
-	for _, arg := range args {
-		c.check(arg)
+	// Traverse arguments, narrowing types within guarded branches if enabled.
+	if !c.checkGuardedArgs(call) {
+		for _, arg := range args {
+			c.check(arg)
+		}
 	}
 
 	// Regular static call with simple name.
@@ -286,6 +301,7 @@ func (c *checker) checkCall(e ast.Expr) {
 		e.SetKindCase(c.NewCall(e.ID(), fn.Name(), args...))
 		// Check to see whether the overload resolves.
 		c.resolveOverloadOrError(e, fn, nil, args)
+		c.checkLiteralComparison(e)
 		return
 	}
 
@@ -322,6 +338,37 @@ func (c *checker) checkCall(e ast.Expr) {
 	c.errors.undeclaredReference(e.ID(), c.location(e), c.env.container.Name(), fnName)
 }
 
+// checkLiteralComparison reports comparisons of values with a literal type against constants which
+// are not permitted by the literal type, e.g. `request.method == 'GTE'`.
+func (c *checker) checkLiteralComparison(e ast.Expr) {
+	call := e.AsCall()
+	args := call.Args()
+	if len(args) != 2 {
+		return
+	}
+	switch call.FunctionName() {
+	case operators.Equals, operators.NotEquals:
+		c.checkLiteralValue(args[0], args[1])
+		c.checkLiteralValue(args[1], args[0])
+	case operators.In:
+		if args[1].Kind() == ast.ListKind {
+			for _, elem := range args[1].AsList().Elements() {
+				c.checkLiteralValue(args[0], elem)
+			}
+		}
+	}
+}
+
+func (c *checker) checkLiteralValue(operand, value ast.Expr) {
+	if value.Kind() != ast.LiteralKind {
+		return
+	}
+	t := substitute(c.mappings, c.getType(operand), false)
+	if !literalTypeAccepts(t, value.AsLiteral()) {
+		c.errors.literalValueNotPermitted(value.ID(), c.location(value), value.AsLiteral(), t)
+	}
+}
+
 func (c *checker) resolveOverloadOrError(
 	e ast.Expr, fn *decls.FunctionDecl, target ast.Expr, args []ast.Expr) {
 	// Attempt to resolve the overload.
@@ -351,7 +398,7 @@ func (c *checker) resolveOverload(
 	var checkedRef *ast.ReferenceInfo
 	for _, overload := range fn.OverloadDecls() {
 		// Determine whether the overload is currently considered.
-		if c.env.isOverloadDisabled(overload.ID()) {
+		if c.env.isOverloadDisabled(overload) {
 			continue
 		}
 
@@ -367,7 +414,10 @@ func (c *checker) resolveOverload(
 		if fn.Name() == operators.LogicalAnd || fn.Name() == operators.LogicalOr {
 			checkedRef = ast.NewFunctionReference(overload.ID())
 			for i, argType := range argTypes {
-				if !c.isAssignable(argType, types.BoolType) {
+				// Values narrowed from an optional type are only unwrapped when the optional is not
+				// assignable to a bool.
+				if !c.isAssignable(argType, types.BoolType) &&
+					!(c.unwrapNarrowedOptionals(args[i]) && c.isAssignable(c.getType(args[i]), types.BoolType)) {
 					c.errors.typeMismatch(
 						args[i].ID(),
 						c.locationByID(args[i].ID()),
@@ -412,6 +462,10 @@ func (c *checker) resolveOverload(
 	}
 
 	if resultType == nil {
+		// Retry with the values narrowed from an optional type unwrapped, if any.
+		if c.unwrapNarrowedOptionals(append([]ast.Expr{target}, args...)...) {
+			return c.resolveOverload(call, fn, target, args)
+		}
 		for i, argType := range argTypes {
 			argTypes[i] = substitute(c.mappings, argType, true)
 		}
@@ -554,6 +608,8 @@ func (c *checker) checkComprehension(e ast.Expr) {
 	// Create a scope for the comprehension since it has a local accumulation variable.
 	// This scope will contain the accumulation variable used to compute the result.
 	accuType := c.getType(comp.AccuInit())
+	outerNarrowed := c.narrowed
+	c.removeNarrowings(comp.AccuVar(), comp.IterVar(), comp.IterVar2())
 	c.env = c.env.enterScope()
 	c.env.AddIdents(decls.NewVariable(comp.AccuVar(), accuType))
 
@@ -609,6 +665,7 @@ func (c *checker) checkComprehension(e ast.Expr) {
 	c.check(comp.Result())
 	// Exit the comprehension scope.
 	c.env = c.env.exitScope()
+	c.narrowed = outerNarrowed
 	c.setType(e, substitute(c.mappings, c.getType(comp.Result()), false))
 }
 
@@ -2449,6 +2449,356 @@ _&&_(_==_(list~type(list(dyn))^list,
              | TestAllTypes{singleInt32: 1, single_bool: true}.singleInt32
              | ........................................^`,
 		},
+		{
+			in:      `type(x) == string && x.startsWith('a')`,
+			env:     testEnv{idents: []*decls.VariableDecl{decls.NewVariable("x", types.DynType)}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_&&_(
+		  _==_(
+		    type(
+		      x~dyn^x
+		    )~type(dyn)^type,
+		    string~type(string)^string
+		  )~bool^equals,
+		  x~string^x.startsWith(
+		    "a"~string
+		  )~bool^starts_with_string
+		)~bool^logical_and`,
+		},
+		{
+			in:   `type(x) == string && x + 1 == 2`,
+			env:  testEnv{idents: []*decls.VariableDecl{decls.NewVariable("x", types.DynType)}},
+			opts: []Option{FlowSensitiveTyping(true)},
+			err: `ERROR: <input>:1:24: found no matching overload for '_+_' applied to '(string, int)'
+		 | type(x) == string && x + 1 == 2
+		 | .......................^`,
+		},
+		{
+			in:      `type(x) == string && x + 1 == 2`,
+			env:     testEnv{idents: []*decls.VariableDecl{decls.NewVariable("x", types.DynType)}},
+			outType: types.BoolType,
+		},
+		{
+			in:      `!(int == type(m.x)) || m.x > 1`,
+			env:     testEnv{idents: []*decls.VariableDecl{decls.NewVariable("m", types.NewMapType(types.StringType, types.DynType))}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_||_(
+		  !_(
+		    _==_(
+		      int~type(int)^int,
+		      type(
+		        m~map(string, dyn)^m.x~dyn
+		      )~type(dyn)^type
+		    )~bool^equals
+		  )~bool^logical_not,
+		  _>_(
+		    m~map(string, dyn)^m.x~int,
+		    1~int
+		  )~bool^greater_int64
+		)~bool^logical_or`,
+		},
+		{
+			in:      `type(x) == int ? x + 1 : x.size()`,
+			env:     testEnv{idents: []*decls.VariableDecl{decls.NewVariable("x", types.DynType)}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.IntType,
+			out: `_?_:_(
+		  _==_(
+		    type(
+		      x~dyn^x
+		    )~type(dyn)^type,
+		    int~type(int)^int
+		  )~bool^equals,
+		  _+_(
+		    x~int^x,
+		    1~int
+		  )~int^add_int64,
+		  x~dyn^x.size()~int^bytes_size|list_size|map_size|string_size
+		)~int^conditional`,
+		},
+		{
+			in:      `type(x) == string && [1].exists(x, x > 0)`,
+			env:     testEnv{idents: []*decls.VariableDecl{decls.NewVariable("x", types.DynType)}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_&&_(
+		  _==_(
+		    type(
+		      x~dyn^x
+		    )~type(dyn)^type,
+		    string~type(string)^string
+		  )~bool^equals,
+		  __comprehension__(
+		    // Variable
+		    x,
+		    // Target
+		    [
+		      1~int
+		    ]~list(int),
+		    // Accumulator
+		    @result,
+		    // Init
+		    false~bool,
+		    // LoopCondition
+		    @not_strictly_false(
+		      !_(
+		        @result~bool^@result
+		      )~bool^logical_not
+		    )~bool^not_strictly_false,
+		    // LoopStep
+		    _||_(
+		      @result~bool^@result,
+		      _>_(
+		        x~int^x,
+		        0~int
+		      )~bool^greater_int64
+		    )~bool^logical_or,
+		    // Result
+		    @result~bool^@result)~bool
+		)~bool^logical_and`,
+		},
+		{
+			in:        `has(msg.single_int64_wrapper) ? msg.single_int64_wrapper : 0`,
+			container: "google.expr.proto3.test",
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("msg", types.NewObjectType("google.expr.proto3.test.TestAllTypes")),
+			}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.IntType,
+			out: `_?_:_(
+		  msg~google.expr.proto3.test.TestAllTypes^msg.single_int64_wrapper~test-only~~bool,
+		  msg~google.expr.proto3.test.TestAllTypes^msg.single_int64_wrapper~int,
+		  0~int
+		)~int^conditional`,
+		},
+		{
+			in:        `[msg].all(m, has(m.single_int64_wrapper) && m.single_int64_wrapper > 1)`,
+			container: "google.expr.proto3.test",
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("msg", types.NewObjectType("google.expr.proto3.test.TestAllTypes")),
+			}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `__comprehension__(
+		  // Variable
+		  m,
+		  // Target
+		  [
+		    msg~google.expr.proto3.test.TestAllTypes^msg
+		  ]~list(google.expr.proto3.test.TestAllTypes),
+		  // Accumulator
+		  @result,
+		  // Init
+		  true~bool,
+		  // LoopCondition
+		  @not_strictly_false(
+		    @result~bool^@result
+		  )~bool^not_strictly_false,
+		  // LoopStep
+		  _&&_(
+		    @result~bool^@result,
+		    _&&_(
+		      m~google.expr.proto3.test.TestAllTypes^m.single_int64_wrapper~test-only~~bool,
+		      _>_(
+		        m~google.expr.proto3.test.TestAllTypes^m.single_int64_wrapper~int,
+		        1~int
+		      )~bool^greater_int64
+		    )~bool^logical_and
+		  )~bool^logical_and,
+		  // Result
+		  @result~bool^@result)~bool`,
+		},
+		{
+			in:   `xs.all(x, type(x) == string && x + 1 == 2)`,
+			env:  testEnv{idents: []*decls.VariableDecl{decls.NewVariable("xs", types.NewListType(types.DynType))}},
+			opts: []Option{FlowSensitiveTyping(true)},
+			err: `ERROR: <input>:1:34: found no matching overload for '_+_' applied to '(string, int)'
+		 | xs.all(x, type(x) == string && x + 1 == 2)
+		 | .................................^`,
+		},
+		{
+			in:   `type(y) == string && [1].exists(i, y + i == 2)`,
+			env:  testEnv{idents: []*decls.VariableDecl{decls.NewVariable("y", types.DynType)}},
+			opts: []Option{FlowSensitiveTyping(true)},
+			err: `ERROR: <input>:1:38: found no matching overload for '_+_' applied to '(string, int)'
+		 | type(y) == string && [1].exists(i, y + i == 2)
+		 | .....................................^`,
+		},
+		{
+			in: `o.hasValue() ? o + 1 : o.value()`,
+			env: testEnv{
+				optionalSyntax: true,
+				idents:         []*decls.VariableDecl{decls.NewVariable("o", types.NewOptionalType(types.IntType))},
+				functions:      optionalFunctions(t),
+			},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.IntType,
+			out: `_?_:_(
+		  o~optional_type(int)^o.hasValue()~bool^optional_hasValue,
+		  _+_(
+		    o~optional_type(int)^o.value()~int^optional_value,
+		    1~int
+		  )~int^add_int64,
+		  o~optional_type(int)^o.value()~int^optional_value
+		)~int^conditional`,
+		},
+		{
+			in: `o.hasValue() && [o][0] == o`,
+			env: testEnv{
+				optionalSyntax: true,
+				idents:         []*decls.VariableDecl{decls.NewVariable("o", types.NewOptionalType(types.IntType))},
+				functions:      optionalFunctions(t),
+			},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_&&_(
+		  o~optional_type(int)^o.hasValue()~bool^optional_hasValue,
+		  _==_(
+		    _[_](
+		      [
+		        o~optional_type(int)^o
+		      ]~list(optional_type(int)),
+		      0~int
+		    )~optional_type(int)^index_list,
+		    o~optional_type(int)^o
+		  )~bool^equals
+		)~bool^logical_and`,
+		},
+		{
+			in: `m.?k.hasValue() && m.?k.hasValue() && m.?k.startsWith('a')`,
+			env: testEnv{
+				optionalSyntax: true,
+				idents: []*decls.VariableDecl{
+					decls.NewVariable("m", types.NewMapType(types.StringType, types.StringType)),
+				},
+				functions: optionalFunctions(t),
+			},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_&&_(
+		  _&&_(
+		    _?._(
+		      m~map(string, string)^m,
+		      "k"
+		    )~optional_type(string)^select_optional_field.hasValue()~bool^optional_hasValue,
+		    _?._(
+		      m~map(string, string)^m,
+		      "k"
+		    )~optional_type(string)^select_optional_field.hasValue()~bool^optional_hasValue
+		  )~bool^logical_and,
+		  _?._(
+		    m~map(string, string)^m,
+		    "k"
+		  )~optional_type(string)^select_optional_field.value()~string^optional_value.startsWith(
+		    "a"~string
+		  )~bool^starts_with_string
+		)~bool^logical_and`,
+		},
+		{
+			in: `method == 'GET' || method in ['POST', 'PUT']`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("method", types.NewStringLiteralType("GET", "POST", "PUT")),
+			}},
+			outType: types.BoolType,
+			out: `_||_(
+		  _==_(
+		    method~"GET" | "POST" | "PUT"^method,
+		    "GET"~string
+		  )~bool^equals,
+		  @in(
+		    method~"GET" | "POST" | "PUT"^method,
+		    [
+		      "POST"~string,
+		      "PUT"~string
+		    ]~list(string)
+		  )~bool^in_list
+		)~bool^logical_or`,
+		},
+		{
+			in: `method == 'GTE' || method in ['GET', 'PATCH']`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("method", types.NewStringLiteralType("GET", "POST")),
+			}},
+			err: `
+		ERROR: <input>:1:11: value "GTE" is not permitted by type '"GET" | "POST"'
+		 | method == 'GTE' || method in ['GET', 'PATCH']
+		 | ..........^
+		ERROR: <input>:1:38: value "PATCH" is not permitted by type '"GET" | "POST"'
+		 | method == 'GTE' || method in ['GET', 'PATCH']
+		 | .....................................^`,
+		},
+		{
+			in: `code != 500 && method.startsWith('G')`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("code", types.NewIntLiteralType(200, 404)),
+				decls.NewVariable("method", types.NewStringLiteralType("GET", "POST")),
+			}},
+			err: `
+		ERROR: <input>:1:9: value 500 is not permitted by type '200 | 404'
+		 | code != 500 && method.startsWith('G')
+		 | ........^`,
+		},
+		{
+			in: `size(x)`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("x", types.NewUnionType(types.StringType, types.BytesType)),
+			}},
+			err: `
+		ERROR: <input>:1:5: found no matching overload for 'size' applied to '(string | bytes)'
+		 | size(x)
+		 | ....^`,
+		},
+		{
+			in: `x == 'a' ? [x] : [1]`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("x", types.NewUnionType(types.StringType, types.IntType)),
+			}},
+			outType: types.NewListType(types.NewUnionType(types.StringType, types.IntType)),
+			out: `_?_:_(
+		  _==_(
+		    x~string | int^x,
+		    "a"~string
+		  )~bool^equals,
+		  [
+		    x~string | int^x
+		  ]~list(string | int),
+		  [
+		    1~int
+		  ]~list(int)
+		)~list(string | int)^conditional`,
+		},
+		{
+			in: `type(x) == string && x.startsWith('a')`,
+			env: testEnv{idents: []*decls.VariableDecl{
+				decls.NewVariable("x", types.NewUnionType(types.StringType, types.IntType)),
+			}},
+			opts:    []Option{FlowSensitiveTyping(true)},
+			outType: types.BoolType,
+			out: `_&&_(
+		  _==_(
+		    type(
+		      x~string | int^x
+		    )~type(string | int)^type,
+		    string~type(string)^string
+		  )~bool^equals,
+		  x~string^x.startsWith(
+		    "a"~string
+		  )~bool^starts_with_string
+		)~bool^logical_and`,
+		},
+	}
+}
+
+func optionalFunctions(t testing.TB) []*decls.FunctionDecl {
+	paramT := types.NewTypeParamType("T")
+	optT := types.NewOptionalType(paramT)
+	return []*decls.FunctionDecl{
+		testFunction(t, "hasValue",
+			decls.MemberOverload("optional_hasValue", []*types.Type{optT}, types.BoolType)),
+		testFunction(t, "value",
+			decls.MemberOverload("optional_value", []*types.Type{optT}, paramT)),
 	}
 }
 
@@ -251,6 +251,21 @@ func OverloadCostEstimate(overloadID string, functionCoster FunctionEstimator) C
 	}
 }
 
+// FieldSizeBound provides the maximum size of a struct field, if known.
+type FieldSizeBound func(structType, fieldName string) (uint64, bool)
+
+// FieldSizeBounds configures a source of maximum sizes for struct fields, such as the bounds
+// declared by the schema a struct type was derived from.
+//
+// The bounds are used to estimate the size of a field selection when the CostEstimator provided to
+// the Cost() call has no estimate for it.
+func FieldSizeBounds(bound FieldSizeBound) CostOption {
+	return func(c *coster) error {
+		c.fieldSizeBounds = append(c.fieldSizeBounds, bound)
+		return nil
+	}
+}
+
 // Cost estimates the cost of the parsed and type checked CEL expression.
 func Cost(checked *ast.AST, estimator CostEstimator, opts ...CostOption) (CostEstimate, error) {
 	c := &coster{
@@ -287,6 +302,7 @@ type coster struct {
 	overloadEstimators map[string]FunctionEstimator
 	// presenceTestCost will either be a zero or one based on whether has() macros count against cost computations.
 	presenceTestCost CostEstimate
+	fieldSizeBounds  []FieldSizeBound
 }
 
 // entrySizeEstimate captures the container kind and associated key/index and value SizeEstimate values.
@@ -925,6 +941,9 @@ func (c *coster) computeSize(e ast.Expr) *SizeEstimate {
 		c.computedSizes[e.ID()] = *size
 		return size
 	}
+	if size := c.fieldSizeBound(e); size != nil {
+		return size
+	}
 	if size := computeTypeSize(c.getType(e)); size != nil {
 		return size
 	}
@@ -937,6 +956,25 @@ func (c *coster) computeSize(e ast.Expr) *SizeEstimate {
 	return nil
 }
 
+// fieldSizeBound returns the size estimate of a struct field selection from the configured field
+// size bounds, if any.
+func (c *coster) fieldSizeBound(e ast.Expr) *SizeEstimate {
+	if len(c.fieldSizeBounds) == 0 || e.Kind() != ast.SelectKind || e.AsSelect().IsTestOnly() {
+		return nil
+	}
+	sel := e.AsSelect()
+	operandType := c.getType(sel.Operand())
+	if operandType.Kind() != types.StructKind {
+		return nil
+	}
+	for _, bound := range c.fieldSizeBounds {
+		if maxSize, found := bound(operandType.TypeName(), sel.FieldName()); found {
+			return &SizeEstimate{Min: 0, Max: maxSize}
+		}
+	}
+	return nil
+}
+
 func (c *coster) setEntrySize(e ast.Expr, size *entrySizeEstimate) {
 	if size == nil {
 		return
@@ -74,7 +74,9 @@ type Env struct {
 	declarations        *Scopes
 	aggLitElemType      aggregateLiteralElementType
 	filteredOverloadIDs map[string]struct{}
+	crossTypeNumeric    bool
 	jsonFieldNames      bool
+	flowSensitiveTyping bool
 }
 
 // NewEnv returns a new *Env with the given parameters.
@@ -105,7 +107,9 @@ func NewEnv(container *containers.Container, provider types.Provider, opts ...Op
 		declarations:        declarations,
 		aggLitElemType:      aggLitElemType,
 		filteredOverloadIDs: filteredOverloadIDs,
+		crossTypeNumeric:    envOptions.crossTypeNumericComparisons,
 		jsonFieldNames:      envOptions.jsonFieldNames,
+		flowSensitiveTyping: envOptions.flowSensitiveTyping,
 	}, nil
 }
 
@@ -308,9 +312,12 @@ func (e *Env) addIdent(decl *decls.VariableDecl) errorMsg {
 	return ""
 }
 
-// isOverloadDisabled returns whether the overloadID is disabled in the current environment.
-func (e *Env) isOverloadDisabled(overloadID string) bool {
-	_, found := e.filteredOverloadIDs[overloadID]
+// isOverloadDisabled returns whether the overload is disabled in the current environment.
+func (e *Env) isOverloadDisabled(overload *decls.OverloadDecl) bool {
+	if overload.IsCrossTypeNumericComparison() && !e.crossTypeNumeric {
+		return true
+	}
+	_, found := e.filteredOverloadIDs[overload.ID()]
 	return found
 }
 
@@ -324,10 +331,12 @@ func (e *Env) validatedDeclarations() *Scopes {
 func (e *Env) enterScope() *Env {
 	childDecls := e.declarations.Push()
 	return &Env{
-		declarations:   childDecls,
-		container:      e.container,
-		provider:       e.provider,
-		aggLitElemType: e.aggLitElemType,
+		declarations:        childDecls,
+		container:           e.container,
+		provider:            e.provider,
+		aggLitElemType:      e.aggLitElemType,
+		crossTypeNumeric:    e.crossTypeNumeric,
+		flowSensitiveTyping: e.flowSensitiveTyping,
 	}
 }
 
@@ -335,10 +344,12 @@ func (e *Env) enterScope() *Env {
 func (e *Env) exitScope() *Env {
 	parentDecls := e.declarations.Pop()
 	return &Env{
-		declarations:   parentDecls,
-		container:      e.container,
-		provider:       e.provider,
-		aggLitElemType: e.aggLitElemType,
+		declarations:        parentDecls,
+		container:           e.container,
+		provider:            e.provider,
+		aggLitElemType:      e.aggLitElemType,
+		crossTypeNumeric:    e.crossTypeNumeric,
+		flowSensitiveTyping: e.flowSensitiveTyping,
 	}
 }
 
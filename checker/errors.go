@@ -15,9 +15,13 @@
 package checker
 
 import (
+	"fmt"
+	"strconv"
+
 	"cel.dev/cel-go/common"
 	"cel.dev/cel-go/common/ast"
 	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
 )
 
 // typeErrors is a specialization of Errors.
@@ -35,6 +39,14 @@ func (e *typeErrors) incompatibleType(id int64, l common.Location, ex ast.Expr,
 		"incompatible type already exists for expression: %v(%d) old:%v, new:%v", ex, ex.ID(), prev, next)
 }
 
+func (e *typeErrors) literalValueNotPermitted(id int64, l common.Location, val ref.Val, t *types.Type) {
+	lit := fmt.Sprintf("%v", val.Value())
+	if str, isStr := val.(types.String); isStr {
+		lit = strconv.Quote(string(str))
+	}
+	e.errs.ReportErrorAtID(id, l, "value %s is not permitted by type '%s'", lit, FormatCELType(t))
+}
+
 func (e *typeErrors) noMatchingOverload(id int64, l common.Location, name string, args []*types.Type, isInstance bool) {
 	signature := formatFunctionDeclType(nil, args, isInstance)
 	e.errs.ReportErrorAtID(id, l, "found no matching overload for '%s' applied to '%s'", name, signature)
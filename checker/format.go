@@ -108,6 +108,9 @@ type formatter func(any) string
 // The type formatting is identical to FormatCheckedType.
 func FormatCELType(t any) string {
 	dt := t.(*types.Type)
+	if dt.IsLiteralType() {
+		return dt.String()
+	}
 	switch dt.Kind() {
 	case types.AnyKind:
 		return "any"
@@ -127,6 +130,12 @@ func FormatCELType(t any) string {
 			// whether the function is a member function is absent.
 			return formatFunctionDeclType(dt.Parameters()[0], dt.Parameters()[1:], false)
 		}
+	case types.UnionKind:
+		members := make([]string, len(dt.Parameters()))
+		for i, p := range dt.Parameters() {
+			members[i] = FormatCELType(p)
+		}
+		return strings.Join(members, " | ")
 	case types.UnspecifiedKind:
 		return ""
 	}
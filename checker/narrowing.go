@@ -0,0 +1,347 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/overloads"
+	"cel.dev/cel-go/common/types"
+)
+
+const (
+	hasValueFunc = "hasValue"
+	valueFunc    = "value"
+)
+
+// narrowings maps the path of a variable or field selection, e.g. `a.b.c`, to the type it is known
+// to have within a branch of a logical or conditional operator.
+type narrowings map[string]*types.Type
+
+// checkGuardedArgs type-checks the arguments of a logical or conditional operator, narrowing the
+// types of variables and field selections within each argument based on the outcome of the
+// arguments which must have been evaluated before it:
+//
+//	type(x) == string && x.startsWith('a')   // x is a string on the right-hand side
+//	type(x) != string || x.startsWith('a')   // x is a string on the right-hand side
+//	has(m.f) ? m.f + 1 : 0                   // m.f is non-null in the truthy branch
+//	o.hasValue() ? o + 1 : 0                 // o is unwrapped from its optional in the truthy branch
+//
+// Returns false if the call is not a logical or conditional operator.
+func (c *checker) checkGuardedArgs(call ast.CallExpr) bool {
+	if !c.env.flowSensitiveTyping || call.IsMemberFunction() {
+		return false
+	}
+	args := call.Args()
+	switch call.FunctionName() {
+	case operators.LogicalAnd, operators.LogicalOr:
+		// The right-hand side of a && is only relevant when the left-hand side is true, and the
+		// right-hand side of a || only when the left-hand side is false.
+		outcome := call.FunctionName() == operators.LogicalAnd
+		outer := c.narrowed
+		for _, arg := range args {
+			c.check(arg)
+			c.narrowed = c.narrowed.with(c.guardNarrowings(arg, outcome))
+		}
+		c.narrowed = outer
+		return true
+	case operators.Conditional:
+		if len(args) != 3 {
+			return false
+		}
+		outer := c.narrowed
+		c.check(args[0])
+		c.narrowed = outer.with(c.guardNarrowings(args[0], true))
+		c.check(args[1])
+		c.narrowed = outer.with(c.guardNarrowings(args[0], false))
+		c.check(args[2])
+		c.narrowed = outer
+		return true
+	}
+	return false
+}
+
+// guardNarrowings returns the narrowed types which hold when the type-checked guard expression
+// evaluates to the given outcome.
+func (c *checker) guardNarrowings(guard ast.Expr, outcome bool) narrowings {
+	switch guard.Kind() {
+	case ast.SelectKind:
+		sel := guard.AsSelect()
+		if !sel.IsTestOnly() || !outcome {
+			return nil
+		}
+		// A presence test on a nullable field, e.g. a wrapper type, implies the field is non-null.
+		path, ok := narrowingPath(sel.Operand())
+		if !ok {
+			return nil
+		}
+		operandType := substitute(c.mappings, c.getType(sel.Operand()), false)
+		if operandType.Kind() != types.StructKind {
+			return nil
+		}
+		ft, found := c.env.provider.FindStructFieldType(operandType.TypeName(), sel.FieldName())
+		if !found {
+			return nil
+		}
+		if t, isNullable := maybeUnwrapNullable(ft.Type); isNullable {
+			return narrowings{path + "." + sel.FieldName(): t}
+		}
+	case ast.CallKind:
+		call := guard.AsCall()
+		args := call.Args()
+		switch call.FunctionName() {
+		case operators.LogicalNot:
+			if len(args) == 1 {
+				return c.guardNarrowings(args[0], !outcome)
+			}
+		case operators.LogicalAnd, operators.LogicalOr:
+			// All arguments of a && are true when it evaluates to true, and all arguments of a ||
+			// are false when it evaluates to false.
+			if outcome != (call.FunctionName() == operators.LogicalAnd) {
+				return nil
+			}
+			var n narrowings
+			for _, arg := range args {
+				n = n.with(c.guardNarrowings(arg, outcome))
+			}
+			return n
+		case operators.Equals, operators.NotEquals:
+			if len(args) != 2 || outcome != (call.FunctionName() == operators.Equals) {
+				return nil
+			}
+			if n := c.typeTestNarrowings(args[0], args[1]); n != nil {
+				return n
+			}
+			return c.typeTestNarrowings(args[1], args[0])
+		case hasValueFunc:
+			if !outcome || !call.IsMemberFunction() || len(args) != 0 {
+				return nil
+			}
+			path, ok := narrowingPath(call.Target())
+			if !ok || c.env.lookupFunction(valueFunc) == nil {
+				return nil
+			}
+			if t, isOpt := maybeUnwrapOptional(substitute(c.mappings, c.getType(call.Target()), false)); isOpt {
+				return narrowings{path: t}
+			}
+		}
+	}
+	return nil
+}
+
+// typeTestNarrowings returns the narrowed type of a `type(x) == T` comparison of a dynamically
+// typed value or a value with a union type.
+func (c *checker) typeTestNarrowings(typeCall, typeExpr ast.Expr) narrowings {
+	if typeCall.Kind() != ast.CallKind {
+		return nil
+	}
+	call := typeCall.AsCall()
+	if call.FunctionName() != overloads.TypeConvertType || call.IsMemberFunction() || len(call.Args()) != 1 {
+		return nil
+	}
+	path, ok := narrowingPath(call.Args()[0])
+	if !ok {
+		return nil
+	}
+	current := substitute(c.mappings, c.getType(call.Args()[0]), false)
+	if !isDyn(current) && current.Kind() != types.UnionKind {
+		return nil
+	}
+	typeType := substitute(c.mappings, c.getType(typeExpr), false)
+	if typeType.Kind() != types.TypeKind || len(typeType.Parameters()) != 1 {
+		return nil
+	}
+	t := substitute(newMapping(), typeType.Parameters()[0], true)
+	if isDynOrError(t) {
+		return nil
+	}
+	if current.Kind() == types.UnionKind {
+		// Narrow to the union members with the same runtime type, e.g. string | int to string.
+		var members []*types.Type
+		for _, m := range current.Parameters() {
+			if m.TypeName() == t.TypeName() {
+				members = append(members, m)
+			}
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		t = types.NewUnionType(members...)
+	}
+	return narrowings{path: t}
+}
+
+// narrowType updates the type of a type-checked variable or field selection if it has been
+// narrowed within the current branch.
+//
+// Values narrowed from an optional type keep the optional type, since they remain valid arguments
+// to functions such as `type()`, equality, and the optional functions, as well as elements of list
+// and map literals. They are only unwrapped when an overload fails to resolve with the optional
+// type, see unwrapNarrowedOptionals.
+func (c *checker) narrowType(e ast.Expr) {
+	if len(c.narrowed) == 0 {
+		return
+	}
+	path, ok := narrowingPath(e)
+	if !ok {
+		return
+	}
+	t, found := c.narrowed[path]
+	if !found {
+		return
+	}
+	current := substitute(c.mappings, c.getType(e), false)
+	if !isOptional(current) || isOptional(t) {
+		c.SetType(e.ID(), t)
+		return
+	}
+	if c.narrowedOptionals == nil {
+		c.narrowedOptionals = map[int64]*types.Type{}
+	}
+	c.narrowedOptionals[e.ID()] = t
+}
+
+// unwrapNarrowedOptionals rewrites the optional expressions among the input which have been
+// narrowed to their value type into a call to `value()`, so that the runtime value agrees with the
+// narrowed type. Returns whether any expression was rewritten.
+func (c *checker) unwrapNarrowedOptionals(exprs ...ast.Expr) bool {
+	unwrapped := false
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		t, found := c.narrowedOptionals[e.ID()]
+		if !found {
+			continue
+		}
+		delete(c.narrowedOptionals, e.ID())
+		// Move the optional expression to a new node which becomes the target of a value() call.
+		target := c.NewUnspecifiedExpr(c.nextID())
+		target.SetKindCase(e)
+		c.SetType(target.ID(), substitute(c.mappings, c.getType(e), false))
+		if ref, found := c.ReferenceMap()[e.ID()]; found {
+			c.SetReference(target.ID(), ref)
+			delete(c.ReferenceMap(), e.ID())
+		}
+		if r, found := c.SourceInfo().GetOffsetRange(e.ID()); found {
+			c.SourceInfo().SetOffsetRange(target.ID(), r)
+		}
+		e.SetKindCase(c.NewMemberCall(e.ID(), valueFunc, target))
+		c.SetType(e.ID(), t)
+		c.SetReference(e.ID(), ast.NewFunctionReference("optional_value"))
+		unwrapped = true
+	}
+	return unwrapped
+}
+
+// removeNarrowings drops the narrowed types of paths rooted at the given variable names, used when
+// the names are shadowed by comprehension variables.
+func (c *checker) removeNarrowings(names ...string) {
+	if len(c.narrowed) == 0 {
+		return
+	}
+	n := narrowings{}
+	for path, t := range c.narrowed {
+		if !shadowsPath(path, names) {
+			n[path] = t
+		}
+	}
+	c.narrowed = n
+}
+
+func (c *checker) nextID() int64 {
+	if c.maxID == 0 {
+		c.maxID = ast.MaxID(c.AST)
+	}
+	id := c.maxID
+	c.maxID++
+	return id
+}
+
+// with returns a copy of the narrowings including the entries from other, preferring the existing
+// entries when both contain the same path.
+func (n narrowings) with(other narrowings) narrowings {
+	if len(other) == 0 {
+		return n
+	}
+	out := make(narrowings, len(n)+len(other))
+	for path, t := range other {
+		out[path] = t
+	}
+	for path, t := range n {
+		out[path] = t
+	}
+	return out
+}
+
+// narrowingPath returns the dotted path of a type-checked variable reference or field selection.
+func narrowingPath(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		if sel.IsTestOnly() {
+			return "", false
+		}
+		path, ok := narrowingPath(sel.Operand())
+		if !ok {
+			return "", false
+		}
+		return path + "." + sel.FieldName(), true
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.OptSelect || len(call.Args()) != 2 {
+			return "", false
+		}
+		field, isStr := maybeUnwrapString(call.Args()[1])
+		if !isStr {
+			return "", false
+		}
+		path, ok := narrowingPath(call.Args()[0])
+		if !ok {
+			return "", false
+		}
+		return path + ".?" + field, true
+	}
+	return "", false
+}
+
+func shadowsPath(path string, names []string) bool {
+	for _, name := range names {
+		if name != "" && (path == name || len(path) > len(name) && path[:len(name)] == name && path[len(name)] == '.') {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeUnwrapNullable returns the primitive type of a nullable wrapper type.
+func maybeUnwrapNullable(t *types.Type) (*types.Type, bool) {
+	prim, found := primitiveTypes[t.Kind()]
+	if !found || !t.IsAssignableType(types.NullType) {
+		return t, false
+	}
+	return prim, true
+}
+
+var primitiveTypes = map[types.Kind]*types.Type{
+	types.BoolKind:   types.BoolType,
+	types.BytesKind:  types.BytesType,
+	types.DoubleKind: types.DoubleType,
+	types.IntKind:    types.IntType,
+	types.StringKind: types.StringType,
+	types.UintKind:   types.UintType,
+}
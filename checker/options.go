@@ -19,6 +19,7 @@ type options struct {
 	homogeneousAggregateLiterals bool
 	validatedDeclarations        *Scopes
 	jsonFieldNames               bool
+	flowSensitiveTyping          bool
 }
 
 // Option is a functional option for configuring the type-checker
@@ -50,3 +51,11 @@ func JSONFieldNames(enabled bool) Option {
 	}
 }
 
+// FlowSensitiveTyping enables the narrowing of types within the branches of logical and conditional
+// operators guarded by type tests, presence tests, and optional value tests.
+func FlowSensitiveTyping(enabled bool) Option {
+	return func(opts *options) error {
+		opts.flowSensitiveTyping = enabled
+		return nil
+	}
+}
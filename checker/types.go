@@ -16,6 +16,7 @@ package checker
 
 import (
 	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
 )
 
 // isDyn returns true if the input t is either type DYN or a well-known ANY message.
@@ -65,6 +66,17 @@ func isEqualOrLessSpecific(t1, t2 *types.Type) bool {
 	if isDyn(t2) || kind2 == types.TypeParamKind {
 		return false
 	}
+	// A union is less specific than its members and the unions it contains.
+	if kind1 == types.UnionKind {
+		return t1.IsAssignableType(t2)
+	}
+	if kind2 == types.UnionKind {
+		return false
+	}
+	// A base type is less specific than its literal types, e.g. string and 'GET' | 'POST'.
+	if t1.IsLiteralType() || t2.IsLiteralType() {
+		return t1.IsAssignableType(t2) && (!t1.IsLiteralType() || t2.IsLiteralType())
+	}
 	// Types must be of the same kind to be equal.
 	if kind1 != kind2 {
 		return false
@@ -132,6 +144,19 @@ func internalIsAssignable(m *mapping, t1, t2 *types.Type) bool {
 		return internalIsAssignableNull(t1)
 	}
 
+	// Union types are assignable from any of their member types.
+	if kind2 == types.UnionKind {
+		return internalIsAssignableUnion(m, t1, t2)
+	}
+	if kind1 == types.UnionKind {
+		for _, member := range t1.Parameters() {
+			if !internalIsAssignable(m, member, t2) {
+				return false
+			}
+		}
+		return true
+	}
+
 	// Test for when the types do not need to agree, but are more specific than dyn.
 	switch kind1 {
 	case types.BoolKind, types.BytesKind, types.DoubleKind, types.IntKind, types.StringKind, types.UintKind,
@@ -152,6 +177,27 @@ func internalIsAssignable(m *mapping, t1, t2 *types.Type) bool {
 	}
 }
 
+// internalIsAssignableUnion returns true if t1, or each member of t1 if it is a union, is assignable
+// to at least one member of the union type t2.
+func internalIsAssignableUnion(m *mapping, t1, t2 *types.Type) bool {
+	if t1.Kind() == types.UnionKind {
+		for _, member := range t1.Parameters() {
+			if !internalIsAssignableUnion(m, member, t2) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, member := range t2.Parameters() {
+		mCopy := m.copy()
+		if internalIsAssignable(mCopy, t1, member) {
+			m.mapping = mCopy.mapping
+			return true
+		}
+	}
+	return false
+}
+
 // isValidTypeSubstitution returns whether t2 (or its type substitution) is a valid type
 // substitution for t1, and whether t2 has a type substitution in mapping m.
 //
@@ -260,7 +306,7 @@ func notReferencedIn(m *mapping, t, withinType *types.Type) bool {
 			return true
 		}
 		return notReferencedIn(m, t, wtSub)
-	case types.OpaqueKind, types.ListKind, types.MapKind, types.TypeKind:
+	case types.OpaqueKind, types.ListKind, types.MapKind, types.TypeKind, types.UnionKind:
 		for _, pt := range withinType.Parameters() {
 			if !notReferencedIn(m, t, pt) {
 				return false
@@ -296,6 +342,8 @@ func substitute(m *mapping, t *types.Type, typeParamToDyn bool) *types.Type {
 			return types.NewTypeTypeWithParam(substitute(m, tParam, typeParamToDyn))
 		}
 		return t
+	case types.UnionKind:
+		return types.NewUnionType(substituteParams(m, t.Parameters(), typeParamToDyn)...)
 	default:
 		return t
 	}
@@ -312,3 +360,29 @@ func substituteParams(m *mapping, typeParams []*types.Type, typeParamToDyn bool)
 func newFunctionType(resultType *types.Type, argTypes ...*types.Type) *types.Type {
 	return types.NewOpaqueType("function", append([]*types.Type{resultType}, argTypes...)...)
 }
+
+// literalTypeAccepts returns whether a constant value is permitted by a literal type, or by a union
+// containing literal types. Values are only checked against members of the same kind as the value.
+func literalTypeAccepts(t *types.Type, val ref.Val) bool {
+	members := []*types.Type{t}
+	if t.Kind() == types.UnionKind {
+		members = t.Parameters()
+	}
+	valKind := val.Type().(*types.Type).Kind()
+	checked := false
+	for _, member := range members {
+		if member.Kind() != valKind {
+			continue
+		}
+		if !member.IsLiteralType() {
+			return true
+		}
+		checked = true
+		for _, lit := range member.LiteralValues() {
+			if lit.Equal(val) == types.True {
+				return true
+			}
+		}
+	}
+	return !checked
+}
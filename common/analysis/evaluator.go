@@ -0,0 +1,375 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// evaluator computes the value of an expression for a given assignment of its variables using
+// three-valued logic, where a nil value indicates an unknown outcome.
+type evaluator struct {
+	a *ast.AST
+	// vars maps the ids of variable references to their paths.
+	vars map[int64]string
+	// domains holds the domain of each variable referenced by the expression.
+	domains map[string]*domain
+
+	assign    Assignment
+	blocks    map[string]ast.Expr
+	memo      map[string]ref.Val
+	residuals map[int64]ast.Expr
+}
+
+func newEvaluator(v *Verifier, a *ast.AST) *evaluator {
+	e := &evaluator{
+		a:       a,
+		vars:    make(map[int64]string),
+		domains: make(map[string]*domain),
+	}
+	e.discover(v, a.Expr())
+	return e
+}
+
+// discover records the outermost variable references with a supported domain.
+func (e *evaluator) discover(v *Verifier, expr ast.Expr) {
+	if path, found := e.path(expr); found {
+		d, declared := v.domains[path]
+		if !declared {
+			d = inferDomain(e.a.GetType(expr.ID()))
+		}
+		if d != nil {
+			e.vars[expr.ID()] = path
+			e.domains[path] = d
+			return
+		}
+	}
+	switch expr.Kind() {
+	case ast.CallKind:
+		call := expr.AsCall()
+		if call.IsMemberFunction() {
+			e.discover(v, call.Target())
+		}
+		for _, arg := range call.Args() {
+			e.discover(v, arg)
+		}
+	case ast.ListKind:
+		for _, elem := range expr.AsList().Elements() {
+			e.discover(v, elem)
+		}
+	case ast.SelectKind:
+		e.discover(v, expr.AsSelect().Operand())
+	}
+}
+
+// path returns the qualified name of an identifier or field selection chain.
+func (e *evaluator) path(expr ast.Expr) (string, bool) {
+	// Block index variables, such as those produced by policy composition, are local bindings.
+	if expr.Kind() == ast.IdentKind && strings.HasPrefix(expr.AsIdent(), "@") {
+		return "", false
+	}
+	if r, found := e.a.ReferenceMap()[expr.ID()]; found {
+		if r.Value != nil || r.Name == "" {
+			return "", false
+		}
+		return r.Name, true
+	}
+	switch expr.Kind() {
+	case ast.IdentKind:
+		return expr.AsIdent(), true
+	case ast.SelectKind:
+		sel := expr.AsSelect()
+		if sel.IsTestOnly() {
+			return "", false
+		}
+		if operand, found := e.path(sel.Operand()); found {
+			return operand + "." + sel.FieldName(), true
+		}
+	}
+	return "", false
+}
+
+func inferDomain(t *types.Type) *domain {
+	if t == nil {
+		return nil
+	}
+	if t.IsLiteralType() {
+		return &domain{values: t.LiteralValues(), exact: true}
+	}
+	switch t.Kind() {
+	case types.BoolKind:
+		return &domain{values: []ref.Val{types.False, types.True}, exact: true}
+	case types.StringKind:
+		return &domain{open: true}
+	}
+	return nil
+}
+
+func (e *evaluator) evaluate(assign Assignment) ref.Val {
+	e.assign = assign
+	e.blocks = make(map[string]ast.Expr)
+	e.memo = make(map[string]ref.Val)
+	e.residuals = make(map[int64]ast.Expr)
+	return e.eval(e.a.Expr())
+}
+
+func (e *evaluator) unsupported(expr ast.Expr) ref.Val {
+	e.residuals[expr.ID()] = expr
+	return nil
+}
+
+func (e *evaluator) eval(expr ast.Expr) ref.Val {
+	if path, found := e.vars[expr.ID()]; found {
+		return e.assign[path]
+	}
+	switch expr.Kind() {
+	case ast.LiteralKind:
+		switch lit := expr.AsLiteral().(type) {
+		case types.Bool, types.Int, types.String:
+			return lit
+		}
+	case ast.IdentKind:
+		name := expr.AsIdent()
+		if r, found := e.a.ReferenceMap()[expr.ID()]; found && r.Value != nil {
+			if val, ok := r.Value.(types.Int); ok {
+				return val
+			}
+			return e.unsupported(expr)
+		}
+		if bound, found := e.blocks[name]; found {
+			if val, memoized := e.memo[name]; memoized {
+				return val
+			}
+			val := e.eval(bound)
+			e.memo[name] = val
+			return val
+		}
+	case ast.CallKind:
+		return e.evalCall(expr)
+	}
+	return e.unsupported(expr)
+}
+
+func (e *evaluator) evalCall(expr ast.Expr) ref.Val {
+	call := expr.AsCall()
+	args := call.Args()
+	if call.IsMemberFunction() {
+		return e.unsupported(expr)
+	}
+	switch call.FunctionName() {
+	case operators.LogicalAnd:
+		return and(e.eval(args[0]), e.eval(args[1]))
+	case operators.LogicalOr:
+		return or(e.eval(args[0]), e.eval(args[1]))
+	case operators.LogicalNot:
+		if b, ok := e.eval(args[0]).(types.Bool); ok {
+			return !b
+		}
+		return nil
+	case operators.NotStrictlyFalse:
+		return e.eval(args[0])
+	case operators.Conditional:
+		cond := e.eval(args[0])
+		if cond == types.True {
+			return e.eval(args[1])
+		}
+		if cond == types.False {
+			return e.eval(args[2])
+		}
+		t, f := e.eval(args[1]), e.eval(args[2])
+		if t != nil && f != nil && t.Type() == f.Type() && t.Equal(f) == types.True {
+			return t
+		}
+		return nil
+	case operators.Equals, operators.NotEquals:
+		if e.isOpenComparison(args[0], args[1]) {
+			return e.unsupported(expr)
+		}
+		lhs, rhs := e.eval(args[0]), e.eval(args[1])
+		if lhs == nil || rhs == nil {
+			return nil
+		}
+		eq := lhs.Type() == rhs.Type() && lhs.Equal(rhs) == types.True
+		return types.Bool(eq == (call.FunctionName() == operators.Equals))
+	case operators.Less, operators.LessEquals, operators.Greater, operators.GreaterEquals:
+		lhs, lok := e.eval(args[0]).(types.Int)
+		rhs, rok := e.eval(args[1]).(types.Int)
+		if !lok || !rok {
+			return e.unknownUnlessInt(expr, args...)
+		}
+		switch call.FunctionName() {
+		case operators.Less:
+			return types.Bool(lhs < rhs)
+		case operators.LessEquals:
+			return types.Bool(lhs <= rhs)
+		case operators.Greater:
+			return types.Bool(lhs > rhs)
+		default:
+			return types.Bool(lhs >= rhs)
+		}
+	case operators.Add, operators.Subtract, operators.Multiply, operators.Divide, operators.Modulo:
+		lhs, lok := e.eval(args[0]).(types.Int)
+		rhs, rok := e.eval(args[1]).(types.Int)
+		if !lok || !rok {
+			return e.unknownUnlessInt(expr, args...)
+		}
+		return intArithmetic(call.FunctionName(), int64(lhs), int64(rhs))
+	case operators.Negate:
+		val, ok := e.eval(args[0]).(types.Int)
+		if !ok {
+			return e.unknownUnlessInt(expr, args...)
+		}
+		if val == math.MinInt64 {
+			return nil
+		}
+		return -val
+	case operators.In:
+		return e.evalIn(expr)
+	case "cel.@block":
+		if len(args) != 2 || args[0].Kind() != ast.ListKind {
+			return e.unsupported(expr)
+		}
+		for i, elem := range args[0].AsList().Elements() {
+			e.blocks[fmt.Sprintf("@index%d", i)] = elem
+		}
+		return e.eval(args[1])
+	}
+	return e.unsupported(expr)
+}
+
+// evalIn supports membership tests against list literals.
+func (e *evaluator) evalIn(expr ast.Expr) ref.Val {
+	args := expr.AsCall().Args()
+	list := args[1]
+	if list.Kind() != ast.ListKind || len(list.AsList().OptionalIndices()) != 0 {
+		return e.unsupported(expr)
+	}
+	for _, elem := range list.AsList().Elements() {
+		if e.isOpenComparison(args[0], elem) {
+			return e.unsupported(expr)
+		}
+	}
+	needle := e.eval(args[0])
+	if needle == nil {
+		return nil
+	}
+	var out ref.Val = types.False
+	for _, elem := range list.AsList().Elements() {
+		val := e.eval(elem)
+		if val == nil {
+			out = nil
+			continue
+		}
+		if val.Type() == needle.Type() && val.Equal(needle) == types.True {
+			return types.True
+		}
+	}
+	return out
+}
+
+// isOpenComparison indicates whether an equality comparison involves a string variable of
+// unbounded domain on one side and anything other than a literal on the other. Such comparisons
+// cannot be decided using the representative values assigned to the variable.
+func (e *evaluator) isOpenComparison(lhs, rhs ast.Expr) bool {
+	isOpen := func(expr ast.Expr) bool {
+		path, found := e.vars[expr.ID()]
+		return found && e.domains[path].open
+	}
+	if isOpen(lhs) {
+		return rhs.Kind() != ast.LiteralKind
+	}
+	if isOpen(rhs) {
+		return lhs.Kind() != ast.LiteralKind
+	}
+	return false
+}
+
+// unknownUnlessInt returns an unknown value, recording the expression as a residual if any of its
+// operands are known values of an unsupported type.
+func (e *evaluator) unknownUnlessInt(expr ast.Expr, args ...ast.Expr) ref.Val {
+	for _, arg := range args {
+		if val := e.eval(arg); val != nil {
+			if _, ok := val.(types.Int); !ok {
+				return e.unsupported(expr)
+			}
+		}
+	}
+	return nil
+}
+
+// intArithmetic applies an arithmetic operator, returning unknown for results which would produce
+// an error at runtime, such as overflow or division by zero.
+func intArithmetic(function string, lhs, rhs int64) ref.Val {
+	switch function {
+	case operators.Add:
+		if (rhs > 0 && lhs > math.MaxInt64-rhs) || (rhs < 0 && lhs < math.MinInt64-rhs) {
+			return nil
+		}
+		return types.Int(lhs + rhs)
+	case operators.Subtract:
+		if (rhs < 0 && lhs > math.MaxInt64+rhs) || (rhs > 0 && lhs < math.MinInt64+rhs) {
+			return nil
+		}
+		return types.Int(lhs - rhs)
+	case operators.Multiply:
+		if lhs != 0 && rhs != 0 {
+			p := lhs * rhs
+			if p/rhs != lhs || (lhs == -1 && rhs == math.MinInt64) || (rhs == -1 && lhs == math.MinInt64) {
+				return nil
+			}
+			return types.Int(p)
+		}
+		return types.Int(0)
+	case operators.Divide:
+		if rhs == 0 || (lhs == math.MinInt64 && rhs == -1) {
+			return nil
+		}
+		return types.Int(lhs / rhs)
+	default:
+		if rhs == 0 || (lhs == math.MinInt64 && rhs == -1) {
+			return nil
+		}
+		return types.Int(lhs % rhs)
+	}
+}
+
+// and implements the commutative logical AND, where false absorbs unknown values.
+func and(lhs, rhs ref.Val) ref.Val {
+	if lhs == types.False || rhs == types.False {
+		return types.False
+	}
+	if lhs == types.True && rhs == types.True {
+		return types.True
+	}
+	return nil
+}
+
+// or implements the commutative logical OR, where true absorbs unknown values.
+func or(lhs, rhs ref.Val) ref.Val {
+	if lhs == types.True || rhs == types.True {
+		return types.True
+	}
+	if lhs == types.False && rhs == types.False {
+		return types.False
+	}
+	return nil
+}
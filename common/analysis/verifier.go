@@ -0,0 +1,396 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package analysis provides bounded symbolic verification of boolean CEL expressions.
+//
+// The Verifier answers whether an expression is always true or always false, and whether one
+// expression implies another, for expressions whose inputs have bounded domains: bools, enums
+// declared as literal types or with explicit values, ints with declared ranges, and strings which
+// are only compared for equality against literals. The answer is established by evaluating the
+// expressions under every combination of input values, so a refuted property is accompanied by a
+// concrete counterexample.
+//
+// Constructs outside of the supported subset, such as function calls and comprehensions, are
+// treated as unknown values and reported as residuals. Unknown values propagate according to
+// CEL's commutative logical operators, so `false && f(x)` is still known to be false.
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Verdict indicates the outcome of a verification query.
+type Verdict int
+
+const (
+	// Proven indicates the property holds for all inputs within the variable domains.
+	Proven Verdict = iota + 1
+
+	// Refuted indicates the property does not hold, and a counterexample is provided.
+	Refuted
+
+	// Inconclusive indicates the property holds for every input where the outcome could be
+	// determined, but some inputs produced an unknown outcome due to unsupported constructs.
+	Inconclusive
+)
+
+// String returns a human-readable name for the verdict.
+func (v Verdict) String() string {
+	switch v {
+	case Proven:
+		return "proven"
+	case Refuted:
+		return "refuted"
+	case Inconclusive:
+		return "inconclusive"
+	default:
+		return "unknown"
+	}
+}
+
+// Assignment maps variable paths, e.g. `request.method`, to values.
+type Assignment map[string]ref.Val
+
+// Result describes the outcome of a verification query.
+type Result struct {
+	// Verdict indicates whether the property was proven, refuted, or could not be determined.
+	Verdict Verdict
+
+	// Counterexample is an assignment of input values for which the property does not hold when
+	// the verdict is Refuted, nil otherwise.
+	Counterexample Assignment
+
+	// Residuals contains the unsupported subexpressions which produced unknown outcomes, ordered
+	// by expression id.
+	Residuals []ast.Expr
+
+	// Assignments is the number of input combinations which were evaluated.
+	Assignments int
+}
+
+// Verifier answers questions about boolean expressions over variables with bounded domains.
+type Verifier struct {
+	domains        map[string]*domain
+	maxAssignments int
+}
+
+// VerifierOption is a functional option for configuring a Verifier.
+type VerifierOption func(*Verifier) (*Verifier, error)
+
+// BoolVariable declares a variable path as a bool.
+//
+// Variables with a checked type of bool are inferred automatically, so this option is only needed
+// for paths with a dynamic type such as a field of a `map(string, dyn)`.
+func BoolVariable(path string) VerifierOption {
+	return declare(path, &domain{values: []ref.Val{types.False, types.True}, exact: true})
+}
+
+// IntRange declares a variable path as an int with values in the inclusive range [min, max].
+//
+// The range may not contain more values than the assignment limit set with MaxAssignments.
+func IntRange(path string, min, max int64) VerifierOption {
+	return func(v *Verifier) (*Verifier, error) {
+		if min > max {
+			return nil, fmt.Errorf("invalid range for %s: min %d greater than max %d", path, min, max)
+		}
+		return declare(path, &domain{exact: true, intRange: &intRange{min: min, max: max}})(v)
+	}
+}
+
+// IntValues declares a variable path as an int which takes only the given values, e.g. the numbers
+// of an enum.
+//
+// Variables with an int literal type are inferred automatically.
+func IntValues(path string, values ...int64) VerifierOption {
+	vals := make([]ref.Val, len(values))
+	for i, val := range values {
+		vals[i] = types.Int(val)
+	}
+	return declare(path, &domain{values: vals, exact: true})
+}
+
+// StringValues declares a variable path as a string which takes only the given values.
+//
+// Variables with a string literal type are inferred automatically.
+func StringValues(path string, values ...string) VerifierOption {
+	vals := make([]ref.Val, len(values))
+	for i, val := range values {
+		vals[i] = types.String(val)
+	}
+	return declare(path, &domain{values: vals, exact: true})
+}
+
+// StringVariable declares a variable path as a string which may take any value.
+//
+// Variables with a checked type of string are inferred automatically. Such variables are
+// supported within equality and `in` comparisons against string literals, and are assigned each
+// literal within the verified expressions along with one value distinct from all literals.
+func StringVariable(path string) VerifierOption {
+	return declare(path, &domain{open: true})
+}
+
+// MaxAssignments sets the maximum number of input combinations the Verifier will evaluate. The
+// default is 1,048,576. Queries whose domains exceed the limit return an error.
+func MaxAssignments(limit int) VerifierOption {
+	return func(v *Verifier) (*Verifier, error) {
+		if limit < 1 {
+			return nil, fmt.Errorf("max assignments must be greater than 0: %d", limit)
+		}
+		v.maxAssignments = limit
+		return v, nil
+	}
+}
+
+func declare(path string, d *domain) VerifierOption {
+	return func(v *Verifier) (*Verifier, error) {
+		if path == "" {
+			return nil, errors.New("variable path must not be empty")
+		}
+		if d.exact && d.intRange == nil && len(d.values) == 0 {
+			return nil, fmt.Errorf("variable %s must have at least one value", path)
+		}
+		v.domains[path] = d
+		return v, nil
+	}
+}
+
+// NewVerifier creates a Verifier with the given variable declarations and options.
+func NewVerifier(opts ...VerifierOption) (*Verifier, error) {
+	v := &Verifier{
+		domains:        make(map[string]*domain),
+		maxAssignments: 1 << 20,
+	}
+	var err error
+	for _, opt := range opts {
+		v, err = opt(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Int ranges are expanded once all options are applied so that the assignment limit does not
+	// depend on the order of the options.
+	paths := make([]string, 0, len(v.domains))
+	for path := range v.domains {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		d := v.domains[path]
+		if d.intRange == nil {
+			continue
+		}
+		if uint64(d.intRange.max-d.intRange.min) >= uint64(v.maxAssignments) {
+			return nil, fmt.Errorf("range for %s exceeds the assignment limit of %d", path, v.maxAssignments)
+		}
+		d.values = d.intRange.values()
+	}
+	return v, nil
+}
+
+// AlwaysTrue determines whether a boolean expression evaluates to true for all inputs.
+//
+// When refuted, the counterexample is an input for which the expression evaluates to false.
+func (v *Verifier) AlwaysTrue(a *ast.AST) (*Result, error) {
+	return v.verify([]*ast.AST{a}, func(vals []ref.Val) (bool, bool) {
+		return holds(vals[0], types.True)
+	})
+}
+
+// AlwaysFalse determines whether a boolean expression evaluates to false for all inputs.
+//
+// When refuted, the counterexample is an input for which the expression evaluates to true.
+func (v *Verifier) AlwaysFalse(a *ast.AST) (*Result, error) {
+	return v.verify([]*ast.AST{a}, func(vals []ref.Val) (bool, bool) {
+		return holds(vals[0], types.False)
+	})
+}
+
+// Implies determines whether the boolean expression `a` evaluating to true implies that the
+// boolean expression `b` evaluates to true for all inputs.
+//
+// When refuted, the counterexample is an input which makes `a` true but `b` false. For example,
+// to show that a tightened policy never allows a request the previous policy denied, verify that
+// the new policy's allow condition implies the old one's.
+func (v *Verifier) Implies(a, b *ast.AST) (*Result, error) {
+	return v.verify([]*ast.AST{a, b}, func(vals []ref.Val) (bool, bool) {
+		if vals[0] == types.False || vals[1] == types.True {
+			return true, true
+		}
+		if vals[0] == types.True && vals[1] == types.False {
+			return false, true
+		}
+		return false, false
+	})
+}
+
+func holds(val, want ref.Val) (bool, bool) {
+	if val == nil {
+		return false, false
+	}
+	return val == want, true
+}
+
+// verify evaluates the expressions for every assignment of the variables they reference, and
+// applies the property to the results. The property reports whether it holds and whether the
+// outcome is known.
+func (v *Verifier) verify(asts []*ast.AST, property func([]ref.Val) (bool, bool)) (*Result, error) {
+	for _, a := range asts {
+		if !a.IsChecked() {
+			return nil, errors.New("verification requires a type-checked AST")
+		}
+		if t := a.GetType(a.Expr().ID()); t.Kind() != types.BoolKind && t.Kind() != types.DynKind {
+			return nil, fmt.Errorf("verification requires a bool expression, got: %v", t)
+		}
+	}
+	evals := make([]*evaluator, len(asts))
+	vars := make(map[string]*domain)
+	literals := make(map[string]bool)
+	for i, a := range asts {
+		evals[i] = newEvaluator(v, a)
+		for path, d := range evals[i].domains {
+			vars[path] = d
+		}
+		collectStringLiterals(a.Expr(), literals)
+	}
+	paths := make([]string, 0, len(vars))
+	for path := range vars {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	domains := make([][]ref.Val, len(paths))
+	total := 1
+	for i, path := range paths {
+		domains[i] = vars[path].enumerate(literals)
+		total *= len(domains[i])
+		if total > v.maxAssignments {
+			return nil, fmt.Errorf("variable domains exceed the assignment limit of %d", v.maxAssignments)
+		}
+	}
+
+	res := &Result{Verdict: Proven}
+	residuals := make(map[int64]ast.Expr)
+	indices := make([]int, len(paths))
+	vals := make([]ref.Val, len(asts))
+	for {
+		assign := make(Assignment, len(paths))
+		for i, path := range paths {
+			assign[path] = domains[i][indices[i]]
+		}
+		for i, e := range evals {
+			vals[i] = e.evaluate(assign)
+		}
+		res.Assignments++
+		ok, known := property(vals)
+		if known && !ok {
+			res.Verdict = Refuted
+			res.Counterexample = assign
+			res.Residuals = nil
+			return res, nil
+		}
+		if !known {
+			res.Verdict = Inconclusive
+			for _, e := range evals {
+				for id, r := range e.residuals {
+					residuals[id] = r
+				}
+			}
+		}
+		// Advance to the next assignment in lexicographic order.
+		i := len(indices) - 1
+		for ; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(domains[i]) {
+				break
+			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			break
+		}
+	}
+	ids := make([]int64, 0, len(residuals))
+	for id := range residuals {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		res.Residuals = append(res.Residuals, residuals[id])
+	}
+	return res, nil
+}
+
+// domain describes the values a variable may take.
+type domain struct {
+	values []ref.Val
+	// exact indicates the values are the complete set of values the variable may take.
+	exact bool
+	// open indicates a string variable which may take any value, and which is assigned the string
+	// literals of the verified expressions along with one distinct value.
+	open bool
+	// intRange is the declared range of an int variable, whose values are populated by NewVerifier.
+	intRange *intRange
+}
+
+// intRange is an inclusive range of int values.
+type intRange struct {
+	min, max int64
+}
+
+func (r *intRange) values() []ref.Val {
+	values := make([]ref.Val, 0, r.max-r.min+1)
+	for i := r.min; ; i++ {
+		values = append(values, types.Int(i))
+		if i == r.max {
+			break
+		}
+	}
+	return values
+}
+
+func (d *domain) enumerate(literals map[string]bool) []ref.Val {
+	if !d.open {
+		return d.values
+	}
+	strs := make([]string, 0, len(literals))
+	for lit := range literals {
+		strs = append(strs, lit)
+	}
+	sort.Strings(strs)
+	vals := make([]ref.Val, 0, len(strs)+1)
+	for _, s := range strs {
+		vals = append(vals, types.String(s))
+	}
+	other := "other"
+	for i := 1; literals[other]; i++ {
+		other = fmt.Sprintf("other_%d", i)
+	}
+	return append(vals, types.String(other))
+}
+
+func collectStringLiterals(e ast.Expr, literals map[string]bool) {
+	ast.PostOrderVisit(e, ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.LiteralKind {
+			return
+		}
+		if s, ok := e.AsLiteral().(types.String); ok {
+			literals[string(s)] = true
+		}
+	}))
+}
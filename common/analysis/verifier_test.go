@@ -0,0 +1,279 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/analysis"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/types"
+)
+
+func TestAlwaysTrue(t *testing.T) {
+	tests := []struct {
+		expr      string
+		verdict   analysis.Verdict
+		example   analysis.Assignment
+		residuals []string
+	}{
+		{expr: `admin || !admin`, verdict: analysis.Proven},
+		{expr: `level >= 0 && level <= 3`, verdict: analysis.Proven},
+		{expr: `level + 1 > level`, verdict: analysis.Proven},
+		{expr: `method in ['GET', 'POST', 'DELETE']`, verdict: analysis.Proven},
+		{expr: `region == 'us' || region != 'us'`, verdict: analysis.Proven},
+		{expr: `admin ? true : level < 4`, verdict: analysis.Proven},
+		{
+			expr:    `admin && level > 0`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"admin": types.False, "level": types.Int(0)},
+		},
+		{
+			expr:    `method != 'DELETE'`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"method": types.String("DELETE")},
+		},
+		{
+			expr:    `region == 'us'`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"region": types.String("other")},
+		},
+		{expr: `admin || region.startsWith('us')`, verdict: analysis.Inconclusive, residuals: []string{"region.startsWith"}},
+		{
+			expr:    `!admin && region.startsWith('us')`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"admin": types.True},
+		},
+		{expr: `level / (level - level) > 0 || true`, verdict: analysis.Proven},
+	}
+	env := newTestEnv(t)
+	v, err := analysis.NewVerifier(analysis.IntRange("level", 0, 3))
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			res, err := v.AlwaysTrue(compile(t, env, tc.expr))
+			if err != nil {
+				t.Fatalf("AlwaysTrue() failed: %v", err)
+			}
+			if res.Verdict != tc.verdict {
+				t.Fatalf("AlwaysTrue() got verdict %v, wanted %v", res.Verdict, tc.verdict)
+			}
+			if tc.example != nil {
+				for path, want := range tc.example {
+					if got := res.Counterexample[path]; got != want {
+						t.Errorf("AlwaysTrue() got counterexample %v, wanted %s = %v", res.Counterexample, path, want)
+					}
+				}
+			}
+			var residuals []string
+			for _, r := range res.Residuals {
+				if r.Kind() == ast.CallKind {
+					residuals = append(residuals, r.AsCall().Target().AsIdent()+"."+r.AsCall().FunctionName())
+				}
+			}
+			if !reflect.DeepEqual(residuals, tc.residuals) {
+				t.Errorf("AlwaysTrue() got residuals %v, wanted %v", residuals, tc.residuals)
+			}
+		})
+	}
+}
+
+func TestAlwaysFalse(t *testing.T) {
+	env := newTestEnv(t)
+	v, err := analysis.NewVerifier(analysis.IntRange("level", 0, 3))
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	res, err := v.AlwaysFalse(compile(t, env, `level > 2 && level < 3`))
+	if err != nil {
+		t.Fatalf("AlwaysFalse() failed: %v", err)
+	}
+	if res.Verdict != analysis.Proven || res.Assignments != 4 {
+		t.Errorf("AlwaysFalse() got %v after %d assignments, wanted proven after 4", res.Verdict, res.Assignments)
+	}
+	res, err = v.AlwaysFalse(compile(t, env, `method == 'GET' && method in ['POST', 'DELETE']`))
+	if err != nil {
+		t.Fatalf("AlwaysFalse() failed: %v", err)
+	}
+	if res.Verdict != analysis.Proven {
+		t.Errorf("AlwaysFalse() got %v, wanted proven", res.Verdict)
+	}
+}
+
+func TestImplies(t *testing.T) {
+	tests := []struct {
+		a       string
+		b       string
+		verdict analysis.Verdict
+		example analysis.Assignment
+	}{
+		{a: `admin && level > 2`, b: `admin || level > 1`, verdict: analysis.Proven},
+		{a: `method == 'GET' && region == 'eu'`, b: `method != 'DELETE'`, verdict: analysis.Proven},
+		{a: `region in ['us', 'eu']`, b: `region != 'cn'`, verdict: analysis.Proven},
+		{
+			a:       `admin || level > 2`,
+			b:       `admin && level > 1`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"admin": types.False, "level": types.Int(3)},
+		},
+		{
+			a:       `region != 'us'`,
+			b:       `region == 'eu'`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"region": types.String("other")},
+		},
+		{a: `admin`, b: `admin || size(region) > 2`, verdict: analysis.Proven},
+		{
+			a:       `!admin || size(region) > 2`,
+			b:       `admin`,
+			verdict: analysis.Refuted,
+			example: analysis.Assignment{"admin": types.False},
+		},
+		{a: `admin && size(region) > 2`, b: `admin && size(region) > 3`, verdict: analysis.Inconclusive},
+	}
+	env := newTestEnv(t)
+	v, err := analysis.NewVerifier(analysis.IntRange("level", 0, 3))
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.a+" => "+tc.b, func(t *testing.T) {
+			res, err := v.Implies(compile(t, env, tc.a), compile(t, env, tc.b))
+			if err != nil {
+				t.Fatalf("Implies() failed: %v", err)
+			}
+			if res.Verdict != tc.verdict {
+				t.Fatalf("Implies() got verdict %v, wanted %v", res.Verdict, tc.verdict)
+			}
+			for path, want := range tc.example {
+				if got := res.Counterexample[path]; got != want {
+					t.Errorf("Implies() got counterexample %v, wanted %s = %v", res.Counterexample, path, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeclaredDomains(t *testing.T) {
+	env, err := cel.NewEnv(
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("tier", cel.IntType),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	v, err := analysis.NewVerifier(
+		analysis.BoolVariable("request.internal"),
+		analysis.StringVariable("request.path"),
+		analysis.StringValues("request.verb", "read", "write"),
+		analysis.IntValues("tier", 1, 5, 10),
+	)
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	newPolicy := compile(t, env, `request.internal && request.verb == 'read' && tier >= 5`)
+	oldPolicy := compile(t, env, `request.internal && (request.verb == 'read' || request.path == '/public') && tier > 1`)
+	res, err := v.Implies(newPolicy, oldPolicy)
+	if err != nil {
+		t.Fatalf("Implies() failed: %v", err)
+	}
+	if res.Verdict != analysis.Proven {
+		t.Errorf("Implies(new, old) got %v, wanted proven", res.Verdict)
+	}
+	res, err = v.Implies(oldPolicy, newPolicy)
+	if err != nil {
+		t.Fatalf("Implies() failed: %v", err)
+	}
+	if res.Verdict != analysis.Refuted {
+		t.Fatalf("Implies(old, new) got %v, wanted refuted", res.Verdict)
+	}
+	if res.Counterexample["request.internal"] != types.True {
+		t.Errorf("Implies(old, new) got counterexample %v, wanted request.internal = true", res.Counterexample)
+	}
+}
+
+func TestVerifierErrors(t *testing.T) {
+	env := newTestEnv(t)
+	v, err := analysis.NewVerifier(analysis.MaxAssignments(2), analysis.IntRange("level", 0, 1))
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	if _, err := v.AlwaysTrue(compile(t, env, `admin && level > 0`)); err == nil {
+		t.Error("AlwaysTrue() exceeding the assignment limit succeeded, wanted error")
+	}
+	if _, err := v.AlwaysTrue(compile(t, env, `level`)); err == nil {
+		t.Error("AlwaysTrue() with a non-bool expression succeeded, wanted error")
+	}
+	parsed, iss := env.Parse(`admin`)
+	if iss.Err() != nil {
+		t.Fatalf("Parse() failed: %v", iss.Err())
+	}
+	if _, err := v.AlwaysTrue(parsed.NativeRep()); err == nil {
+		t.Error("AlwaysTrue() with a parsed AST succeeded, wanted error")
+	}
+	if _, err := analysis.NewVerifier(analysis.IntRange("x", 2, 1)); err == nil {
+		t.Error("NewVerifier() with an empty range succeeded, wanted error")
+	}
+	if _, err := analysis.NewVerifier(analysis.IntValues("x")); err == nil {
+		t.Error("NewVerifier() with no values succeeded, wanted error")
+	}
+	if _, err := analysis.NewVerifier(analysis.MaxAssignments(0)); err == nil {
+		t.Error("NewVerifier() with a zero assignment limit succeeded, wanted error")
+	}
+	if _, err := analysis.NewVerifier(analysis.IntRange("x", 0, 10), analysis.MaxAssignments(4)); err == nil {
+		t.Error("NewVerifier() with a range exceeding a later assignment limit succeeded, wanted error")
+	}
+}
+
+func TestVerifierOptionOrder(t *testing.T) {
+	orders := [][]analysis.VerifierOption{
+		{analysis.MaxAssignments(1 << 21), analysis.IntRange("level", 0, 1<<20)},
+		{analysis.IntRange("level", 0, 1<<20), analysis.MaxAssignments(1 << 21)},
+	}
+	for _, opts := range orders {
+		if _, err := analysis.NewVerifier(opts...); err != nil {
+			t.Errorf("NewVerifier() failed: %v", err)
+		}
+	}
+}
+
+func newTestEnv(t testing.TB) *cel.Env {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.Variable("admin", cel.BoolType),
+		cel.Variable("level", cel.IntType),
+		cel.Variable("method", cel.StringLiteralType("GET", "POST", "DELETE")),
+		cel.Variable("region", cel.StringType),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	return env
+}
+
+func compile(t testing.TB, env *cel.Env, expr string) *ast.AST {
+	t.Helper()
+	checked, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		t.Fatalf("Compile(%q) failed: %v", expr, iss.Err())
+	}
+	return checked.NativeRep()
+}
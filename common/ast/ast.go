@@ -244,6 +244,13 @@ func CopySourceInfo(info *SourceInfo) *SourceInfo {
 		extCopy = make([]Extension, len(info.extensions))
 		copy(extCopy, info.extensions)
 	}
+	var commentsCopy map[int64][]Comment
+	if len(info.comments) > 0 {
+		commentsCopy = make(map[int64][]Comment, len(info.comments))
+		for id, comments := range info.comments {
+			commentsCopy[id] = slices.Clone(comments)
+		}
+	}
 	return &SourceInfo{
 		syntax:       info.syntax,
 		desc:         info.desc,
@@ -252,6 +259,7 @@ func CopySourceInfo(info *SourceInfo) *SourceInfo {
 		baseCol:      info.baseCol,
 		offsetRanges: rangesCopy,
 		macroCalls:   callsCopy,
+		comments:     commentsCopy,
 		extensions:   extCopy,
 	}
 }
@@ -266,6 +274,7 @@ type SourceInfo struct {
 	baseCol      int32
 	offsetRanges map[int64]OffsetRange
 	macroCalls   map[int64]Expr
+	comments     map[int64][]Comment
 
 	// extensions indicate versioned optional features which affect the execution of one or more CEL component.
 	extensions []Extension
@@ -286,6 +295,13 @@ func (s *SourceInfo) RenumberIDs(idGen IDGenerator) {
 		newRanges[idGen(id)] = s.offsetRanges[id]
 	}
 	s.offsetRanges = newRanges
+	if len(s.comments) > 0 {
+		newComments := make(map[int64][]Comment, len(s.comments))
+		for id, comments := range s.comments {
+			newComments[idGen(id)] = comments
+		}
+		s.comments = newComments
+	}
 }
 
 // SyntaxVersion returns the syntax version associated with the text expression.
@@ -345,6 +361,35 @@ func (s *SourceInfo) ClearMacroCall(id int64) {
 	}
 }
 
+// Comments returns a map of expression id to the source comments attached to the expression.
+//
+// Note, parsing options must be enabled to populate comments before this method will return a value.
+func (s *SourceInfo) Comments() map[int64][]Comment {
+	if s == nil {
+		return map[int64][]Comment{}
+	}
+	return s.comments
+}
+
+// GetComments returns the comments attached to the given expression id in source order.
+func (s *SourceInfo) GetComments(id int64) []Comment {
+	if s == nil {
+		return nil
+	}
+	return s.comments[id]
+}
+
+// AddComment attaches a comment to the expression with the given id.
+func (s *SourceInfo) AddComment(id int64, c Comment) {
+	if s == nil {
+		return
+	}
+	if s.comments == nil {
+		s.comments = make(map[int64][]Comment)
+	}
+	s.comments[id] = append(s.comments[id], c)
+}
+
 // OffsetRanges returns a map of expression id to OffsetRange values where the range indicates either:
 // the start and end position in the input stream where the expression occurs, or the start position
 // only. If the range only captures start position, the stop position of the range will be equal to
@@ -465,6 +510,27 @@ func (s *SourceInfo) AddExtension(ext Extension) {
 	s.extensions = append(s.extensions, ext)
 }
 
+// CommentKind indicates the placement of a comment relative to the expression it is attached to.
+type CommentKind int
+
+const (
+	// LeadingComment appears on the line or lines preceding the expression.
+	LeadingComment CommentKind = iota + 1
+
+	// TrailingComment appears at the end of the line on which the expression ends.
+	TrailingComment
+
+	// FooterComment appears on its own line after the expression when no other expression follows
+	// within the enclosing scope, e.g. before a closing parenthesis or at the end of the input.
+	FooterComment
+)
+
+// Comment captures the text of a source comment, including the leading '//', and its placement.
+type Comment struct {
+	Kind CommentKind
+	Text string
+}
+
 // OffsetRange captures the start and stop positions of a section of text in the input expression.
 type OffsetRange struct {
 	Start int32
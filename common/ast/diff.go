@@ -0,0 +1,209 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Difference describes a pair of corresponding subtrees which differ between two ASTs.
+type Difference struct {
+	// Left is the subtree within the first AST.
+	Left Expr
+
+	// Right is the subtree within the second AST.
+	Right Expr
+
+	// LeftRange is the source span of the left subtree, or an empty range at offset -1 if the
+	// subtree has no recorded source positions.
+	//
+	// The span covers the offset ranges recorded for the nodes within the subtree, and so begins at
+	// the subtree's first token but may end before a closing delimiter.
+	LeftRange OffsetRange
+
+	// RightRange is the source span of the right subtree, with the same caveats as LeftRange.
+	RightRange OffsetRange
+}
+
+// Diff structurally compares two ASTs and returns the outermost pairs of corresponding subtrees
+// which differ.
+//
+// Expression ids, type-check metadata, and macro bookkeeping within the source info are ignored,
+// so two ASTs produced from sources which differ only in whitespace, comments, or redundant
+// parentheses have no differences. When a node differs in a property of its own, such as a function
+// name, field name, literal value, or the number of its children, the node is reported as a whole.
+// Otherwise, its children are compared pairwise.
+func Diff(left, right *AST) []Difference {
+	d := &differ{leftInfo: left.SourceInfo(), rightInfo: right.SourceInfo()}
+	d.diff(left.Expr(), right.Expr())
+	return d.diffs
+}
+
+// ExprEquals indicates whether two expressions are structurally equal, ignoring expression ids.
+func ExprEquals(left, right Expr) bool {
+	d := &differ{}
+	d.diff(left, right)
+	return len(d.diffs) == 0
+}
+
+type differ struct {
+	leftInfo  *SourceInfo
+	rightInfo *SourceInfo
+	diffs     []Difference
+}
+
+func (d *differ) diff(left, right Expr) {
+	if !shallowEquals(left, right) {
+		d.report(left, right)
+		return
+	}
+	switch left.Kind() {
+	case CallKind:
+		l, r := left.AsCall(), right.AsCall()
+		if l.IsMemberFunction() {
+			d.diff(l.Target(), r.Target())
+		}
+		d.diffAll(l.Args(), r.Args())
+	case ComprehensionKind:
+		l, r := left.AsComprehension(), right.AsComprehension()
+		d.diff(l.IterRange(), r.IterRange())
+		d.diff(l.AccuInit(), r.AccuInit())
+		d.diff(l.LoopCondition(), r.LoopCondition())
+		d.diff(l.LoopStep(), r.LoopStep())
+		d.diff(l.Result(), r.Result())
+	case ListKind:
+		d.diffAll(left.AsList().Elements(), right.AsList().Elements())
+	case MapKind:
+		l, r := left.AsMap().Entries(), right.AsMap().Entries()
+		for i := range l {
+			d.diff(l[i].AsMapEntry().Key(), r[i].AsMapEntry().Key())
+			d.diff(l[i].AsMapEntry().Value(), r[i].AsMapEntry().Value())
+		}
+	case SelectKind:
+		d.diff(left.AsSelect().Operand(), right.AsSelect().Operand())
+	case StructKind:
+		l, r := left.AsStruct().Fields(), right.AsStruct().Fields()
+		for i := range l {
+			d.diff(l[i].AsStructField().Value(), r[i].AsStructField().Value())
+		}
+	}
+}
+
+func (d *differ) diffAll(left, right []Expr) {
+	for i := range left {
+		d.diff(left[i], right[i])
+	}
+}
+
+func (d *differ) report(left, right Expr) {
+	d.diffs = append(d.diffs, Difference{
+		Left:       left,
+		Right:      right,
+		LeftRange:  subtreeRange(d.leftInfo, left),
+		RightRange: subtreeRange(d.rightInfo, right),
+	})
+}
+
+// shallowEquals compares the properties of two expression nodes, excluding their subexpressions
+// but including the number of subexpressions.
+func shallowEquals(left, right Expr) bool {
+	if left.Kind() != right.Kind() {
+		return false
+	}
+	switch left.Kind() {
+	case CallKind:
+		l, r := left.AsCall(), right.AsCall()
+		return l.FunctionName() == r.FunctionName() &&
+			l.IsMemberFunction() == r.IsMemberFunction() &&
+			len(l.Args()) == len(r.Args())
+	case ComprehensionKind:
+		l, r := left.AsComprehension(), right.AsComprehension()
+		return l.IterVar() == r.IterVar() &&
+			l.IterVar2() == r.IterVar2() &&
+			l.AccuVar() == r.AccuVar()
+	case IdentKind:
+		return left.AsIdent() == right.AsIdent()
+	case ListKind:
+		l, r := left.AsList(), right.AsList()
+		if l.Size() != r.Size() || len(l.OptionalIndices()) != len(r.OptionalIndices()) {
+			return false
+		}
+		for i, idx := range l.OptionalIndices() {
+			if r.OptionalIndices()[i] != idx {
+				return false
+			}
+		}
+		return true
+	case LiteralKind:
+		return literalEquals(left.AsLiteral(), right.AsLiteral())
+	case MapKind:
+		l, r := left.AsMap().Entries(), right.AsMap().Entries()
+		if len(l) != len(r) {
+			return false
+		}
+		for i := range l {
+			if l[i].AsMapEntry().IsOptional() != r[i].AsMapEntry().IsOptional() {
+				return false
+			}
+		}
+		return true
+	case SelectKind:
+		l, r := left.AsSelect(), right.AsSelect()
+		return l.FieldName() == r.FieldName() && l.IsTestOnly() == r.IsTestOnly()
+	case StructKind:
+		l, r := left.AsStruct(), right.AsStruct()
+		if l.TypeName() != r.TypeName() || len(l.Fields()) != len(r.Fields()) {
+			return false
+		}
+		for i, lf := range l.Fields() {
+			rf := r.Fields()[i]
+			if lf.AsStructField().Name() != rf.AsStructField().Name() ||
+				lf.AsStructField().IsOptional() != rf.AsStructField().IsOptional() {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
+// literalEquals compares literals by type as well as value, since CEL equality considers numeric
+// values of different types equal.
+func literalEquals(left, right ref.Val) bool {
+	return left.Type() == right.Type() && left.Equal(right) == types.True
+}
+
+// subtreeRange returns the smallest range which covers the recorded offsets of every node within the
+// subtree.
+func subtreeRange(info *SourceInfo, e Expr) OffsetRange {
+	r := OffsetRange{Start: -1, Stop: -1}
+	if info == nil {
+		return r
+	}
+	PostOrderVisit(e, NewExprVisitor(func(e Expr) {
+		o, found := info.GetOffsetRange(e.ID())
+		if !found {
+			return
+		}
+		if r.Start < 0 || o.Start < r.Start {
+			r.Start = o.Start
+		}
+		if o.Stop > r.Stop {
+			r.Stop = o.Stop
+		}
+	}))
+	return r
+}
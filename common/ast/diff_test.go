@@ -0,0 +1,108 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/parser"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		left  string
+		right string
+		// diffs lists the unparsed left and right subtrees which differ.
+		diffs [][2]string
+	}{
+		{left: `a + b`, right: `(a) +   b // sum`},
+		{left: `[1, 2].exists(x, x > 1)`, right: `[1, 2].exists(x, x > 1)`},
+		{left: `a.b.c`, right: `a.b.d`, diffs: [][2]string{{`a.b.c`, `a.b.d`}}},
+		{left: `f(x, y + 1)`, right: `f(x, y + 2)`, diffs: [][2]string{{`1`, `2`}}},
+		{left: `1`, right: `1.0`, diffs: [][2]string{{`1`, `1.0`}}},
+		{left: `a && b`, right: `a || b`, diffs: [][2]string{{`a && b`, `a || b`}}},
+		{
+			left:  `x.all(i, i > 0) && y == 'a'`,
+			right: `x.all(j, j > 0) && y == 'b'`,
+			diffs: [][2]string{{`x.all(i, i > 0)`, `x.all(j, j > 0)`}, {`"a"`, `"b"`}},
+		},
+		{left: `[1, 2]`, right: `[1, 2, 3]`, diffs: [][2]string{{`[1, 2]`, `[1, 2, 3]`}}},
+		{left: `{'a': x}`, right: `{'a': y}`, diffs: [][2]string{{`x`, `y`}}},
+		{left: `has(a.b)`, right: `a.b`, diffs: [][2]string{{`has(a.b)`, `a.b`}}},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.left+" vs "+tc.right, func(t *testing.T) {
+			left := mustParse(t, tc.left)
+			right := mustParse(t, tc.right)
+			var diffs [][2]string
+			for _, d := range ast.Diff(left, right) {
+				diffs = append(diffs, [2]string{
+					mustUnparse(t, d.Left, left.SourceInfo()),
+					mustUnparse(t, d.Right, right.SourceInfo()),
+				})
+			}
+			if !reflect.DeepEqual(diffs, tc.diffs) {
+				t.Errorf("Diff() got %v, wanted %v", diffs, tc.diffs)
+			}
+			if equal := ast.ExprEquals(left.Expr(), right.Expr()); equal != (len(tc.diffs) == 0) {
+				t.Errorf("ExprEquals() got %t, wanted %t", equal, len(tc.diffs) == 0)
+			}
+		})
+	}
+}
+
+func TestDiffRanges(t *testing.T) {
+	left := mustParse(t, `f(x, y + 1)`)
+	right := mustParse(t, "f(x,\n  y + 20)")
+	diffs := ast.Diff(left, right)
+	if len(diffs) != 1 {
+		t.Fatalf("Diff() got %v, wanted one difference", diffs)
+	}
+	if want := (ast.OffsetRange{Start: 9, Stop: 10}); diffs[0].LeftRange != want {
+		t.Errorf("Diff() got left range %v, wanted %v", diffs[0].LeftRange, want)
+	}
+	if want := (ast.OffsetRange{Start: 11, Stop: 13}); diffs[0].RightRange != want {
+		t.Errorf("Diff() got right range %v, wanted %v", diffs[0].RightRange, want)
+	}
+	if loc := right.SourceInfo().GetLocationByOffset(diffs[0].RightRange.Start); loc.Line() != 2 || loc.Column() != 6 {
+		t.Errorf("Diff() got right location %d:%d, wanted 2:6", loc.Line(), loc.Column())
+	}
+}
+
+func mustParse(t testing.TB, expr string) *ast.AST {
+	t.Helper()
+	p, err := parser.NewParser(parser.Macros(parser.AllMacros...), parser.PopulateMacroCalls(true))
+	if err != nil {
+		t.Fatalf("parser.NewParser() failed: %v", err)
+	}
+	parsed, iss := p.Parse(common.NewTextSource(expr))
+	if len(iss.GetErrors()) != 0 {
+		t.Fatalf("Parse(%s) failed: %s", expr, iss.ToDisplayString())
+	}
+	return parsed
+}
+
+func mustUnparse(t testing.TB, e ast.Expr, info *ast.SourceInfo) string {
+	t.Helper()
+	out, err := parser.Unparse(e, info)
+	if err != nil {
+		t.Fatalf("Unparse() failed: %v", err)
+	}
+	return out
+}
@@ -656,6 +656,9 @@ type OverloadDecl struct {
 	//
 	// This is useful for creating overloads which operate on a type-interface rather than a concrete type.
 	operandTrait int
+	// crossTypeNumericComparison indicates that the overload compares numeric values of different
+	// types and is only considered by the type-checker when cross-type comparisons are enabled.
+	crossTypeNumericComparison bool
 
 	// Function implementation options. Optional, but encouraged.
 	// unaryOp is a function binding that takes a single argument.
@@ -677,6 +680,12 @@ func (o *OverloadDecl) Examples() []string {
 	return common.ParseDescriptions(o.doc)
 }
 
+// IsCrossTypeNumericComparison returns whether the overload compares numeric values of different
+// types.
+func (o *OverloadDecl) IsCrossTypeNumericComparison() bool {
+	return o != nil && o.crossTypeNumericComparison
+}
+
 // ID mirrors the overload signature and provides a unique id which may be referenced within the type-checker
 // and interpreter to optimize performance.
 //
@@ -1024,6 +1033,15 @@ func OverloadOperandTrait(trait int) OverloadOpt {
 	}
 }
 
+// OverloadIsCrossTypeNumericComparison marks the overload as a comparison between numeric values
+// of different types which is only available when cross-type numeric comparisons are enabled.
+func OverloadIsCrossTypeNumericComparison() OverloadOpt {
+	return func(o *OverloadDecl) (*OverloadDecl, error) {
+		o.crossTypeNumericComparison = true
+		return o, nil
+	}
+}
+
 // NewConstant creates a new constant declaration.
 func NewConstant(name string, t *types.Type, v ref.Val) *VariableDecl {
 	return &VariableDecl{name: name, varType: t, value: v}
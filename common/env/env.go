@@ -24,6 +24,7 @@ import (
 
 	"cel.dev/cel-go/common/decls"
 	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
 )
 
 // NewConfig creates an instance of a YAML serializable CEL environment configuration.
@@ -51,6 +52,9 @@ type Config struct {
 	Validators      []*Validator     `yaml:"validators,omitempty"`
 	Features        []*Feature       `yaml:"features,omitempty"`
 	Limits          []*Limit         `yaml:"limits,omitempty"`
+	// Schemas lists the paths of JSON Schema, OpenAPI v3, or CustomResourceDefinition documents
+	// whose object schemas are declared as types.
+	Schemas []string `yaml:"schemas,omitempty"`
 }
 
 // Validate validates the whole configuration is well-formed.
@@ -103,6 +107,11 @@ func (c *Config) Validate() error {
 			errs = append(errs, err)
 		}
 	}
+	for _, path := range c.Schemas {
+		if path == "" {
+			errs = append(errs, errors.New("invalid schema: missing path"))
+		}
+	}
 	return errors.Join(errs...)
 }
 
@@ -218,6 +227,12 @@ func (c *Config) AddLimits(limits ...*Limit) *Config {
 	return c
 }
 
+// AddSchemas appends one or more schema document paths to the config.
+func (c *Config) AddSchemas(paths ...string) *Config {
+	c.Schemas = append(c.Schemas, paths...)
+	return c
+}
+
 // NewImport returns a serializable import value from the qualified type name.
 func NewImport(name string) *Import {
 	return &Import{Name: name}
@@ -779,6 +794,11 @@ func NewTypeDesc(typeName string, params ...*TypeDesc) *TypeDesc {
 	return &TypeDesc{TypeName: typeName, Params: params}
 }
 
+// NewLiteralTypeDesc describes a string or int type which permits only the given values.
+func NewLiteralTypeDesc(typeName string, values ...any) *TypeDesc {
+	return &TypeDesc{TypeName: typeName, Values: values}
+}
+
 // NewTypeParam describe a type-param type.
 func NewTypeParam(paramName string) *TypeDesc {
 	return &TypeDesc{TypeName: paramName, IsTypeParam: true}
@@ -789,6 +809,8 @@ type TypeDesc struct {
 	TypeName    string      `yaml:"type_name"`
 	Params      []*TypeDesc `yaml:"params,omitempty"`
 	IsTypeParam bool        `yaml:"is_type_param,omitempty"`
+	// Values restricts a string or int type to a fixed set of literal values.
+	Values []any `yaml:"values,omitempty"`
 }
 
 // String implements the strings.Stringer interface method.
@@ -801,6 +823,13 @@ func (td *TypeDesc) String() string {
 	if len(ps) != 0 {
 		typeName = fmt.Sprintf("%s(%s)", typeName, strings.Join(ps, ","))
 	}
+	if len(td.Values) != 0 {
+		vs := make([]string, len(td.Values))
+		for i, v := range td.Values {
+			vs[i] = fmt.Sprintf("%v", v)
+		}
+		typeName = fmt.Sprintf("%s[%s]", typeName, strings.Join(vs, ","))
+	}
 	return typeName
 }
 
@@ -815,6 +844,10 @@ func (td *TypeDesc) Validate() error {
 	if td.IsTypeParam && len(td.Params) != 0 {
 		return errors.New("invalid type: param type cannot have parameters")
 	}
+	if len(td.Values) != 0 {
+		_, err := td.literalValues()
+		return err
+	}
 	switch td.TypeName {
 	case "list":
 		if len(td.Params) != 1 {
@@ -844,11 +877,58 @@ func (td *TypeDesc) Validate() error {
 			return fmt.Errorf("invalid type: type expects 0 or 1 parameters, got %d", len(td.Params))
 		}
 		return td.Params[0].Validate()
+	case "union":
+		if len(td.Params) == 0 {
+			return errors.New("invalid type: union expects at least 1 parameter, got 0")
+		}
+		for _, p := range td.Params {
+			if err := p.Validate(); err != nil {
+				return err
+			}
+		}
 	default:
 	}
 	return nil
 }
 
+// literalValues returns the literal values of a string or int literal type as CEL values.
+func (td *TypeDesc) literalValues() ([]ref.Val, error) {
+	if td.TypeName != "string" && td.TypeName != "int" {
+		return nil, fmt.Errorf("invalid type: values are only supported for string and int types, got %s", td.TypeName)
+	}
+	if len(td.Params) != 0 || td.IsTypeParam {
+		return nil, fmt.Errorf("invalid type: %s with values cannot have parameters", td.TypeName)
+	}
+	vals := make([]ref.Val, len(td.Values))
+	for i, v := range td.Values {
+		switch v := v.(type) {
+		case string:
+			if td.TypeName == "string" {
+				vals[i] = types.String(v)
+				continue
+			}
+		case int:
+			if td.TypeName == "int" {
+				vals[i] = types.Int(v)
+				continue
+			}
+		case int64:
+			if td.TypeName == "int" {
+				vals[i] = types.Int(v)
+				continue
+			}
+		case float64:
+			// Numeric values within protobuf and JSON configurations are always doubles.
+			if td.TypeName == "int" && v == float64(int64(v)) {
+				vals[i] = types.Int(int64(v))
+				continue
+			}
+		}
+		return nil, fmt.Errorf("invalid type: unsupported %s value: %v", td.TypeName, v)
+	}
+	return vals, nil
+}
+
 func formatSpecifierImpl(td *TypeDesc, sb *strings.Builder) {
 	if td.IsTypeParam {
 		sb.WriteRune('~')
@@ -883,6 +963,21 @@ func (td *TypeDesc) AsCELType(tp types.Provider) (*types.Type, error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(td.Values) != 0 {
+		vals, _ := td.literalValues()
+		if td.TypeName == "string" {
+			strs := make([]string, len(vals))
+			for i, v := range vals {
+				strs[i] = string(v.(types.String))
+			}
+			return types.NewStringLiteralType(strs...), nil
+		}
+		ints := make([]int64, len(vals))
+		for i, v := range vals {
+			ints[i] = int64(v.(types.Int))
+		}
+		return types.NewIntLiteralType(ints...), nil
+	}
 	switch td.TypeName {
 	case "dyn":
 		return types.DynType, nil
@@ -938,6 +1033,15 @@ func (td *TypeDesc) AsCELType(tp types.Provider) (*types.Type, error) {
 			return nil, err
 		}
 		return types.NewTypeTypeWithParam(pt), nil
+	case "union":
+		members := make([]*types.Type, len(td.Params))
+		for i, p := range td.Params {
+			members[i], err = p.AsCELType(tp)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return types.NewUnionType(members...), nil
 	default:
 		if td.IsTypeParam {
 			return types.NewTypeParamType(td.TypeName), nil
@@ -971,6 +1075,13 @@ func SerializeTypeDesc(t *types.Type) *TypeDesc {
 	if t.Kind() == types.TypeParamKind {
 		return NewTypeParam(typeName)
 	}
+	if t.IsLiteralType() {
+		values := make([]any, len(t.LiteralValues()))
+		for i, v := range t.LiteralValues() {
+			values[i] = v.Value()
+		}
+		return NewLiteralTypeDesc(typeName, values...)
+	}
 	if t != types.NullType && t.IsAssignableType(types.NullType) {
 		if wrapperTypeName, found := wrapperTypes[t.Kind()]; found {
 			return NewTypeDesc(wrapperTypeName)
@@ -344,6 +344,11 @@ func TestConfigValidateErrors(t *testing.T) {
 			in:   NewConfig("invalid extension").AddExtensions(NewExtension("", 0)),
 			want: errors.New("invalid extension"),
 		},
+		{
+			name: "invalid schema",
+			in:   NewConfig("invalid schema").AddSchemas(""),
+			want: errors.New("invalid schema: missing path"),
+		},
 		{
 			name: "invalid context variable",
 			in:   NewConfig("invalid context variable").SetContextVariable(NewContextVariable("")),
@@ -746,6 +751,21 @@ func TestVariableAsCELVariable(t *testing.T) {
 			},
 			want: decls.NewVariable("msg", types.NewNullableType(types.StringType)),
 		},
+		{
+			name: "string literal type",
+			v:    NewVariable("method", NewLiteralTypeDesc("string", "GET", "POST")),
+			want: decls.NewVariable("method", types.NewStringLiteralType("GET", "POST")),
+		},
+		{
+			name: "int literal type",
+			v:    NewVariable("code", NewLiteralTypeDesc("int", 200, 404.0)),
+			want: decls.NewVariable("code", types.NewIntLiteralType(200, 404)),
+		},
+		{
+			name: "union type",
+			v:    NewVariable("id", NewTypeDesc("union", NewTypeDesc("string"), NewTypeDesc("int"))),
+			want: decls.NewVariable("id", types.NewUnionType(types.StringType, types.IntType)),
+		},
 	}
 
 	tp, err := types.NewRegistry()
@@ -783,6 +803,8 @@ func TestTypeDescString(t *testing.T) {
 		{desc: NewTypeDesc("list", NewTypeParam("T")), want: "list(T)"},
 		{desc: NewTypeDesc("type", NewTypeParam("T")), want: "type(T)"},
 		{desc: NewTypeDesc("map", NewTypeDesc("string"), NewTypeParam("T")), want: "map(string,T)"},
+		{desc: NewLiteralTypeDesc("string", "GET", "POST"), want: "string[GET,POST]"},
+		{desc: NewTypeDesc("union", NewTypeDesc("string"), NewTypeDesc("int")), want: "union(string,int)"},
 	}
 	for _, tc := range tests {
 		if tc.desc.String() != tc.want {
@@ -1046,6 +1068,31 @@ func TestTypeDescAsCELTypeErrors(t *testing.T) {
 			t:    &TypeDesc{TypeName: "undefined"},
 			want: errors.New("undefined type"),
 		},
+		{
+			name: "invalid union",
+			t:    &TypeDesc{TypeName: "union"},
+			want: errors.New("expects at least 1 parameter"),
+		},
+		{
+			name: "undefined union member type",
+			t:    NewTypeDesc("union", NewTypeDesc("string"), NewTypeDesc("undefined")),
+			want: errors.New("undefined type name"),
+		},
+		{
+			name: "literal values on unsupported type",
+			t:    NewLiteralTypeDesc("double", 1.5),
+			want: errors.New("values are only supported for string and int types"),
+		},
+		{
+			name: "literal value of the wrong type",
+			t:    NewLiteralTypeDesc("int", "GET"),
+			want: errors.New("unsupported int value: GET"),
+		},
+		{
+			name: "literal values with params",
+			t:    &TypeDesc{TypeName: "string", Params: []*TypeDesc{{TypeName: "int"}}, Values: []any{"a"}},
+			want: errors.New("cannot have parameters"),
+		},
 	}
 	tp, err := types.NewRegistry()
 	if err != nil {
@@ -25,6 +25,7 @@ type internalTypeDesc struct {
 	TypeName    string      `yaml:"type_name"`
 	Params      []*TypeDesc `yaml:"params,omitempty"`
 	IsTypeParam bool        `yaml:"is_type_param,omitempty"`
+	Values      []any       `yaml:"values,omitempty"`
 }
 
 // Embedding TypeDesc in variable causes issues with customizing
@@ -39,6 +40,7 @@ type internalVariable struct {
 	TypeName    string      `yaml:"type_name"`
 	Params      []*TypeDesc `yaml:"params,omitempty"`
 	IsTypeParam bool        `yaml:"is_type_param,omitempty"`
+	Values      []any       `yaml:"values,omitempty"`
 }
 
 // UnmarshalYAML implements yaml.Unmarshal
@@ -55,6 +57,7 @@ func (v *Variable) UnmarshalYAML(n *yaml.Node) error {
 			TypeName:    buf.TypeName,
 			Params:      buf.Params,
 			IsTypeParam: buf.IsTypeParam,
+			Values:      buf.Values,
 		}
 	} else if buf.Type != nil {
 		v.TypeDesc = buf.Type
@@ -74,6 +77,7 @@ func (v *Variable) MarshalYAML() (any, error) {
 		buf.TypeName = t.TypeName
 		buf.Params = t.Params
 		buf.IsTypeParam = t.IsTypeParam
+		buf.Values = t.Values
 	}
 	return &buf, nil
 }
@@ -104,6 +108,7 @@ func (td *TypeDesc) UnmarshalYAML(n *yaml.Node) error {
 	td.TypeName = buf.TypeName
 	td.Params = buf.Params
 	td.IsTypeParam = buf.IsTypeParam
+	td.Values = buf.Values
 	return nil
 }
 
@@ -218,6 +218,38 @@ variables:
       params:
         - type_name: int
         - type_name: string
+`,
+		},
+		{
+			name: "literal and union types",
+			yamlIn: `name: foo
+variables:
+    - name: method
+      type_name: string
+      values: [GET, POST]
+    - name: id
+      type:
+        type_name: union
+        params:
+          - type_name: string
+          - type_name: int
+            values: [1, 2]
+`,
+			yamlOut: `name: foo
+variables:
+    - name: method
+      type_name: string
+      values:
+        - GET
+        - POST
+    - name: id
+      type_name: union
+      params:
+        - type_name: string
+        - type_name: int
+          values:
+            - 1
+            - 2
 `,
 		},
 		{
@@ -0,0 +1,359 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Provider is a types.Provider which exposes the rows of Arrow record batches as CEL struct types.
+//
+// Arrow types are mapped to CEL types as follows:
+//
+//   - The schema becomes a struct type with the name given to NewProvider, and nested `Struct`
+//     fields become struct types named by the path to the field, e.g. `Event.source`. The struct
+//     elements of a list are named with an `Item` suffix, and struct map values with a `Value`
+//     suffix.
+//   - Signed integers become `int`, unsigned integers `uint`, floating point values `double`,
+//     strings `string`, and binary values `bytes`.
+//   - `Date32`, `Date64`, and `Timestamp` become `timestamp`, while `Time32`, `Time64`, and
+//     `Duration` become `duration`.
+//   - `List` and `Map` types become `list(T)` and `map(K, V)`.
+//   - Nullable primitive fields become wrapper types.
+//
+// Rows are read from the columns of the record batch on field access rather than being converted
+// to maps. A field whose value is null is reported as not set by `has()`.
+type Provider struct {
+	name    string
+	structs map[string]*StructType
+}
+
+// NewProvider creates a Provider for the rows of record batches with the given schema, where the
+// name is the CEL type name of a row.
+func NewProvider(name string, schema *Schema) (*Provider, error) {
+	p := &Provider{name: name, structs: map[string]*StructType{}}
+	if _, err := p.declare(name, schema.Fields); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// declare creates the struct type for the given fields along with any nested struct types.
+func (p *Provider) declare(name string, fields []Field) (*StructType, error) {
+	if _, found := p.structs[name]; found {
+		return nil, fmt.Errorf("invalid arrow schema: duplicate type %s", name)
+	}
+	st := newStructType(name, fields)
+	p.structs[name] = st
+	for i, f := range fields {
+		t, err := p.celType(name+"."+f.Name, f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arrow schema: %s field %s: %w", name, f.Name, err)
+		}
+		st.addField(p, i, f, t)
+	}
+	return st, nil
+}
+
+// Types returns the struct types declared by the schema, sorted by name.
+//
+// The types may be registered with a types.Registry, or provided to cel.Types, to make them
+// available within a CEL environment.
+func (p *Provider) Types() []ref.Type {
+	names := make([]string, 0, len(p.structs))
+	for name := range p.structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]ref.Type, len(names))
+	for i, name := range names {
+		out[i] = p.structs[name]
+	}
+	return out
+}
+
+// NewRow returns a CEL value for the row at the given index within the record batch.
+func (p *Provider) NewRow(batch RecordBatch, index int) ref.Val {
+	if index < 0 || index >= batch.NumRows() {
+		return types.NewErr("index out of range: %d", index)
+	}
+	st := p.structs[p.name]
+	columns := make([]Column, len(st.fields))
+	for i := range columns {
+		columns[i] = batch.Column(i)
+	}
+	return &Row{typ: st, columns: columns, index: index}
+}
+
+// EnumValue implements types.Provider.
+func (p *Provider) EnumValue(enumName string) ref.Val {
+	return types.NewErr("unknown enum name '%s'", enumName)
+}
+
+// FindIdent implements types.Provider.
+func (p *Provider) FindIdent(identName string) (ref.Val, bool) {
+	if st, found := p.structs[identName]; found {
+		return st.celType, true
+	}
+	return nil, false
+}
+
+// FindStructType implements types.Provider.
+func (p *Provider) FindStructType(structType string) (*types.Type, bool) {
+	if st, found := p.structs[structType]; found {
+		return types.NewTypeTypeWithParam(st.celType), true
+	}
+	return nil, false
+}
+
+// FindStructFieldNames implements types.Provider.
+func (p *Provider) FindStructFieldNames(structType string) ([]string, bool) {
+	if st, found := p.structs[structType]; found {
+		return st.FieldNames(), true
+	}
+	return nil, false
+}
+
+// FindStructFieldType implements types.Provider.
+func (p *Provider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	if st, found := p.structs[structType]; found {
+		return st.FindFieldType(fieldName)
+	}
+	return nil, false
+}
+
+// NewValue implements types.Provider. Arrow rows are read-only and may not be constructed.
+func (p *Provider) NewValue(structType string, fields map[string]ref.Val) ref.Val {
+	return types.NewErr("unsupported type construction: %s", structType)
+}
+
+// celType returns the CEL type of an Arrow field, declaring nested struct types with the name.
+func (p *Provider) celType(name string, f Field) (*types.Type, error) {
+	t, err := p.valueType(name, f)
+	if err != nil {
+		return nil, err
+	}
+	if f.Nullable && isPrimitive(t) {
+		return types.NewNullableType(t), nil
+	}
+	return t, nil
+}
+
+func (p *Provider) valueType(name string, f Field) (*types.Type, error) {
+	switch f.Type {
+	case Null:
+		return types.NullType, nil
+	case Bool:
+		return types.BoolType, nil
+	case Int8, Int16, Int32, Int64:
+		return types.IntType, nil
+	case Uint8, Uint16, Uint32, Uint64:
+		return types.UintType, nil
+	case Float16, Float32, Float64:
+		return types.DoubleType, nil
+	case String, LargeString:
+		return types.StringType, nil
+	case Binary, LargeBinary, FixedSizeBinary:
+		return types.BytesType, nil
+	case Date32, Date64, Timestamp:
+		return types.TimestampType, nil
+	case Time32, Time64, Duration:
+		return types.DurationType, nil
+	case List, LargeList:
+		if len(f.Children) != 1 {
+			return nil, fmt.Errorf("list must have one child field, got %d", len(f.Children))
+		}
+		elem, err := p.valueType(name+".Item", f.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		return types.NewListType(elem), nil
+	case Map:
+		if len(f.Children) != 2 {
+			return nil, fmt.Errorf("map must have two child fields, got %d", len(f.Children))
+		}
+		key, err := p.valueType(name+".Key", f.Children[0])
+		if err != nil {
+			return nil, err
+		}
+		switch key.Kind() {
+		case types.BoolKind, types.IntKind, types.StringKind, types.UintKind:
+		default:
+			return nil, fmt.Errorf("unsupported map key type: %v", key)
+		}
+		val, err := p.valueType(name+".Value", f.Children[1])
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMapType(key, val), nil
+	case Struct:
+		st, err := p.declare(name, f.Children)
+		if err != nil {
+			return nil, err
+		}
+		return st.celType, nil
+	}
+	return nil, fmt.Errorf("unsupported arrow type: %d", f.Type)
+}
+
+// convert reads the value at the index of the column as a CEL value according to its field.
+func (p *Provider) convert(name string, f Field, col Column, i int) (ref.Val, error) {
+	if col.IsNull(i) {
+		return types.NullValue, nil
+	}
+	switch f.Type {
+	case Date32:
+		return toTimestamp(col.Value(i), 24*time.Hour)
+	case Date64:
+		return toTimestamp(col.Value(i), time.Millisecond)
+	case Timestamp:
+		return toTimestamp(col.Value(i), f.Unit.Duration())
+	case Time32, Time64, Duration:
+		d, ok := toInt(col.Value(i))
+		if !ok {
+			return nil, fmt.Errorf("invalid duration value: %v", col.Value(i))
+		}
+		return types.Duration{Duration: time.Duration(d) * f.Unit.Duration()}, nil
+	case Struct:
+		sc, ok := col.(StructColumn)
+		if !ok {
+			return nil, fmt.Errorf("unsupported struct column: %T", col)
+		}
+		st := p.structs[name]
+		columns := make([]Column, len(st.fields))
+		for j := range columns {
+			columns[j] = sc.Field(j)
+		}
+		return &Row{typ: st, columns: columns, index: i}, nil
+	case List, LargeList:
+		lc, ok := col.(ListColumn)
+		if !ok {
+			return nil, fmt.Errorf("unsupported list column: %T", col)
+		}
+		start, end := lc.ValueOffsets(i)
+		elems := lc.ListValues()
+		vals := make([]ref.Val, 0, end-start)
+		for j := start; j < end; j++ {
+			v, err := p.convert(name+".Item", f.Children[0], elems, j)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return types.NewRefValList(types.DefaultTypeAdapter, vals), nil
+	case Map:
+		mc, ok := col.(MapColumn)
+		if !ok {
+			return nil, fmt.Errorf("unsupported map column: %T", col)
+		}
+		start, end := mc.ValueOffsets(i)
+		keys, items := mc.Keys(), mc.Items()
+		entries := make(map[ref.Val]ref.Val, end-start)
+		for j := start; j < end; j++ {
+			k, err := p.convert(name+".Key", f.Children[0], keys, j)
+			if err != nil {
+				return nil, err
+			}
+			v, err := p.convert(name+".Value", f.Children[1], items, j)
+			if err != nil {
+				return nil, err
+			}
+			entries[k] = v
+		}
+		return types.NewRefValMap(types.DefaultTypeAdapter, entries), nil
+	}
+	v := col.Value(i)
+	switch f.Type {
+	case Int8, Int16, Int32, Int64:
+		if n, ok := toInt(v); ok {
+			return types.Int(n), nil
+		}
+	case Uint8, Uint16, Uint32, Uint64:
+		if n, ok := toUint(v); ok {
+			return types.Uint(n), nil
+		}
+	case Float16, Float32, Float64:
+		switch n := v.(type) {
+		case float32:
+			return types.Double(n), nil
+		case float64:
+			return types.Double(n), nil
+		}
+	case Binary, LargeBinary, FixedSizeBinary:
+		if b, ok := v.(string); ok {
+			return types.Bytes(b), nil
+		}
+	}
+	return types.DefaultTypeAdapter.NativeToValue(v), nil
+}
+
+func toTimestamp(v any, unit time.Duration) (ref.Val, error) {
+	if t, ok := v.(time.Time); ok {
+		return types.Timestamp{Time: t.UTC()}, nil
+	}
+	n, ok := toInt(v)
+	if !ok {
+		return nil, fmt.Errorf("invalid timestamp value: %v", v)
+	}
+	if unit >= time.Second {
+		return types.Timestamp{Time: time.Unix(n*int64(unit/time.Second), 0).UTC()}, nil
+	}
+	return types.Timestamp{Time: time.Unix(0, 0).Add(time.Duration(n) * unit).UTC()}, nil
+}
+
+func isPrimitive(t *types.Type) bool {
+	switch t.Kind() {
+	case types.BoolKind, types.BytesKind, types.DoubleKind, types.IntKind, types.StringKind, types.UintKind:
+		return true
+	}
+	return false
+}
+
+func toInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toUint(v any) (uint64, bool) {
+	switch v := v.(type) {
+	case uint:
+		return uint64(v), true
+	case uint8:
+		return uint64(v), true
+	case uint16:
+		return uint64(v), true
+	case uint32:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	}
+	return 0, false
+}
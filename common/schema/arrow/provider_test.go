@@ -0,0 +1,269 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+)
+
+var eventSchema = &Schema{
+	Fields: []Field{
+		{Name: "id", Type: Int64},
+		{Name: "name", Type: String, Nullable: true},
+		{Name: "size", Type: Uint32},
+		{Name: "score", Type: Float32, Nullable: true},
+		{Name: "time", Type: Timestamp, Unit: Millisecond},
+		{Name: "day", Type: Date32},
+		{Name: "latency", Type: Duration, Unit: Microsecond},
+		{Name: "tags", Type: List, Children: []Field{{Name: "item", Type: String}}},
+		{Name: "source", Type: Struct, Nullable: true, Children: []Field{
+			{Name: "host", Type: String},
+			{Name: "port", Type: Int32, Nullable: true},
+		}},
+		{Name: "labels", Type: Map, Children: []Field{{Name: "key", Type: String}, {Name: "value", Type: Int64}}},
+		{Name: "payload", Type: Binary, Nullable: true},
+	},
+}
+
+func TestProviderFieldTypes(t *testing.T) {
+	p := newTestProvider(t)
+	tests := []struct {
+		structType string
+		field      string
+		want       *types.Type
+	}{
+		{structType: "Event", field: "id", want: types.IntType},
+		{structType: "Event", field: "name", want: types.NewNullableType(types.StringType)},
+		{structType: "Event", field: "size", want: types.UintType},
+		{structType: "Event", field: "score", want: types.NewNullableType(types.DoubleType)},
+		{structType: "Event", field: "time", want: types.TimestampType},
+		{structType: "Event", field: "day", want: types.TimestampType},
+		{structType: "Event", field: "latency", want: types.DurationType},
+		{structType: "Event", field: "tags", want: types.NewListType(types.StringType)},
+		{structType: "Event", field: "source", want: types.NewObjectType("Event.source")},
+		{structType: "Event", field: "labels", want: types.NewMapType(types.StringType, types.IntType)},
+		{structType: "Event", field: "payload", want: types.NewNullableType(types.BytesType)},
+		{structType: "Event.source", field: "port", want: types.NewNullableType(types.IntType)},
+	}
+	for _, tc := range tests {
+		ft, found := p.FindStructFieldType(tc.structType, tc.field)
+		if !found {
+			t.Errorf("FindStructFieldType(%q, %q) not found", tc.structType, tc.field)
+			continue
+		}
+		if !ft.Type.IsExactType(tc.want) {
+			t.Errorf("FindStructFieldType(%q, %q) got %v, wanted %v", tc.structType, tc.field, ft.Type, tc.want)
+		}
+	}
+	if fields, _ := p.FindStructFieldNames("Event.source"); !reflect.DeepEqual(fields, []string{"host", "port"}) {
+		t.Errorf("FindStructFieldNames(Event.source) got %v, wanted [host port]", fields)
+	}
+	var names []string
+	for _, typ := range p.Types() {
+		names = append(names, typ.TypeName())
+	}
+	if want := []string{"Event", "Event.source"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Types() got %v, wanted %v", names, want)
+	}
+}
+
+func TestProviderRows(t *testing.T) {
+	p := newTestProvider(t)
+	batch := newTestBatch()
+	row0 := p.NewRow(batch, 0).(*Row)
+	row1 := p.NewRow(batch, 1).(*Row)
+	tests := []struct {
+		row   *Row
+		field string
+		want  ref.Val
+	}{
+		{row: row0, field: "id", want: types.Int(1)},
+		{row: row1, field: "id", want: types.Int(2)},
+		{row: row0, field: "name", want: types.String("login")},
+		{row: row1, field: "name", want: types.NullValue},
+		{row: row0, field: "size", want: types.Uint(512)},
+		{row: row0, field: "score", want: types.Double(0.5)},
+		{row: row0, field: "time", want: types.Timestamp{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{row: row0, field: "day", want: types.Timestamp{Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		{row: row0, field: "latency", want: types.Duration{Duration: 1500 * time.Microsecond}},
+		{row: row0, field: "tags", want: types.DefaultTypeAdapter.NativeToValue([]string{"a", "b"})},
+		{row: row1, field: "tags", want: types.DefaultTypeAdapter.NativeToValue([]string{"c"})},
+		{row: row1, field: "labels", want: types.DefaultTypeAdapter.NativeToValue(map[string]int64{"retries": 3})},
+		{row: row0, field: "payload", want: types.Bytes("raw")},
+	}
+	for _, tc := range tests {
+		got := tc.row.Get(types.String(tc.field))
+		if got.Equal(tc.want) != types.True {
+			t.Errorf("row[%d].%s got %v, wanted %v", tc.row.Index(), tc.field, got, tc.want)
+		}
+	}
+
+	// Nested structs read from the child columns of the struct column.
+	src := row0.Get(types.String("source"))
+	if src.Type().TypeName() != "Event.source" {
+		t.Fatalf("source got type %v, wanted Event.source", src.Type())
+	}
+	srcRow := src.(traits.Indexer)
+	if host := srcRow.Get(types.String("host")); host != types.String("example.com") {
+		t.Errorf("source.host got %v, wanted example.com", host)
+	}
+	if port := srcRow.Get(types.String("port")); port != types.Int(443) {
+		t.Errorf("source.port got %v, wanted 443", port)
+	}
+
+	// Null fields are not set.
+	if row1.IsSet(types.String("name")) != types.False || row1.IsSet(types.String("source")) != types.False {
+		t.Error("row[1] null fields set, wanted unset")
+	}
+	if row0.IsSet(types.String("name")) != types.True {
+		t.Error("row[0].name unset, wanted set")
+	}
+	if !types.IsError(row0.Get(types.String("undefined"))) {
+		t.Error("row[0].undefined got value, wanted no such field error")
+	}
+	if row0.Equal(p.NewRow(batch, 0)) != types.True || row0.Equal(row1) != types.False {
+		t.Error("row equality got unexpected result")
+	}
+	if !types.IsError(p.NewRow(batch, 2)) {
+		t.Error("NewRow(2) got value, wanted index out of range error")
+	}
+	native, err := row1.ConvertToNative(reflect.TypeOf(map[string]any{}))
+	if err != nil {
+		t.Fatalf("ConvertToNative() failed: %v", err)
+	}
+	if m := native.(map[string]any); m["id"] != int64(2) || len(m) != 7 {
+		t.Errorf("ConvertToNative() got %v, wanted 7 set fields with id 2", m)
+	}
+}
+
+func TestNewProviderErrors(t *testing.T) {
+	tests := []struct {
+		schema *Schema
+		err    string
+	}{
+		{
+			schema: &Schema{Fields: []Field{{Name: "l", Type: List}}},
+			err:    "list must have one child field",
+		},
+		{
+			schema: &Schema{Fields: []Field{{Name: "m", Type: Map, Children: []Field{{Type: Float64}, {Type: Int64}}}}},
+			err:    "unsupported map key type: double",
+		},
+		{
+			schema: &Schema{Fields: []Field{{Name: "u", Type: TypeID(100)}}},
+			err:    "unsupported arrow type: 100",
+		},
+	}
+	for _, tc := range tests {
+		_, err := NewProvider("Event", tc.schema)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("NewProvider() got error %v, wanted error containing %q", err, tc.err)
+		}
+	}
+}
+
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	p, err := NewProvider("Event", eventSchema)
+	if err != nil {
+		t.Fatalf("NewProvider() failed: %v", err)
+	}
+	return p
+}
+
+func newTestBatch() *testBatch {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &testBatch{
+		rows: 2,
+		columns: []Column{
+			&testColumn{values: []any{int64(1), int64(2)}},
+			&testColumn{values: []any{"login", nil}},
+			&testColumn{values: []any{uint32(512), uint32(1024)}},
+			&testColumn{values: []any{float32(0.5), nil}},
+			&testColumn{values: []any{ts.UnixMilli(), ts.UnixMilli()}},
+			&testColumn{values: []any{int32(20455), int32(20456)}},
+			&testColumn{values: []any{int64(1500), int64(10)}},
+			&testListColumn{
+				testColumn: testColumn{values: []any{0, 0}},
+				offsets:    []int{0, 2, 3},
+				elems:      &testColumn{values: []any{"a", "b", "c"}},
+			},
+			&testStructColumn{
+				testColumn: testColumn{values: []any{0, nil}},
+				fields: []Column{
+					&testColumn{values: []any{"example.com", ""}},
+					&testColumn{values: []any{int32(443), nil}},
+				},
+			},
+			&testMapColumn{
+				testColumn: testColumn{values: []any{0, 0}},
+				offsets:    []int{0, 0, 1},
+				keys:       &testColumn{values: []any{"retries"}},
+				items:      &testColumn{values: []any{int64(3)}},
+			},
+			&testColumn{values: []any{[]byte("raw"), nil}},
+		},
+	}
+}
+
+type testBatch struct {
+	rows    int
+	columns []Column
+}
+
+func (b *testBatch) NumRows() int        { return b.rows }
+func (b *testBatch) Column(i int) Column { return b.columns[i] }
+
+type testColumn struct {
+	values []any
+}
+
+func (c *testColumn) Len() int          { return len(c.values) }
+func (c *testColumn) IsNull(i int) bool { return c.values[i] == nil }
+func (c *testColumn) Value(i int) any   { return c.values[i] }
+
+type testStructColumn struct {
+	testColumn
+	fields []Column
+}
+
+func (c *testStructColumn) Field(i int) Column { return c.fields[i] }
+
+type testListColumn struct {
+	testColumn
+	offsets []int
+	elems   Column
+}
+
+func (c *testListColumn) ValueOffsets(i int) (int, int) { return c.offsets[i], c.offsets[i+1] }
+func (c *testListColumn) ListValues() Column            { return c.elems }
+
+type testMapColumn struct {
+	testColumn
+	offsets []int
+	keys    Column
+	items   Column
+}
+
+func (c *testMapColumn) ValueOffsets(i int) (int, int) { return c.offsets[i], c.offsets[i+1] }
+func (c *testMapColumn) Keys() Column                  { return c.keys }
+func (c *testMapColumn) Items() Column                 { return c.items }
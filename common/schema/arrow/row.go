@@ -0,0 +1,224 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package arrow
+
+import (
+	"fmt"
+	"reflect"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+)
+
+var (
+	anyType       = reflect.TypeOf((*any)(nil)).Elem()
+	fieldsMapType = reflect.TypeOf(map[string]any{})
+)
+
+// StructType is a CEL struct type declared by an Arrow schema or struct field.
+//
+// StructType implements both ref.Type and types.StructTypeDescriptor so that it may be registered
+// with a types.Registry.
+type StructType struct {
+	name       string
+	celType    *types.Type
+	fields     []Field
+	fieldTypes map[string]*types.FieldType
+	fieldNames []string
+}
+
+func newStructType(name string, fields []Field) *StructType {
+	return &StructType{
+		name:       name,
+		celType:    types.NewObjectType(name, traits.FieldTesterType|traits.IndexerType),
+		fields:     fields,
+		fieldTypes: map[string]*types.FieldType{},
+	}
+}
+
+func (t *StructType) addField(p *Provider, index int, f Field, ft *types.Type) {
+	path := t.name + "." + f.Name
+	t.fieldTypes[f.Name] = &types.FieldType{
+		Type: ft,
+		IsSet: func(target any) bool {
+			row, ok := target.(*Row)
+			return ok && !row.columns[index].IsNull(row.index)
+		},
+		GetFrom: func(target any) (any, error) {
+			row, ok := target.(*Row)
+			if !ok {
+				return nil, fmt.Errorf("unsupported %s value: %T", t.name, target)
+			}
+			return p.convert(path, f, row.columns[index], row.index)
+		},
+	}
+	t.fieldNames = append(t.fieldNames, f.Name)
+}
+
+// HasTrait implements ref.Type.
+func (t *StructType) HasTrait(trait int) bool {
+	return t.celType.HasTrait(trait)
+}
+
+// TypeName implements ref.Type.
+func (t *StructType) TypeName() string {
+	return t.name
+}
+
+// ReflectType implements types.StructTypeDescriptor. Struct types have no Go type.
+func (t *StructType) ReflectType() reflect.Type {
+	return nil
+}
+
+// FieldNames implements types.StructTypeDescriptor, returning the fields in schema order.
+func (t *StructType) FieldNames() []string {
+	return t.fieldNames
+}
+
+// FindFieldType implements types.StructTypeDescriptor.
+func (t *StructType) FindFieldType(fieldName string) (*types.FieldType, bool) {
+	ft, found := t.fieldTypes[fieldName]
+	return ft, found
+}
+
+// NewValue implements types.StructTypeDescriptor. Arrow rows are read-only and may not be
+// constructed.
+func (t *StructType) NewValue(adapter types.Adapter, fields map[string]ref.Val) ref.Val {
+	return types.NewErr("unsupported type construction: %s", t.name)
+}
+
+// Adapt implements types.StructTypeDescriptor.
+func (t *StructType) Adapt(adapter types.Adapter, value any) ref.Val {
+	if row, ok := value.(*Row); ok && row.typ == t {
+		return row
+	}
+	return adapter.NativeToValue(value)
+}
+
+// Row is a CEL value of a StructType which reads its fields from the columns of a record batch.
+type Row struct {
+	typ     *StructType
+	columns []Column
+	index   int
+}
+
+// ConvertToNative implements ref.Val, supporting conversion to `map[string]any`.
+func (r *Row) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if typeDesc != fieldsMapType && typeDesc != anyType {
+		return nil, fmt.Errorf("type conversion error from '%s' to '%v'", r.typ.name, typeDesc)
+	}
+	out := make(map[string]any, len(r.typ.fieldNames))
+	for _, name := range r.typ.fieldNames {
+		ft := r.typ.fieldTypes[name]
+		if !ft.IsSet(r) {
+			continue
+		}
+		v, err := ft.GetFrom(r)
+		if err != nil {
+			return nil, err
+		}
+		native, err := v.(ref.Val).ConvertToNative(anyType)
+		if err != nil {
+			native = v.(ref.Val).Value()
+		}
+		out[name] = native
+	}
+	return out, nil
+}
+
+// ConvertToType implements ref.Val.
+func (r *Row) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case types.TypeType:
+		return r.typ.celType
+	}
+	if typeVal.TypeName() == r.typ.name {
+		return r
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", r.typ.name, typeVal)
+}
+
+// Equal implements ref.Val, comparing the set fields of rows of the same type.
+func (r *Row) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*Row)
+	if !ok || o.typ.name != r.typ.name {
+		return types.False
+	}
+	for _, name := range r.typ.fieldNames {
+		field := types.String(name)
+		set := r.IsSet(field)
+		if set != o.IsSet(field) {
+			return types.False
+		}
+		if set == types.False {
+			continue
+		}
+		if eq := r.Get(field).Equal(o.Get(field)); eq != types.True {
+			return types.False
+		}
+	}
+	return types.True
+}
+
+// Type implements ref.Val.
+func (r *Row) Type() ref.Type {
+	return r.typ.celType
+}
+
+// Value implements ref.Val, returning the row itself since its fields are read from the columns
+// of the record batch.
+func (r *Row) Value() any {
+	return r
+}
+
+// Index returns the index of the row within its columns.
+func (r *Row) Index() int {
+	return r.index
+}
+
+// IsSet implements traits.FieldTester. Fields with a null value are not set.
+func (r *Row) IsSet(field ref.Val) ref.Val {
+	ft, err := r.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(ft.IsSet(r))
+}
+
+// Get implements traits.Indexer.
+func (r *Row) Get(field ref.Val) ref.Val {
+	ft, err := r.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	v, err := ft.GetFrom(r)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return v.(ref.Val)
+}
+
+func (r *Row) field(field ref.Val) (*types.FieldType, error) {
+	name, ok := field.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("no such overload: %s.%v", r.typ.name, field)
+	}
+	ft, found := r.typ.fieldTypes[string(name)]
+	if !found {
+		return nil, fmt.Errorf("no such field: %s", name)
+	}
+	return ft, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package arrow provides a CEL type provider backed by Apache Arrow schemas.
+//
+// The package describes Arrow schemas and columnar data with a minimal set of interfaces so that
+// rows of a record batch may be evaluated in place by CEL regardless of the Arrow implementation
+// which produced them.
+package arrow
+
+import "time"
+
+// TypeID identifies the Arrow data type of a field.
+type TypeID int
+
+const (
+	// Null is the type of a field whose values are always null.
+	Null TypeID = iota
+	Bool
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+	Float16
+	Float32
+	Float64
+	String
+	LargeString
+	Binary
+	LargeBinary
+	FixedSizeBinary
+	// Date32 values are the number of days since the Unix epoch.
+	Date32
+	// Date64 values are the number of milliseconds since the Unix epoch.
+	Date64
+	// Timestamp values are the number of Field.Unit since the Unix epoch.
+	Timestamp
+	// Time32 and Time64 values are the number of Field.Unit since midnight.
+	Time32
+	Time64
+	// Duration values are a number of Field.Unit.
+	Duration
+	// List fields have a single child field describing the list elements.
+	List
+	LargeList
+	// Struct fields have a child field per struct member.
+	Struct
+	// Map fields have two child fields describing the keys and values.
+	Map
+)
+
+// TimeUnit is the resolution of temporal values.
+type TimeUnit int
+
+const (
+	Second TimeUnit = iota
+	Millisecond
+	Microsecond
+	Nanosecond
+)
+
+// Duration returns the time.Duration of one unit.
+func (u TimeUnit) Duration() time.Duration {
+	switch u {
+	case Millisecond:
+		return time.Millisecond
+	case Microsecond:
+		return time.Microsecond
+	case Nanosecond:
+		return time.Nanosecond
+	}
+	return time.Second
+}
+
+// Field describes a named column or nested member within an Arrow schema.
+type Field struct {
+	Name     string
+	Type     TypeID
+	Nullable bool
+	// Unit is the resolution of Timestamp, Time32, Time64, and Duration fields.
+	Unit TimeUnit
+	// Children are the element field of a List, the member fields of a Struct, or the key and
+	// value fields of a Map.
+	Children []Field
+}
+
+// Schema describes the columns of an Arrow record batch.
+type Schema struct {
+	Fields []Field
+}
+
+// Column is a column of values, or a nested array of values, within a record batch.
+type Column interface {
+	// Len returns the number of values in the column.
+	Len() int
+
+	// IsNull returns whether the value at index i is null.
+	IsNull(i int) bool
+
+	// Value returns the value at index i as a Go value.
+	//
+	// Integers may be returned as any Go integer type, floats as float32 or float64, strings as
+	// string, binary values as []byte, and temporal values as integers in the field's unit. The
+	// Value method is not called for List, Struct, and Map columns.
+	Value(i int) any
+}
+
+// StructColumn is a column of Struct values.
+type StructColumn interface {
+	Column
+
+	// Field returns the column of the i-th member of the struct.
+	Field(i int) Column
+}
+
+// ListColumn is a column of List values.
+type ListColumn interface {
+	Column
+
+	// ValueOffsets returns the start and end offsets of the list at index i within ListValues.
+	ValueOffsets(i int) (start, end int)
+
+	// ListValues returns the column of all list elements.
+	ListValues() Column
+}
+
+// MapColumn is a column of Map values.
+type MapColumn interface {
+	Column
+
+	// ValueOffsets returns the start and end offsets of the map entries at index i within the
+	// Keys and Items columns.
+	ValueOffsets(i int) (start, end int)
+
+	// Keys returns the column of all map keys.
+	Keys() Column
+
+	// Items returns the column of all map values.
+	Items() Column
+}
+
+// RecordBatch is a set of equal length columns described by a Schema.
+type RecordBatch interface {
+	// NumRows returns the number of rows within the batch.
+	NumRows() int
+
+	// Column returns the i-th column of the batch.
+	Column(i int) Column
+}
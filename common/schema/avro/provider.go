@@ -0,0 +1,405 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Provider is a types.Provider which exposes Avro records as CEL struct types.
+//
+// Avro types are mapped to CEL types as follows:
+//
+//   - `record` types become struct types named by the record's full name.
+//   - `boolean`, `int`, `long`, `float`, `double`, `string`, `bytes`, and `fixed` become `bool`,
+//     `int`, `double`, `string`, and `bytes`.
+//   - `enum` types become string literal types of the enum symbols.
+//   - `array` and `map` types become `list(T)` and `map(string, V)`.
+//   - Unions of `null` with a single type become the wrapper type of a primitive, or the type
+//     itself otherwise. Other unions become union types.
+//   - The `timestamp-millis`, `timestamp-micros`, `timestamp-nanos`, `local-timestamp-*`, and `date`
+//     logical types become `timestamp`, while `time-millis`, `time-micros`, and `duration` become
+//     `duration`.
+//
+// Record values are accessed in place rather than being converted to maps. A record may be
+// represented as a `[]any` of field values in schema order, as produced by positional decoders, or
+// as a `map[string]any` keyed by field name. Fields whose value is null are reported as not set by
+// `has()`.
+type Provider struct {
+	records map[string]*RecordType
+}
+
+// NewProvider creates a Provider from the record types declared within the given schemas.
+func NewProvider(schemas ...*Schema) (*Provider, error) {
+	p := &Provider{records: map[string]*RecordType{}}
+	for _, s := range schemas {
+		if err := p.declare(s); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range p.records {
+		for i, f := range r.schema.Fields {
+			t, err := p.celType(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid avro schema: record %s field %s: %w", r.name, f.Name, err)
+			}
+			r.addField(p, i, f, t)
+		}
+	}
+	return p, nil
+}
+
+// declare collects the record types reachable from the schema.
+func (p *Provider) declare(s *Schema) error {
+	switch s.Type {
+	case "record":
+		if existing, found := p.records[s.Name]; found {
+			if existing.schema != s {
+				return fmt.Errorf("invalid avro schema: duplicate record %s", s.Name)
+			}
+			return nil
+		}
+		p.records[s.Name] = newRecordType(s)
+		for _, f := range s.Fields {
+			if err := p.declare(f.Type); err != nil {
+				return err
+			}
+		}
+	case "array":
+		return p.declare(s.Items)
+	case "map":
+		return p.declare(s.Values)
+	case "union":
+		for _, b := range s.Branches {
+			if err := p.declare(b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Types returns the record types declared by the schemas, sorted by name.
+//
+// The types may be registered with a types.Registry, or provided to cel.Types, to make them
+// available within a CEL environment.
+func (p *Provider) Types() []ref.Type {
+	names := make([]string, 0, len(p.records))
+	for name := range p.records {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]ref.Type, len(names))
+	for i, name := range names {
+		out[i] = p.records[name]
+	}
+	return out
+}
+
+// NewRecord returns a CEL value for a record of the named type, where the fields are either a
+// `[]any` in schema order or a `map[string]any` keyed by field name.
+func (p *Provider) NewRecord(recordType string, fields any) ref.Val {
+	r, found := p.records[recordType]
+	if !found {
+		return types.NewErr("unknown type '%s'", recordType)
+	}
+	return r.Adapt(types.DefaultTypeAdapter, fields)
+}
+
+// EnumValue implements types.Provider.
+func (p *Provider) EnumValue(enumName string) ref.Val {
+	return types.NewErr("unknown enum name '%s'", enumName)
+}
+
+// FindIdent implements types.Provider.
+func (p *Provider) FindIdent(identName string) (ref.Val, bool) {
+	if r, found := p.records[identName]; found {
+		return r.celType, true
+	}
+	return nil, false
+}
+
+// FindStructType implements types.Provider.
+func (p *Provider) FindStructType(structType string) (*types.Type, bool) {
+	if r, found := p.records[structType]; found {
+		return types.NewTypeTypeWithParam(r.celType), true
+	}
+	return nil, false
+}
+
+// FindStructFieldNames implements types.Provider.
+func (p *Provider) FindStructFieldNames(structType string) ([]string, bool) {
+	if r, found := p.records[structType]; found {
+		return r.FieldNames(), true
+	}
+	return nil, false
+}
+
+// FindStructFieldType implements types.Provider.
+func (p *Provider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	if r, found := p.records[structType]; found {
+		return r.FindFieldType(fieldName)
+	}
+	return nil, false
+}
+
+// NewValue implements types.Provider.
+func (p *Provider) NewValue(structType string, fields map[string]ref.Val) ref.Val {
+	if r, found := p.records[structType]; found {
+		return r.NewValue(types.DefaultTypeAdapter, fields)
+	}
+	return types.NewErr("unknown type '%s'", structType)
+}
+
+// celType returns the CEL type of an Avro schema.
+func (p *Provider) celType(s *Schema) (*types.Type, error) {
+	switch s.LogicalType {
+	case "timestamp-millis", "timestamp-micros", "timestamp-nanos",
+		"local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos", "date":
+		return types.TimestampType, nil
+	case "time-millis", "time-micros", "duration":
+		return types.DurationType, nil
+	}
+	switch s.Type {
+	case "null":
+		return types.NullType, nil
+	case "boolean":
+		return types.BoolType, nil
+	case "int", "long":
+		return types.IntType, nil
+	case "float", "double":
+		return types.DoubleType, nil
+	case "string":
+		return types.StringType, nil
+	case "bytes", "fixed":
+		return types.BytesType, nil
+	case "enum":
+		return types.NewStringLiteralType(s.Symbols...), nil
+	case "record":
+		return p.records[s.Name].celType, nil
+	case "array":
+		elem, err := p.celType(s.Items)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewListType(elem), nil
+	case "map":
+		val, err := p.celType(s.Values)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewMapType(types.StringType, val), nil
+	case "union":
+		branches := s.nonNullBranches()
+		members := make([]*types.Type, len(branches))
+		for i, b := range branches {
+			t, err := p.celType(b)
+			if err != nil {
+				return nil, err
+			}
+			members[i] = t
+		}
+		switch {
+		case len(members) == 0:
+			return types.NullType, nil
+		case len(members) == 1 && s.IsNullable() && isPrimitive(members[0]):
+			return types.NewNullableType(members[0]), nil
+		}
+		return types.NewUnionType(members...), nil
+	}
+	return nil, fmt.Errorf("unsupported avro type: %s", s.Type)
+}
+
+// convert converts a decoded Avro value to a CEL value according to its schema.
+func (p *Provider) convert(s *Schema, v any) (ref.Val, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.NullValue, nil
+	case ref.Val:
+		return v, nil
+	}
+	switch s.LogicalType {
+	case "timestamp-millis", "local-timestamp-millis":
+		return convertTime(v, time.Millisecond)
+	case "timestamp-micros", "local-timestamp-micros":
+		return convertTime(v, time.Microsecond)
+	case "timestamp-nanos", "local-timestamp-nanos":
+		return convertTime(v, time.Nanosecond)
+	case "date":
+		return convertTime(v, 24*time.Hour)
+	case "time-millis":
+		return convertDuration(v, time.Millisecond)
+	case "time-micros":
+		return convertDuration(v, time.Microsecond)
+	case "duration":
+		return convertAvroDuration(v)
+	}
+	switch s.Type {
+	case "int", "long":
+		if i, ok := toInt(v); ok {
+			return types.Int(i), nil
+		}
+	case "float", "double":
+		switch f := v.(type) {
+		case float32:
+			return types.Double(f), nil
+		case float64:
+			return types.Double(f), nil
+		}
+	case "bytes", "fixed":
+		switch b := v.(type) {
+		case []byte:
+			return types.Bytes(b), nil
+		case string:
+			return types.Bytes(b), nil
+		}
+	case "enum":
+		if i, ok := toInt(v); ok && i >= 0 && i < int64(len(s.Symbols)) {
+			return types.String(s.Symbols[i]), nil
+		}
+	case "record":
+		return p.records[s.Name].Adapt(types.DefaultTypeAdapter, v), nil
+	case "array":
+		if reflect.TypeOf(v).Kind() == reflect.Slice {
+			return types.NewDynamicList(&valueAdapter{p: p, s: s.Items}, v), nil
+		}
+	case "map":
+		if m, ok := v.(map[string]any); ok {
+			return types.NewStringInterfaceMap(&valueAdapter{p: p, s: s.Values}, m), nil
+		}
+	case "union":
+		return p.convertUnion(s, v)
+	}
+	return types.DefaultTypeAdapter.NativeToValue(v), nil
+}
+
+// convertUnion converts a union value, which may either be the value of a branch, or a single
+// entry map from the branch type name to the value as produced by some decoders.
+func (p *Provider) convertUnion(s *Schema, v any) (ref.Val, error) {
+	branches := s.nonNullBranches()
+	if m, ok := v.(map[string]any); ok && len(m) == 1 {
+		for name, bv := range m {
+			for _, b := range branches {
+				if b.Name == name || (b.Name == "" && b.Type == name) {
+					return p.convert(b, bv)
+				}
+			}
+		}
+	}
+	if len(branches) == 1 {
+		return p.convert(branches[0], v)
+	}
+	for _, b := range branches {
+		if b.Type == "record" {
+			switch v.(type) {
+			case []any, map[string]any:
+				return p.convert(b, v)
+			}
+		}
+	}
+	return types.DefaultTypeAdapter.NativeToValue(v), nil
+}
+
+// valueAdapter converts the elements of Avro arrays and maps on access.
+type valueAdapter struct {
+	p *Provider
+	s *Schema
+}
+
+// NativeToValue implements types.Adapter.
+func (a *valueAdapter) NativeToValue(value any) ref.Val {
+	val, err := a.p.convert(a.s, value)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return val
+}
+
+func convertTime(v any, unit time.Duration) (ref.Val, error) {
+	if t, ok := v.(time.Time); ok {
+		return types.Timestamp{Time: t.UTC()}, nil
+	}
+	i, ok := toInt(v)
+	if !ok {
+		return nil, fmt.Errorf("invalid timestamp value: %v", v)
+	}
+	if unit >= time.Second {
+		return types.Timestamp{Time: time.Unix(i*int64(unit/time.Second), 0).UTC()}, nil
+	}
+	return types.Timestamp{Time: time.Unix(0, 0).Add(time.Duration(i) * unit).UTC()}, nil
+}
+
+func convertDuration(v any, unit time.Duration) (ref.Val, error) {
+	if d, ok := v.(time.Duration); ok {
+		return types.Duration{Duration: d}, nil
+	}
+	i, ok := toInt(v)
+	if !ok {
+		return nil, fmt.Errorf("invalid duration value: %v", v)
+	}
+	return types.Duration{Duration: time.Duration(i) * unit}, nil
+}
+
+// convertAvroDuration converts the Avro `duration` logical type, a fixed value containing the
+// months, days, and milliseconds of the duration as little-endian unsigned integers.
+func convertAvroDuration(v any) (ref.Val, error) {
+	if d, ok := v.(time.Duration); ok {
+		return types.Duration{Duration: d}, nil
+	}
+	b, ok := v.([]byte)
+	if !ok || len(b) != 12 {
+		return nil, fmt.Errorf("invalid duration value: %v", v)
+	}
+	months := binary.LittleEndian.Uint32(b[0:4])
+	days := binary.LittleEndian.Uint32(b[4:8])
+	millis := binary.LittleEndian.Uint32(b[8:12])
+	if months != 0 {
+		return nil, fmt.Errorf("unsupported duration value: %d months", months)
+	}
+	d := time.Duration(days)*24*time.Hour + time.Duration(millis)*time.Millisecond
+	return types.Duration{Duration: d}, nil
+}
+
+func isPrimitive(t *types.Type) bool {
+	switch t.Kind() {
+	case types.BoolKind, types.BytesKind, types.DoubleKind, types.IntKind, types.StringKind, types.UintKind:
+		return !t.IsLiteralType()
+	}
+	return false
+}
+
+func toInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
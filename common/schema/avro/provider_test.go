@@ -0,0 +1,259 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+const (
+	order    = "com.example.shop.Order"
+	customer = "com.example.shop.Customer"
+	lineItem = "com.example.shop.LineItem"
+)
+
+func TestParse(t *testing.T) {
+	s := parseTestSchema(t)
+	if s.Type != "record" || s.Name != order || s.Doc != "A customer order." {
+		t.Errorf("Parse() got %s %s %q, wanted record %s", s.Type, s.Name, s.Doc, order)
+	}
+	cust := s.Fields[1].Type
+	if cust.Name != customer {
+		t.Errorf("customer got name %q, wanted %s", cust.Name, customer)
+	}
+	// The recursive reference resolves to the enclosing record declaration.
+	if referrer := cust.Fields[2].Type; !referrer.IsNullable() || referrer.Branches[1] != cust {
+		t.Errorf("referrer got %v, wanted nullable reference to Customer", referrer)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{doc: `{"type": "record"`, err: "invalid avro schema"},
+		{doc: `{"type": "record", "fields": []}`, err: "record missing name"},
+		{doc: `{"type": "record", "name": "A", "fields": [{"type": "int"}]}`, err: "field without a name"},
+		{doc: `{"type": "record", "name": "A", "fields": [{"name": "b", "type": "B"}]}`, err: `undefined type "B"`},
+		{doc: `["int", {"type": "enum", "name": "E", "symbols": []}, {"type": "fixed", "name": "E", "size": 1}]`, err: `duplicate type "E"`},
+		{doc: `{"type": "enum", "name": "E", "symbols": [1]}`, err: "invalid symbol"},
+	}
+	for _, tc := range tests {
+		_, err := Parse([]byte(tc.doc))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Parse(%q) got error %v, wanted error containing %q", tc.doc, err, tc.err)
+		}
+	}
+}
+
+func TestProviderFieldTypes(t *testing.T) {
+	p := newTestProvider(t)
+	tests := []struct {
+		structType string
+		field      string
+		want       *types.Type
+	}{
+		{structType: order, field: "id", want: types.IntType},
+		{structType: order, field: "customer", want: types.NewObjectType(customer)},
+		{structType: order, field: "status", want: types.NewStringLiteralType("NEW", "SHIPPED", "CANCELLED")},
+		{structType: order, field: "total", want: types.DoubleType},
+		{structType: order, field: "discount", want: types.NewNullableType(types.DoubleType)},
+		{structType: order, field: "items", want: types.NewListType(types.NewObjectType(lineItem))},
+		{structType: order, field: "attributes", want: types.NewMapType(types.StringType, types.StringType)},
+		{structType: order, field: "createdAt", want: types.TimestampType},
+		{structType: order, field: "shipBy", want: types.TimestampType},
+		{structType: order, field: "window", want: types.DurationType},
+		{structType: order, field: "reference", want: types.NewUnionType(types.StringType, types.IntType)},
+		{structType: customer, field: "email", want: types.NewNullableType(types.StringType)},
+		{structType: customer, field: "referrer", want: types.NewObjectType(customer)},
+		{structType: lineItem, field: "quantity", want: types.IntType},
+	}
+	for _, tc := range tests {
+		ft, found := p.FindStructFieldType(tc.structType, tc.field)
+		if !found {
+			t.Errorf("FindStructFieldType(%q, %q) not found", tc.structType, tc.field)
+			continue
+		}
+		if !ft.Type.IsExactType(tc.want) {
+			t.Errorf("FindStructFieldType(%q, %q) got %v, wanted %v", tc.structType, tc.field, ft.Type, tc.want)
+		}
+	}
+	if fields, _ := p.FindStructFieldNames(lineItem); !reflect.DeepEqual(fields, []string{"sku", "quantity"}) {
+		t.Errorf("FindStructFieldNames(LineItem) got %v, wanted [sku quantity]", fields)
+	}
+	if _, found := p.FindStructType("com.example.shop.Status"); found {
+		t.Error("FindStructType(Status) found, wanted only record types")
+	}
+	var names []string
+	for _, typ := range p.Types() {
+		names = append(names, typ.TypeName())
+	}
+	if want := []string{customer, lineItem, order}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Types() got %v, wanted %v", names, want)
+	}
+}
+
+func TestProviderFieldValues(t *testing.T) {
+	p := newTestProvider(t)
+	createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	window := []byte{0, 0, 0, 0, 2, 0, 0, 0, 0xe8, 0x03, 0, 0}
+	// Positional record values, in schema order.
+	positional := []any{
+		int64(42),
+		[]any{"Ada", nil, nil},
+		int32(1),
+		float64(99.5),
+		nil,
+		[]any{[]any{"A-1", int32(2)}},
+		map[string]any{"gift": "true"},
+		createdAt.UnixMilli(),
+		int32(20516),
+		window,
+		map[string]any{"long": int64(7)},
+	}
+	// Named record values, as produced by generic decoders.
+	named := map[string]any{
+		"id":        int64(43),
+		"customer":  map[string]any{"name": "Bob", "email": map[string]any{"string": "bob@example.com"}},
+		"status":    "SHIPPED",
+		"discount":  float32(0.5),
+		"createdAt": createdAt,
+		"reference": "ref-1",
+	}
+	tests := []struct {
+		field  string
+		record any
+		want   ref.Val
+		err    string
+	}{
+		{field: "id", record: positional, want: types.Int(42)},
+		{field: "id", record: named, want: types.Int(43)},
+		{field: "status", record: positional, want: types.String("SHIPPED")},
+		{field: "status", record: named, want: types.String("SHIPPED")},
+		{field: "discount", record: named, want: types.Double(0.5)},
+		{field: "createdAt", record: positional, want: types.Timestamp{Time: createdAt}},
+		{field: "createdAt", record: named, want: types.Timestamp{Time: createdAt}},
+		{field: "shipBy", record: positional, want: types.Timestamp{Time: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)}},
+		{field: "window", record: positional, want: types.Duration{Duration: 48*time.Hour + time.Second}},
+		{field: "reference", record: positional, want: types.Int(7)},
+		{field: "reference", record: named, want: types.String("ref-1")},
+		{field: "attributes", record: positional, want: types.DefaultTypeAdapter.NativeToValue(map[string]string{"gift": "true"})},
+		{field: "total", record: named, err: "no such key: total"},
+		{field: "total", record: "order", err: "unsupported com.example.shop.Order value"},
+		{field: "window", record: []any{9: []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}}, err: "unsupported duration value: 1 months"},
+	}
+	for _, tc := range tests {
+		ft, found := p.FindStructFieldType(order, tc.field)
+		if !found {
+			t.Fatalf("FindStructFieldType(%q) not found", tc.field)
+		}
+		got, err := ft.GetFrom(tc.record)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("GetFrom(%s) got %v, %v, wanted error containing %q", tc.field, got, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetFrom(%s) failed: %v", tc.field, err)
+		}
+		if got.(ref.Val).Equal(tc.want) != types.True {
+			t.Errorf("GetFrom(%s) got %v, wanted %v", tc.field, got, tc.want)
+		}
+	}
+
+	// Nested records are accessed in place.
+	rec := p.NewRecord(order, positional).(*Record)
+	cust := rec.Get(types.String("customer")).(*Record)
+	if cust.Get(types.String("name")) != types.String("Ada") {
+		t.Errorf("customer.name got %v, wanted Ada", cust.Get(types.String("name")))
+	}
+	if cust.IsSet(types.String("email")) != types.False {
+		t.Error("customer.email set, wanted null field to be unset")
+	}
+	item := rec.Get(types.String("items")).(interface{ Get(ref.Val) ref.Val }).Get(types.Int(0))
+	if item.Type().TypeName() != lineItem {
+		t.Errorf("items[0] got type %v, wanted %s", item.Type(), lineItem)
+	}
+	if q := item.(*Record).Get(types.String("quantity")); q != types.Int(2) {
+		t.Errorf("items[0].quantity got %v, wanted 2", q)
+	}
+	namedCust := p.NewRecord(order, named).(*Record).Get(types.String("customer")).(*Record)
+	if email := namedCust.Get(types.String("email")); email != types.String("bob@example.com") {
+		t.Errorf("customer.email got %v, wanted bob@example.com", email)
+	}
+	if !types.IsError(cust.Get(types.String("phone"))) {
+		t.Error("customer.phone got value, wanted no such field error")
+	}
+	if !types.IsError(p.NewRecord("Unknown", positional)) {
+		t.Error("NewRecord(Unknown) got value, wanted error")
+	}
+}
+
+func TestProviderNewValue(t *testing.T) {
+	p := newTestProvider(t)
+	item := p.NewValue(lineItem, map[string]ref.Val{"sku": types.String("A-1"), "quantity": types.Int(2)})
+	if types.IsError(item) {
+		t.Fatalf("NewValue(LineItem) failed: %v", item)
+	}
+	positional := p.NewRecord(lineItem, []any{"A-1", int32(2)})
+	if item.Equal(positional) != types.True {
+		t.Errorf("%v.Equal(%v) returned false", item, positional)
+	}
+	other := p.NewRecord(lineItem, []any{"A-1", int32(3)})
+	if item.Equal(other) != types.False {
+		t.Errorf("%v.Equal(%v) returned true", item, other)
+	}
+	native, err := positional.ConvertToNative(reflect.TypeOf(map[string]any{}))
+	if err != nil {
+		t.Fatalf("ConvertToNative() failed: %v", err)
+	}
+	if want := map[string]any{"sku": "A-1", "quantity": int64(2)}; !reflect.DeepEqual(native, want) {
+		t.Errorf("ConvertToNative() got %v, wanted %v", native, want)
+	}
+	if out := p.NewValue(lineItem, map[string]ref.Val{"price": types.Int(1)}); !types.IsError(out) {
+		t.Errorf("NewValue(LineItem{price: 1}) got %v, wanted error", out)
+	}
+}
+
+func parseTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	data, err := os.ReadFile("testdata/order.avsc")
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	return s
+}
+
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	p, err := NewProvider(parseTestSchema(t))
+	if err != nil {
+		t.Fatalf("NewProvider() failed: %v", err)
+	}
+	return p
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"fmt"
+	"reflect"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+)
+
+var (
+	anyType       = reflect.TypeOf((*any)(nil)).Elem()
+	fieldsMapType = reflect.TypeOf(map[string]any{})
+)
+
+// RecordType is a CEL struct type declared by an Avro record schema.
+//
+// RecordType implements both ref.Type and types.StructTypeDescriptor so that it may be registered
+// with a types.Registry.
+type RecordType struct {
+	name       string
+	schema     *Schema
+	celType    *types.Type
+	fields     map[string]*types.FieldType
+	fieldNames []string
+}
+
+func newRecordType(s *Schema) *RecordType {
+	return &RecordType{
+		name:    s.Name,
+		schema:  s,
+		celType: types.NewObjectType(s.Name, traits.FieldTesterType|traits.IndexerType),
+		fields:  map[string]*types.FieldType{},
+	}
+}
+
+func (t *RecordType) addField(p *Provider, index int, f *Field, ft *types.Type) {
+	// lookup returns the raw value of the field from either a positional or named representation.
+	lookup := func(target any) (any, bool) {
+		switch fields := target.(type) {
+		case []any:
+			if index < len(fields) {
+				return fields[index], true
+			}
+		case map[string]any:
+			v, found := fields[f.Name]
+			return v, found
+		}
+		return nil, false
+	}
+	t.fields[f.Name] = &types.FieldType{
+		Type: ft,
+		IsSet: func(target any) bool {
+			v, found := lookup(target)
+			return found && v != nil
+		},
+		GetFrom: func(target any) (any, error) {
+			switch target.(type) {
+			case []any, map[string]any:
+			default:
+				return nil, fmt.Errorf("unsupported %s value: %T", t.name, target)
+			}
+			v, found := lookup(target)
+			if !found {
+				return nil, fmt.Errorf("no such key: %s", f.Name)
+			}
+			return p.convert(f.Type, v)
+		},
+	}
+	t.fieldNames = append(t.fieldNames, f.Name)
+}
+
+// HasTrait implements ref.Type.
+func (t *RecordType) HasTrait(trait int) bool {
+	return t.celType.HasTrait(trait)
+}
+
+// TypeName implements ref.Type.
+func (t *RecordType) TypeName() string {
+	return t.name
+}
+
+// ReflectType implements types.StructTypeDescriptor. Record types have no Go type.
+func (t *RecordType) ReflectType() reflect.Type {
+	return nil
+}
+
+// FieldNames implements types.StructTypeDescriptor, returning the fields in schema order.
+func (t *RecordType) FieldNames() []string {
+	return t.fieldNames
+}
+
+// FindFieldType implements types.StructTypeDescriptor.
+func (t *RecordType) FindFieldType(fieldName string) (*types.FieldType, bool) {
+	ft, found := t.fields[fieldName]
+	return ft, found
+}
+
+// NewValue implements types.StructTypeDescriptor.
+func (t *RecordType) NewValue(adapter types.Adapter, fields map[string]ref.Val) ref.Val {
+	values := make(map[string]any, len(fields))
+	for name, val := range fields {
+		if _, found := t.fields[name]; !found {
+			return types.NewErr("no such field: %s", name)
+		}
+		values[name] = val
+	}
+	return &Record{typ: t, fields: values}
+}
+
+// Adapt implements types.StructTypeDescriptor, wrapping a `[]any` or `map[string]any` value as a
+// record without copying it.
+func (t *RecordType) Adapt(adapter types.Adapter, value any) ref.Val {
+	switch value.(type) {
+	case []any, map[string]any:
+		return &Record{typ: t, fields: value}
+	}
+	return adapter.NativeToValue(value)
+}
+
+// Record is a CEL value of a RecordType backed by the decoded field values.
+type Record struct {
+	typ    *RecordType
+	fields any
+}
+
+// ConvertToNative implements ref.Val, supporting conversion to `map[string]any`, or to `[]any`
+// when the record is positional.
+func (r *Record) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case anyType:
+		return r.fields, nil
+	case reflect.TypeOf(r.fields):
+		return r.fields, nil
+	case fieldsMapType:
+		out := make(map[string]any, len(r.typ.fieldNames))
+		for _, name := range r.typ.fieldNames {
+			ft := r.typ.fields[name]
+			if !ft.IsSet(r.fields) {
+				continue
+			}
+			v, err := ft.GetFrom(r.fields)
+			if err != nil {
+				return nil, err
+			}
+			native, err := v.(ref.Val).ConvertToNative(anyType)
+			if err != nil {
+				native = v.(ref.Val).Value()
+			}
+			out[name] = native
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("type conversion error from '%s' to '%v'", r.typ.name, typeDesc)
+}
+
+// ConvertToType implements ref.Val.
+func (r *Record) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case types.TypeType:
+		return r.typ.celType
+	}
+	if typeVal.TypeName() == r.typ.name {
+		return r
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", r.typ.name, typeVal)
+}
+
+// Equal implements ref.Val, comparing the set fields of records of the same type.
+func (r *Record) Equal(other ref.Val) ref.Val {
+	o, ok := other.(*Record)
+	if !ok || o.typ.name != r.typ.name {
+		return types.False
+	}
+	for _, name := range r.typ.fieldNames {
+		field := types.String(name)
+		set := r.IsSet(field)
+		if set != o.IsSet(field) {
+			return types.False
+		}
+		if set == types.False {
+			continue
+		}
+		if eq := r.Get(field).Equal(o.Get(field)); eq != types.True {
+			return types.False
+		}
+	}
+	return types.True
+}
+
+// Type implements ref.Val.
+func (r *Record) Type() ref.Type {
+	return r.typ.celType
+}
+
+// Value implements ref.Val, returning the backing field values.
+func (r *Record) Value() any {
+	return r.fields
+}
+
+// IsSet implements traits.FieldTester. Fields with a null value are not set.
+func (r *Record) IsSet(field ref.Val) ref.Val {
+	ft, err := r.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(ft.IsSet(r.fields))
+}
+
+// Get implements traits.Indexer.
+func (r *Record) Get(field ref.Val) ref.Val {
+	ft, err := r.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	v, err := ft.GetFrom(r.fields)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return v.(ref.Val)
+}
+
+func (r *Record) field(field ref.Val) (*types.FieldType, error) {
+	name, ok := field.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("no such overload: %s.%v", r.typ.name, field)
+	}
+	ft, found := r.typ.fields[string(name)]
+	if !found {
+		return nil, fmt.Errorf("no such field: %s", name)
+	}
+	return ft, nil
+}
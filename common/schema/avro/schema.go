@@ -0,0 +1,225 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package avro provides a CEL type provider backed by Apache Avro schemas.
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema is a parsed Avro schema.
+type Schema struct {
+	// Type is the Avro type name: a primitive type name, `record`, `enum`, `fixed`, `array`, `map`,
+	// or `union`.
+	Type string
+	// Name is the full name of a named type, i.e. a record, enum, or fixed type.
+	Name string
+	// LogicalType is the logical type annotation of the schema, if any.
+	LogicalType string
+	Doc         string
+
+	// Fields are the fields of a record.
+	Fields []*Field
+	// Symbols are the symbols of an enum.
+	Symbols []string
+	// Size is the number of bytes in a fixed value.
+	Size int
+	// Items is the schema of the elements of an array.
+	Items *Schema
+	// Values is the schema of the values of a map.
+	Values *Schema
+	// Branches are the schemas of the members of a union.
+	Branches []*Schema
+}
+
+// Field is a field of an Avro record.
+type Field struct {
+	Name string
+	Doc  string
+	Type *Schema
+}
+
+// IsNullable returns whether the schema is a union which includes the `null` type.
+func (s *Schema) IsNullable() bool {
+	if s.Type != "union" {
+		return false
+	}
+	for _, b := range s.Branches {
+		if b.Type == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNullBranches returns the union branches which are not the `null` type.
+func (s *Schema) nonNullBranches() []*Schema {
+	var out []*Schema
+	for _, b := range s.Branches {
+		if b.Type != "null" {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+var primitiveTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+// Parse parses an Avro schema from its JSON representation.
+func Parse(data []byte) (*Schema, error) {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	p := &parser{names: map[string]*Schema{}}
+	return p.parse(raw, "")
+}
+
+type parser struct {
+	names map[string]*Schema
+}
+
+func (p *parser) parse(raw any, namespace string) (*Schema, error) {
+	switch v := raw.(type) {
+	case string:
+		if primitiveTypes[v] {
+			return &Schema{Type: v}, nil
+		}
+		if s, found := p.names[fullName(v, namespace)]; found {
+			return s, nil
+		}
+		if s, found := p.names[v]; found {
+			return s, nil
+		}
+		return nil, fmt.Errorf("invalid avro schema: undefined type %q", v)
+	case []any:
+		u := &Schema{Type: "union"}
+		for _, b := range v {
+			branch, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			u.Branches = append(u.Branches, branch)
+		}
+		return u, nil
+	case map[string]any:
+		return p.parseComplex(v, namespace)
+	}
+	return nil, fmt.Errorf("invalid avro schema: unexpected value %v", raw)
+}
+
+func (p *parser) parseComplex(obj map[string]any, namespace string) (*Schema, error) {
+	typeName, _ := obj["type"].(string)
+	s := &Schema{Type: typeName}
+	s.LogicalType, _ = obj["logicalType"].(string)
+	s.Doc, _ = obj["doc"].(string)
+	switch typeName {
+	case "record", "error", "enum", "fixed":
+		name, _ := obj["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("invalid avro schema: %s missing name", typeName)
+		}
+		if ns, found := obj["namespace"].(string); found && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		s.Name = fullName(name, namespace)
+		if idx := strings.LastIndex(s.Name, "."); idx >= 0 {
+			namespace = s.Name[:idx]
+		}
+		if _, found := p.names[s.Name]; found {
+			return nil, fmt.Errorf("invalid avro schema: duplicate type %q", s.Name)
+		}
+		// Named types are declared before their fields are parsed to support recursive types.
+		p.names[s.Name] = s
+	}
+	switch typeName {
+	case "record", "error":
+		s.Type = "record"
+		fields, _ := obj["fields"].([]any)
+		for _, f := range fields {
+			fobj, ok := f.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid avro schema: record %s has invalid field %v", s.Name, f)
+			}
+			name, _ := fobj["name"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("invalid avro schema: record %s has field without a name", s.Name)
+			}
+			ft, err := p.parse(fobj["type"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			doc, _ := fobj["doc"].(string)
+			s.Fields = append(s.Fields, &Field{Name: name, Doc: doc, Type: ft})
+		}
+	case "enum":
+		symbols, _ := obj["symbols"].([]any)
+		for _, sym := range symbols {
+			str, ok := sym.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid avro schema: enum %s has invalid symbol %v", s.Name, sym)
+			}
+			s.Symbols = append(s.Symbols, str)
+		}
+	case "fixed":
+		size, _ := obj["size"].(float64)
+		s.Size = int(size)
+	case "array":
+		items, err := p.parse(obj["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.Items = items
+	case "map":
+		values, err := p.parse(obj["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		s.Values = values
+	default:
+		if !primitiveTypes[typeName] {
+			// A complex schema may also wrap a reference to a named type.
+			ref, err := p.parse(obj["type"], namespace)
+			if err != nil {
+				return nil, err
+			}
+			if s.LogicalType == "" {
+				return ref, nil
+			}
+			cpy := *ref
+			cpy.LogicalType = s.LogicalType
+			return &cpy, nil
+		}
+	}
+	return s, nil
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+)
+
+var (
+	anyType       = reflect.TypeOf((*any)(nil)).Elem()
+	jsonValueType = reflect.TypeOf(map[string]any{})
+)
+
+// ObjectType is a CEL struct type declared by an object schema.
+//
+// ObjectType implements both ref.Type and types.StructTypeDescriptor so that it may be registered
+// with a types.Registry.
+type ObjectType struct {
+	name       string
+	schema     *Schema
+	celType    *types.Type
+	fields     map[string]*objectField
+	fieldNames []string
+}
+
+type objectField struct {
+	schema    *Schema
+	t         *types.Type
+	fieldType *types.FieldType
+}
+
+func newObjectType(name string, s *Schema) *ObjectType {
+	return &ObjectType{
+		name:    name,
+		schema:  s,
+		celType: types.NewObjectType(name, traits.FieldTesterType|traits.IndexerType),
+		fields:  map[string]*objectField{},
+	}
+}
+
+func (t *ObjectType) addField(p *Provider, name string, s *Schema, ft *types.Type, required bool) {
+	isSet := func(target any) bool {
+		fields, ok := target.(map[string]any)
+		if !ok {
+			return false
+		}
+		v, found := fields[name]
+		return found && v != nil
+	}
+	getFrom := func(target any) (any, error) {
+		fields, ok := target.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unsupported %s value: %T", t.name, target)
+		}
+		v, found := fields[name]
+		if !found {
+			switch {
+			case s.Default != nil:
+				v = s.Default
+			case required:
+				return nil, fmt.Errorf("missing required field: %s", name)
+			default:
+				return nil, fmt.Errorf("no such key: %s", name)
+			}
+		}
+		return p.convert(ft, v)
+	}
+	t.fields[name] = &objectField{
+		schema: s,
+		t:      ft,
+		fieldType: &types.FieldType{
+			Type:    ft,
+			IsSet:   isSet,
+			GetFrom: getFrom,
+		},
+	}
+	t.fieldNames = append(t.fieldNames, name)
+}
+
+// HasTrait implements ref.Type.
+func (t *ObjectType) HasTrait(trait int) bool {
+	return t.celType.HasTrait(trait)
+}
+
+// TypeName implements ref.Type.
+func (t *ObjectType) TypeName() string {
+	return t.name
+}
+
+// ReflectType implements types.StructTypeDescriptor. Object types have no Go type.
+func (t *ObjectType) ReflectType() reflect.Type {
+	return nil
+}
+
+// FieldNames implements types.StructTypeDescriptor.
+func (t *ObjectType) FieldNames() []string {
+	return t.fieldNames
+}
+
+// FindFieldType implements types.StructTypeDescriptor.
+func (t *ObjectType) FindFieldType(fieldName string) (*types.FieldType, bool) {
+	f, found := t.fields[fieldName]
+	if !found {
+		return nil, false
+	}
+	return f.fieldType, true
+}
+
+// NewValue implements types.StructTypeDescriptor.
+func (t *ObjectType) NewValue(adapter types.Adapter, fields map[string]ref.Val) ref.Val {
+	values := make(map[string]any, len(fields))
+	for name, val := range fields {
+		if _, found := t.fields[name]; !found {
+			return types.NewErr("no such field: %s", name)
+		}
+		values[name] = val
+	}
+	return &Object{typ: t, fields: values}
+}
+
+// Adapt implements types.StructTypeDescriptor, converting a `map[string]any` value to an object.
+func (t *ObjectType) Adapt(adapter types.Adapter, value any) ref.Val {
+	if fields, ok := value.(map[string]any); ok {
+		return &Object{typ: t, fields: fields}
+	}
+	return adapter.NativeToValue(value)
+}
+
+// Object is a CEL value of an ObjectType, backed by a map of field names to values.
+type Object struct {
+	typ    *ObjectType
+	fields map[string]any
+}
+
+// ConvertToNative implements ref.Val, supporting conversion to `map[string]any`.
+func (o *Object) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if typeDesc != jsonValueType && typeDesc != anyType {
+		return nil, fmt.Errorf("type conversion error from '%s' to '%v'", o.typ.name, typeDesc)
+	}
+	out := make(map[string]any, len(o.fields))
+	for name, v := range o.fields {
+		val, isVal := v.(ref.Val)
+		if !isVal {
+			out[name] = v
+			continue
+		}
+		native, err := val.ConvertToNative(anyType)
+		if err != nil {
+			native = val.Value()
+		}
+		out[name] = native
+	}
+	return out, nil
+}
+
+// ConvertToType implements ref.Val.
+func (o *Object) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case types.TypeType:
+		return o.typ.celType
+	}
+	if typeVal.TypeName() == o.typ.name {
+		return o
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", o.typ.name, typeVal)
+}
+
+// Equal implements ref.Val, comparing the set fields of objects of the same type.
+func (o *Object) Equal(other ref.Val) ref.Val {
+	otherObj, ok := other.(*Object)
+	if !ok || otherObj.typ.name != o.typ.name {
+		return types.False
+	}
+	for _, name := range o.typ.fieldNames {
+		field := types.String(name)
+		set := o.IsSet(field)
+		if set != otherObj.IsSet(field) {
+			return types.False
+		}
+		if set == types.False {
+			continue
+		}
+		if eq := o.Get(field).Equal(otherObj.Get(field)); eq != types.True {
+			return types.False
+		}
+	}
+	return types.True
+}
+
+// Type implements ref.Val.
+func (o *Object) Type() ref.Type {
+	return o.typ.celType
+}
+
+// Value implements ref.Val, returning the backing map of field values.
+func (o *Object) Value() any {
+	return o.fields
+}
+
+// IsSet implements traits.FieldTester.
+func (o *Object) IsSet(field ref.Val) ref.Val {
+	f, err := o.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(f.fieldType.IsSet(o.fields))
+}
+
+// Get implements traits.Indexer.
+func (o *Object) Get(field ref.Val) ref.Val {
+	f, err := o.field(field)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	v, err := f.fieldType.GetFrom(o.fields)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return v.(ref.Val)
+}
+
+func (o *Object) field(field ref.Val) (*objectField, error) {
+	name, ok := field.(types.String)
+	if !ok {
+		return nil, fmt.Errorf("no such overload: %s.%v", o.typ.name, field)
+	}
+	f, found := o.typ.fields[string(name)]
+	if !found {
+		return nil, fmt.Errorf("no such field: %s", name)
+	}
+	return f, nil
+}
@@ -0,0 +1,476 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Provider is a types.Provider which exposes object schemas as CEL struct types.
+//
+// Schemas are mapped to CEL types as follows:
+//
+//   - `object` schemas with `properties` become struct types. Anonymous nested object schemas are
+//     named after the path to the property, e.g. `CronTab.spec`. Array items and map values are
+//     named `Item` and `Value` respectively, e.g. `CronTab.spec.containers.Item`.
+//   - `object` schemas with only `additionalProperties` become `map(string, V)`.
+//   - `array` schemas become `list(T)`.
+//   - `string`, `integer`, `number`, and `boolean` schemas become `string`, `int`, `double`, and
+//     `bool`. Strings with a `byte`, `date-time`, or `duration` format become `bytes`, `timestamp`,
+//     and `duration` values. Nullable primitives become wrapper types.
+//   - `enum` values on string and integer schemas become literal types.
+//   - `anyOf` and `oneOf` schemas become union types.
+//   - `x-kubernetes-int-or-string` becomes `int | string`, `x-kubernetes-embedded-resource`
+//     objects include `apiVersion`, `kind`, and `metadata` fields, and
+//     `x-kubernetes-preserve-unknown-fields` objects without properties become `dyn`.
+//
+// Values of struct types are represented at runtime as `map[string]any` values, such as those
+// produced by decoding JSON or YAML. Field values are converted to the CEL type declared by the
+// schema when they are selected. Absent fields, and fields with a null value, are reported as not
+// set by `has()`. Selecting an absent field produces its `default` value if one is declared, and
+// otherwise an error.
+type Provider struct {
+	schemas map[string]*Schema
+	objects map[string]*ObjectType
+	adapter types.Adapter
+}
+
+// NewProvider creates a Provider from a set of named schemas.
+func NewProvider(schemas map[string]*Schema) (*Provider, error) {
+	p := &Provider{
+		schemas: schemas,
+		objects: map[string]*ObjectType{},
+		adapter: types.DefaultTypeAdapter,
+	}
+	// Declare the named object types before resolving any field types so that schemas may refer to
+	// one another, or to themselves.
+	for _, name := range sortedKeys(schemas) {
+		if s := schemas[name]; isObjectSchema(s) {
+			p.objects[name] = newObjectType(name, s)
+		}
+	}
+	for _, name := range sortedKeys(schemas) {
+		s := schemas[name]
+		if obj, found := p.objects[name]; found {
+			if err := p.resolveFields(obj); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := p.celType(name, s); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Types returns the object types declared by the schemas, sorted by name.
+//
+// The types may be registered with a types.Registry, or provided to cel.Types, to make them
+// available within a CEL environment.
+func (p *Provider) Types() []ref.Type {
+	out := make([]ref.Type, 0, len(p.objects))
+	for _, name := range sortedKeys(p.objects) {
+		out = append(out, p.objects[name])
+	}
+	return out
+}
+
+// EnumValue implements types.Provider.
+func (p *Provider) EnumValue(enumName string) ref.Val {
+	return types.NewErr("unknown enum name '%s'", enumName)
+}
+
+// FindIdent implements types.Provider.
+func (p *Provider) FindIdent(identName string) (ref.Val, bool) {
+	if obj, found := p.objects[identName]; found {
+		return obj.celType, true
+	}
+	return nil, false
+}
+
+// FindStructType implements types.Provider.
+func (p *Provider) FindStructType(structType string) (*types.Type, bool) {
+	if obj, found := p.objects[structType]; found {
+		return types.NewTypeTypeWithParam(obj.celType), true
+	}
+	return nil, false
+}
+
+// FindStructFieldNames implements types.Provider.
+func (p *Provider) FindStructFieldNames(structType string) ([]string, bool) {
+	if obj, found := p.objects[structType]; found {
+		return obj.FieldNames(), true
+	}
+	return nil, false
+}
+
+// FindStructFieldType implements types.Provider.
+func (p *Provider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	if obj, found := p.objects[structType]; found {
+		return obj.FindFieldType(fieldName)
+	}
+	return nil, false
+}
+
+// FindStructFieldDescription returns the description of an object property, if present.
+func (p *Provider) FindStructFieldDescription(structType, fieldName string) (string, bool) {
+	if obj, found := p.objects[structType]; found {
+		if f, found := obj.fields[fieldName]; found {
+			return f.schema.Description, true
+		}
+	}
+	return "", false
+}
+
+// NewValue implements types.Provider.
+func (p *Provider) NewValue(structType string, fields map[string]ref.Val) ref.Val {
+	if obj, found := p.objects[structType]; found {
+		return obj.NewValue(p.adapter, fields)
+	}
+	return types.NewErr("unknown type '%s'", structType)
+}
+
+// FieldMaxSize returns the maximum size of a field as declared by the `maxLength`, `maxItems`, or
+// `maxProperties` bound of its schema.
+//
+// The size is measured in the same units as the CEL `size()` function.
+func (p *Provider) FieldMaxSize(structType, fieldName string) (uint64, bool) {
+	obj, found := p.objects[structType]
+	if !found {
+		return 0, false
+	}
+	f, found := obj.fields[fieldName]
+	if !found {
+		return 0, false
+	}
+	s := p.resolve(f.schema)
+	var bound *uint64
+	switch f.t.Kind() {
+	case types.StringKind, types.BytesKind:
+		bound = s.MaxLength
+	case types.ListKind:
+		bound = s.MaxItems
+	case types.MapKind, types.StructKind:
+		bound = s.MaxProperties
+	}
+	if bound == nil {
+		return 0, false
+	}
+	return *bound, true
+}
+
+// resolveFields computes the CEL types of the properties of an object type.
+func (p *Provider) resolveFields(obj *ObjectType) error {
+	s := p.resolve(obj.schema)
+	props := s.Properties
+	if s.XKubernetesEmbeddedResource {
+		props = withEmbeddedResourceFields(props)
+	}
+	for _, name := range sortedKeys(props) {
+		ft, err := p.celType(obj.name+"."+name, props[name])
+		if err != nil {
+			return fmt.Errorf("invalid schema %s: property %q: %w", obj.name, name, err)
+		}
+		obj.addField(p, name, props[name], ft, s.IsRequired(name))
+	}
+	return nil
+}
+
+// celType returns the CEL type of a schema, where name is used as the name of the schema if it
+// declares an anonymous object type.
+func (p *Provider) celType(name string, s *Schema) (*types.Type, error) {
+	if s == nil || s.rejectAll {
+		return types.DynType, nil
+	}
+	if s.Ref != "" {
+		refName, target, err := p.lookupRef(s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if obj, found := p.objects[refName]; found {
+			return obj.celType, nil
+		}
+		return p.celType(refName, target)
+	}
+	if members := append(append([]*Schema{}, s.AnyOf...), s.OneOf...); len(members) != 0 {
+		memberTypes := make([]*types.Type, len(members))
+		for i, m := range members {
+			mt, err := p.celType(fmt.Sprintf("%s.Option%d", name, i), m)
+			if err != nil {
+				return nil, err
+			}
+			memberTypes[i] = mt
+		}
+		return types.NewUnionType(memberTypes...), nil
+	}
+	if s.XKubernetesIntOrString {
+		return types.NewUnionType(types.IntType, types.StringType), nil
+	}
+	var t *types.Type
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "byte":
+			t = types.BytesType
+		case "date-time":
+			t = types.TimestampType
+		case "duration":
+			t = types.DurationType
+		default:
+			t = types.StringType
+			if len(s.Enum) != 0 {
+				values := make([]string, 0, len(s.Enum))
+				for _, v := range s.Enum {
+					if str, ok := v.(string); ok {
+						values = append(values, str)
+					}
+				}
+				t = types.NewStringLiteralType(values...)
+			}
+		}
+	case "integer":
+		t = types.IntType
+		if len(s.Enum) != 0 {
+			values := make([]int64, 0, len(s.Enum))
+			for _, v := range s.Enum {
+				if i, ok := toInt(v); ok {
+					values = append(values, i)
+				}
+			}
+			t = types.NewIntLiteralType(values...)
+		}
+	case "number":
+		t = types.DoubleType
+	case "boolean":
+		t = types.BoolType
+	case "array":
+		elem, err := p.celType(name+".Item", s.Items)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewListType(elem), nil
+	case "object", "":
+		if isObjectSchema(s) {
+			obj, found := p.objects[name]
+			if !found {
+				obj = newObjectType(name, s)
+				p.objects[name] = obj
+				if err := p.resolveFields(obj); err != nil {
+					return nil, err
+				}
+			}
+			return obj.celType, nil
+		}
+		if s.AdditionalProperties != nil && !s.AdditionalProperties.rejectAll {
+			val, err := p.celType(name+".Value", s.AdditionalProperties)
+			if err != nil {
+				return nil, err
+			}
+			return types.NewMapType(types.StringType, val), nil
+		}
+		if s.Type == "" || s.XKubernetesPreserveUnknownFields {
+			return types.DynType, nil
+		}
+		return types.NewMapType(types.StringType, types.DynType), nil
+	default:
+		return nil, fmt.Errorf("unsupported schema type: %s", s.Type)
+	}
+	if s.Nullable {
+		return types.NewNullableType(t), nil
+	}
+	return t, nil
+}
+
+// resolve follows the reference of a schema, if any.
+func (p *Provider) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < len(p.schemas); i++ {
+		_, target, err := p.lookupRef(s.Ref)
+		if err != nil {
+			return s
+		}
+		s = target
+	}
+	return s
+}
+
+// lookupRef resolves a local schema reference such as `#/components/schemas/Name`, `#/$defs/Name`,
+// or `#/definitions/Name`.
+func (p *Provider) lookupRef(ref string) (string, *Schema, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return "", nil, fmt.Errorf("unsupported schema reference: %s", ref)
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	s, found := p.schemas[name]
+	if !found {
+		return "", nil, fmt.Errorf("undefined schema reference: %s", ref)
+	}
+	return name, s, nil
+}
+
+// convert converts a field value to a CEL value of the given type.
+func (p *Provider) convert(t *types.Type, v any) (ref.Val, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.NullValue, nil
+	case ref.Val:
+		return v, nil
+	}
+	switch t.Kind() {
+	case types.IntKind:
+		if i, ok := toInt(v); ok {
+			return types.Int(i), nil
+		}
+	case types.DoubleKind:
+		if d, ok := toDouble(v); ok {
+			return types.Double(d), nil
+		}
+	case types.BytesKind:
+		if str, ok := v.(string); ok {
+			b, err := base64.StdEncoding.DecodeString(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bytes value: %w", err)
+			}
+			return types.Bytes(b), nil
+		}
+	case types.TimestampKind:
+		if str, ok := v.(string); ok {
+			ts, err := time.Parse(time.RFC3339, str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date-time value: %w", err)
+			}
+			return types.Timestamp{Time: ts}, nil
+		}
+	case types.DurationKind:
+		if str, ok := v.(string); ok {
+			d, err := time.ParseDuration(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration value: %w", err)
+			}
+			return types.Duration{Duration: d}, nil
+		}
+	case types.UnionKind:
+		for _, m := range t.Parameters() {
+			if m.Kind() == types.IntKind {
+				if i, ok := toInt(v); ok {
+					return types.Int(i), nil
+				}
+			}
+		}
+	case types.ListKind:
+		if elems, ok := v.([]any); ok {
+			vals := make([]ref.Val, len(elems))
+			for i, elem := range elems {
+				val, err := p.convert(t.Parameters()[0], elem)
+				if err != nil {
+					return nil, err
+				}
+				vals[i] = val
+			}
+			return types.NewRefValList(p.adapter, vals), nil
+		}
+	case types.MapKind:
+		if entries, ok := v.(map[string]any); ok {
+			vals := make(map[ref.Val]ref.Val, len(entries))
+			for k, entry := range entries {
+				val, err := p.convert(t.Parameters()[1], entry)
+				if err != nil {
+					return nil, err
+				}
+				vals[types.String(k)] = val
+			}
+			return types.NewRefValMap(p.adapter, vals), nil
+		}
+	case types.StructKind:
+		if fields, ok := v.(map[string]any); ok {
+			if obj, found := p.objects[t.TypeName()]; found {
+				return &Object{typ: obj, fields: fields}, nil
+			}
+		}
+	}
+	return p.adapter.NativeToValue(v), nil
+}
+
+// isObjectSchema returns whether the schema declares an object type with named properties.
+func isObjectSchema(s *Schema) bool {
+	if s == nil || s.Ref != "" || len(s.AnyOf) != 0 || len(s.OneOf) != 0 {
+		return false
+	}
+	if s.Type != "object" && s.Type != "" {
+		return false
+	}
+	return len(s.Properties) != 0 || s.XKubernetesEmbeddedResource
+}
+
+// withEmbeddedResourceFields adds the fields common to all Kubernetes resources to a set of
+// properties.
+func withEmbeddedResourceFields(props map[string]*Schema) map[string]*Schema {
+	out := map[string]*Schema{
+		"apiVersion": {Type: "string"},
+		"kind":       {Type: "string"},
+		"metadata": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":         {Type: "string"},
+				"generateName": {Type: "string"},
+			},
+			XKubernetesPreserveUnknownFields: true,
+		},
+	}
+	for name, s := range props {
+		out[name] = s
+	}
+	return out
+}
+
+func toInt(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+func toDouble(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	if i, ok := toInt(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
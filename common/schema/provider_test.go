@@ -0,0 +1,309 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+const cronTab = "stable.example.com.v1.CronTab"
+
+func TestProviderFieldTypes(t *testing.T) {
+	p := newTestProvider(t, "testdata/crontab_crd.yaml", "testdata/petstore.json")
+	tests := []struct {
+		structType string
+		field      string
+		want       *types.Type
+	}{
+		{structType: cronTab, field: "apiVersion", want: types.StringType},
+		{structType: cronTab, field: "spec", want: types.NewObjectType(cronTab + ".spec")},
+		{structType: cronTab + ".spec", field: "replicas", want: types.IntType},
+		{
+			structType: cronTab + ".spec",
+			field:      "concurrencyPolicy",
+			want:       types.NewStringLiteralType("Allow", "Forbid", "Replace"),
+		},
+		{
+			structType: cronTab + ".spec",
+			field:      "port",
+			want:       types.NewUnionType(types.IntType, types.StringType),
+		},
+		{structType: cronTab + ".spec", field: "timeout", want: types.DurationType},
+		{
+			structType: cronTab + ".spec",
+			field:      "labels",
+			want:       types.NewMapType(types.StringType, types.StringType),
+		},
+		{
+			structType: cronTab + ".spec",
+			field:      "containers",
+			want:       types.NewListType(types.NewObjectType(cronTab + ".spec.containers.Item")),
+		},
+		{structType: cronTab + ".spec", field: "extra", want: types.DynType},
+		{structType: cronTab + ".status", field: "lastScheduleTime", want: types.TimestampType},
+		{structType: cronTab + ".metadata", field: "name", want: types.StringType},
+		{structType: "Pet", field: "tag", want: types.NewNullableType(types.StringType)},
+		{structType: "Pet", field: "owner", want: types.NewObjectType("Owner")},
+		{structType: "Pet", field: "status", want: types.NewStringLiteralType("available", "pending", "sold")},
+		{structType: "Pet", field: "photo", want: types.BytesType},
+		{structType: "Pet", field: "weight", want: types.DoubleType},
+		{structType: "Pet", field: "friends", want: types.NewListType(types.NewObjectType("Pet"))},
+		{
+			structType: "Pet",
+			field:      "identifier",
+			want:       types.NewUnionType(types.StringType, types.IntType),
+		},
+	}
+	for _, tc := range tests {
+		ft, found := p.FindStructFieldType(tc.structType, tc.field)
+		if !found {
+			t.Errorf("FindStructFieldType(%q, %q) not found", tc.structType, tc.field)
+			continue
+		}
+		if !ft.Type.IsExactType(tc.want) {
+			t.Errorf("FindStructFieldType(%q, %q) got %v, wanted %v", tc.structType, tc.field, ft.Type, tc.want)
+		}
+	}
+	if _, found := p.FindStructFieldType("Pet", "undefined"); found {
+		t.Error("FindStructFieldType(Pet, undefined) found, wanted not found")
+	}
+	if _, found := p.FindStructType("Status"); found {
+		t.Error("FindStructType(Status) found, wanted only object types")
+	}
+	if fields, _ := p.FindStructFieldNames("Owner"); !reflect.DeepEqual(fields, []string{"name", "vip"}) {
+		t.Errorf("FindStructFieldNames(Owner) got %v, wanted [name vip]", fields)
+	}
+}
+
+func TestProviderFieldValues(t *testing.T) {
+	p := newTestProvider(t, "testdata/crontab_crd.yaml")
+	spec := map[string]any{
+		"schedule":   "*/5 * * * *",
+		"replicas":   float64(3),
+		"port":       float64(8080),
+		"timeout":    "1m30s",
+		"labels":     map[string]any{"app": "cron"},
+		"containers": []any{map[string]any{"name": "main", "args": []any{"-v"}}},
+		"extra":      map[string]any{"any": true},
+	}
+	tests := []struct {
+		structType string
+		field      string
+		obj        map[string]any
+		want       ref.Val
+		err        string
+	}{
+		{structType: cronTab + ".spec", field: "replicas", obj: spec, want: types.Int(3)},
+		{structType: cronTab + ".spec", field: "port", obj: spec, want: types.Int(8080)},
+		{
+			structType: cronTab + ".spec",
+			field:      "port",
+			obj:        map[string]any{"port": "http"},
+			want:       types.String("http"),
+		},
+		{
+			structType: cronTab + ".spec",
+			field:      "timeout",
+			obj:        spec,
+			want:       types.Duration{Duration: 90 * time.Second},
+		},
+		{
+			structType: cronTab + ".spec",
+			field:      "labels",
+			obj:        spec,
+			want:       types.DefaultTypeAdapter.NativeToValue(map[string]string{"app": "cron"}),
+		},
+		{structType: cronTab + ".spec", field: "image", obj: spec, want: types.String("busybox")},
+		{structType: cronTab + ".spec", field: "replicas", obj: map[string]any{}, err: "no such key: replicas"},
+		{
+			structType: cronTab + ".spec",
+			field:      "schedule",
+			obj:        map[string]any{},
+			err:        "missing required field: schedule",
+		},
+		{
+			structType: cronTab + ".status",
+			field:      "lastScheduleTime",
+			obj:        map[string]any{"lastScheduleTime": "2026-01-02T03:04:05Z"},
+			want:       types.Timestamp{Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		{
+			structType: cronTab + ".status",
+			field:      "lastScheduleTime",
+			obj:        map[string]any{"lastScheduleTime": "yesterday"},
+			err:        "invalid date-time value",
+		},
+	}
+	for _, tc := range tests {
+		ft, found := p.FindStructFieldType(tc.structType, tc.field)
+		if !found {
+			t.Fatalf("FindStructFieldType(%q, %q) not found", tc.structType, tc.field)
+		}
+		got, err := ft.GetFrom(tc.obj)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("GetFrom(%v) got %v, %v, wanted error containing %q", tc.obj, got, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetFrom(%v) failed: %v", tc.obj, err)
+		}
+		if got.(ref.Val).Equal(tc.want) != types.True {
+			t.Errorf("GetFrom(%v) got %v, wanted %v", tc.obj, got, tc.want)
+		}
+	}
+
+	// Nested objects are converted to Object values of the nested object type.
+	ft, _ := p.FindStructFieldType(cronTab+".spec", "containers")
+	containers, err := ft.GetFrom(spec)
+	if err != nil {
+		t.Fatalf("GetFrom(containers) failed: %v", err)
+	}
+	container := containers.(ref.Val).(interface{ Get(ref.Val) ref.Val }).Get(types.Int(0))
+	if container.Type().TypeName() != cronTab+".spec.containers.Item" {
+		t.Errorf("containers[0] got type %v, wanted %s.spec.containers.Item", container.Type(), cronTab)
+	}
+	obj := container.(*Object)
+	if obj.Get(types.String("name")) != types.String("main") {
+		t.Errorf("containers[0].name got %v, wanted main", obj.Get(types.String("name")))
+	}
+	if obj.IsSet(types.String("args")) != types.True || obj.IsSet(types.String("name")) != types.True {
+		t.Error("containers[0] fields not set, wanted set")
+	}
+	if !types.IsError(obj.Get(types.String("image"))) {
+		t.Error("containers[0].image got value, wanted no such field error")
+	}
+
+	// Null values are reported as not set.
+	if ft.IsSet(map[string]any{"containers": nil}) {
+		t.Error("IsSet(containers: null) returned true")
+	}
+}
+
+func TestProviderNewValue(t *testing.T) {
+	p := newTestProvider(t, "testdata/petstore.json")
+	pet := p.NewValue("Pet", map[string]ref.Val{"id": types.Int(1), "name": types.String("Rex")})
+	if types.IsError(pet) {
+		t.Fatalf("NewValue(Pet) failed: %v", pet)
+	}
+	if pet.Type().TypeName() != "Pet" {
+		t.Errorf("NewValue(Pet) got type %v, wanted Pet", pet.Type())
+	}
+	other := p.NewValue("Pet", map[string]ref.Val{"id": types.Int(1), "name": types.String("Rex")})
+	if pet.Equal(other) != types.True {
+		t.Errorf("%v.Equal(%v) returned false", pet, other)
+	}
+	third := p.NewValue("Pet", map[string]ref.Val{"id": types.Int(2), "name": types.String("Rex")})
+	if pet.Equal(third) != types.False {
+		t.Errorf("%v.Equal(%v) returned true", pet, third)
+	}
+	native, err := pet.ConvertToNative(reflect.TypeOf(map[string]any{}))
+	if err != nil {
+		t.Fatalf("ConvertToNative() failed: %v", err)
+	}
+	if want := map[string]any{"id": int64(1), "name": "Rex"}; !reflect.DeepEqual(native, want) {
+		t.Errorf("ConvertToNative() got %v, wanted %v", native, want)
+	}
+	if out := p.NewValue("Pet", map[string]ref.Val{"age": types.Int(1)}); !types.IsError(out) {
+		t.Errorf("NewValue(Pet{age: 1}) got %v, wanted error", out)
+	}
+	if out := p.NewValue("Status", map[string]ref.Val{}); !types.IsError(out) {
+		t.Errorf("NewValue(Status{}) got %v, wanted error", out)
+	}
+}
+
+func TestProviderFieldMaxSize(t *testing.T) {
+	p := newTestProvider(t, "testdata/crontab_crd.yaml")
+	tests := []struct {
+		structType string
+		field      string
+		size       uint64
+		found      bool
+	}{
+		{structType: cronTab + ".spec", field: "schedule", size: 64, found: true},
+		{structType: cronTab + ".spec", field: "containers", size: 8, found: true},
+		{structType: cronTab + ".spec", field: "labels", size: 16, found: true},
+		{structType: cronTab + ".spec", field: "image"},
+		{structType: cronTab + ".spec", field: "undefined"},
+		{structType: "undefined", field: "schedule"},
+	}
+	for _, tc := range tests {
+		size, found := p.FieldMaxSize(tc.structType, tc.field)
+		if size != tc.size || found != tc.found {
+			t.Errorf("FieldMaxSize(%q, %q) got %d, %v, wanted %d, %v", tc.structType, tc.field, size, found, tc.size, tc.found)
+		}
+	}
+}
+
+func TestNewProviderErrors(t *testing.T) {
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{
+			doc: `$defs: {A: {type: object, properties: {b: {$ref: "#/$defs/B"}}}}`,
+			err: "undefined schema reference: #/$defs/B",
+		},
+		{
+			doc: `$defs: {A: {type: object, properties: {b: {$ref: "other.json#/B"}}}}`,
+			err: "unsupported schema reference",
+		},
+		{
+			doc: `$defs: {A: {type: object, properties: {b: {type: tuple}}}}`,
+			err: "unsupported schema type: tuple",
+		},
+	}
+	for _, tc := range tests {
+		schemas, err := Parse([]byte(tc.doc))
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.doc, err)
+		}
+		_, err = NewProvider(schemas)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("NewProvider(%q) got error %v, wanted error containing %q", tc.doc, err, tc.err)
+		}
+	}
+}
+
+func newTestProvider(t *testing.T, files ...string) *Provider {
+	t.Helper()
+	schemas := map[string]*Schema{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("os.ReadFile(%q) failed: %v", file, err)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", file, err)
+		}
+		for name, s := range parsed {
+			schemas[name] = s
+		}
+	}
+	p, err := NewProvider(schemas)
+	if err != nil {
+		t.Fatalf("NewProvider() failed: %v", err)
+	}
+	return p
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema provides a CEL type provider backed by JSON Schema and OpenAPI v3 schemas.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Schema is a JSON Schema or OpenAPI v3 schema object.
+//
+// Only the subset of keywords which affect the CEL type of a value, or the size of a value, are
+// represented.
+type Schema struct {
+	Type        string `yaml:"type,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Ref         string `yaml:"$ref,omitempty"`
+	Nullable    bool   `yaml:"nullable,omitempty"`
+	Default     any    `yaml:"default,omitempty"`
+	Enum        []any  `yaml:"enum,omitempty"`
+
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty"`
+	AnyOf                []*Schema          `yaml:"anyOf,omitempty"`
+	OneOf                []*Schema          `yaml:"oneOf,omitempty"`
+
+	MaxItems      *uint64 `yaml:"maxItems,omitempty"`
+	MaxLength     *uint64 `yaml:"maxLength,omitempty"`
+	MaxProperties *uint64 `yaml:"maxProperties,omitempty"`
+
+	XKubernetesPreserveUnknownFields bool     `yaml:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XKubernetesIntOrString           bool     `yaml:"x-kubernetes-int-or-string,omitempty"`
+	XKubernetesEmbeddedResource      bool     `yaml:"x-kubernetes-embedded-resource,omitempty"`
+	XKubernetesListType              string   `yaml:"x-kubernetes-list-type,omitempty"`
+	XKubernetesListMapKeys           []string `yaml:"x-kubernetes-list-map-keys,omitempty"`
+
+	// rejectAll indicates the schema was the boolean schema `false`.
+	rejectAll bool
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+//
+// In addition to schema objects, the boolean schemas `true` and `false` are supported, as is a
+// list of type names where the `null` type marks the schema as nullable.
+func (s *Schema) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return fmt.Errorf("unsupported schema at line %d: %q", n.Line, n.Value)
+		}
+		*s = Schema{rejectAll: !b}
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("unsupported schema at line %d", n.Line)
+	}
+	var typeNames []string
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		if key.Value != "type" || val.Kind != yaml.SequenceNode {
+			continue
+		}
+		if err := val.Decode(&typeNames); err != nil {
+			return err
+		}
+		// Clear the list of type names so that the remainder of the schema decodes normally.
+		n.Content = append(n.Content[:i:i], n.Content[i+2:]...)
+		break
+	}
+	type plainSchema Schema
+	if err := n.Decode((*plainSchema)(s)); err != nil {
+		return err
+	}
+	var nonNull []string
+	for _, t := range typeNames {
+		if t == "null" {
+			s.Nullable = true
+			continue
+		}
+		nonNull = append(nonNull, t)
+	}
+	switch len(nonNull) {
+	case 0:
+	case 1:
+		s.Type = nonNull[0]
+	default:
+		for _, t := range nonNull {
+			s.AnyOf = append(s.AnyOf, &Schema{Type: t})
+		}
+	}
+	return nil
+}
+
+// IsRequired returns whether the property is listed as required by the schema.
+func (s *Schema) IsRequired(property string) bool {
+	for _, r := range s.Required {
+		if r == property {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse reads the named schemas declared within a JSON or YAML document.
+//
+// The following document formats are supported:
+//
+//   - OpenAPI v3 documents, where each entry in `components.schemas` is a named schema.
+//   - Kubernetes CustomResourceDefinitions, where the `openAPIV3Schema` of each version is named
+//     `<group>.<version>.<kind>`, e.g. `stable.example.com.v1.CronTab`.
+//   - JSON Schema documents, where each entry in `$defs` or `definitions` is a named schema, and the
+//     root schema is named by its `title`, if present.
+func Parse(data []byte) (map[string]*Schema, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid schema document: %w", err)
+	}
+	schemas := map[string]*Schema{}
+	add := func(name string, s *Schema) error {
+		if s == nil {
+			return nil
+		}
+		if _, found := schemas[name]; found {
+			return fmt.Errorf("invalid schema document: duplicate schema %q", name)
+		}
+		schemas[name] = s
+		return nil
+	}
+	var errs []error
+	if doc.Kind == "CustomResourceDefinition" {
+		for _, v := range doc.Spec.Versions {
+			name := fmt.Sprintf("%s.%s.%s", doc.Spec.Group, v.Name, doc.Spec.Names.Kind)
+			if v.Schema == nil {
+				continue
+			}
+			errs = append(errs, add(name, v.Schema.OpenAPIV3Schema))
+		}
+	}
+	for _, defs := range []map[string]*Schema{doc.Components.Schemas, doc.Defs, doc.Definitions} {
+		for _, name := range sortedKeys(defs) {
+			errs = append(errs, add(name, defs[name]))
+		}
+	}
+	if doc.Kind != "CustomResourceDefinition" && doc.Title != "" {
+		var root Schema
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("invalid schema document: %w", err)
+		}
+		errs = append(errs, add(doc.Title, &root))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New("invalid schema document: no named schemas found")
+	}
+	return schemas, nil
+}
+
+// document captures the locations of named schemas within the supported document formats.
+type document struct {
+	Kind       string `yaml:"kind"`
+	Title      string `yaml:"title"`
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+	Defs        map[string]*Schema `yaml:"$defs"`
+	Definitions map[string]*Schema `yaml:"definitions"`
+	Spec        struct {
+		Group string `yaml:"group"`
+		Names struct {
+			Kind string `yaml:"kind"`
+		} `yaml:"names"`
+		Versions []struct {
+			Name   string `yaml:"name"`
+			Schema *struct {
+				OpenAPIV3Schema *Schema `yaml:"openAPIV3Schema"`
+			} `yaml:"schema"`
+		} `yaml:"versions"`
+	} `yaml:"spec"`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		doc   string
+		names []string
+	}{
+		{
+			name:  "crd",
+			file:  "testdata/crontab_crd.yaml",
+			names: []string{"stable.example.com.v1.CronTab"},
+		},
+		{
+			name:  "openapi",
+			file:  "testdata/petstore.json",
+			names: []string{"Owner", "Pet", "Status"},
+		},
+		{
+			name: "json schema",
+			doc: `{
+				"title": "Account",
+				"type": "object",
+				"properties": {"address": {"$ref": "#/$defs/Address"}},
+				"$defs": {"Address": {"type": "object", "properties": {"city": {"type": "string"}}}}
+			}`,
+			names: []string{"Account", "Address"},
+		},
+		{
+			name: "json schema definitions",
+			doc: `
+definitions:
+  Color:
+    type: string
+    enum: [red, green]
+`,
+			names: []string{"Color"},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			data := []byte(tc.doc)
+			if tc.file != "" {
+				var err error
+				data, err = os.ReadFile(tc.file)
+				if err != nil {
+					t.Fatalf("os.ReadFile(%q) failed: %v", tc.file, err)
+				}
+			}
+			schemas, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+			if got := sortedKeys(schemas); !reflect.DeepEqual(got, tc.names) {
+				t.Errorf("Parse() got schemas %v, wanted %v", got, tc.names)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{doc: `{"type": "object"}`, err: "no named schemas found"},
+		{doc: `definitions: [1, 2]`, err: "invalid schema document"},
+		{doc: `definitions: {A: 1}`, err: "unsupported schema"},
+		{doc: `{"title": "A", "$defs": {"A": {"type": "string"}}}`, err: `duplicate schema "A"`},
+	}
+	for _, tc := range tests {
+		_, err := Parse([]byte(tc.doc))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Parse(%q) got error %v, wanted error containing %q", tc.doc, err, tc.err)
+		}
+	}
+}
+
+func TestSchemaUnmarshalTypeList(t *testing.T) {
+	schemas, err := Parse([]byte(`
+$defs:
+  Name:
+    type: [string, "null"]
+  Id:
+    type: [string, integer]
+  Any: true
+  None: false
+`))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if s := schemas["Name"]; s.Type != "string" || !s.Nullable {
+		t.Errorf("Name schema got type %q, nullable %v, wanted nullable string", s.Type, s.Nullable)
+	}
+	if s := schemas["Id"]; s.Type != "" || len(s.AnyOf) != 2 {
+		t.Errorf("Id schema got type %q, anyOf %v, wanted anyOf string, integer", s.Type, s.AnyOf)
+	}
+	if s := schemas["Any"]; s.rejectAll {
+		t.Error("Any schema rejects all values, wanted true schema")
+	}
+	if s := schemas["None"]; !s.rejectAll {
+		t.Error("None schema accepts all values, wanted false schema")
+	}
+}
@@ -17,6 +17,7 @@ package types
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"google.golang.org/protobuf/proto"
@@ -95,6 +96,10 @@ const (
 
 	// UnknownKind represents an unknown value type.
 	UnknownKind
+
+	// UnionKind represents a type which may be any one of a set of member types. This kind only
+	// exists at type-check time.
+	UnionKind
 )
 
 var (
@@ -238,6 +243,9 @@ type Type struct {
 
 	// traitMask is a mask of flags which indicate the capabilities of the type.
 	traitMask int
+
+	// literals holds the set of values permitted by a literal type, e.g. 'GET' | 'POST'.
+	literals []ref.Val
 }
 
 // ConvertToNative implements ref.Val.ConvertToNative.
@@ -302,6 +310,9 @@ func (t *Type) isTypeInternal(other *Type, checkTypeParamName bool) bool {
 	if (checkTypeParamName || t.Kind() != TypeParamKind) && t.TypeName() != other.TypeName() {
 		return false
 	}
+	if len(t.literals) != len(other.literals) || !containsLiterals(t.literals, other.literals) {
+		return false
+	}
 	for i, p := range t.Parameters() {
 		if !p.isTypeInternal(other.Parameters()[i], checkTypeParamName) {
 			return false
@@ -347,6 +358,20 @@ func (t *Type) Parameters() []*Type {
 	return t.parameters
 }
 
+// LiteralValues returns the set of values permitted by a literal type, or nil if the type is not
+// a literal type.
+func (t *Type) LiteralValues() []ref.Val {
+	if t == nil {
+		return nil
+	}
+	return t.literals
+}
+
+// IsLiteralType indicates whether the type permits only a fixed set of string or int values.
+func (t *Type) IsLiteralType() bool {
+	return len(t.LiteralValues()) != 0
+}
+
 // DeclaredTypeName indicates the fully qualified and parameterized type-check type name.
 func (t *Type) DeclaredTypeName() string {
 	// if the type itself is neither null, nor dyn, but is assignable to null, then it's a wrapper type.
@@ -394,6 +419,7 @@ func (t *Type) WithTraits(traits int) *Type {
 		isAssignableType:        t.isAssignableType,
 		isAssignableRuntimeType: t.isAssignableRuntimeType,
 		traitMask:               traits,
+		literals:                t.literals,
 	}
 }
 
@@ -402,6 +428,16 @@ func (t *Type) String() string {
 	if t.Kind() == TypeParamKind {
 		return fmt.Sprintf("<%s>", t.DeclaredTypeName())
 	}
+	if t.IsLiteralType() {
+		return formatLiterals(t.literals)
+	}
+	if t.Kind() == UnionKind {
+		members := make([]string, len(t.Parameters()))
+		for i, p := range t.Parameters() {
+			members[i] = p.String()
+		}
+		return strings.Join(members, " | ")
+	}
 	if len(t.Parameters()) == 0 {
 		return t.DeclaredTypeName()
 	}
@@ -521,6 +557,164 @@ func NewNullableType(wrapped *Type) *Type {
 	}
 }
 
+// NewUnionType creates a type which is assignable from any of its member types, e.g. a value of
+// type `string | int` may be either a string or an int.
+//
+// Nested union types are flattened and duplicate members are removed. Literal types of the same
+// kind are merged into a single literal type. A union of a single member is the member type itself,
+// and a union containing dyn is dyn.
+func NewUnionType(members ...*Type) *Type {
+	var flat []*Type
+	for _, m := range members {
+		if m.Kind() == UnionKind {
+			flat = append(flat, m.Parameters()...)
+		} else {
+			flat = append(flat, m)
+		}
+	}
+	var params []*Type
+	for _, m := range flat {
+		if m.isDyn() && m.Kind() != TypeParamKind {
+			return DynType
+		}
+		merged := false
+		for i, p := range params {
+			if p.IsExactType(m) {
+				merged = true
+				break
+			}
+			if p.IsLiteralType() && m.IsLiteralType() && p.Kind() == m.Kind() {
+				params[i] = newLiteralType(p, append(append([]ref.Val{}, p.literals...), m.literals...))
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			params = append(params, m)
+		}
+	}
+	if len(params) == 1 {
+		return params[0]
+	}
+	traitMask := 0
+	if len(params) != 0 {
+		traitMask = params[0].traitMask
+		for _, p := range params[1:] {
+			traitMask &= p.traitMask
+		}
+	}
+	return &Type{
+		kind:            UnionKind,
+		parameters:      params,
+		runtimeTypeName: "union",
+		traitMask:       traitMask,
+		isAssignableType: func(other *Type) bool {
+			if other.Kind() == UnionKind {
+				for _, o := range other.Parameters() {
+					if !unionAccepts(params, o) {
+						return false
+					}
+				}
+				return true
+			}
+			return unionAccepts(params, other)
+		},
+		isAssignableRuntimeType: func(val ref.Val) bool {
+			for _, p := range params {
+				if p.IsAssignableRuntimeType(val) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func unionAccepts(members []*Type, t *Type) bool {
+	for _, m := range members {
+		if m.IsAssignableType(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewStringLiteralType creates a string type which permits only the given values, e.g. an enum
+// delivered as strings: 'GET' | 'POST'.
+//
+// A literal type is assignable to and from its base type. Comparisons of literal type values
+// against constants outside the set of values are reported by the type-checker.
+func NewStringLiteralType(values ...string) *Type {
+	literals := make([]ref.Val, len(values))
+	for i, v := range values {
+		literals[i] = String(v)
+	}
+	return newLiteralType(StringType, literals)
+}
+
+// NewIntLiteralType creates an int type which permits only the given values, e.g. 1 | 2 | 3.
+//
+// A literal type is assignable to and from its base type. Comparisons of literal type values
+// against constants outside the set of values are reported by the type-checker.
+func NewIntLiteralType(values ...int64) *Type {
+	literals := make([]ref.Val, len(values))
+	for i, v := range values {
+		literals[i] = Int(v)
+	}
+	return newLiteralType(IntType, literals)
+}
+
+func newLiteralType(base *Type, values []ref.Val) *Type {
+	var literals []ref.Val
+	for _, v := range values {
+		if !containsLiterals(literals, []ref.Val{v}) {
+			literals = append(literals, v)
+		}
+	}
+	t := &Type{
+		kind:            base.Kind(),
+		runtimeTypeName: base.TypeName(),
+		traitMask:       base.traitMask,
+		literals:        literals,
+	}
+	t.isAssignableType = func(other *Type) bool {
+		if other.IsLiteralType() {
+			return other.Kind() == t.Kind() && containsLiterals(literals, other.literals)
+		}
+		return t.defaultIsAssignableType(other)
+	}
+	return t
+}
+
+// containsLiterals returns whether all of the values are within the set of literals.
+func containsLiterals(literals, values []ref.Val) bool {
+	for _, v := range values {
+		found := false
+		for _, l := range literals {
+			if l.Equal(v) == True {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func formatLiterals(literals []ref.Val) string {
+	vals := make([]string, len(literals))
+	for i, l := range literals {
+		if s, isStr := l.(String); isStr {
+			vals[i] = strconv.Quote(string(s))
+			continue
+		}
+		vals[i] = fmt.Sprintf("%v", l.Value())
+	}
+	return strings.Join(vals, " | ")
+}
+
 // NewOptionalType creates an abstract parameterized type instance corresponding to CEL's notion of optional.
 func NewOptionalType(param *Type) *Type {
 	return NewOpaqueType("optional_type", param)
@@ -670,6 +864,9 @@ func TypeToExprType(t *Type) (*exprpb.Type, error) {
 		return chkdecls.NewTypeType(nil), nil
 	case UintKind:
 		return maybeWrapper(t, chkdecls.Uint), nil
+	case UnionKind:
+		// Union types have no protobuf representation and are erased to dyn.
+		return chkdecls.Dyn, nil
 	}
 	return nil, fmt.Errorf("missing type conversion to proto: %v", t)
 }
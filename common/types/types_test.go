@@ -23,6 +23,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	chkdecls "cel.dev/cel-go/checker/decls"
+	"cel.dev/cel-go/common/types/ref"
 	"cel.dev/cel-go/common/types/traits"
 
 	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
@@ -816,3 +817,115 @@ func TestTypeConvertToNative(t *testing.T) {
 		t.Error("ConvertToNative() did not error")
 	}
 }
+
+func TestUnionType(t *testing.T) {
+	tests := []struct {
+		in  *Type
+		out string
+	}{
+		{
+			in:  NewUnionType(StringType, IntType),
+			out: "string | int",
+		},
+		{
+			in:  NewUnionType(StringType, NewUnionType(IntType, StringType)),
+			out: "string | int",
+		},
+		{
+			in:  NewUnionType(StringType, StringType),
+			out: "string",
+		},
+		{
+			in:  NewUnionType(StringType, DynType),
+			out: "dyn",
+		},
+		{
+			in:  NewUnionType(NewStringLiteralType("GET"), NewStringLiteralType("POST", "GET"), IntType),
+			out: `"GET" | "POST" | int`,
+		},
+		{
+			in:  NewIntLiteralType(1, 2, 2),
+			out: "1 | 2",
+		},
+	}
+	for _, tst := range tests {
+		if tst.in.String() != tst.out {
+			t.Errorf("String() got %v, wanted %v", tst.in, tst.out)
+		}
+	}
+}
+
+func TestUnionTypeIsAssignableType(t *testing.T) {
+	strOrInt := NewUnionType(StringType, IntType)
+	methods := NewStringLiteralType("GET", "POST")
+	tests := []struct {
+		t1           *Type
+		t2           *Type
+		isAssignable bool
+	}{
+		{t1: strOrInt, t2: StringType, isAssignable: true},
+		{t1: strOrInt, t2: IntType, isAssignable: true},
+		{t1: strOrInt, t2: NewUnionType(IntType, StringType), isAssignable: true},
+		{t1: strOrInt, t2: methods, isAssignable: true},
+		{t1: strOrInt, t2: DoubleType, isAssignable: false},
+		{t1: strOrInt, t2: NewUnionType(IntType, DoubleType), isAssignable: false},
+		{t1: StringType, t2: strOrInt, isAssignable: false},
+		{t1: methods, t2: StringType, isAssignable: true},
+		{t1: methods, t2: NewStringLiteralType("GET"), isAssignable: true},
+		{t1: methods, t2: NewStringLiteralType("PUT"), isAssignable: false},
+		{t1: methods, t2: IntType, isAssignable: false},
+		{t1: StringType, t2: methods, isAssignable: true},
+		{t1: NewIntLiteralType(1, 2), t2: NewStringLiteralType("1"), isAssignable: false},
+	}
+	for _, tst := range tests {
+		if tst.t1.IsAssignableType(tst.t2) != tst.isAssignable {
+			t.Errorf("%v.IsAssignableType(%v) got %v, wanted %v", tst.t1, tst.t2, !tst.isAssignable, tst.isAssignable)
+		}
+	}
+}
+
+func TestUnionTypeIsAssignableRuntimeType(t *testing.T) {
+	strOrInt := NewUnionType(StringType, IntType)
+	if !strOrInt.IsAssignableRuntimeType(String("hello")) {
+		t.Error("strOrInt.IsAssignableRuntimeType('hello') returned false")
+	}
+	if !strOrInt.IsAssignableRuntimeType(Int(1)) {
+		t.Error("strOrInt.IsAssignableRuntimeType(1) returned false")
+	}
+	if strOrInt.IsAssignableRuntimeType(Double(1)) {
+		t.Error("strOrInt.IsAssignableRuntimeType(1.0) returned true")
+	}
+	if !strOrInt.HasTrait(traits.AdderType) {
+		t.Error("strOrInt.HasTrait(AdderType) returned false")
+	}
+	if strOrInt.HasTrait(traits.SizerType) {
+		t.Error("strOrInt.HasTrait(SizerType) returned true")
+	}
+}
+
+func TestLiteralType(t *testing.T) {
+	methods := NewStringLiteralType("GET", "POST")
+	if !methods.IsLiteralType() || StringType.IsLiteralType() {
+		t.Error("IsLiteralType() returned an unexpected result")
+	}
+	if !reflect.DeepEqual(methods.LiteralValues(), []ref.Val{String("GET"), String("POST")}) {
+		t.Errorf("LiteralValues() got %v, wanted [GET POST]", methods.LiteralValues())
+	}
+	if methods.TypeName() != StringType.TypeName() {
+		t.Errorf("TypeName() got %v, wanted %v", methods.TypeName(), StringType.TypeName())
+	}
+	if methods.IsExactType(StringType) || methods.IsExactType(NewStringLiteralType("GET")) {
+		t.Error("IsExactType() returned true for a different literal type")
+	}
+	if !methods.IsExactType(NewStringLiteralType("GET", "POST")) {
+		t.Error("IsExactType() returned false for the same literal type")
+	}
+	// Union types are erased to dyn within the protobuf type representation.
+	et, err := TypeToExprType(NewUnionType(StringType, IntType))
+	if err != nil {
+		t.Fatalf("TypeToExprType() failed: %v", err)
+	}
+	if !proto.Equal(et, chkdecls.Dyn) {
+		t.Errorf("TypeToExprType() got %v, wanted dyn", et)
+	}
+}
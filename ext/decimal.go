@@ -0,0 +1,1153 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ext
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/checker"
+	"cel.dev/cel-go/common/cost"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+	"cel.dev/cel-go/interpreter"
+
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+// Decimals returns a cel.EnvOption to configure an exact decimal number type along with functions
+// for creating, converting, and rounding decimal values.
+//
+// Decimal values are represented as an arbitrary precision coefficient and a base-10 scale, so
+// values such as `0.1` are represented exactly. The results of arithmetic are rounded to the
+// configured number of significant digits, 34 by default, using the configured rounding mode,
+// RoundHalfEven by default. See DecimalPrecision and DecimalRounding.
+//
+// This library includes a TypeAdapter which converts `google.type.Decimal` protobuf messages, and
+// `google.protobuf.Any` messages which contain them, to decimal values. Decimal values may be
+// converted to `google.type.Decimal` messages, e.g. with cel.ValueAsProto.
+//
+// # Decimal
+//
+// The `decimal` function creates a decimal from a string, int, uint, or double. Strings use the
+// same format as `google.type.Decimal`: an optional sign, digits with an optional decimal point,
+// and an optional exponent. Doubles are converted from their shortest decimal representation.
+//
+//	decimal(string) -> decimal
+//	decimal(int) -> decimal
+//	decimal(uint) -> decimal
+//	decimal(double) -> decimal
+//
+// Examples:
+//
+//	decimal('19.99')
+//	decimal('-1.5e3') == decimal(-1500)
+//	decimal(0.1) == decimal('0.1')
+//
+// # Arithmetic
+//
+// Decimals support the `+`, `-`, `*`, `/`, and `%` operators as well as negation. Addition,
+// subtraction, multiplication, and modulus are exact unless the result exceeds the configured
+// precision. Division by zero and modulus by zero produce errors.
+//
+// Examples:
+//
+//	decimal('0.1') + decimal('0.2') == decimal('0.3') // true
+//	decimal('19.99') * decimal(3) // decimal('59.97')
+//	decimal(1) / decimal(3) // decimal('0.3333333333333333333333333333333333')
+//	decimal('10.5') % decimal(3) // decimal('1.5')
+//
+// # Comparison
+//
+// Decimals may be compared with one another using the standard equality and ordering operators.
+// When cel.CrossTypeNumericComparisons is enabled, decimals may also be ordered against int, uint,
+// and double values. Following CEL's heterogeneous equality, a decimal is equal to an int, uint, or
+// double value with the same numeric value. Comparisons against doubles use the exact binary value
+// of the double.
+//
+// Examples:
+//
+//	decimal('1.50') == decimal('1.5') // true
+//	decimal('2.5') < decimal(3) // true
+//	decimal('2.5') < 3 // true with cel.CrossTypeNumericComparisons
+//	dyn(decimal('2.0')) == 2 // true
+//
+// # Round
+//
+// Returns the decimal rounded to the given number of digits after the decimal point using the
+// configured rounding mode. A negative scale rounds to a power of ten.
+//
+//	<decimal>.round(<int>) -> <decimal>
+//
+// Examples:
+//
+//	decimal('2.345').round(2) // decimal('2.34') with RoundHalfEven
+//	decimal('1250').round(-2) // decimal('1.2e3')
+//
+// # Conversions
+//
+// Decimals may be converted to strings, doubles, ints, and uints. Conversion to int and uint
+// truncates any fractional digits and produces an error if the result is out of range.
+//
+//	string(decimal) -> string
+//	double(decimal) -> double
+//	int(decimal) -> int
+//	uint(decimal) -> uint
+//
+// Examples:
+//
+//	string(decimal('1.50')) // '1.50'
+//	int(decimal('-2.7')) // -2
+func Decimals(opts ...DecimalsOption) cel.EnvOption {
+	lib := &decimalLib{context: defaultDecimalContext}
+	for _, o := range opts {
+		lib = o(lib)
+	}
+	return func(e *cel.Env) (*cel.Env, error) {
+		if lib.context.precision <= 0 {
+			return nil, fmt.Errorf("invalid decimal precision: %d", lib.context.precision)
+		}
+		e, err := cel.Lib(lib)(e)
+		if err != nil {
+			return nil, err
+		}
+		adapter := &decimalAdapter{Adapter: e.CELTypeAdapter(), context: lib.context}
+		return cel.CustomTypeAdapter(adapter)(e)
+	}
+}
+
+// DecimalsOption declares a functional operator for configuring the Decimals library behavior.
+type DecimalsOption func(*decimalLib) *decimalLib
+
+// DecimalPrecision sets the maximum number of significant digits retained by the results of
+// decimal operations. The default precision of 34 digits matches IEEE 754 decimal128.
+func DecimalPrecision(digits int) DecimalsOption {
+	return func(lib *decimalLib) *decimalLib {
+		lib.context = &decimalContext{precision: digits, rounding: lib.context.rounding}
+		return lib
+	}
+}
+
+// DecimalRounding sets the rounding mode used when a result exceeds the configured precision
+// and by the `round` function.
+func DecimalRounding(mode RoundingMode) DecimalsOption {
+	return func(lib *decimalLib) *decimalLib {
+		lib.context = &decimalContext{precision: lib.context.precision, rounding: mode}
+		return lib
+	}
+}
+
+// RoundingMode determines how decimal values are rounded when digits are discarded.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, with ties rounded to the even neighbor.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, with ties rounded away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest value, with ties rounded toward zero.
+	RoundHalfDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundDown rounds toward zero.
+	RoundDown
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+)
+
+const (
+	decimalFunc    = "decimal"
+	decimalRound   = "round"
+	decimalTypeURL = "type.googleapis.com/google.type.Decimal"
+
+	decimalProtoName = protoreflect.FullName("google.type.Decimal")
+
+	defaultDecimalPrecision = 34
+	// maxDecimalExponent is the largest supported power of ten of the most significant digit.
+	maxDecimalExponent = 6144
+	// decimalDigitCostFactor is the cost of processing each digit of a decimal value.
+	decimalDigitCostFactor = 0.1
+)
+
+var (
+	// DecimalType represents an exact decimal number.
+	DecimalType = types.NewOpaqueType("decimal").WithTraits(
+		traits.AdderType | traits.ComparerType | traits.DividerType | traits.ModderType |
+			traits.MultiplierType | traits.NegatorType | traits.SubtractorType)
+
+	defaultDecimalContext = &decimalContext{precision: defaultDecimalPrecision, rounding: RoundHalfEven}
+
+	bigOne = big.NewInt(1)
+	bigTen = big.NewInt(10)
+
+	anyPbType        = reflect.TypeOf(&anypb.Any{})
+	bigRatType       = reflect.TypeOf(&big.Rat{})
+	decimalValueType = reflect.TypeOf(Decimal{})
+	protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+	errDecimalOverflow = errors.New("decimal overflow")
+)
+
+type decimalLib struct {
+	context *decimalContext
+}
+
+func (*decimalLib) LibraryName() string {
+	return "cel.lib.ext.decimal"
+}
+
+func (lib *decimalLib) CompileOptions() []cel.EnvOption {
+	opts := []cel.EnvOption{
+		cel.Types(DecimalType),
+		cel.Function(decimalFunc,
+			cel.Overload("string_to_decimal", []*cel.Type{cel.StringType}, DecimalType,
+				cel.UnaryBinding(lib.stringToDecimal)),
+			cel.Overload("int_to_decimal", []*cel.Type{cel.IntType}, DecimalType,
+				cel.UnaryBinding(lib.intToDecimal)),
+			cel.Overload("uint_to_decimal", []*cel.Type{cel.UintType}, DecimalType,
+				cel.UnaryBinding(lib.uintToDecimal)),
+			cel.Overload("double_to_decimal", []*cel.Type{cel.DoubleType}, DecimalType,
+				cel.UnaryBinding(lib.doubleToDecimal)),
+		),
+		cel.Function(decimalRound,
+			cel.MemberOverload("decimal_round_int", []*cel.Type{DecimalType, cel.IntType}, DecimalType,
+				cel.BinaryBinding(decimalRoundScale)),
+		),
+		cel.Function("string",
+			cel.Overload("decimal_to_string", []*cel.Type{DecimalType}, cel.StringType,
+				cel.UnaryBinding(decimalConvertTo(types.StringType))),
+		),
+		cel.Function("double",
+			cel.Overload("decimal_to_double", []*cel.Type{DecimalType}, cel.DoubleType,
+				cel.UnaryBinding(decimalConvertTo(types.DoubleType))),
+		),
+		cel.Function("int",
+			cel.Overload("decimal_to_int", []*cel.Type{DecimalType}, cel.IntType,
+				cel.UnaryBinding(decimalConvertTo(types.IntType))),
+		),
+		cel.Function("uint",
+			cel.Overload("decimal_to_uint", []*cel.Type{DecimalType}, cel.UintType,
+				cel.UnaryBinding(decimalConvertTo(types.UintType))),
+		),
+		// The arithmetic and comparison operators are bound by the standard library to the traits
+		// implemented by the Decimal type.
+		cel.Function(operators.Add,
+			cel.Overload("add_decimal", []*cel.Type{DecimalType, DecimalType}, DecimalType)),
+		cel.Function(operators.Subtract,
+			cel.Overload("subtract_decimal", []*cel.Type{DecimalType, DecimalType}, DecimalType)),
+		cel.Function(operators.Multiply,
+			cel.Overload("multiply_decimal", []*cel.Type{DecimalType, DecimalType}, DecimalType)),
+		cel.Function(operators.Divide,
+			cel.Overload("divide_decimal", []*cel.Type{DecimalType, DecimalType}, DecimalType)),
+		cel.Function(operators.Modulo,
+			cel.Overload("modulo_decimal", []*cel.Type{DecimalType, DecimalType}, DecimalType)),
+		cel.Function(operators.Negate,
+			cel.Overload("negate_decimal", []*cel.Type{DecimalType}, DecimalType)),
+	}
+	costOpts := []checker.CostOption{
+		checker.OverloadCostEstimate("string_to_decimal", lib.estimateParseCost),
+		checker.OverloadCostEstimate("int_to_decimal", lib.estimateNewDecimalCost),
+		checker.OverloadCostEstimate("uint_to_decimal", lib.estimateNewDecimalCost),
+		checker.OverloadCostEstimate("double_to_decimal", lib.estimateNewDecimalCost),
+		checker.OverloadCostEstimate("decimal_round_int", lib.estimateUnaryCost),
+		checker.OverloadCostEstimate("decimal_to_string", lib.estimateToStringCost),
+		checker.OverloadCostEstimate("decimal_to_double", lib.estimateUnaryCost),
+		checker.OverloadCostEstimate("decimal_to_int", lib.estimateUnaryCost),
+		checker.OverloadCostEstimate("decimal_to_uint", lib.estimateUnaryCost),
+		checker.OverloadCostEstimate("add_decimal", lib.estimateLinearCost),
+		checker.OverloadCostEstimate("subtract_decimal", lib.estimateLinearCost),
+		checker.OverloadCostEstimate("multiply_decimal", lib.estimateMultiplyCost),
+		checker.OverloadCostEstimate("divide_decimal", lib.estimateDivideCost),
+		checker.OverloadCostEstimate("modulo_decimal", lib.estimateLinearCost),
+		checker.OverloadCostEstimate("negate_decimal", lib.estimateUnaryCost),
+	}
+	for _, op := range decimalComparisons {
+		overloadID := op.prefix + "_decimal"
+		overloads := []cel.FunctionOpt{
+			cel.Overload(overloadID, []*cel.Type{DecimalType, DecimalType}, cel.BoolType),
+		}
+		costOpts = append(costOpts, checker.OverloadCostEstimate(overloadID, lib.estimateCompareCost))
+		for _, other := range decimalComparableTypes {
+			lhsID := fmt.Sprintf("%s_decimal_%s", op.prefix, other.name)
+			rhsID := fmt.Sprintf("%s_%s_decimal", op.prefix, other.name)
+			overloads = append(overloads,
+				cel.Overload(lhsID, []*cel.Type{DecimalType, other.t}, cel.BoolType,
+					cel.OverloadIsCrossTypeNumericComparison()),
+				cel.Overload(rhsID, []*cel.Type{other.t, DecimalType}, cel.BoolType,
+					cel.OverloadIsCrossTypeNumericComparison()),
+			)
+			costOpts = append(costOpts,
+				checker.OverloadCostEstimate(lhsID, lib.estimateCompareCost),
+				checker.OverloadCostEstimate(rhsID, lib.estimateCompareCost))
+		}
+		opts = append(opts, cel.Function(op.function, overloads...))
+	}
+	return append(opts, cel.CostEstimatorOptions(costOpts...))
+}
+
+func (lib *decimalLib) ProgramOptions() []cel.ProgramOption {
+	trackers := []interpreter.CostTrackerOption{
+		interpreter.OverloadCostTracker("string_to_decimal", trackDecimalParseCost),
+		interpreter.OverloadCostTracker("int_to_decimal", trackDecimalNewCost),
+		interpreter.OverloadCostTracker("uint_to_decimal", trackDecimalNewCost),
+		interpreter.OverloadCostTracker("double_to_decimal", trackDecimalNewCost),
+		interpreter.OverloadCostTracker("decimal_round_int", trackDecimalUnaryCost),
+		interpreter.OverloadCostTracker("decimal_to_string", trackDecimalUnaryCost),
+		interpreter.OverloadCostTracker("decimal_to_double", trackDecimalUnaryCost),
+		interpreter.OverloadCostTracker("decimal_to_int", trackDecimalUnaryCost),
+		interpreter.OverloadCostTracker("decimal_to_uint", trackDecimalUnaryCost),
+		interpreter.OverloadCostTracker("add_decimal", trackDecimalLinearCost),
+		interpreter.OverloadCostTracker("subtract_decimal", trackDecimalLinearCost),
+		interpreter.OverloadCostTracker("multiply_decimal", trackDecimalMultiplyCost),
+		interpreter.OverloadCostTracker("divide_decimal", lib.trackDivideCost),
+		interpreter.OverloadCostTracker("modulo_decimal", trackDecimalLinearCost),
+		interpreter.OverloadCostTracker("negate_decimal", trackDecimalUnaryCost),
+	}
+	for _, op := range decimalComparisons {
+		trackers = append(trackers, interpreter.OverloadCostTracker(op.prefix+"_decimal", trackDecimalLinearCost))
+		for _, other := range decimalComparableTypes {
+			trackers = append(trackers,
+				interpreter.OverloadCostTracker(fmt.Sprintf("%s_decimal_%s", op.prefix, other.name), trackDecimalLinearCost),
+				interpreter.OverloadCostTracker(fmt.Sprintf("%s_%s_decimal", op.prefix, other.name), trackDecimalLinearCost))
+		}
+	}
+	return []cel.ProgramOption{
+		cel.CostTrackerOptions(trackers...),
+		cel.CustomDecoratorV2(decorateDecimalOperands),
+	}
+}
+
+var (
+	decimalComparisons = []struct {
+		function string
+		prefix   string
+	}{
+		{function: operators.Less, prefix: "less"},
+		{function: operators.LessEquals, prefix: "less_equals"},
+		{function: operators.Greater, prefix: "greater"},
+		{function: operators.GreaterEquals, prefix: "greater_equals"},
+	}
+
+	decimalComparableTypes = []struct {
+		name string
+		t    *cel.Type
+	}{
+		{name: "int64", t: cel.IntType},
+		{name: "uint64", t: cel.UintType},
+		{name: "double", t: cel.DoubleType},
+	}
+
+	// decimalReversedComparisons contains the ids of the comparison overloads in which a decimal
+	// appears on the right-hand side of an int, uint, or double.
+	decimalReversedComparisons = reversedComparisonOverloads()
+)
+
+func reversedComparisonOverloads() map[string]bool {
+	ids := map[string]bool{}
+	for _, op := range decimalComparisons {
+		for _, other := range decimalComparableTypes {
+			ids[fmt.Sprintf("%s_%s_decimal", op.prefix, other.name)] = true
+		}
+	}
+	return ids
+}
+
+// decorateDecimalOperands plans comparisons and equality tests in which a decimal may appear on the
+// right-hand side of an int, uint, or double. The built-in numeric types are unaware of decimals, so
+// these operations are evaluated by the decimal operand instead.
+func decorateDecimalOperands(i interpreter.InterpretableV2) (interpreter.InterpretableV2, error) {
+	call, ok := i.(interpreter.InterpretableCall)
+	if !ok || len(call.Args()) != 2 {
+		return i, nil
+	}
+	switch call.Function() {
+	case operators.Equals, operators.NotEquals:
+	case operators.Less, operators.LessEquals, operators.Greater, operators.GreaterEquals:
+		if !decimalReversedComparisons[call.OverloadID()] {
+			return i, nil
+		}
+	default:
+		return i, nil
+	}
+	return &evalDecimalOperands{InterpretableCall: call, lhs: call.Args()[0], rhs: call.Args()[1]}, nil
+}
+
+// evalDecimalOperands evaluates a binary comparison or equality test, deferring to the right-hand
+// side when it is a decimal.
+type evalDecimalOperands struct {
+	interpreter.InterpretableCall
+	lhs interpreter.InterpretableV2
+	rhs interpreter.InterpretableV2
+}
+
+// Exec implements the InterpretableV2 interface method.
+func (e *evalDecimalOperands) Exec(frame *interpreter.ExecutionFrame) ref.Val {
+	lVal := e.lhs.Exec(frame)
+	if types.IsError(lVal) {
+		return lVal
+	}
+	rVal := e.rhs.Exec(frame)
+	if types.IsError(rVal) {
+		return rVal
+	}
+	var unk *types.Unknown
+	unk, _ = types.MaybeMergeUnknowns(lVal, unk)
+	unk, _ = types.MaybeMergeUnknowns(rVal, unk)
+	if unk != nil {
+		return unk
+	}
+	d, isDecimal := rVal.(Decimal)
+	switch e.Function() {
+	case operators.Equals:
+		if isDecimal {
+			return d.Equal(lVal)
+		}
+		return types.Equal(lVal, rVal)
+	case operators.NotEquals:
+		if isDecimal {
+			return types.Bool(d.Equal(lVal) != types.True)
+		}
+		return types.Bool(types.Equal(lVal, rVal) != types.True)
+	}
+	if !isDecimal {
+		return types.NewErrWithNodeID(e.ID(), "no such overload: %s", e.Function())
+	}
+	// NaN is unordered, so every comparison against it is false.
+	if f, isDouble := lVal.(types.Double); isDouble && math.IsNaN(float64(f)) {
+		return types.False
+	}
+	cmp, isInt := d.Compare(lVal).(types.Int)
+	if !isInt {
+		return types.NewErrWithNodeID(e.ID(), "no such overload: %s", e.Function())
+	}
+	// The decimal compared itself to the left-hand side, so the result is reversed.
+	switch e.Function() {
+	case operators.Less:
+		return types.Bool(cmp > 0)
+	case operators.LessEquals:
+		return types.Bool(cmp >= 0)
+	case operators.Greater:
+		return types.Bool(cmp < 0)
+	default:
+		return types.Bool(cmp <= 0)
+	}
+}
+
+// Eval implements the Interpretable interface method.
+func (e *evalDecimalOperands) Eval(ctx interpreter.Activation) ref.Val {
+	return e.Exec(interpreter.AsFrame(ctx))
+}
+
+func (lib *decimalLib) stringToDecimal(val ref.Val) ref.Val {
+	d, err := parseDecimal(string(val.(types.String)), lib.context)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+func (lib *decimalLib) intToDecimal(val ref.Val) ref.Val {
+	return lib.context.finish(big.NewInt(int64(val.(types.Int))), 0)
+}
+
+func (lib *decimalLib) uintToDecimal(val ref.Val) ref.Val {
+	return lib.context.finish(new(big.Int).SetUint64(uint64(val.(types.Uint))), 0)
+}
+
+func (lib *decimalLib) doubleToDecimal(val ref.Val) ref.Val {
+	f := float64(val.(types.Double))
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return types.NewErr("invalid decimal: %v", f)
+	}
+	d, err := parseDecimal(strconv.FormatFloat(f, 'g', -1, 64), lib.context)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+func decimalRoundScale(val, scale ref.Val) ref.Val {
+	return val.(Decimal).Round(int64(scale.(types.Int)))
+}
+
+func decimalConvertTo(t ref.Type) func(ref.Val) ref.Val {
+	return func(val ref.Val) ref.Val {
+		return val.ConvertToType(t)
+	}
+}
+
+// ParseDecimal parses a decimal value from a string using the default precision and rounding.
+//
+// The string format matches `google.type.Decimal`, e.g. `-12.50` or `1.5e-3`.
+func ParseDecimal(s string) (Decimal, error) {
+	return parseDecimal(s, defaultDecimalContext)
+}
+
+func parseDecimal(s string, ctx *decimalContext) (Decimal, error) {
+	invalid := fmt.Errorf("invalid decimal: %q", s)
+	str := s
+	neg := false
+	if len(str) > 0 && (str[0] == '+' || str[0] == '-') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+	mantissa, exponent := str, ""
+	if idx := strings.IndexAny(str, "eE"); idx >= 0 {
+		mantissa, exponent = str[:idx], str[idx+1:]
+		if exponent == "" {
+			return Decimal{}, invalid
+		}
+	}
+	intPart, fracPart := mantissa, ""
+	if idx := strings.IndexByte(mantissa, '.'); idx >= 0 {
+		intPart, fracPart = mantissa[:idx], mantissa[idx+1:]
+	}
+	digits := intPart + fracPart
+	if digits == "" || !isDigits(digits) {
+		return Decimal{}, invalid
+	}
+	scale := int64(len(fracPart))
+	if exponent != "" {
+		unsigned := strings.TrimLeft(exponent, "+-")
+		if len(exponent)-len(unsigned) > 1 || !isDigits(unsigned) {
+			return Decimal{}, invalid
+		}
+		exp, err := strconv.ParseInt(exponent, 10, 32)
+		if err != nil {
+			return Decimal{}, errDecimalOverflow
+		}
+		scale -= exp
+	}
+	if scale > math.MaxInt32 || scale < math.MinInt32 {
+		return Decimal{}, errDecimalOverflow
+	}
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+	return ctx.newDecimal(coef, int(scale))
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// decimalContext holds the precision and rounding mode used by decimal operations.
+type decimalContext struct {
+	precision int
+	rounding  RoundingMode
+}
+
+// newDecimal creates a decimal from the coefficient and scale, rounding the coefficient to the
+// context precision.
+func (ctx *decimalContext) newDecimal(coef *big.Int, scale int) (Decimal, error) {
+	if n := numDigits(coef); n > ctx.precision {
+		drop := n - ctx.precision
+		coef = roundQuotient(coef, pow10(drop), ctx.rounding)
+		scale -= drop
+		// Rounding up may carry into an additional digit, e.g. 999 -> 1000.
+		if numDigits(coef) > ctx.precision {
+			coef.Quo(coef, bigTen)
+			scale--
+		}
+	}
+	// Values smaller than the smallest supported exponent are rounded, possibly to zero.
+	if maxScale := maxDecimalExponent + ctx.precision; scale > maxScale {
+		coef = roundQuotient(coef, pow10(scale-maxScale), ctx.rounding)
+		scale = maxScale
+	}
+	if coef.Sign() == 0 && scale < 0 {
+		scale = 0
+	}
+	if scale < 0 {
+		n := numDigits(coef)
+		if n-1-scale > maxDecimalExponent {
+			return Decimal{}, errDecimalOverflow
+		}
+		// Prefer an integral representation when the value fits within the precision.
+		if n-scale <= ctx.precision {
+			coef = new(big.Int).Mul(coef, pow10(-scale))
+			scale = 0
+		}
+	}
+	return Decimal{coef: coef, scale: scale, context: ctx}, nil
+}
+
+// finish wraps newDecimal for use as the result of a CEL operation.
+func (ctx *decimalContext) finish(coef *big.Int, scale int) ref.Val {
+	d, err := ctx.newDecimal(coef, scale)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+// Decimal is an exact decimal number, equal to coef * 10^-scale.
+type Decimal struct {
+	coef    *big.Int
+	scale   int
+	context *decimalContext
+}
+
+// Add implements traits.Adder.
+func (d Decimal) Add(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	a, b, scale := alignDecimals(d, o)
+	return d.ctx().finish(a.Add(a, b), scale)
+}
+
+// Subtract implements traits.Subtractor.
+func (d Decimal) Subtract(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	a, b, scale := alignDecimals(d, o)
+	return d.ctx().finish(a.Sub(a, b), scale)
+}
+
+// Multiply implements traits.Multiplier.
+func (d Decimal) Multiply(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return d.ctx().finish(new(big.Int).Mul(d.coef, o.coef), d.scale+o.scale)
+}
+
+// Divide implements traits.Divider, rounding the quotient to the context precision.
+func (d Decimal) Divide(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	if o.coef.Sign() == 0 {
+		return types.NewErr("division by zero")
+	}
+	ctx := d.ctx()
+	idealScale := d.scale - o.scale
+	if d.coef.Sign() == 0 {
+		return ctx.finish(new(big.Int), max(idealScale, 0))
+	}
+	// Scale the dividend so that the quotient has at least two more digits than the precision.
+	extra := max(ctx.precision+2+numDigits(o.coef)-numDigits(d.coef), 0)
+	q, r := new(big.Int).QuoRem(new(big.Int).Mul(d.coef, pow10(extra)), o.coef, new(big.Int))
+	scale := idealScale + extra
+	if r.Sign() != 0 {
+		// Append a sticky digit so that rounding accounts for the non-zero remainder.
+		q.Mul(q, bigTen)
+		if q.Sign() < 0 {
+			q.Sub(q, bigOne)
+		} else {
+			q.Add(q, bigOne)
+		}
+		scale++
+	} else {
+		// Exact quotients drop the trailing zeros introduced by scaling the dividend.
+		rem := new(big.Int)
+		for scale > idealScale {
+			qt, _ := new(big.Int).QuoRem(q, bigTen, rem)
+			if rem.Sign() != 0 {
+				break
+			}
+			q = qt
+			scale--
+		}
+	}
+	return ctx.finish(q, scale)
+}
+
+// Modulo implements traits.Modder, where the result has the sign of the dividend.
+func (d Decimal) Modulo(other ref.Val) ref.Val {
+	o, ok := other.(Decimal)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	if o.coef.Sign() == 0 {
+		return types.NewErr("modulus by zero")
+	}
+	a, b, scale := alignDecimals(d, o)
+	return d.ctx().finish(a.Rem(a, b), scale)
+}
+
+// Negate implements traits.Negater.
+func (d Decimal) Negate() ref.Val {
+	return Decimal{coef: new(big.Int).Neg(d.coef), scale: d.scale, context: d.context}
+}
+
+// Round returns the decimal rounded to the given number of fractional digits.
+func (d Decimal) Round(scale int64) ref.Val {
+	if scale > maxDecimalExponent || scale < -maxDecimalExponent {
+		return types.NewErr("invalid decimal scale: %d", scale)
+	}
+	ctx := d.ctx()
+	s := int(scale)
+	if s >= d.scale {
+		return d
+	}
+	return ctx.finish(roundQuotient(d.coef, pow10(d.scale-s), ctx.rounding), s)
+}
+
+// Compare implements traits.Comparer, supporting comparisons against decimal, int, uint, and double
+// values.
+func (d Decimal) Compare(other ref.Val) ref.Val {
+	switch o := other.(type) {
+	case Decimal:
+		return types.Int(compareDecimals(d, o))
+	case types.Int:
+		return types.Int(compareDecimals(d, Decimal{coef: big.NewInt(int64(o))}))
+	case types.Uint:
+		return types.Int(compareDecimals(d, Decimal{coef: new(big.Int).SetUint64(uint64(o))}))
+	case types.Double:
+		f := float64(o)
+		switch {
+		case math.IsNaN(f):
+			return types.NewErr("NaN values cannot be ordered")
+		case math.IsInf(f, 1):
+			return types.IntNegOne
+		case math.IsInf(f, -1):
+			return types.IntOne
+		}
+		return types.Int(d.rat().Cmp(new(big.Rat).SetFloat64(f)))
+	}
+	return types.MaybeNoSuchOverloadErr(other)
+}
+
+// ConvertToNative implements ref.Val.
+//
+// Decimals may be converted to strings, floats, *big.Rat, `google.type.Decimal` messages, and
+// `google.protobuf.Any` messages containing a `google.type.Decimal`.
+func (d Decimal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case decimalValueType:
+		return d, nil
+	case bigRatType:
+		return d.rat(), nil
+	case anyPbType:
+		return &anypb.Any{TypeUrl: decimalTypeURL, Value: d.marshalProto()}, nil
+	}
+	switch typeDesc.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(d.String()).Convert(typeDesc).Interface(), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := d.float64()
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(f).Convert(typeDesc).Interface(), nil
+	}
+	if typeDesc.Kind() == reflect.Ptr && typeDesc.Implements(protoMessageType) {
+		msg := reflect.New(typeDesc.Elem()).Interface().(proto.Message)
+		if field, ok := decimalValueField(msg); ok {
+			msg.ProtoReflect().Set(field, protoreflect.ValueOfString(d.String()))
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("type conversion error from '%s' to '%v'", DecimalType, typeDesc)
+}
+
+// ConvertToType implements ref.Val.
+func (d Decimal) ConvertToType(typeValue ref.Type) ref.Val {
+	switch typeValue {
+	case DecimalType:
+		return d
+	case types.TypeType:
+		return DecimalType
+	case types.StringType:
+		return types.String(d.String())
+	case types.DoubleType:
+		f, err := d.float64()
+		if err != nil {
+			return types.WrapErr(err)
+		}
+		return types.Double(f)
+	case types.IntType:
+		i := d.truncate()
+		if !i.IsInt64() {
+			return types.NewErr("integer overflow")
+		}
+		return types.Int(i.Int64())
+	case types.UintType:
+		i := d.truncate()
+		if !i.IsUint64() {
+			return types.NewErr("unsigned integer overflow")
+		}
+		return types.Uint(i.Uint64())
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", DecimalType, typeValue)
+}
+
+// Equal implements ref.Val, comparing decimals by numeric value so that `1.50 == 1.5`.
+//
+// Decimals are also equal to int, uint, and double values with the same numeric value, in agreement
+// with Compare.
+func (d Decimal) Equal(other ref.Val) ref.Val {
+	switch other.(type) {
+	case Decimal, types.Int, types.Uint, types.Double:
+		return types.Bool(d.Compare(other) == types.IntZero)
+	}
+	return types.False
+}
+
+// Type implements ref.Val.
+func (d Decimal) Type() ref.Type {
+	return DecimalType
+}
+
+// Value implements ref.Val, returning the decimal itself.
+func (d Decimal) Value() any {
+	return d
+}
+
+// Size returns the number of digits in the decimal coefficient.
+// Used in the size estimation of the runtime cost.
+func (d Decimal) Size() ref.Val {
+	return types.Int(numDigits(d.coef))
+}
+
+// String returns the decimal in plain notation, or in scientific notation when the value is an
+// integer too large to represent within the configured precision.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.coef).Text(10)
+	sign := ""
+	if d.coef.Sign() < 0 {
+		sign = "-"
+	}
+	switch {
+	case d.scale < 0:
+		return sign + digits + "e" + strconv.Itoa(-d.scale)
+	case d.scale == 0:
+		return sign + digits
+	}
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+	split := len(digits) - d.scale
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+func (d Decimal) ctx() *decimalContext {
+	if d.context == nil {
+		return defaultDecimalContext
+	}
+	return d.context
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.scale >= 0 {
+		return new(big.Rat).SetFrac(d.coef, pow10(d.scale))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Mul(d.coef, pow10(-d.scale)))
+}
+
+func (d Decimal) float64() (float64, error) {
+	f, err := strconv.ParseFloat(d.String(), 64)
+	if err != nil && math.IsInf(f, 0) {
+		return 0, errors.New("double overflow")
+	}
+	return f, nil
+}
+
+// truncate returns the integral part of the decimal.
+func (d Decimal) truncate() *big.Int {
+	if d.scale <= 0 {
+		return new(big.Int).Mul(d.coef, pow10(-d.scale))
+	}
+	return new(big.Int).Quo(d.coef, pow10(d.scale))
+}
+
+// marshalProto encodes the decimal as a `google.type.Decimal` message.
+func (d Decimal) marshalProto() []byte {
+	return protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), d.String())
+}
+
+// alignDecimals returns copies of the decimal coefficients scaled to a common scale.
+func alignDecimals(a, b Decimal) (*big.Int, *big.Int, int) {
+	scale := max(a.scale, b.scale)
+	return new(big.Int).Mul(a.coef, pow10(scale-a.scale)), new(big.Int).Mul(b.coef, pow10(scale-b.scale)), scale
+}
+
+func compareDecimals(a, b Decimal) int {
+	if a.scale == b.scale {
+		return a.coef.Cmp(b.coef)
+	}
+	x, y, _ := alignDecimals(a, b)
+	return x.Cmp(y)
+}
+
+// roundQuotient divides x by y, rounding the result according to the rounding mode.
+func roundQuotient(x, y *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := x.Sign() * y.Sign()
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(y))
+	var away bool
+	switch mode {
+	case RoundHalfEven:
+		away = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	case RoundHalfUp:
+		away = cmpHalf >= 0
+	case RoundHalfDown:
+		away = cmpHalf > 0
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = sign > 0
+	case RoundFloor:
+		away = sign < 0
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+func numDigits(x *big.Int) int {
+	if x.Sign() == 0 {
+		return 1
+	}
+	return len(new(big.Int).Abs(x).Text(10))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// decimalValueField returns the `value` field of the message if it is a `google.type.Decimal`.
+func decimalValueField(msg proto.Message) (protoreflect.FieldDescriptor, bool) {
+	desc := msg.ProtoReflect().Descriptor()
+	if desc.FullName() != decimalProtoName {
+		return nil, false
+	}
+	field := desc.Fields().ByName("value")
+	return field, field != nil && field.Kind() == protoreflect.StringKind
+}
+
+// decimalAdapter adapts `google.type.Decimal` messages while preserving existing adapters.
+type decimalAdapter struct {
+	types.Adapter
+	context *decimalContext
+}
+
+func (a *decimalAdapter) NativeToValue(value any) ref.Val {
+	switch v := value.(type) {
+	case Decimal:
+		return v
+	case *anypb.Any:
+		if v.GetTypeUrl() == decimalTypeURL {
+			return a.unmarshalProto(v.GetValue())
+		}
+	case proto.Message:
+		if field, ok := decimalValueField(v); ok {
+			return a.parse(v.ProtoReflect().Get(field).String())
+		}
+	}
+	// Delegate to the wrapped adapter (e.g., Protobuf adapter)
+	return a.Adapter.NativeToValue(value)
+}
+
+func (a *decimalAdapter) unmarshalProto(b []byte) ref.Val {
+	value := ""
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return types.WrapErr(protowire.ParseError(n))
+		}
+		b = b[n:]
+		if num == 1 && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return types.WrapErr(protowire.ParseError(n))
+			}
+			value, b = v, b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return types.WrapErr(protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return a.parse(value)
+}
+
+func (a *decimalAdapter) parse(value string) ref.Val {
+	d, err := parseDecimal(value, a.context)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+// Cost estimation functions for decimal extensions.
+//
+// The cost of decimal operations scales with the number of digits in the operands, which never
+// exceeds the configured precision.
+
+func (lib *decimalLib) decimalSize(estimator checker.CostEstimator, node checker.AstNode) checker.SizeEstimate {
+	if !node.Type().IsExactType(DecimalType) {
+		return fixedSizeEstimate(1)
+	}
+	sz := estimateSize(estimator, node)
+	precision := uint64(lib.context.precision)
+	return checker.SizeEstimate{Min: min(max(sz.Min, 1), precision), Max: min(max(sz.Max, 1), precision)}
+}
+
+func (lib *decimalLib) resultSize() *checker.SizeEstimate {
+	sz := rangedSizeEstimate(1, uint64(lib.context.precision))
+	return &sz
+}
+
+func (lib *decimalLib) operandSizes(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) []checker.SizeEstimate {
+	var sizes []checker.SizeEstimate
+	if target != nil {
+		sizes = append(sizes, lib.decimalSize(estimator, *target))
+	}
+	for _, arg := range args {
+		sizes = append(sizes, lib.decimalSize(estimator, arg))
+	}
+	return sizes
+}
+
+func (lib *decimalLib) estimateParseCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	if len(args) < 1 {
+		return nil
+	}
+	sz := estimateSize(estimator, args[0])
+	return callEstimate(sz.MultiplyByCostFactor(stringCostFactor).Add(callCostEstimate), lib.resultSize())
+}
+
+func (lib *decimalLib) estimateNewDecimalCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	return callEstimate(callCostEstimate, lib.resultSize())
+}
+
+func (lib *decimalLib) estimateUnaryCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	sizes := lib.operandSizes(estimator, target, args)
+	if len(sizes) < 1 {
+		return nil
+	}
+	return callEstimate(sizes[0].MultiplyByCostFactor(decimalDigitCostFactor).Add(callCostEstimate), lib.resultSize())
+}
+
+func (lib *decimalLib) estimateToStringCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	est := lib.estimateUnaryCost(estimator, target, args)
+	if est != nil {
+		// The string includes a sign, a decimal point, and leading zeros or an exponent.
+		sz := rangedSizeEstimate(1, uint64(lib.context.precision+maxDecimalExponent+2))
+		est.ResultSize = &sz
+	}
+	return est
+}
+
+func (lib *decimalLib) estimateLinearCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	sizes := lib.operandSizes(estimator, target, args)
+	if len(sizes) < 2 {
+		return nil
+	}
+	return callEstimate(sizes[0].Add(sizes[1]).MultiplyByCostFactor(decimalDigitCostFactor).Add(callCostEstimate), lib.resultSize())
+}
+
+func (lib *decimalLib) estimateCompareCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	est := lib.estimateLinearCost(estimator, target, args)
+	if est != nil {
+		est.ResultSize = nil
+	}
+	return est
+}
+
+func (lib *decimalLib) estimateMultiplyCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	sizes := lib.operandSizes(estimator, target, args)
+	if len(sizes) < 2 {
+		return nil
+	}
+	return callEstimate(sizes[0].Multiply(sizes[1]).MultiplyByCostFactor(decimalDigitCostFactor).Add(callCostEstimate), lib.resultSize())
+}
+
+func (lib *decimalLib) estimateDivideCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	sizes := lib.operandSizes(estimator, target, args)
+	if len(sizes) < 2 {
+		return nil
+	}
+	// Long division produces precision digits of quotient, each costing a pass over the divisor.
+	quotient := sizes[0].Add(fixedSizeEstimate(uint64(lib.context.precision)))
+	return callEstimate(quotient.Multiply(sizes[1]).MultiplyByCostFactor(decimalDigitCostFactor).Add(callCostEstimate), lib.resultSize())
+}
+
+// Runtime cost tracking functions for decimal extensions.
+
+func trackDecimalParseCost(args []ref.Val, result ref.Val) *uint64 {
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(actualSize(args[0]), stringCostFactor), callCost)
+	return &total
+}
+
+func trackDecimalNewCost(args []ref.Val, result ref.Val) *uint64 {
+	total := callCost
+	return &total
+}
+
+func trackDecimalUnaryCost(args []ref.Val, result ref.Val) *uint64 {
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(actualSize(args[0]), decimalDigitCostFactor), callCost)
+	return &total
+}
+
+func trackDecimalLinearCost(args []ref.Val, result ref.Val) *uint64 {
+	digits := cost.SafeAdd(actualSize(args[0]), actualSize(args[1]))
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(digits, decimalDigitCostFactor), callCost)
+	return &total
+}
+
+func trackDecimalMultiplyCost(args []ref.Val, result ref.Val) *uint64 {
+	digits := cost.SafeMultiply(actualSize(args[0]), actualSize(args[1]))
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(digits, decimalDigitCostFactor), callCost)
+	return &total
+}
+
+func (lib *decimalLib) trackDivideCost(args []ref.Val, result ref.Val) *uint64 {
+	quotient := cost.SafeAdd(actualSize(args[0]), uint64(lib.context.precision))
+	digits := cost.SafeMultiply(quotient, actualSize(args[1]))
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(digits, decimalDigitCostFactor), callCost)
+	return &total
+}
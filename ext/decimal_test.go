@@ -0,0 +1,377 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ext
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/checker"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestDecimals(t *testing.T) {
+	tests := []struct {
+		expr string
+		opts []DecimalsOption
+		out  any
+	}{
+		// Construction and formatting
+		{expr: "string(decimal('19.99'))", out: "19.99"},
+		{expr: "string(decimal('-0.050'))", out: "-0.050"},
+		{expr: "string(decimal('.5'))", out: "0.5"},
+		{expr: "string(decimal('1.'))", out: "1"},
+		{expr: "string(decimal('1.5e-3'))", out: "0.0015"},
+		{expr: "string(decimal('-1.5E3'))", out: "-1500"},
+		{expr: "string(decimal(42))", out: "42"},
+		{expr: "string(decimal(42u))", out: "42"},
+		{expr: "string(decimal(0.1))", out: "0.1"},
+		{expr: "string(decimal(1e300))", out: "1e300"},
+		{expr: "decimal('1.50') == decimal('1.5')", out: true},
+		{expr: "decimal('1.50') != decimal('1.51')", out: true},
+		{expr: "dyn(decimal(1)) == 1", out: true},
+		{expr: "dyn(1) == decimal('1.0')", out: true},
+		{expr: "dyn(2u) != decimal('2')", out: false},
+		{expr: "dyn(0.5) == decimal('0.5')", out: true},
+		{expr: "dyn(decimal('0.1')) == 0.1", out: false},
+		{expr: "dyn(decimal(1)) == '1'", out: false},
+		{expr: "[1, dyn(decimal(2))].exists(x, x == 2)", out: true},
+		{expr: "type(decimal(1)) == decimal", out: true},
+
+		// Arithmetic
+		{expr: "decimal('0.1') + decimal('0.2') == decimal('0.3')", out: true},
+		{expr: "string(decimal('19.99') * decimal(3))", out: "59.97"},
+		{expr: "string(decimal('10') - decimal('0.01'))", out: "9.99"},
+		{expr: "string(decimal(1) / decimal(3))", out: "0.3333333333333333333333333333333333"},
+		{expr: "string(decimal(2) / decimal(3))", out: "0.6666666666666666666666666666666667"},
+		{expr: "string(decimal(1) / decimal(8))", out: "0.125"},
+		{expr: "string(decimal('1.00') / decimal(4))", out: "0.25"},
+		{expr: "string(decimal(100) / decimal('0.5'))", out: "200"},
+		{expr: "string(decimal(0) / decimal(7))", out: "0"},
+		{expr: "string(decimal('10.5') % decimal(3))", out: "1.5"},
+		{expr: "string(decimal('-10.5') % decimal(3))", out: "-1.5"},
+		{expr: "string(-decimal('2.50'))", out: "-2.50"},
+
+		// Precision and rounding
+		{expr: "string(decimal(1) / decimal(3))", opts: []DecimalsOption{DecimalPrecision(5)}, out: "0.33333"},
+		{expr: "string(decimal(2) / decimal(3))", opts: []DecimalsOption{DecimalPrecision(5), DecimalRounding(RoundDown)}, out: "0.66666"},
+		{expr: "string(decimal('123456') * decimal(10))", opts: []DecimalsOption{DecimalPrecision(5)}, out: "12346e2"},
+		{expr: "string(decimal('99999') + decimal(1))", opts: []DecimalsOption{DecimalPrecision(5)}, out: "10000e1"},
+		{expr: "string(decimal('999999'))", opts: []DecimalsOption{DecimalPrecision(5)}, out: "10000e2"},
+		{expr: "string(decimal('2.345').round(2))", out: "2.34"},
+		{expr: "string(decimal('2.355').round(2))", out: "2.36"},
+		{expr: "string(decimal('2.345').round(2))", opts: []DecimalsOption{DecimalRounding(RoundHalfUp)}, out: "2.35"},
+		{expr: "string(decimal('2.345').round(2))", opts: []DecimalsOption{DecimalRounding(RoundHalfDown)}, out: "2.34"},
+		{expr: "string(decimal('2.341').round(2))", opts: []DecimalsOption{DecimalRounding(RoundUp)}, out: "2.35"},
+		{expr: "string(decimal('-2.341').round(2))", opts: []DecimalsOption{DecimalRounding(RoundUp)}, out: "-2.35"},
+		{expr: "string(decimal('-2.349').round(2))", opts: []DecimalsOption{DecimalRounding(RoundDown)}, out: "-2.34"},
+		{expr: "string(decimal('-2.341').round(2))", opts: []DecimalsOption{DecimalRounding(RoundCeiling)}, out: "-2.34"},
+		{expr: "string(decimal('-2.341').round(2))", opts: []DecimalsOption{DecimalRounding(RoundFloor)}, out: "-2.35"},
+		{expr: "string(decimal('2.5').round(5))", out: "2.5"},
+		{expr: "string(decimal('1250').round(-2))", out: "1200"},
+		{expr: "string(decimal('1350').round(-2))", out: "1400"},
+
+		// Comparisons
+		{expr: "decimal('2.5') < decimal(3)", out: true},
+		{expr: "decimal('2.5') <= decimal('2.50')", out: true},
+		{expr: "decimal('-2.5') > decimal('-3')", out: true},
+		{expr: "decimal('2.5') >= decimal('2.51')", out: false},
+		{expr: "[decimal('1.1'), decimal('0.9')].exists(d, d < decimal(1))", out: true},
+
+		// Conversions
+		{expr: "double(decimal('2.5'))", out: 2.5},
+		{expr: "int(decimal('-2.7'))", out: int64(-2)},
+		{expr: "uint(decimal('2.7'))", out: uint64(2)},
+		{expr: "int(decimal('1e3'))", out: int64(1000)},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			env, err := cel.NewEnv(Decimals(tc.opts...))
+			if err != nil {
+				t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+			}
+			out := testDecimalEval(t, env, tc.expr)
+			if out.Value() != tc.out {
+				t.Errorf("got %v, wanted %v", out.Value(), tc.out)
+			}
+		})
+	}
+}
+
+func TestDecimalsCrossTypeComparisons(t *testing.T) {
+	tests := []struct {
+		expr string
+		out  bool
+	}{
+		{expr: "decimal('2.5') < 3", out: true},
+		{expr: "3 < decimal('2.5')", out: false},
+		{expr: "decimal('2.5') >= 2u", out: true},
+		{expr: "2u >= decimal('2.5')", out: false},
+		{expr: "decimal('0.1') < 0.1", out: true},
+		{expr: "0.1 > decimal('0.1')", out: true},
+		{expr: "decimal('0.5') <= 0.5", out: true},
+		{expr: "decimal('1e300') < double('inf')", out: true},
+		{expr: "decimal('-1e300') > double('-inf')", out: true},
+		{expr: "[1, 2, 3].exists(i, decimal('2.5') < i)", out: true},
+		{expr: "[decimal('0.5'), decimal('1.5')].all(d, d < 2u)", out: true},
+		{expr: "decimal('1') <= 1 && decimal('1') >= 1 && dyn(decimal('1')) == 1", out: true},
+		{expr: "1 <= decimal('1') && 1 >= decimal('1') && dyn(1) == decimal('1')", out: true},
+		{expr: "double('nan') < decimal(1)", out: false},
+		{expr: "double('nan') >= decimal(1)", out: false},
+	}
+	env, err := cel.NewEnv(Decimals(), cel.CrossTypeNumericComparisons(true))
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			out := testDecimalEval(t, env, tc.expr)
+			if out != types.Bool(tc.out) {
+				t.Errorf("got %v, wanted %v", out, tc.out)
+			}
+		})
+	}
+
+	// Cross-type comparisons are only declared when enabled in the environment.
+	env, err = cel.NewEnv(Decimals())
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+	}
+	_, iss := env.Compile("decimal('2.5') < 3")
+	if iss.Err() == nil || !strings.Contains(iss.Err().Error(), "found no matching overload for '_<_'") {
+		t.Errorf("Compile() got %v, wanted no matching overload error", iss.Err())
+	}
+
+	// The built-in numeric types do not compare against decimals outside of the decimal library.
+	d, err := ParseDecimal("2.5")
+	if err != nil {
+		t.Fatalf("ParseDecimal() failed: %v", err)
+	}
+	if out := types.Int(3).Compare(d); !types.IsError(out) {
+		t.Errorf("types.Int(3).Compare(decimal) got %v, wanted error", out)
+	}
+}
+
+func TestDecimalsErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{expr: "decimal('abc')", err: "invalid decimal"},
+		{expr: "decimal('1.2.3')", err: "invalid decimal"},
+		{expr: "decimal('1e')", err: "invalid decimal"},
+		{expr: "decimal('1e+-2')", err: "invalid decimal"},
+		{expr: "decimal('')", err: "invalid decimal"},
+		{expr: "decimal(double('nan'))", err: "invalid decimal"},
+		{expr: "decimal('1e7000')", err: "decimal overflow"},
+		{expr: "decimal(1) / decimal(0)", err: "division by zero"},
+		{expr: "decimal(1) % decimal('0.0')", err: "modulus by zero"},
+		{expr: "int(decimal('1e19'))", err: "integer overflow"},
+		{expr: "uint(decimal(-1))", err: "unsigned integer overflow"},
+		{expr: "decimal(1).round(7000)", err: "invalid decimal scale"},
+	}
+	env, err := cel.NewEnv(Decimals())
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			_, _, err = prg.Eval(cel.NoVars())
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("prg.Eval() got error %v, wanted error containing %q", err, tc.err)
+			}
+		})
+	}
+
+	if _, err := cel.NewEnv(Decimals(DecimalPrecision(0))); err == nil {
+		t.Error("cel.NewEnv(Decimals(DecimalPrecision(0))) succeeded, wanted error")
+	}
+}
+
+func TestDecimalsConversions(t *testing.T) {
+	d, err := ParseDecimal("-12.50")
+	if err != nil {
+		t.Fatalf("ParseDecimal() failed: %v", err)
+	}
+	tests := []struct {
+		typ  reflect.Type
+		want any
+	}{
+		{typ: reflect.TypeOf(""), want: "-12.50"},
+		{typ: reflect.TypeOf(float64(0)), want: -12.5},
+		{typ: reflect.TypeOf(&big.Rat{}), want: big.NewRat(-25, 2)},
+		{typ: reflect.TypeOf(Decimal{}), want: d},
+	}
+	for _, tc := range tests {
+		got, err := d.ConvertToNative(tc.typ)
+		if err != nil {
+			t.Fatalf("ConvertToNative(%v) failed: %v", tc.typ, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ConvertToNative(%v) got %v, wanted %v", tc.typ, got, tc.want)
+		}
+	}
+	if _, err := d.ConvertToNative(reflect.TypeOf(0)); err == nil {
+		t.Error("ConvertToNative(int) succeeded, wanted error")
+	}
+	if got := d.ConvertToType(types.TypeType); got != DecimalType {
+		t.Errorf("ConvertToType(type) got %v, wanted decimal", got)
+	}
+	if got := d.ConvertToType(types.BoolType); !types.IsError(got) {
+		t.Errorf("ConvertToType(bool) got %v, wanted error", got)
+	}
+}
+
+func TestDecimalsProtoRoundTrip(t *testing.T) {
+	env, err := cel.NewEnv(Decimals(), cel.Variable("price", DecimalType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+	}
+	out := testDecimalEval(t, env, "decimal('19.99') * decimal(2)")
+	pb, err := cel.ValueAsProto(out)
+	if err != nil {
+		t.Fatalf("cel.ValueAsProto() failed: %v", err)
+	}
+	if url := pb.GetObjectValue().GetTypeUrl(); url != "type.googleapis.com/google.type.Decimal" {
+		t.Errorf("cel.ValueAsProto() got type url %q, wanted google.type.Decimal", url)
+	}
+	val := env.CELTypeAdapter().NativeToValue(pb.GetObjectValue())
+	if val.Equal(out) != types.True || val.(Decimal).String() != "39.98" {
+		t.Errorf("NativeToValue() got %v, wanted 39.98", val)
+	}
+
+	// Decimal messages supplied as inputs are adapted to decimal values.
+	ast, iss := env.Compile("price * decimal(2)")
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	anyVal, err := val.ConvertToNative(reflect.TypeOf(&anypb.Any{}))
+	if err != nil {
+		t.Fatalf("ConvertToNative(Any) failed: %v", err)
+	}
+	res, _, err := prg.Eval(map[string]any{"price": anyVal})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if res.(Decimal).String() != "79.96" {
+		t.Errorf("prg.Eval() got %v, wanted 79.96", res)
+	}
+}
+
+func TestDecimalsCost(t *testing.T) {
+	tests := []struct {
+		expr          string
+		estimatedCost checker.CostEstimate
+		runtimeCost   uint64
+	}{
+		{
+			expr:          "decimal('19.99')",
+			estimatedCost: checker.CostEstimate{Min: 2, Max: 2},
+			runtimeCost:   2,
+		},
+		{
+			expr:          "decimal(1) + decimal(2)",
+			estimatedCost: checker.CostEstimate{Min: 4, Max: 10},
+			runtimeCost:   4,
+		},
+		{
+			expr:          "decimal(1) * decimal(2)",
+			estimatedCost: checker.CostEstimate{Min: 4, Max: 119},
+			runtimeCost:   4,
+		},
+		{
+			expr:          "decimal(1) / decimal(3)",
+			estimatedCost: checker.CostEstimate{Min: 7, Max: 235},
+			runtimeCost:   7,
+		},
+		{
+			expr:          "decimal(1) < decimal(2)",
+			estimatedCost: checker.CostEstimate{Min: 4, Max: 10},
+			runtimeCost:   4,
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			env, err := cel.NewEnv(Decimals())
+			if err != nil {
+				t.Fatalf("cel.NewEnv(Decimals()) failed: %v", err)
+			}
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			est, err := env.EstimateCost(ast, &noopCostEstimator{})
+			if err != nil {
+				t.Fatalf("env.EstimateCost() failed: %v", err)
+			}
+			if est != tc.estimatedCost {
+				t.Errorf("env.EstimateCost() got %v, wanted %v", est, tc.estimatedCost)
+			}
+			prg, err := env.Program(ast, cel.CostTracking(&noopCostEstimator{}))
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			_, det, err := prg.Eval(cel.NoVars())
+			if err != nil {
+				t.Fatalf("prg.Eval() failed: %v", err)
+			}
+			if det.ActualCost() == nil {
+				t.Fatal("prg.Eval() got nil cost, wanted cost tracking")
+			}
+			if *det.ActualCost() != tc.runtimeCost {
+				t.Errorf("prg.Eval() got cost %d, wanted %d", *det.ActualCost(), tc.runtimeCost)
+			}
+		})
+	}
+}
+
+func testDecimalEval(t *testing.T, env *cel.Env, expr string) ref.Val {
+	t.Helper()
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile(%q) failed: %v", expr, iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	out, _, err := prg.Eval(cel.NoVars())
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	return out
+}
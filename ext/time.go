@@ -0,0 +1,1323 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ext
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/checker"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/cost"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+	"cel.dev/cel-go/interpreter"
+
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+// Time returns a cel.EnvOption to configure calendar and time-zone-aware functions for working
+// with dates, times of day, and timestamps.
+//
+// The library introduces a `date` type for civil dates and a `time_of_day` type for wall clock
+// times. Both convert to and from the `google.type.Date` and `google.type.TimeOfDay` protobuf
+// messages.
+//
+// Functions which accept a time zone use the same format as the standard library timestamp
+// accessors: either an IANA time zone name such as `America/New_York`, or a UTC offset such as
+// `-05:00`. When the time zone is omitted, UTC is used. Constant time zones, truncation units, cron
+// expressions, dates, times of day, and ISO-8601 durations are validated when the expression is
+// compiled.
+//
+// # Date
+//
+// Creates a date from a `YYYY-MM-DD` string, from a year, month, and day, or from the calendar
+// date of a timestamp in the given time zone.
+//
+//	date(<string>) -> <date>
+//	date(<int>, <int>, <int>) -> <date>
+//	<timestamp>.date() -> <date>
+//	<timestamp>.date(<string>) -> <date>
+//
+// Examples:
+//
+//	date('2026-12-24')
+//	date(2026, 12, 24) == date('2026-12-24') // true
+//	timestamp('2026-12-24T02:00:00Z').date('America/Los_Angeles') // date('2026-12-23')
+//
+// Dates may be compared with the ordering operators, converted to a `string`, and converted to a
+// `timestamp` at the start of the day in the given time zone.
+//
+//	timestamp(<date>) -> <timestamp>
+//	timestamp(<date>, <string>) -> <timestamp>
+//
+// The components of a date are returned by the `year`, `month`, `day`, and `dayOfWeek` functions.
+// Unlike `getMonth`, months are one-based. Days of the week are zero-based starting with Sunday,
+// matching `getDayOfWeek`.
+//
+//	<date>.year() -> <int>
+//	<date>.month() -> <int>
+//	<date>.day() -> <int>
+//	<date>.dayOfWeek() -> <int>
+//
+// # TimeOfDay
+//
+// Creates a time of day from an `HH:MM`, `HH:MM:SS`, or `HH:MM:SS.fffffffff` string, or from the
+// wall clock time of a timestamp in the given time zone.
+//
+//	timeOfDay(<string>) -> <time_of_day>
+//	<timestamp>.timeOfDay() -> <time_of_day>
+//	<timestamp>.timeOfDay(<string>) -> <time_of_day>
+//
+// Times of day may be compared with the ordering operators and converted to a `string`. Their
+// components are returned by the `hours`, `minutes`, and `seconds` functions.
+//
+// Examples:
+//
+//	timeOfDay('09:30')
+//	now.timeOfDay('Europe/Paris') >= timeOfDay('09:00')
+//
+// # Truncate
+//
+// Returns the timestamp truncated to the start of the given calendar unit in the given time zone.
+// Supported units are `year`, `month`, `week`, `day`, `hour`, `minute`, and `second`. Weeks start
+// on Monday.
+//
+//	<timestamp>.truncate(<string>) -> <timestamp>
+//	<timestamp>.truncate(<string>, <string>) -> <timestamp>
+//
+// Examples:
+//
+//	timestamp('2026-03-15T10:20:30Z').truncate('day') // timestamp('2026-03-15T00:00:00Z')
+//	timestamp('2026-03-15T10:20:30Z').truncate('month', '-05:00') // timestamp('2026-03-01T05:00:00Z')
+//
+// # AddMonths
+//
+// Returns the date or timestamp offset by the given number of calendar months. When the
+// resulting month has fewer days, the day is clamped to the end of the month. Timestamps retain
+// their wall clock time in the given time zone.
+//
+//	<date>.addMonths(<int>) -> <date>
+//	<timestamp>.addMonths(<int>) -> <timestamp>
+//	<timestamp>.addMonths(<int>, <string>) -> <timestamp>
+//
+// Examples:
+//
+//	date('2026-01-31').addMonths(1) // date('2026-02-28')
+//	date('2026-03-15').addMonths(-12) // date('2025-03-15')
+//
+// Dates may also be offset by a number of days using `addDays`.
+//
+//	<date>.addDays(<int>) -> <date>
+//
+// # Between
+//
+// Returns whether the value is within the window described by a start and an end. Timestamp
+// windows include the start and exclude the end. Date windows include both the start and the end
+// date. Time of day windows include the start and exclude the end, and wrap past midnight when
+// the end is before the start.
+//
+//	<timestamp>.between(<timestamp>, <timestamp>) -> <bool>
+//	<date>.between(<date>, <date>) -> <bool>
+//	<time_of_day>.between(<time_of_day>, <time_of_day>) -> <bool>
+//
+// Examples:
+//
+//	now.date('America/New_York').between(date('2026-12-20'), date('2027-01-02'))
+//	timeOfDay('23:30').between(timeOfDay('22:00'), timeOfDay('06:00')) // true
+//
+// # IsWeekend
+//
+// Returns whether the date, or the date of the timestamp in the given time zone, is a Saturday or
+// a Sunday.
+//
+//	<date>.isWeekend() -> <bool>
+//	<timestamp>.isWeekend() -> <bool>
+//	<timestamp>.isWeekend(<string>) -> <bool>
+//
+// # MatchesCron
+//
+// Returns whether the timestamp, in the given time zone, falls within a minute matched by a
+// standard five field cron expression: minute, hour, day of month, month, and day of week.
+// Fields support `*`, lists, ranges, steps, and English month and day names. As with cron, when
+// both the day of month and the day of week are restricted, a time matches if either does. The
+// `@yearly`, `@annually`, `@monthly`, `@weekly`, `@daily`, `@midnight`, and `@hourly` macros are
+// also supported.
+//
+//	<timestamp>.matchesCron(<string>) -> <bool>
+//	<timestamp>.matchesCron(<string>, <string>) -> <bool>
+//
+// Examples:
+//
+//	now.matchesCron('* 9-16 * * MON-FRI', 'America/New_York')
+//	timestamp('2026-01-05T09:00:00Z').matchesCron('0 9 * * 1') // true
+//
+// # IsoDuration
+//
+// Parses an ISO-8601 duration such as `PT1H30M` or `P1DT12H`. Weeks are treated as 7 days and
+// days as 24 hours. Years and months are not supported since they do not have a fixed length.
+// The duration may be negated with a leading `-`.
+//
+//	isoDuration(<string>) -> <duration>
+//
+// Examples:
+//
+//	isoDuration('PT1H30M') == duration('90m') // true
+//	isoDuration('P1W') == duration('168h') // true
+func Time() cel.EnvOption {
+	return func(e *cel.Env) (*cel.Env, error) {
+		e, err := cel.Lib(&timeLib{})(e)
+		if err != nil {
+			return nil, err
+		}
+		return cel.CustomTypeAdapter(&timeAdapter{Adapter: e.CELTypeAdapter()})(e)
+	}
+}
+
+const (
+	dateFunc        = "date"
+	timeOfDayFunc   = "timeOfDay"
+	yearFunc        = "year"
+	monthFunc       = "month"
+	dayFunc         = "day"
+	dayOfWeekFunc   = "dayOfWeek"
+	hoursFunc       = "hours"
+	minutesFunc     = "minutes"
+	secondsFunc     = "seconds"
+	truncateFunc    = "truncate"
+	addDaysFunc     = "addDays"
+	addMonthsFunc   = "addMonths"
+	betweenFunc     = "between"
+	isWeekendFunc   = "isWeekend"
+	matchesCronFunc = "matchesCron"
+	isoDurationFunc = "isoDuration"
+
+	dateTypeURL      = "type.googleapis.com/google.type.Date"
+	timeOfDayTypeURL = "type.googleapis.com/google.type.TimeOfDay"
+
+	dateProtoName      = protoreflect.FullName("google.type.Date")
+	timeOfDayProtoName = protoreflect.FullName("google.type.TimeOfDay")
+)
+
+var (
+	// DateType represents a civil date without a time zone.
+	DateType = types.NewOpaqueType("date").WithTraits(traits.ComparerType)
+
+	// TimeOfDayType represents a wall clock time without a date or time zone.
+	TimeOfDayType = types.NewOpaqueType("time_of_day").WithTraits(traits.ComparerType)
+
+	timeValueType = reflect.TypeOf(time.Time{})
+
+	errDurationRange = errors.New("duration out of range")
+)
+
+type timeLib struct{}
+
+func (*timeLib) LibraryName() string {
+	return "cel.lib.ext.time"
+}
+
+func (*timeLib) CompileOptions() []cel.EnvOption {
+	opts := []cel.EnvOption{
+		cel.Types(DateType, TimeOfDayType),
+		cel.Function(dateFunc,
+			cel.Overload("string_to_date", []*cel.Type{cel.StringType}, DateType,
+				cel.UnaryBinding(stringToDate)),
+			cel.Overload("int_int_int_to_date", []*cel.Type{cel.IntType, cel.IntType, cel.IntType}, DateType,
+				cel.FunctionBinding(intsToDate)),
+			cel.MemberOverload("timestamp_to_date", []*cel.Type{cel.TimestampType}, DateType,
+				cel.UnaryBinding(inTimeZone(timestampToDate))),
+			cel.MemberOverload("timestamp_to_date_tz", []*cel.Type{cel.TimestampType, cel.StringType}, DateType,
+				cel.BinaryBinding(withTimeZone(timestampToDate))),
+		),
+		cel.Function(timeOfDayFunc,
+			cel.Overload("string_to_time_of_day", []*cel.Type{cel.StringType}, TimeOfDayType,
+				cel.UnaryBinding(stringToTimeOfDay)),
+			cel.MemberOverload("timestamp_to_time_of_day", []*cel.Type{cel.TimestampType}, TimeOfDayType,
+				cel.UnaryBinding(inTimeZone(timestampToTimeOfDay))),
+			cel.MemberOverload("timestamp_to_time_of_day_tz", []*cel.Type{cel.TimestampType, cel.StringType}, TimeOfDayType,
+				cel.BinaryBinding(withTimeZone(timestampToTimeOfDay))),
+		),
+		cel.Function("timestamp",
+			cel.Overload("date_to_timestamp", []*cel.Type{DateType}, cel.TimestampType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return d.(Date).timestamp(time.UTC)
+				})),
+			cel.Overload("date_to_timestamp_tz", []*cel.Type{DateType, cel.StringType}, cel.TimestampType,
+				cel.BinaryBinding(func(d, tz ref.Val) ref.Val {
+					loc, err := loadTimeZone(string(tz.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return d.(Date).timestamp(loc)
+				})),
+		),
+		cel.Function("string",
+			cel.Overload("date_to_string", []*cel.Type{DateType}, cel.StringType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.String(d.(Date).String())
+				})),
+			cel.Overload("time_of_day_to_string", []*cel.Type{TimeOfDayType}, cel.StringType,
+				cel.UnaryBinding(func(t ref.Val) ref.Val {
+					return types.String(t.(TimeOfDay).String())
+				})),
+		),
+		cel.Function(yearFunc,
+			cel.MemberOverload("date_year", []*cel.Type{DateType}, cel.IntType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.Int(d.(Date).Year)
+				})),
+		),
+		cel.Function(monthFunc,
+			cel.MemberOverload("date_month", []*cel.Type{DateType}, cel.IntType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.Int(d.(Date).Month)
+				})),
+		),
+		cel.Function(dayFunc,
+			cel.MemberOverload("date_day", []*cel.Type{DateType}, cel.IntType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.Int(d.(Date).Day)
+				})),
+		),
+		cel.Function(dayOfWeekFunc,
+			cel.MemberOverload("date_day_of_week", []*cel.Type{DateType}, cel.IntType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.Int(d.(Date).Weekday())
+				})),
+		),
+		cel.Function(hoursFunc,
+			cel.MemberOverload("time_of_day_hours", []*cel.Type{TimeOfDayType}, cel.IntType,
+				cel.UnaryBinding(func(t ref.Val) ref.Val {
+					return types.Int(t.(TimeOfDay).Hours)
+				})),
+		),
+		cel.Function(minutesFunc,
+			cel.MemberOverload("time_of_day_minutes", []*cel.Type{TimeOfDayType}, cel.IntType,
+				cel.UnaryBinding(func(t ref.Val) ref.Val {
+					return types.Int(t.(TimeOfDay).Minutes)
+				})),
+		),
+		cel.Function(secondsFunc,
+			cel.MemberOverload("time_of_day_seconds", []*cel.Type{TimeOfDayType}, cel.IntType,
+				cel.UnaryBinding(func(t ref.Val) ref.Val {
+					return types.Int(t.(TimeOfDay).Seconds)
+				})),
+		),
+		cel.Function(truncateFunc,
+			cel.MemberOverload("timestamp_truncate", []*cel.Type{cel.TimestampType, cel.StringType}, cel.TimestampType,
+				cel.BinaryBinding(func(ts, unit ref.Val) ref.Val {
+					return timestampTruncate(ts, unit, time.UTC)
+				})),
+			cel.MemberOverload("timestamp_truncate_tz", []*cel.Type{cel.TimestampType, cel.StringType, cel.StringType}, cel.TimestampType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					loc, err := loadTimeZone(string(args[2].(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return timestampTruncate(args[0], args[1], loc)
+				})),
+		),
+		cel.Function(addDaysFunc,
+			cel.MemberOverload("date_add_days", []*cel.Type{DateType, cel.IntType}, DateType,
+				cel.BinaryBinding(func(d, days ref.Val) ref.Val {
+					return d.(Date).addDays(int64(days.(types.Int)))
+				})),
+		),
+		cel.Function(addMonthsFunc,
+			cel.MemberOverload("date_add_months", []*cel.Type{DateType, cel.IntType}, DateType,
+				cel.BinaryBinding(func(d, months ref.Val) ref.Val {
+					return d.(Date).addMonths(int64(months.(types.Int)))
+				})),
+			cel.MemberOverload("timestamp_add_months", []*cel.Type{cel.TimestampType, cel.IntType}, cel.TimestampType,
+				cel.BinaryBinding(func(ts, months ref.Val) ref.Val {
+					return timestampAddMonths(ts, months, time.UTC)
+				})),
+			cel.MemberOverload("timestamp_add_months_tz", []*cel.Type{cel.TimestampType, cel.IntType, cel.StringType}, cel.TimestampType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					loc, err := loadTimeZone(string(args[2].(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return timestampAddMonths(args[0], args[1], loc)
+				})),
+		),
+		cel.Function(betweenFunc,
+			cel.MemberOverload("timestamp_between", []*cel.Type{cel.TimestampType, cel.TimestampType, cel.TimestampType}, cel.BoolType,
+				cel.FunctionBinding(timestampBetween)),
+			cel.MemberOverload("date_between", []*cel.Type{DateType, DateType, DateType}, cel.BoolType,
+				cel.FunctionBinding(dateBetween)),
+			cel.MemberOverload("time_of_day_between", []*cel.Type{TimeOfDayType, TimeOfDayType, TimeOfDayType}, cel.BoolType,
+				cel.FunctionBinding(timeOfDayBetween)),
+		),
+		cel.Function(isWeekendFunc,
+			cel.MemberOverload("date_is_weekend", []*cel.Type{DateType}, cel.BoolType,
+				cel.UnaryBinding(func(d ref.Val) ref.Val {
+					return types.Bool(isWeekend(d.(Date).Weekday()))
+				})),
+			cel.MemberOverload("timestamp_is_weekend", []*cel.Type{cel.TimestampType}, cel.BoolType,
+				cel.UnaryBinding(inTimeZone(timestampIsWeekend))),
+			cel.MemberOverload("timestamp_is_weekend_tz", []*cel.Type{cel.TimestampType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(withTimeZone(timestampIsWeekend))),
+		),
+		cel.Function(matchesCronFunc,
+			cel.MemberOverload("timestamp_matches_cron", []*cel.Type{cel.TimestampType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(func(ts, expr ref.Val) ref.Val {
+					return timestampMatchesCron(ts, expr, time.UTC)
+				})),
+			cel.MemberOverload("timestamp_matches_cron_tz", []*cel.Type{cel.TimestampType, cel.StringType, cel.StringType}, cel.BoolType,
+				cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+					loc, err := loadTimeZone(string(args[2].(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return timestampMatchesCron(args[0], args[1], loc)
+				})),
+		),
+		cel.Function(isoDurationFunc,
+			cel.Overload("string_to_iso_duration", []*cel.Type{cel.StringType}, cel.DurationType,
+				cel.UnaryBinding(func(s ref.Val) ref.Val {
+					d, err := parseISODuration(string(s.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+					return types.Duration{Duration: d}
+				})),
+		),
+		cel.ASTValidators(
+			timeFormatValidator{funcName: dateFunc, checks: []argCheck{checkDate}, memberChecks: []argCheck{checkTimeZone}},
+			timeFormatValidator{funcName: timeOfDayFunc, checks: []argCheck{checkTimeOfDay}, memberChecks: []argCheck{checkTimeZone}},
+			timeFormatValidator{funcName: "timestamp", checks: []argCheck{nil, checkTimeZone}},
+			timeFormatValidator{funcName: truncateFunc, memberChecks: []argCheck{checkTruncateUnit, checkTimeZone}},
+			timeFormatValidator{funcName: addMonthsFunc, memberChecks: []argCheck{nil, checkTimeZone}},
+			timeFormatValidator{funcName: isWeekendFunc, memberChecks: []argCheck{checkTimeZone}},
+			timeFormatValidator{funcName: matchesCronFunc, memberChecks: []argCheck{checkCron, checkTimeZone}},
+			timeFormatValidator{funcName: isoDurationFunc, checks: []argCheck{checkISODuration}},
+		),
+		cel.CostEstimatorOptions(
+			checker.OverloadCostEstimate("string_to_date", estimateTimeParseCost),
+			checker.OverloadCostEstimate("string_to_time_of_day", estimateTimeParseCost),
+			checker.OverloadCostEstimate("string_to_iso_duration", estimateTimeParseCost),
+			checker.OverloadCostEstimate("timestamp_matches_cron", estimateTimeParseCost),
+			checker.OverloadCostEstimate("timestamp_matches_cron_tz", estimateTimeParseCost),
+		),
+	}
+	for _, op := range []struct {
+		function string
+		prefix   string
+	}{
+		{function: operators.Less, prefix: "less"},
+		{function: operators.LessEquals, prefix: "less_equals"},
+		{function: operators.Greater, prefix: "greater"},
+		{function: operators.GreaterEquals, prefix: "greater_equals"},
+	} {
+		// The ordering operators are bound by the standard library to the Comparer trait.
+		opts = append(opts, cel.Function(op.function,
+			cel.Overload(op.prefix+"_date", []*cel.Type{DateType, DateType}, cel.BoolType),
+			cel.Overload(op.prefix+"_time_of_day", []*cel.Type{TimeOfDayType, TimeOfDayType}, cel.BoolType),
+		))
+	}
+	return opts
+}
+
+func (*timeLib) ProgramOptions() []cel.ProgramOption {
+	return []cel.ProgramOption{
+		cel.CostTrackerOptions(
+			interpreter.OverloadCostTracker("string_to_date", trackTimeParseCost),
+			interpreter.OverloadCostTracker("string_to_time_of_day", trackTimeParseCost),
+			interpreter.OverloadCostTracker("string_to_iso_duration", trackTimeParseCost),
+			interpreter.OverloadCostTracker("timestamp_matches_cron", trackMatchesCronCost),
+			interpreter.OverloadCostTracker("timestamp_matches_cron_tz", trackMatchesCronCost),
+		),
+	}
+}
+
+// inTimeZone adapts a function of a time and location to a unary binding which uses UTC.
+func inTimeZone(fn func(time.Time) ref.Val) func(ref.Val) ref.Val {
+	return func(ts ref.Val) ref.Val {
+		return fn(ts.(types.Timestamp).Time.UTC())
+	}
+}
+
+// withTimeZone adapts a function of a time to a binary binding with a time zone argument.
+func withTimeZone(fn func(time.Time) ref.Val) func(ref.Val, ref.Val) ref.Val {
+	return func(ts, tz ref.Val) ref.Val {
+		loc, err := loadTimeZone(string(tz.(types.String)))
+		if err != nil {
+			return types.WrapErr(err)
+		}
+		return fn(ts.(types.Timestamp).Time.In(loc))
+	}
+}
+
+// loadTimeZone returns the location for an IANA time zone name or a `(+|-)HH:MM` UTC offset.
+func loadTimeZone(tz string) (*time.Location, error) {
+	ind := strings.Index(tz, ":")
+	if ind == -1 {
+		return time.LoadLocation(tz)
+	}
+	hr, err := strconv.Atoi(tz[0:ind])
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone offset: %s", tz)
+	}
+	min, err := strconv.Atoi(tz[ind+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone offset: %s", tz)
+	}
+	if hr < -23 || hr > 23 {
+		return nil, fmt.Errorf("timezone offset hours out of range [-23, 23]: %s", tz)
+	}
+	if min < 0 || min > 59 {
+		return nil, fmt.Errorf("timezone offset minutes out of range [0, 59]: %s", tz)
+	}
+	offset := hr*60 + min
+	if strings.HasPrefix(tz, "-") {
+		offset = hr*60 - min
+	}
+	return time.FixedZone("", offset*60), nil
+}
+
+func stringToDate(val ref.Val) ref.Val {
+	d, err := ParseDate(string(val.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+func intsToDate(args ...ref.Val) ref.Val {
+	year, month, day := args[0].(types.Int), args[1].(types.Int), args[2].(types.Int)
+	d, err := newDate(int64(year), int64(month), int64(day))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+func timestampToDate(t time.Time) ref.Val {
+	return dateOf(t)
+}
+
+func stringToTimeOfDay(val ref.Val) ref.Val {
+	t, err := ParseTimeOfDay(string(val.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return t
+}
+
+func timestampToTimeOfDay(t time.Time) ref.Val {
+	return TimeOfDay{Hours: t.Hour(), Minutes: t.Minute(), Seconds: t.Second(), Nanos: t.Nanosecond()}
+}
+
+func timestampIsWeekend(t time.Time) ref.Val {
+	return types.Bool(isWeekend(t.Weekday()))
+}
+
+func isWeekend(day time.Weekday) bool {
+	return day == time.Saturday || day == time.Sunday
+}
+
+func timestampTruncate(ts, unit ref.Val, loc *time.Location) ref.Val {
+	t := ts.(types.Timestamp).Time.In(loc)
+	truncated, err := truncateTime(t, string(unit.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Timestamp{Time: truncated.UTC()}
+}
+
+func truncateTime(t time.Time, unit string) (time.Time, error) {
+	y, m, d := t.Date()
+	loc := t.Location()
+	switch unit {
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc), nil
+	case "month":
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc), nil
+	case "week":
+		// Weeks start on Monday, as in ISO-8601.
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, loc), nil
+	case "day":
+		return time.Date(y, m, d, 0, 0, 0, 0, loc), nil
+	case "hour":
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, loc), nil
+	case "minute":
+		return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc), nil
+	case "second":
+		return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported truncation unit: %q", unit)
+}
+
+func timestampAddMonths(ts, months ref.Val, loc *time.Location) ref.Val {
+	t := ts.(types.Timestamp).Time.In(loc)
+	y, m, d, err := addMonths(t.Year(), t.Month(), t.Day(), int64(months.(types.Int)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Timestamp{Time: time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC()}
+}
+
+// addMonths offsets the year and month by the number of months, clamping the day to the end of
+// the resulting month.
+func addMonths(year int, month time.Month, day int, months int64) (int, time.Month, int, error) {
+	total := int64(year)*12 + int64(month-1) + months
+	if total < 12 || total >= 10000*12 {
+		return 0, 0, 0, errors.New("date out of range")
+	}
+	y, m := int(total/12), time.Month(total%12+1)
+	return y, m, min(day, daysIn(y, m)), nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func timestampBetween(args ...ref.Val) ref.Val {
+	t := args[0].(types.Timestamp).Time
+	start, end := args[1].(types.Timestamp).Time, args[2].(types.Timestamp).Time
+	return types.Bool(!t.Before(start) && t.Before(end))
+}
+
+func dateBetween(args ...ref.Val) ref.Val {
+	d, start, end := args[0].(Date), args[1].(Date), args[2].(Date)
+	return types.Bool(d.compare(start) >= 0 && d.compare(end) <= 0)
+}
+
+func timeOfDayBetween(args ...ref.Val) ref.Val {
+	t, start, end := args[0].(TimeOfDay), args[1].(TimeOfDay), args[2].(TimeOfDay)
+	if start.compare(end) <= 0 {
+		return types.Bool(t.compare(start) >= 0 && t.compare(end) < 0)
+	}
+	// The window wraps past midnight, e.g. 22:00 to 06:00.
+	return types.Bool(t.compare(start) >= 0 || t.compare(end) < 0)
+}
+
+func timestampMatchesCron(ts, expr ref.Val, loc *time.Location) ref.Val {
+	sched, err := parseCron(string(expr.(types.String)))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return types.Bool(sched.matches(ts.(types.Timestamp).Time.In(loc)))
+}
+
+// Date is a civil date, equivalent to a `google.type.Date` with all fields set.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// ParseDate parses a date in the `YYYY-MM-DD` format.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date: %q", s)
+	}
+	return dateOf(t), nil
+}
+
+func newDate(year, month, day int64) (Date, error) {
+	if year < 1 || year > 9999 || month < 1 || month > 12 || day < 1 ||
+		day > int64(daysIn(int(year), time.Month(month))) {
+		return Date{}, fmt.Errorf("invalid date: %04d-%02d-%02d", year, month, day)
+	}
+	return Date{Year: int(year), Month: time.Month(month), Day: int(day)}, nil
+}
+
+func dateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// Weekday returns the day of the week of the date.
+func (d Date) Weekday() time.Weekday {
+	return d.time(time.UTC).Weekday()
+}
+
+// String returns the date in the `YYYY-MM-DD` format.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d Date) time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d Date) timestamp(loc *time.Location) ref.Val {
+	return types.Timestamp{Time: d.time(loc).UTC()}
+}
+
+func (d Date) addDays(days int64) ref.Val {
+	if days > 10000*366 || days < -10000*366 {
+		return types.NewErr("date out of range")
+	}
+	out := dateOf(d.time(time.UTC).AddDate(0, 0, int(days)))
+	if out.Year < 1 || out.Year > 9999 {
+		return types.NewErr("date out of range")
+	}
+	return out
+}
+
+func (d Date) addMonths(months int64) ref.Val {
+	y, m, day, err := addMonths(d.Year, d.Month, d.Day, months)
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return Date{Year: y, Month: m, Day: day}
+}
+
+func (d Date) compare(other Date) int {
+	return d.time(time.UTC).Compare(other.time(time.UTC))
+}
+
+// Compare implements traits.Comparer.
+func (d Date) Compare(other ref.Val) ref.Val {
+	o, ok := other.(Date)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return types.Int(d.compare(o))
+}
+
+// ConvertToNative implements ref.Val.
+//
+// Dates may be converted to strings, time.Time values at midnight UTC, `google.type.Date`
+// messages, and `google.protobuf.Any` messages containing a `google.type.Date`.
+func (d Date) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(d):
+		return d, nil
+	case timeValueType:
+		return d.time(time.UTC), nil
+	case anyPbType:
+		return &anypb.Any{TypeUrl: dateTypeURL, Value: marshalInt32Fields(d.Year, int(d.Month), d.Day)}, nil
+	}
+	if typeDesc.Kind() == reflect.String {
+		return reflect.ValueOf(d.String()).Convert(typeDesc).Interface(), nil
+	}
+	if msg, ok := newProtoMessage(typeDesc, dateProtoName); ok {
+		setInt32Fields(msg, map[protoreflect.Name]int{"year": d.Year, "month": int(d.Month), "day": d.Day})
+		return msg, nil
+	}
+	return nil, fmt.Errorf("type conversion error from '%s' to '%v'", DateType, typeDesc)
+}
+
+// ConvertToType implements ref.Val.
+func (d Date) ConvertToType(typeValue ref.Type) ref.Val {
+	switch typeValue {
+	case DateType:
+		return d
+	case types.TypeType:
+		return DateType
+	case types.StringType:
+		return types.String(d.String())
+	case types.TimestampType:
+		return d.timestamp(time.UTC)
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", DateType, typeValue)
+}
+
+// Equal implements ref.Val.
+func (d Date) Equal(other ref.Val) ref.Val {
+	o, ok := other.(Date)
+	return types.Bool(ok && d == o)
+}
+
+// Type implements ref.Val.
+func (d Date) Type() ref.Type {
+	return DateType
+}
+
+// Value implements ref.Val, returning the date itself.
+func (d Date) Value() any {
+	return d
+}
+
+// TimeOfDay is a wall clock time, equivalent to a `google.type.TimeOfDay`.
+type TimeOfDay struct {
+	Hours   int
+	Minutes int
+	Seconds int
+	Nanos   int
+}
+
+// ParseTimeOfDay parses a time of day in the `HH:MM`, `HH:MM:SS`, or `HH:MM:SS.fffffffff` format.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	for _, layout := range []string{"15:04:05.999999999", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeOfDay{Hours: t.Hour(), Minutes: t.Minute(), Seconds: t.Second(), Nanos: t.Nanosecond()}, nil
+		}
+	}
+	return TimeOfDay{}, fmt.Errorf("invalid time of day: %q", s)
+}
+
+func newTimeOfDay(hours, minutes, seconds, nanos int) (TimeOfDay, error) {
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 || seconds < 0 || seconds > 59 ||
+		nanos < 0 || nanos > 999999999 {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day: %02d:%02d:%02d.%09d", hours, minutes, seconds, nanos)
+	}
+	return TimeOfDay{Hours: hours, Minutes: minutes, Seconds: seconds, Nanos: nanos}, nil
+}
+
+// String returns the time of day in the `HH:MM:SS` format, with fractional seconds if present.
+func (t TimeOfDay) String() string {
+	out := fmt.Sprintf("%02d:%02d:%02d", t.Hours, t.Minutes, t.Seconds)
+	if t.Nanos != 0 {
+		out += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanos), "0")
+	}
+	return out
+}
+
+func (t TimeOfDay) sinceMidnight() time.Duration {
+	return time.Duration(t.Hours)*time.Hour + time.Duration(t.Minutes)*time.Minute +
+		time.Duration(t.Seconds)*time.Second + time.Duration(t.Nanos)
+}
+
+func (t TimeOfDay) compare(other TimeOfDay) int {
+	a, b := t.sinceMidnight(), other.sinceMidnight()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Compare implements traits.Comparer.
+func (t TimeOfDay) Compare(other ref.Val) ref.Val {
+	o, ok := other.(TimeOfDay)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(other)
+	}
+	return types.Int(t.compare(o))
+}
+
+// ConvertToNative implements ref.Val.
+//
+// Times of day may be converted to strings, durations since midnight, `google.type.TimeOfDay`
+// messages, and `google.protobuf.Any` messages containing a `google.type.TimeOfDay`.
+func (t TimeOfDay) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	switch typeDesc {
+	case reflect.TypeOf(t):
+		return t, nil
+	case reflect.TypeOf(time.Duration(0)):
+		return t.sinceMidnight(), nil
+	case anyPbType:
+		return &anypb.Any{TypeUrl: timeOfDayTypeURL, Value: marshalInt32Fields(t.Hours, t.Minutes, t.Seconds, t.Nanos)}, nil
+	}
+	if typeDesc.Kind() == reflect.String {
+		return reflect.ValueOf(t.String()).Convert(typeDesc).Interface(), nil
+	}
+	if msg, ok := newProtoMessage(typeDesc, timeOfDayProtoName); ok {
+		setInt32Fields(msg, map[protoreflect.Name]int{
+			"hours": t.Hours, "minutes": t.Minutes, "seconds": t.Seconds, "nanos": t.Nanos})
+		return msg, nil
+	}
+	return nil, fmt.Errorf("type conversion error from '%s' to '%v'", TimeOfDayType, typeDesc)
+}
+
+// ConvertToType implements ref.Val.
+func (t TimeOfDay) ConvertToType(typeValue ref.Type) ref.Val {
+	switch typeValue {
+	case TimeOfDayType:
+		return t
+	case types.TypeType:
+		return TimeOfDayType
+	case types.StringType:
+		return types.String(t.String())
+	}
+	return types.NewErr("type conversion error from '%s' to '%s'", TimeOfDayType, typeValue)
+}
+
+// Equal implements ref.Val.
+func (t TimeOfDay) Equal(other ref.Val) ref.Val {
+	o, ok := other.(TimeOfDay)
+	return types.Bool(ok && t == o)
+}
+
+// Type implements ref.Val.
+func (t TimeOfDay) Type() ref.Type {
+	return TimeOfDayType
+}
+
+// Value implements ref.Val, returning the time of day itself.
+func (t TimeOfDay) Value() any {
+	return t
+}
+
+// newProtoMessage creates a message of the reflect type if it is a message with the given name.
+func newProtoMessage(typeDesc reflect.Type, name protoreflect.FullName) (proto.Message, bool) {
+	if typeDesc.Kind() != reflect.Ptr || !typeDesc.Implements(protoMessageType) {
+		return nil, false
+	}
+	msg := reflect.New(typeDesc.Elem()).Interface().(proto.Message)
+	return msg, msg.ProtoReflect().Descriptor().FullName() == name
+}
+
+func setInt32Fields(msg proto.Message, values map[protoreflect.Name]int) {
+	pr := msg.ProtoReflect()
+	fields := pr.Descriptor().Fields()
+	for name, v := range values {
+		if f := fields.ByName(name); f != nil {
+			pr.Set(f, protoreflect.ValueOfInt32(int32(v)))
+		}
+	}
+}
+
+func getInt32Fields(msg proto.Message, names ...protoreflect.Name) []int {
+	pr := msg.ProtoReflect()
+	fields := pr.Descriptor().Fields()
+	out := make([]int, len(names))
+	for i, name := range names {
+		if f := fields.ByName(name); f != nil {
+			out[i] = int(pr.Get(f).Int())
+		}
+	}
+	return out
+}
+
+// marshalInt32Fields encodes the values as the int32 fields numbered from one.
+func marshalInt32Fields(values ...int) []byte {
+	var b []byte
+	for i, v := range values {
+		if v == 0 {
+			continue
+		}
+		b = protowire.AppendTag(b, protowire.Number(i+1), protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(int32(v))))
+	}
+	return b
+}
+
+// unmarshalInt32Fields decodes the int32 fields numbered from one, ignoring unknown fields.
+func unmarshalInt32Fields(b []byte, count int) ([]int, error) {
+	out := make([]int, count)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ == protowire.VarintType && num >= 1 && int(num) <= count {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			out[num-1], b = int(int32(v)), b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return out, nil
+}
+
+// timeAdapter adapts `google.type.Date` and `google.type.TimeOfDay` messages while preserving
+// existing adapters.
+type timeAdapter struct {
+	types.Adapter
+}
+
+func (a *timeAdapter) NativeToValue(value any) ref.Val {
+	switch v := value.(type) {
+	case Date, TimeOfDay:
+		return v.(ref.Val)
+	case *anypb.Any:
+		switch v.GetTypeUrl() {
+		case dateTypeURL:
+			f, err := unmarshalInt32Fields(v.GetValue(), 3)
+			if err != nil {
+				return types.WrapErr(err)
+			}
+			return adaptDate(f)
+		case timeOfDayTypeURL:
+			f, err := unmarshalInt32Fields(v.GetValue(), 4)
+			if err != nil {
+				return types.WrapErr(err)
+			}
+			return adaptTimeOfDay(f)
+		}
+	case proto.Message:
+		switch v.ProtoReflect().Descriptor().FullName() {
+		case dateProtoName:
+			return adaptDate(getInt32Fields(v, "year", "month", "day"))
+		case timeOfDayProtoName:
+			return adaptTimeOfDay(getInt32Fields(v, "hours", "minutes", "seconds", "nanos"))
+		}
+	}
+	// Delegate to the wrapped adapter (e.g., Protobuf adapter)
+	return a.Adapter.NativeToValue(value)
+}
+
+func adaptDate(f []int) ref.Val {
+	// Partial dates, such as an anniversary without a year, are not supported.
+	d, err := newDate(int64(f[0]), int64(f[1]), int64(f[2]))
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return d
+}
+
+func adaptTimeOfDay(f []int) ref.Val {
+	t, err := newTimeOfDay(f[0], f[1], f[2], f[3])
+	if err != nil {
+		return types.WrapErr(err)
+	}
+	return t
+}
+
+// parseISODuration parses an ISO-8601 duration of weeks, days, hours, minutes, and seconds.
+func parseISODuration(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid ISO-8601 duration: %q", s)
+	str := s
+	neg := false
+	if len(str) > 0 && (str[0] == '-' || str[0] == '+') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+	if !strings.HasPrefix(str, "P") {
+		return 0, invalid
+	}
+	str = str[1:]
+	// Designators must appear in order, with hours, minutes, and seconds following the 'T'.
+	const dateDesignators, timeDesignators = "YMWD", "HMS"
+	designators := dateDesignators
+	inTime := false
+	components := 0
+	var total time.Duration
+	for len(str) > 0 {
+		if str[0] == 'T' {
+			if inTime || len(str) == 1 {
+				return 0, invalid
+			}
+			inTime, designators = true, timeDesignators
+			str = str[1:]
+			continue
+		}
+		end := strings.IndexFunc(str, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if end <= 0 {
+			return 0, invalid
+		}
+		num, designator := str[:end], str[end]
+		str = str[end+1:]
+		pos := strings.IndexByte(designators, designator)
+		if pos < 0 {
+			return 0, invalid
+		}
+		designators = designators[pos+1:]
+		var unit time.Duration
+		switch {
+		case !inTime && (designator == 'Y' || designator == 'M'):
+			return 0, fmt.Errorf("unsupported ISO-8601 duration: %q: years and months do not have a fixed length", s)
+		case designator == 'W':
+			unit = 7 * 24 * time.Hour
+		case designator == 'D':
+			unit = 24 * time.Hour
+		case designator == 'H':
+			unit = time.Hour
+		case designator == 'M':
+			unit = time.Minute
+		case designator == 'S':
+			unit = time.Second
+		}
+		d, err := scaleDuration(num, unit)
+		if errors.Is(err, errDurationRange) {
+			return 0, err
+		}
+		if err != nil {
+			return 0, invalid
+		}
+		if total > math.MaxInt64-d {
+			return 0, errDurationRange
+		}
+		total += d
+		components++
+	}
+	if components == 0 {
+		return 0, invalid
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// scaleDuration multiplies the unit by a decimal number with an optional fractional part.
+func scaleDuration(num string, unit time.Duration) (time.Duration, error) {
+	intPart, fracPart, _ := strings.Cut(strings.Replace(num, ",", ".", 1), ".")
+	if intPart == "" || strings.ContainsAny(fracPart, ".,") {
+		return 0, errors.New("invalid number")
+	}
+	n, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || n > int64(math.MaxInt64/unit) {
+		return 0, errDurationRange
+	}
+	d := time.Duration(n) * unit
+	if fracPart != "" {
+		if len(fracPart) > 9 {
+			fracPart = fracPart[:9]
+		}
+		frac, err := strconv.ParseInt(fracPart, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		// Split the unit to avoid overflowing when scaling by the fraction.
+		scale := time.Duration(math.Pow10(len(fracPart)))
+		d += (unit/scale)*time.Duration(frac) + (unit%scale)*time.Duration(frac)/scale
+		if d < 0 {
+			return 0, errDurationRange
+		}
+	}
+	return d, nil
+}
+
+// cronSchedule is a parsed five field cron expression, with a bit set for each matching value.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields are unrestricted, since a time matches
+	// either restricted day field.
+	domStar, dowStar bool
+}
+
+var (
+	cronMacros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+	cronMonthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronDayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, found := cronMacros[strings.ToLower(spec)]; found {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	var err error
+	sched := &cronSchedule{
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}
+	specs := []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{field: cronField{name: "minute", min: 0, max: 59}, bits: &sched.minute},
+		{field: cronField{name: "hour", min: 0, max: 23}, bits: &sched.hour},
+		{field: cronField{name: "day of month", min: 1, max: 31}, bits: &sched.dom},
+		{field: cronField{name: "month", min: 1, max: 12, names: cronMonthNames}, bits: &sched.month},
+		{field: cronField{name: "day of week", min: 0, max: 7, names: cronDayNames}, bits: &sched.dow},
+	}
+	for i, s := range specs {
+		if *s.bits, err = s.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+	// Both 0 and 7 represent Sunday.
+	if sched.dow&(1<<7) != 0 {
+		sched.dow |= 1
+	}
+	return sched, nil
+}
+
+func (f cronField) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(spec, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step: %q", f.name, part)
+			}
+		}
+		var lo, hi int
+		if rng == "*" {
+			lo, hi = f.min, f.max
+		} else {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(loStr); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(hiStr); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range: %q", f.name, part)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s value: %q", f.name, s)
+	}
+	return v, nil
+}
+
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<t.Day()) != 0
+	dowMatch := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// --- Static Validators ---
+
+type argCheck func(arg string) error
+
+// timeFormatValidator validates the constant string arguments of a function, where checks apply
+// to the arguments of global calls and memberChecks to the arguments of member calls.
+type timeFormatValidator struct {
+	funcName     string
+	checks       []argCheck
+	memberChecks []argCheck
+}
+
+func (v timeFormatValidator) Name() string {
+	return fmt.Sprintf("cel.validator.time.%s", v.funcName)
+}
+
+func (v timeFormatValidator) Validate(e *cel.Env, _ cel.ValidatorConfig, a *ast.AST, iss *cel.Issues) {
+	root := ast.NavigateAST(a)
+	funcCalls := ast.MatchDescendants(root, ast.FunctionMatcher(v.funcName))
+	for _, call := range funcCalls {
+		checks := v.checks
+		if call.AsCall().IsMemberFunction() {
+			checks = v.memberChecks
+		}
+		for i, arg := range call.AsCall().Args() {
+			if i >= len(checks) || checks[i] == nil || arg.Kind() != ast.LiteralKind {
+				continue
+			}
+			str, ok := arg.AsLiteral().(types.String)
+			if !ok {
+				continue
+			}
+			if err := checks[i](string(str)); err != nil {
+				iss.ReportErrorAtID(arg.ID(), "invalid %s argument: %v", v.funcName, err)
+			}
+		}
+	}
+}
+
+func checkDate(arg string) error {
+	_, err := ParseDate(arg)
+	return err
+}
+
+func checkTimeOfDay(arg string) error {
+	_, err := ParseTimeOfDay(arg)
+	return err
+}
+
+func checkTimeZone(arg string) error {
+	_, err := loadTimeZone(arg)
+	return err
+}
+
+func checkTruncateUnit(arg string) error {
+	_, err := truncateTime(time.Time{}, arg)
+	return err
+}
+
+func checkCron(arg string) error {
+	_, err := parseCron(arg)
+	return err
+}
+
+func checkISODuration(arg string) error {
+	_, err := parseISODuration(arg)
+	return err
+}
+
+// Cost estimation functions for time extensions.
+
+func estimateTimeParseCost(estimator checker.CostEstimator, target *checker.AstNode, args []checker.AstNode) *checker.CallEstimate {
+	if len(args) < 1 {
+		return nil
+	}
+	sz := estimateSize(estimator, args[0])
+	return callEstimate(sz.MultiplyByCostFactor(stringCostFactor).Add(callCostEstimate), nil)
+}
+
+func trackTimeParseCost(args []ref.Val, result ref.Val) *uint64 {
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(actualSize(args[0]), stringCostFactor), callCost)
+	return &total
+}
+
+func trackMatchesCronCost(args []ref.Val, result ref.Val) *uint64 {
+	total := cost.SafeAdd(cost.SafeMultiplyByFactor(actualSize(args[1]), stringCostFactor), callCost)
+	return &total
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+
+	anypb "google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		expr string
+		out  any
+	}{
+		// Dates
+		{expr: "string(date('2026-12-24'))", out: "2026-12-24"},
+		{expr: "date(2026, 12, 24) == date('2026-12-24')", out: true},
+		{expr: "string(timestamp('2026-12-24T02:00:00Z').date())", out: "2026-12-24"},
+		{expr: "string(timestamp('2026-12-24T02:00:00Z').date('America/Los_Angeles'))", out: "2026-12-23"},
+		{expr: "timestamp(date('2026-12-24')) == timestamp('2026-12-24T00:00:00Z')", out: true},
+		{expr: "timestamp(date('2026-12-24'), '-05:00') == timestamp('2026-12-24T05:00:00Z')", out: true},
+		{expr: "date('2026-03-15').year()", out: int64(2026)},
+		{expr: "date('2026-03-15').month()", out: int64(3)},
+		{expr: "date('2026-03-15').day()", out: int64(15)},
+		{expr: "date('2026-03-15').dayOfWeek()", out: int64(0)},
+		{expr: "date('2026-03-15') < date('2026-03-16')", out: true},
+		{expr: "date('2026-03-15') >= date('2026-03-16')", out: false},
+		{expr: "string(date('2026-02-27').addDays(2))", out: "2026-03-01"},
+		{expr: "string(date('2026-01-01').addDays(-1))", out: "2025-12-31"},
+
+		// Times of day
+		{expr: "string(timeOfDay('09:30'))", out: "09:30:00"},
+		{expr: "string(timeOfDay('09:30:15.250'))", out: "09:30:15.25"},
+		{expr: "string(timestamp('2026-01-05T14:45:00Z').timeOfDay('Europe/Paris'))", out: "15:45:00"},
+		{expr: "timestamp('2026-01-05T14:45:00Z').timeOfDay() > timeOfDay('14:00')", out: true},
+		{expr: "timeOfDay('09:30:15').hours()", out: int64(9)},
+		{expr: "timeOfDay('09:30:15').minutes()", out: int64(30)},
+		{expr: "timeOfDay('09:30:15').seconds()", out: int64(15)},
+
+		// Truncate
+		{expr: "timestamp('2026-03-15T10:20:30.5Z').truncate('second') == timestamp('2026-03-15T10:20:30Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('minute') == timestamp('2026-03-15T10:20:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('hour') == timestamp('2026-03-15T10:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('day') == timestamp('2026-03-15T00:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('week') == timestamp('2026-03-09T00:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('month') == timestamp('2026-03-01T00:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('year') == timestamp('2026-01-01T00:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-15T10:20:30Z').truncate('month', '-05:00') == timestamp('2026-03-01T05:00:00Z')", out: true},
+		{expr: "timestamp('2026-03-01T02:00:00Z').truncate('day', 'America/New_York') == timestamp('2026-02-28T05:00:00Z')", out: true},
+
+		// AddMonths
+		{expr: "string(date('2026-01-31').addMonths(1))", out: "2026-02-28"},
+		{expr: "string(date('2028-01-31').addMonths(1))", out: "2028-02-29"},
+		{expr: "string(date('2026-03-15').addMonths(-12))", out: "2025-03-15"},
+		{expr: "string(date('2026-11-30').addMonths(14))", out: "2028-01-30"},
+		{expr: "timestamp('2026-01-31T10:00:00Z').addMonths(1) == timestamp('2026-02-28T10:00:00Z')", out: true},
+		// Wall clock time is retained across the daylight saving time transition.
+		{expr: "timestamp('2026-02-15T14:00:00Z').addMonths(1, 'America/New_York') == timestamp('2026-03-15T13:00:00Z')", out: true},
+
+		// Between
+		{expr: "timestamp('2026-12-20T00:00:00Z').between(timestamp('2026-12-20T00:00:00Z'), timestamp('2027-01-02T00:00:00Z'))", out: true},
+		{expr: "timestamp('2027-01-02T00:00:00Z').between(timestamp('2026-12-20T00:00:00Z'), timestamp('2027-01-02T00:00:00Z'))", out: false},
+		{expr: "date('2027-01-02').between(date('2026-12-20'), date('2027-01-02'))", out: true},
+		{expr: "date('2027-01-03').between(date('2026-12-20'), date('2027-01-02'))", out: false},
+		{expr: "timeOfDay('12:00').between(timeOfDay('09:00'), timeOfDay('17:00'))", out: true},
+		{expr: "timeOfDay('17:00').between(timeOfDay('09:00'), timeOfDay('17:00'))", out: false},
+		{expr: "timeOfDay('23:30').between(timeOfDay('22:00'), timeOfDay('06:00'))", out: true},
+		{expr: "timeOfDay('05:59').between(timeOfDay('22:00'), timeOfDay('06:00'))", out: true},
+		{expr: "timeOfDay('12:00').between(timeOfDay('22:00'), timeOfDay('06:00'))", out: false},
+
+		// IsWeekend
+		{expr: "date('2026-03-14').isWeekend()", out: true},
+		{expr: "date('2026-03-16').isWeekend()", out: false},
+		{expr: "timestamp('2026-03-14T12:00:00Z').isWeekend()", out: true},
+		{expr: "timestamp('2026-03-16T02:00:00Z').isWeekend('America/Los_Angeles')", out: true},
+
+		// MatchesCron
+		{expr: "timestamp('2026-01-05T09:00:00Z').matchesCron('0 9 * * MON-FRI')", out: true},
+		{expr: "timestamp('2026-01-05T09:01:00Z').matchesCron('0 9 * * MON-FRI')", out: false},
+		{expr: "timestamp('2026-01-04T09:00:00Z').matchesCron('0 9 * * MON-FRI')", out: false},
+		{expr: "timestamp('2026-01-05T14:00:00Z').matchesCron('0 9 * * 1-5', 'America/New_York')", out: true},
+		{expr: "timestamp('2026-01-05T10:45:30Z').matchesCron('*/15 9-17 * * *')", out: true},
+		{expr: "timestamp('2026-01-05T10:50:00Z').matchesCron('*/15 9-17 * * *')", out: false},
+		{expr: "timestamp('2026-12-25T00:00:00Z').matchesCron('0 0 25 DEC *')", out: true},
+		{expr: "timestamp('2026-01-04T00:00:00Z').matchesCron('0 0 * * 7')", out: true},
+		{expr: "timestamp('2026-01-04T00:00:00Z').matchesCron('@weekly')", out: true},
+		// A time matches either restricted day field: the 1st of the month or any Friday.
+		{expr: "timestamp('2026-01-09T00:00:00Z').matchesCron('0 0 1 * FRI')", out: true},
+		{expr: "timestamp('2026-01-08T00:00:00Z').matchesCron('0 0 1 * FRI')", out: false},
+		{expr: "timestamp('2026-01-05T00:05:00Z').matchesCron('5,10 0 * JAN,FEB *')", out: true},
+
+		// IsoDuration
+		{expr: "isoDuration('PT1H30M') == duration('90m')", out: true},
+		{expr: "isoDuration('P1W') == duration('168h')", out: true},
+		{expr: "isoDuration('P1DT12H') == duration('36h')", out: true},
+		{expr: "isoDuration('PT0.5S') == duration('500ms')", out: true},
+		{expr: "isoDuration('PT1,25S') == duration('1.25s')", out: true},
+		{expr: "isoDuration('P0.5D') == duration('12h')", out: true},
+		{expr: "isoDuration('-PT15M') == duration('-15m')", out: true},
+	}
+	env, err := cel.NewEnv(Time())
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Time()) failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			out, _, err := prg.Eval(cel.NoVars())
+			if err != nil {
+				t.Fatalf("prg.Eval() failed: %v", err)
+			}
+			if out.Value() != tc.out {
+				t.Errorf("got %v, wanted %v", out.Value(), tc.out)
+			}
+		})
+	}
+}
+
+func TestTimeErrors(t *testing.T) {
+	tests := []struct {
+		expr       string
+		compileErr string
+		evalErr    string
+	}{
+		{expr: "date('2026-02-30')", compileErr: "invalid date argument"},
+		{expr: "timeOfDay('25:00')", compileErr: "invalid timeOfDay argument"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').date('Mars/Olympus')", compileErr: "invalid date argument"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').truncate('fortnight')", compileErr: "unsupported truncation unit"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').truncate('day', '+25:00')", compileErr: "invalid truncate argument"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').isWeekend('Nowhere')", compileErr: "invalid isWeekend argument"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').addMonths(1, 'Nowhere')", compileErr: "invalid addMonths argument"},
+		{expr: "timestamp(date('2026-01-01'), 'Nowhere')", compileErr: "invalid timestamp argument"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').matchesCron('0 9 * *')", compileErr: "expected 5 fields"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').matchesCron('0 24 * * *')", compileErr: "invalid hour value"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').matchesCron('0 9 * * FRI-MON')", compileErr: "invalid day of week range"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').matchesCron('*/0 * * * *')", compileErr: "invalid minute step"},
+		{expr: "isoDuration('P1M')", compileErr: "years and months do not have a fixed length"},
+		{expr: "isoDuration('PT')", compileErr: "invalid ISO-8601 duration"},
+		{expr: "isoDuration('P1H')", compileErr: "invalid ISO-8601 duration"},
+		{expr: "isoDuration('PT1S1M')", compileErr: "invalid ISO-8601 duration"},
+		{expr: "isoDuration('P1.2.3D')", compileErr: "invalid ISO-8601 duration"},
+
+		// Non-constant arguments are validated at evaluation time.
+		{expr: "date(['2026-02-30'][0])", evalErr: "invalid date"},
+		{expr: "date(2026, 2, 29)", evalErr: "invalid date"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').truncate(['decade'][0])", evalErr: "unsupported truncation unit"},
+		{expr: "timestamp('2026-01-01T00:00:00Z').matchesCron(['bad'][0])", evalErr: "invalid cron expression"},
+		{expr: "date('9999-12-31').addDays(1)", evalErr: "date out of range"},
+		{expr: "date('2026-01-01').addMonths(120000)", evalErr: "date out of range"},
+		{expr: "isoDuration(['P300000W'][0])", evalErr: "duration out of range"},
+	}
+	env, err := cel.NewEnv(Time())
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Time()) failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			ast, iss := env.Compile(tc.expr)
+			if tc.compileErr != "" {
+				if iss.Err() == nil || !strings.Contains(iss.Err().Error(), tc.compileErr) {
+					t.Errorf("env.Compile(%q) got %v, wanted error containing %q", tc.expr, iss.Err(), tc.compileErr)
+				}
+				return
+			}
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			_, _, err = prg.Eval(cel.NoVars())
+			if err == nil || !strings.Contains(err.Error(), tc.evalErr) {
+				t.Errorf("prg.Eval() got error %v, wanted error containing %q", err, tc.evalErr)
+			}
+		})
+	}
+}
+
+func TestTimeProtoRoundTrip(t *testing.T) {
+	env, err := cel.NewEnv(Time(),
+		cel.Variable("holiday", DateType),
+		cel.Variable("opens", TimeOfDayType))
+	if err != nil {
+		t.Fatalf("cel.NewEnv(Time()) failed: %v", err)
+	}
+	for _, expr := range []string{"date('2026-12-24')", "timeOfDay('09:30:15.5')"} {
+		ast, iss := env.Compile(expr)
+		if iss.Err() != nil {
+			t.Fatalf("env.Compile(%q) failed: %v", expr, iss.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			t.Fatalf("env.Program() failed: %v", err)
+		}
+		out, _, err := prg.Eval(cel.NoVars())
+		if err != nil {
+			t.Fatalf("prg.Eval() failed: %v", err)
+		}
+		pb, err := cel.ValueAsProto(out)
+		if err != nil {
+			t.Fatalf("cel.ValueAsProto(%v) failed: %v", out, err)
+		}
+		val := env.CELTypeAdapter().NativeToValue(pb.GetObjectValue())
+		if val.Equal(out) != types.True {
+			t.Errorf("NativeToValue() got %v, wanted %v", val, out)
+		}
+	}
+
+	// Messages supplied as inputs are adapted to dates and times of day.
+	ast, iss := env.Compile("holiday.isWeekend() || opens > timeOfDay('09:00')")
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	holiday, _ := Date{Year: 2026, Month: time.December, Day: 24}.ConvertToNative(reflect.TypeOf(&anypb.Any{}))
+	opens, _ := TimeOfDay{Hours: 8}.ConvertToNative(reflect.TypeOf(&anypb.Any{}))
+	out, _, err := prg.Eval(map[string]any{"holiday": holiday, "opens": opens})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if out != types.False {
+		t.Errorf("prg.Eval() got %v, wanted false", out)
+	}
+}
+
+func TestTimeConversions(t *testing.T) {
+	d := Date{Year: 2026, Month: time.March, Day: 15}
+	if got, err := d.ConvertToNative(reflect.TypeOf(time.Time{})); err != nil || got != time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Date.ConvertToNative(time.Time) got %v, %v", got, err)
+	}
+	if got, err := d.ConvertToNative(reflect.TypeOf("")); err != nil || got != "2026-03-15" {
+		t.Errorf("Date.ConvertToNative(string) got %v, %v", got, err)
+	}
+	if _, err := d.ConvertToNative(reflect.TypeOf(0)); err == nil {
+		t.Error("Date.ConvertToNative(int) succeeded, wanted error")
+	}
+	if got := d.ConvertToType(types.TimestampType); got.Equal(types.Timestamp{Time: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)}) != types.True {
+		t.Errorf("Date.ConvertToType(timestamp) got %v", got)
+	}
+	tod := TimeOfDay{Hours: 9, Minutes: 30}
+	if got, err := tod.ConvertToNative(reflect.TypeOf(time.Duration(0))); err != nil || got != 9*time.Hour+30*time.Minute {
+		t.Errorf("TimeOfDay.ConvertToNative(time.Duration) got %v, %v", got, err)
+	}
+	if got := tod.ConvertToType(types.TypeType); got != TimeOfDayType {
+		t.Errorf("TimeOfDay.ConvertToType(type) got %v, wanted time_of_day", got)
+	}
+	if got := tod.ConvertToType(types.IntType); !types.IsError(got) {
+		t.Errorf("TimeOfDay.ConvertToType(int) got %v, wanted error", got)
+	}
+}
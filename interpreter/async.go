@@ -421,6 +421,12 @@ func (pool *asyncCallTrackerPool) release(tracker *asyncCallStateTracker) {
 	if tracker == nil {
 		return
 	}
+	tracker.reset()
+	pool.Pool.Put(tracker)
+}
+
+// reset clears the recorded call states so the tracker may be reused for a new evaluation.
+func (tracker *asyncCallStateTracker) reset() {
 	tracker.mu.Lock()
 	// Clearing with delete reuses the backing arrays, which is ideal for the common case but pins
 	// a large allocation in the pool after a wide fan-out (e.g. an async call over a big list).
@@ -439,7 +445,6 @@ func (pool *asyncCallTrackerPool) release(tracker *asyncCallStateTracker) {
 	}
 	tracker.nextCallID.Store(0)
 	tracker.mu.Unlock()
-	pool.Pool.Put(tracker)
 }
 
 func newAsyncCallTrackerPool() *asyncCallTrackerPool {
@@ -136,20 +136,59 @@ func (f *ExecutionFrame) Close() {
 	f.ctx = nil
 	f.parent = nil
 	if f.Activation != nil {
-		switch a := f.Activation.(type) {
-		case *hierarchicalActivation:
-			if child, ok := a.child.(*inputActivation); ok {
-				activationInput.release(child)
-			}
-			activationStack.release(a)
-		case *inputActivation:
-			activationInput.release(a)
-		}
-		f.Activation = nil
+		f.releaseActivation()
 		frameStack.Put(f)
 	}
 }
 
+// Reset rebinds a root execution frame to a new input so that the frame, and the evaluation
+// context configured on it, may be reused across successive evaluations of the same program.
+//
+// Per-evaluation state is cleared while its storage is retained: the async call tracker and
+// cost tracker are reset in place, and any EvalState is dropped so that a new one is created for
+// the next evaluation. Reset must only be called once the previous evaluation has completed and
+// no asynchronous calls launched from the frame remain in flight.
+func (f *ExecutionFrame) Reset(input any) error {
+	if f.parent != nil {
+		return errors.New("Reset() called on child frame")
+	}
+	f.releaseActivation()
+	switch v := input.(type) {
+	case Activation:
+		f.Activation = v
+	case map[string]any:
+		f.Activation = activationInput.create(v)
+	default:
+		return fmt.Errorf("invalid input, wanted Activation or map[string]any, got: (%T)%v", input, input)
+	}
+	if f.ctx != nil {
+		f.ctx.state = nil
+		if f.ctx.costs != nil {
+			f.ctx.costs.Reset()
+		}
+		if f.ctx.asyncCalls != nil {
+			f.ctx.asyncCalls.reset()
+		}
+		f.ctx.interrupted.Store(false)
+		f.ctx.interruptCheckCount.Store(0)
+	}
+	return nil
+}
+
+// releaseActivation returns any pool-allocated activation held by the frame to its pool.
+func (f *ExecutionFrame) releaseActivation() {
+	switch a := f.Activation.(type) {
+	case *hierarchicalActivation:
+		if child, ok := a.child.(*inputActivation); ok {
+			activationInput.release(child)
+		}
+		activationStack.release(a)
+	case *inputActivation:
+		activationInput.release(a)
+	}
+	f.Activation = nil
+}
+
 // Push pushes the given activation onto the activation stack and returns the new frame.
 //
 // This operation is internal to the interpreter and is used to handle comprehension
@@ -283,6 +322,16 @@ func (f *ExecutionFrame) AsyncCall(callID int64) AsyncCall {
 	return acs
 }
 
+// SetCostTracker configures the CostTracker used to record the runtime cost of evaluations
+// performed with the frame, in place of one produced by the program's cost tracker factory.
+func (f *ExecutionFrame) SetCostTracker(tracker *CostTracker) error {
+	if f.ctx == nil {
+		return errors.New("cost tracker options require the execution frame to have a context configured")
+	}
+	f.ctx.costs = tracker
+	return nil
+}
+
 // SetCompletions configures a channel to receive callIDs when asynchronous evaluations finish.
 func (f *ExecutionFrame) SetCompletions(ch chan<- int64) error {
 	if f.ctx == nil {
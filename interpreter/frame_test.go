@@ -475,6 +475,52 @@ func TestFrameSetContextChildError(t *testing.T) {
 	}
 }
 
+func TestFrameReset(t *testing.T) {
+	f := mustNewExecutionFrame(t, map[string]any{"a": 1})
+	defer f.Close()
+	if err := f.SetContext(context.Background(), 1); err != nil {
+		t.Fatalf("SetContext failed: %v", err)
+	}
+	tracker, err := NewCostTracker(nil)
+	if err != nil {
+		t.Fatalf("NewCostTracker() failed: %v", err)
+	}
+	tracker.cost = 10
+	tracker.stack.push(types.True, 1)
+	if err := f.SetCostTracker(tracker); err != nil {
+		t.Fatalf("SetCostTracker() failed: %v", err)
+	}
+	f.ctx.state = NewEvalState()
+	f.ctx.interrupted.Store(true)
+
+	if err := f.Reset(map[string]any{"b": 2}); err != nil {
+		t.Fatalf("Reset() failed: %v", err)
+	}
+	if val, found := f.ResolveName("b"); !found || val != 2 {
+		t.Errorf("ResolveName('b') got %v, %t; want 2, true", val, found)
+	}
+	if val, found := f.ResolveName("a"); found {
+		t.Errorf("ResolveName('a') found after Reset(): %v", val)
+	}
+	if f.ctx.costs != tracker || tracker.ActualCost() != 0 || len(tracker.stack) != 0 {
+		t.Errorf("Reset() did not reset the cost tracker: cost=%d, stack=%v", tracker.ActualCost(), tracker.stack)
+	}
+	if f.ctx.state != nil {
+		t.Error("Reset() did not clear the eval state")
+	}
+	if f.CheckInterrupt() {
+		t.Error("Reset() did not clear the interrupted state")
+	}
+	if err := f.Reset(123); err == nil {
+		t.Error("Reset() with int input did not return error")
+	}
+	child := f.Push(EmptyActivation())
+	defer child.Pop()
+	if err := child.Reset(EmptyActivation()); err == nil {
+		t.Error("Reset() on a child frame did not return error")
+	}
+}
+
 func TestNewExecutionFrameInvalidInput(t *testing.T) {
 	f, err := NewExecutionFrame(123)
 	if err == nil {
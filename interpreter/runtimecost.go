@@ -16,6 +16,7 @@ package interpreter
 
 import (
 	"errors"
+	"sync/atomic"
 
 	"cel.dev/cel-go/common"
 	"cel.dev/cel-go/common/cost"
@@ -104,6 +105,7 @@ func (ct *costTrackerFactory) Observe(vars Activation, id int64, programStep any
 		// The state is configured with CostTrackFactory so this shouldn't happen.
 		return
 	}
+	startCost := tracker.cost
 	switch t := programStep.(type) {
 	case ConstantQualifier:
 		// TODO: Push identifiers on to the stack before observing constant qualifiers that apply to them
@@ -171,6 +173,50 @@ func (ct *costTrackerFactory) Observe(vars Activation, id int64, programStep any
 	if tracker.Limit != nil && tracker.cost > *tracker.Limit {
 		panic(EvalCancelledError{Cause: CostLimitExceeded, Message: "operation cancelled: actual cost limit exceeded"})
 	}
+	if tracker.Budget != nil && tracker.cost > startCost && !tracker.Budget.charge(tracker.cost-startCost) {
+		panic(EvalCancelledError{Cause: CostLimitExceeded, Message: "operation cancelled: shared cost budget exceeded"})
+	}
+}
+
+// CostBudget is a runtime cost limit shared by every CostTracker it is attached to.
+//
+// The incremental cost of each evaluation step observed by an attached tracker is charged to the
+// budget, and the evaluation which first causes the budget to be exceeded is cancelled. A budget is
+// safe for concurrent use by trackers on different goroutines.
+type CostBudget struct {
+	limit uint64
+	used  atomic.Uint64
+}
+
+// NewCostBudget creates a CostBudget which permits at most `limit` units of cost to be consumed.
+func NewCostBudget(limit uint64) *CostBudget {
+	return &CostBudget{limit: limit}
+}
+
+// Limit returns the total cost permitted by the budget.
+func (b *CostBudget) Limit() uint64 {
+	return b.limit
+}
+
+// Used returns the total cost charged to the budget so far.
+func (b *CostBudget) Used() uint64 {
+	return b.used.Load()
+}
+
+// Exceeded reports whether the charges against the budget have exceeded its limit.
+func (b *CostBudget) Exceeded() bool {
+	return b.Used() > b.limit
+}
+
+// charge records the cost against the budget and reports whether the budget still holds.
+func (b *CostBudget) charge(c uint64) bool {
+	for {
+		used := b.used.Load()
+		next := cost.SafeAdd(used, c)
+		if b.used.CompareAndSwap(used, next) {
+			return next <= b.limit
+		}
+	}
 }
 
 // CostTrackerOption configures the behavior of CostTracker objects.
@@ -224,11 +270,21 @@ func OverloadCostTracker(overloadID string, fnTracker FunctionTracker) CostTrack
 // FunctionTracker computes the actual cost of evaluating the functions with the given arguments and result.
 type FunctionTracker func(args []ref.Val, result ref.Val) *uint64
 
+// CostTrackerBudget attaches a CostBudget shared with other trackers, in addition to any per-evaluation
+// limit set with CostTrackerLimit.
+func CostTrackerBudget(budget *CostBudget) CostTrackerOption {
+	return func(tracker *CostTracker) error {
+		tracker.Budget = budget
+		return nil
+	}
+}
+
 // CostTracker represents the information needed for tracking runtime cost.
 type CostTracker struct {
 	Estimator           ActualCostEstimator
 	overloadTrackers    map[string]FunctionTracker
 	Limit               *uint64
+	Budget              *CostBudget
 	presenceTestHasCost bool
 
 	cost  uint64
@@ -243,11 +299,19 @@ func (c *CostTracker) Clone() (*CostTracker, error) {
 		Estimator:           c.Estimator,
 		overloadTrackers:    c.overloadTrackers,
 		Limit:               c.Limit,
+		Budget:              c.Budget,
 		presenceTestHasCost: c.presenceTestHasCost,
 	}
 	return tracker, nil
 }
 
+// Reset clears the tracked cost so the tracker may be reused for another evaluation.
+func (c *CostTracker) Reset() {
+	c.cost = 0
+	clear(c.stack)
+	c.stack = c.stack[:0]
+}
+
 // ActualCost returns the runtime cost
 func (c *CostTracker) ActualCost() uint64 {
 	return c.cost
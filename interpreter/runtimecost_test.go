@@ -786,6 +786,28 @@ func TestRuntimeCost(t *testing.T) {
 			limit:              5,
 			expectExceedsLimit: true,
 		},
+		{
+			name: "within shared budget",
+			expr: `"abcdefg".contains(str1 + str2)`,
+			vars: []*decls.VariableDecl{
+				decls.NewVariable("str1", types.StringType),
+				decls.NewVariable("str2", types.StringType),
+			},
+			in:      map[string]any{"str1": "val1", "str2": "val2222222"},
+			options: []CostTrackerOption{CostTrackerBudget(NewCostBudget(6))},
+			want:    6,
+		},
+		{
+			name: "above shared budget",
+			expr: `"abcdefg".contains(str1 + str2)`,
+			vars: []*decls.VariableDecl{
+				decls.NewVariable("str1", types.StringType),
+				decls.NewVariable("str2", types.StringType),
+			},
+			in:                 map[string]any{"str1": "val1", "str2": "val2222222"},
+			options:            []CostTrackerOption{CostTrackerBudget(NewCostBudget(5))},
+			expectExceedsLimit: true,
+		},
 		{
 			name: "ternary as operand",
 			expr: `(1 > 2 ? 5 : 3) > 1`,
@@ -0,0 +1,306 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"strings"
+
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/runes"
+)
+
+// sourceTokens indexes the significant tokens and comments within an expression source so that the
+// textual extent of expression nodes may be recovered from their recorded offsets.
+type sourceTokens struct {
+	buf      runes.Buffer
+	toks     []token
+	comments []token
+	index    map[int32]int
+}
+
+func newSourceTokens(buf runes.Buffer) *sourceTokens {
+	st := &sourceTokens{buf: buf, index: make(map[int32]int)}
+	lex := newLexer(buf)
+	for {
+		tok := lex.Lex()
+		if tok.kind == tokEnd || tok.end <= tok.start {
+			break
+		}
+		switch tok.kind {
+		case tokWhitespace:
+		case tokComment:
+			st.comments = append(st.comments, tok)
+		default:
+			st.index[tok.start] = len(st.toks)
+			st.toks = append(st.toks, tok)
+		}
+	}
+	return st
+}
+
+func (st *sourceTokens) text(start, end int32) string {
+	return st.buf.Slice(int(start), int(end))
+}
+
+// matching returns the index of the token which closes the delimiter at index i.
+func (st *sourceTokens) matching(i int) int {
+	depth := 0
+	for ; i < len(st.toks); i++ {
+		switch st.toks[i].kind {
+		case tokLeftParen, tokLeftBracket, tokLeftBrace:
+			depth++
+		case tokRightParen, tokRightBracket, tokRightBrace:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(st.toks) - 1
+}
+
+// hasNewline indicates whether the source contains a line break between the start and end offsets.
+func (st *sourceTokens) hasNewline(start, end int32) bool {
+	return strings.ContainsAny(st.text(start, end), "\r\n")
+}
+
+// extents computes the textual range of every expression node, including the tokens which belong to
+// the node's own syntax such as closing delimiters and field names.
+type extents struct {
+	*sourceTokens
+	info   *ast.SourceInfo
+	ranges map[int64]ast.OffsetRange
+	nodes  []ast.Expr
+}
+
+func newExtents(expr ast.Expr, info *ast.SourceInfo, st *sourceTokens) *extents {
+	ex := &extents{sourceTokens: st, info: info, ranges: make(map[int64]ast.OffsetRange)}
+	ex.compute(expr)
+	return ex
+}
+
+func (ex *extents) compute(e ast.Expr) ast.OffsetRange {
+	ex.nodes = append(ex.nodes, e)
+	r, found := ex.info.GetOffsetRange(e.ID())
+	i, indexed := ex.index[r.Start]
+	if !found || !indexed {
+		r = ast.OffsetRange{Start: -1, Stop: -1}
+	} else {
+		r = ex.ownRange(e, i)
+	}
+	for _, child := range exprChildren(e) {
+		cr := ex.compute(child)
+		if cr.Start < 0 {
+			continue
+		}
+		if r.Start < 0 || cr.Start < r.Start {
+			r.Start = cr.Start
+		}
+		if cr.Stop > r.Stop {
+			r.Stop = cr.Stop
+		}
+	}
+	ex.ranges[e.ID()] = r
+	return r
+}
+
+// ownRange returns the range of the tokens which make up the node's own syntax given the index of
+// the token at which the node's recorded offset begins.
+func (ex *extents) ownRange(e ast.Expr, i int) ast.OffsetRange {
+	toks := ex.toks
+	last := len(toks) - 1
+	start := toks[i].start
+	switch e.Kind() {
+	case ast.IdentKind, ast.LiteralKind:
+		// Include the identifier or literal which follows a leading '.' or '-'.
+		if (toks[i].kind == tokDot || toks[i].kind == tokMinus) && i < last {
+			i++
+		}
+		return ast.OffsetRange{Start: start, Stop: toks[i].end}
+	case ast.SelectKind:
+		// The recorded offset is the '.', so include the field name.
+		for i < last && (toks[i].kind == tokDot || toks[i].kind == tokQuestion) {
+			i++
+		}
+		return ast.OffsetRange{Start: start, Stop: toks[i].end}
+	case ast.ListKind, ast.MapKind:
+		return ast.OffsetRange{Start: start, Stop: toks[ex.matching(i)].end}
+	case ast.StructKind:
+		// The recorded offset is the final segment of the type name, so scan back to the first
+		// segment and forward to the closing brace.
+		first := i
+		if toks[first].kind == tokDot && first > 0 {
+			first--
+		}
+		for first > 1 && toks[first-1].kind == tokDot && isIdentLike(toks[first-2].kind) {
+			first -= 2
+		}
+		if first > 0 && toks[first-1].kind == tokDot && (first == 1 || !endsOperand(toks[first-2].kind)) {
+			first--
+		}
+		open := i
+		for open < last && toks[open].kind != tokLeftBrace {
+			open++
+		}
+		return ast.OffsetRange{Start: toks[first].start, Stop: toks[ex.matching(open)].end}
+	case ast.CallKind:
+		call := e.AsCall()
+		switch call.FunctionName() {
+		case operators.Index, operators.OptIndex:
+			return ast.OffsetRange{Start: start, Stop: toks[ex.matching(i)].end}
+		}
+		if isOperatorCall(call.FunctionName()) {
+			return ast.OffsetRange{Start: start, Stop: toks[i].end}
+		}
+		open := i
+		for open < last && toks[open].kind != tokLeftParen {
+			open++
+		}
+		return ast.OffsetRange{Start: start, Stop: toks[ex.matching(open)].end}
+	}
+	return ast.OffsetRange{Start: start, Stop: toks[i].end}
+}
+
+// attachComments associates each comment in the source with the expression node it most plausibly
+// describes and records the result in the source info.
+//
+// A comment which follows code on the same line trails the outermost node ending on that line before
+// the comment, where a `&&` or `||` link which begins on an earlier line is narrowed to the operand
+// preceding the comment. Otherwise, the comment leads the outermost node beginning at the next token. Comments
+// which precede a closing delimiter or the end of input are recorded as footers of the preceding node.
+func attachComments(expr ast.Expr, info *ast.SourceInfo, buf runes.Buffer) {
+	st := newSourceTokens(buf)
+	if len(st.comments) == 0 || len(st.toks) == 0 {
+		return
+	}
+	ex := newExtents(expr, info, st)
+	for _, cm := range st.comments {
+		text := strings.TrimRight(st.text(cm.start, cm.end), " \t\r\n")
+		prev, next := -1, -1
+		for i, tok := range st.toks {
+			if tok.end <= cm.start {
+				prev = i
+			} else if tok.start >= cm.end {
+				next = i
+				break
+			}
+		}
+		if prev >= 0 && !st.hasNewline(st.toks[prev].end, cm.start) {
+			if n := ex.lastEndingBefore(cm.start); n != nil && !st.hasNewline(ex.ranges[n.ID()].Stop, cm.start) {
+				n = ex.lastOperandOnLine(n, st.toks[prev].start)
+				info.AddComment(n.ID(), ast.Comment{Kind: ast.TrailingComment, Text: text})
+				continue
+			}
+		}
+		if next >= 0 {
+			if n := ex.outermostStartingAt(st.toks[next].start); n != nil {
+				info.AddComment(n.ID(), ast.Comment{Kind: ast.LeadingComment, Text: text})
+				continue
+			}
+		}
+		if n := ex.lastEndingBefore(cm.start); n != nil {
+			info.AddComment(n.ID(), ast.Comment{Kind: ast.FooterComment, Text: text})
+			continue
+		}
+		info.AddComment(expr.ID(), ast.Comment{Kind: ast.LeadingComment, Text: text})
+	}
+}
+
+// lastOperandOnLine narrows a logical operator which begins on an earlier line than the token at
+// the offset to its final operand, so that a trailing comment is anchored to the operand which
+// precedes it rather than to the enclosing link of a `&&` or `||` chain.
+func (ex *extents) lastOperandOnLine(n ast.Expr, offset int32) ast.Expr {
+	for n.Kind() == ast.CallKind {
+		call := n.AsCall()
+		fn := call.FunctionName()
+		if (fn != operators.LogicalAnd && fn != operators.LogicalOr) || len(call.Args()) == 0 {
+			break
+		}
+		if !ex.hasNewline(ex.ranges[n.ID()].Start, offset) {
+			break
+		}
+		n = call.Args()[len(call.Args())-1]
+	}
+	return n
+}
+
+// lastEndingBefore returns the outermost node with the greatest extent which ends before the offset.
+func (ex *extents) lastEndingBefore(offset int32) ast.Expr {
+	var best ast.Expr
+	var bestRange ast.OffsetRange
+	for _, n := range ex.nodes {
+		r := ex.ranges[n.ID()]
+		if r.Start < 0 || r.Stop > offset {
+			continue
+		}
+		if best == nil || r.Stop > bestRange.Stop || (r.Stop == bestRange.Stop && r.Start < bestRange.Start) {
+			best, bestRange = n, r
+		}
+	}
+	return best
+}
+
+// outermostStartingAt returns the node with the greatest extent which begins at the offset.
+func (ex *extents) outermostStartingAt(offset int32) ast.Expr {
+	var best ast.Expr
+	var bestRange ast.OffsetRange
+	for _, n := range ex.nodes {
+		r := ex.ranges[n.ID()]
+		if r.Start != offset {
+			continue
+		}
+		if best == nil || r.Stop > bestRange.Stop {
+			best, bestRange = n, r
+		}
+	}
+	return best
+}
+
+// exprChildren returns the direct subexpressions of an unexpanded expression in source order.
+func exprChildren(e ast.Expr) []ast.Expr {
+	switch e.Kind() {
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			return append([]ast.Expr{call.Target()}, call.Args()...)
+		}
+		return call.Args()
+	case ast.ListKind:
+		return e.AsList().Elements()
+	case ast.MapKind:
+		var children []ast.Expr
+		for _, entry := range e.AsMap().Entries() {
+			children = append(children, entry.AsMapEntry().Key(), entry.AsMapEntry().Value())
+		}
+		return children
+	case ast.StructKind:
+		var children []ast.Expr
+		for _, field := range e.AsStruct().Fields() {
+			children = append(children, field.AsStructField().Value())
+		}
+		return children
+	case ast.SelectKind:
+		return []ast.Expr{e.AsSelect().Operand()}
+	}
+	return nil
+}
+
+// isOperatorCall indicates whether the function name refers to an operator rather than a function
+// invoked with call syntax.
+func isOperatorCall(function string) bool {
+	_, found := operators.FindReverse(function)
+	return found || function == operators.OptSelect
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+)
+
+func TestPopulateComments(t *testing.T) {
+	tests := []struct {
+		expr string
+		out  []string
+	}{
+		{
+			expr: `a + b`,
+		},
+		{
+			expr: "// leading\na + b",
+			out:  []string{"leading a + b: // leading"},
+		},
+		{
+			expr: "a && // after a\nb",
+			out:  []string{"trailing a: // after a"},
+		},
+		{
+			expr: "a\n&& b // about b\n&& c",
+			out:  []string{"trailing b: // about b"},
+		},
+		{
+			expr: "a && b // about a && b\n&& c",
+			out:  []string{"trailing a && b: // about a && b"},
+		},
+		{
+			expr: "f(\n  x, // first\n  // second\n  y\n)",
+			out:  []string{"trailing x: // first", "leading y: // second"},
+		},
+		{
+			expr: "[1, 2\n// end of list\n]",
+			out:  []string{"footer 2: // end of list"},
+		},
+		{
+			expr: "x.exists(i, i > 0) // done",
+			out:  []string{"trailing x.exists(i, i > 0): // done"},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		for _, prsr := range []string{"antlr", "pratt"} {
+			t.Run(fmt.Sprintf("%s/%s", prsr, tc.expr), func(t *testing.T) {
+				var parsed *ast.AST
+				src := common.NewTextSource(tc.expr)
+				if prsr == "antlr" {
+					p, err := NewParser(PopulateComments(true))
+					if err != nil {
+						t.Fatalf("NewParser() failed: %v", err)
+					}
+					var errs *common.Errors
+					parsed, errs = p.Parse(src)
+					if len(errs.GetErrors()) != 0 {
+						t.Fatalf("Parse(%q) failed: %v", tc.expr, errs.ToDisplayString())
+					}
+				} else {
+					p, err := NewPrattParser(PopulateComments(true))
+					if err != nil {
+						t.Fatalf("NewPrattParser() failed: %v", err)
+					}
+					var errs *common.Errors
+					parsed, errs = p.Parse(src)
+					if len(errs.GetErrors()) != 0 {
+						t.Fatalf("Parse(%q) failed: %v", tc.expr, errs.ToDisplayString())
+					}
+				}
+				exprs := make(map[int64]ast.Expr)
+				ast.PostOrderVisit(parsed.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+					exprs[e.ID()] = e
+				}))
+				var out []string
+				for id, comments := range parsed.SourceInfo().Comments() {
+					for _, c := range comments {
+						node, err := Unparse(exprs[id], parsed.SourceInfo())
+						if err != nil {
+							t.Fatalf("Unparse() failed: %v", err)
+						}
+						out = append(out, fmt.Sprintf("%s %s: %s", commentKind(c.Kind), node, c.Text))
+					}
+				}
+				sort.Strings(out)
+				want := append([]string{}, tc.out...)
+				sort.Strings(want)
+				if len(out) != 0 || len(want) != 0 {
+					if !reflect.DeepEqual(out, want) {
+						t.Errorf("Parse(%q) got comments %v, wanted %v", tc.expr, out, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPopulateCommentsDisabled(t *testing.T) {
+	p, err := NewPrattParser()
+	if err != nil {
+		t.Fatalf("NewPrattParser() failed: %v", err)
+	}
+	parsed, errs := p.Parse(common.NewTextSource("a // comment"))
+	if len(errs.GetErrors()) != 0 {
+		t.Fatalf("Parse() failed: %v", errs.ToDisplayString())
+	}
+	if len(parsed.SourceInfo().Comments()) != 0 {
+		t.Errorf("Parse() got comments %v, wanted none", parsed.SourceInfo().Comments())
+	}
+}
+
+func commentKind(k ast.CommentKind) string {
+	switch k {
+	case ast.LeadingComment:
+		return "leading"
+	case ast.TrailingComment:
+		return "trailing"
+	case ast.FooterComment:
+		return "footer"
+	}
+	return "unknown"
+}
@@ -0,0 +1,308 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"strings"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/runes"
+)
+
+// CompletionKind describes the syntactic position of a cursor within a possibly incomplete expression.
+type CompletionKind int
+
+const (
+	// IdentifierCompletion indicates the cursor is positioned where an identifier may appear, either
+	// at the end of a partially typed identifier or at the start of a new one.
+	IdentifierCompletion CompletionKind = iota + 1
+
+	// MemberCompletion indicates the cursor follows a '.' or '.?' applied to an operand expression.
+	MemberCompletion
+
+	// CallArgumentCompletion indicates the cursor is at the start of an argument within a call's
+	// argument list, immediately following the '(' or a ','.
+	CallArgumentCompletion
+)
+
+// String returns a human-readable name for the completion kind.
+func (k CompletionKind) String() string {
+	switch k {
+	case IdentifierCompletion:
+		return "identifier"
+	case MemberCompletion:
+		return "member"
+	case CallArgumentCompletion:
+		return "call_argument"
+	default:
+		return "unknown"
+	}
+}
+
+// CompletionContext describes the syntactic context surrounding a cursor offset.
+type CompletionContext struct {
+	// Kind indicates the syntactic position of the cursor.
+	Kind CompletionKind
+
+	// Offset is the code point offset of the cursor within the source.
+	Offset int32
+
+	// Prefix contains the portion of the identifier typed before the cursor, if any.
+	Prefix string
+
+	// Operand is the expression to the left of the '.' for a MemberCompletion.
+	//
+	// The operand is nil when no parsed AST was provided or when the operand could not be located.
+	Operand ast.Expr
+
+	// Call describes the innermost call whose argument list encloses the cursor, if any.
+	Call *CallContext
+}
+
+// CallContext describes a call whose argument list encloses the cursor.
+type CallContext struct {
+	// Function is the name of the function being called.
+	Function string
+
+	// ArgIndex is the zero-based index of the argument containing the cursor, excluding the receiver.
+	ArgIndex int
+
+	// IsMemberFunction indicates whether the call is receiver-style.
+	IsMemberFunction bool
+
+	// Target is the receiver of a member call, or nil if the call is global or the target could not
+	// be located within the parsed AST.
+	Target ast.Expr
+
+	lparen int32
+}
+
+// Completion computes the completion context at the code point `offset` within the source.
+//
+// The parsed AST is optional and should be the result of an ErrorTolerant parse of the same source.
+// When provided, it is used to locate the operand of member accesses and the target of member calls
+// so that their types may be resolved by the caller.
+//
+// A nil context is returned when the offset falls within a literal or comment.
+func Completion(parsed *ast.AST, source common.Source, offset int) *CompletionContext {
+	buf, ok := source.(runes.Buffer)
+	if !ok {
+		buf = runes.NewBuffer(source.Content())
+	}
+	cursor := int32(offset)
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor > int32(buf.Len()) {
+		cursor = int32(buf.Len())
+	}
+	toks, next := completionTokens(buf, cursor)
+	if toks == nil {
+		return nil
+	}
+	cc := &CompletionContext{Kind: IdentifierCompletion, Offset: cursor}
+	ident := ""
+	if n := len(toks); n > 0 && isIdentLike(toks[n-1].kind) && toks[n-1].end >= cursor {
+		cc.Prefix = buf.Slice(int(toks[n-1].start), int(cursor))
+		ident = buf.Slice(int(toks[n-1].start), int(toks[n-1].end))
+		toks = toks[:n-1]
+	}
+	cc.Call = enclosingCall(buf, toks)
+
+	n := len(toks)
+	if n > 0 {
+		last := toks[n-1]
+		dotIdx := -1
+		if last.kind == tokDot {
+			dotIdx = n - 1
+		} else if last.kind == tokQuestion && n > 1 && toks[n-2].kind == tokDot {
+			dotIdx = n - 2
+		}
+		if dotIdx > 0 && endsOperand(toks[dotIdx-1].kind) {
+			cc.Kind = MemberCompletion
+			if parsed != nil {
+				cc.Operand = findOperand(parsed, toks[dotIdx].start)
+				// The text following the cursor may turn the member access into a member call,
+				// in which case the target of the call is the operand.
+				if cc.Operand == nil && next.kind == tokLeftParen {
+					cc.Operand = findCallTarget(parsed, next.start, ident)
+				}
+			}
+		} else if cc.Prefix == "" && cc.Call != nil &&
+			(last.kind == tokLeftParen || last.kind == tokComma) {
+			cc.Kind = CallArgumentCompletion
+		}
+	}
+	if cc.Call != nil && cc.Call.IsMemberFunction && parsed != nil {
+		cc.Call.Target = findCallTarget(parsed, cc.Call.lparen, cc.Call.Function)
+	}
+	return cc
+}
+
+// completionTokens returns the significant tokens which start before the cursor as well as the first
+// significant token following the identifier at the cursor, if any.
+//
+// A nil token list is returned when the cursor falls within a literal or comment.
+func completionTokens(buf runes.Buffer, cursor int32) ([]token, token) {
+	toks := []token{}
+	lex := newLexer(buf)
+	for {
+		tok := lex.Lex()
+		if tok.kind == tokEnd {
+			return toks, tok
+		}
+		if tok.start >= cursor {
+			if tok.kind == tokWhitespace || tok.kind == tokComment {
+				continue
+			}
+			return toks, tok
+		}
+		if tok.end > cursor && isLiteralOrComment(tok.kind) {
+			return nil, tok
+		}
+		if tok.end == cursor && (tok.kind == tokComment || isUnterminatedLiteral(buf, tok)) {
+			return nil, tok
+		}
+		if tok.kind == tokWhitespace || tok.kind == tokComment {
+			continue
+		}
+		toks = append(toks, tok)
+		if tok.end <= tok.start {
+			// Guard against a lexer error which does not advance the input.
+			end := int32(buf.Len())
+			return toks, token{kind: tokEnd, start: end, end: end}
+		}
+	}
+}
+
+// enclosingCall scans the tokens preceding the cursor to determine the innermost call whose argument
+// list has been opened, but not closed.
+func enclosingCall(buf runes.Buffer, toks []token) *CallContext {
+	var frames []*CallContext
+	for i, tok := range toks {
+		switch tok.kind {
+		case tokLeftParen:
+			var call *CallContext
+			if i > 0 && isIdentLike(toks[i-1].kind) {
+				call = &CallContext{
+					Function: buf.Slice(int(toks[i-1].start), int(toks[i-1].end)),
+					lparen:   tok.start,
+				}
+				if i > 1 && toks[i-2].kind == tokDot {
+					if i > 2 && endsOperand(toks[i-3].kind) {
+						call.IsMemberFunction = true
+					} else {
+						call.Function = "." + call.Function
+					}
+				}
+			}
+			frames = append(frames, call)
+		case tokLeftBracket, tokLeftBrace:
+			frames = append(frames, nil)
+		case tokRightParen, tokRightBracket, tokRightBrace:
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+		case tokComma:
+			if len(frames) > 0 && frames[len(frames)-1] != nil {
+				frames[len(frames)-1].ArgIndex++
+			}
+		}
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+	return frames[len(frames)-1]
+}
+
+// findOperand locates the operand of the select or optional select whose '.' begins at the offset.
+func findOperand(parsed *ast.AST, dotOffset int32) ast.Expr {
+	info := parsed.SourceInfo()
+	matches := ast.MatchDescendants(ast.NavigateAST(parsed), func(e ast.NavigableExpr) bool {
+		r, found := info.GetOffsetRange(e.ID())
+		if !found || r.Start != dotOffset {
+			return false
+		}
+		switch e.Kind() {
+		case ast.SelectKind:
+			return true
+		case ast.CallKind:
+			return e.AsCall().FunctionName() == operators.OptSelect
+		}
+		return false
+	})
+	if len(matches) == 0 {
+		return nil
+	}
+	if matches[0].Kind() == ast.SelectKind {
+		return matches[0].AsSelect().Operand()
+	}
+	return matches[0].AsCall().Args()[0]
+}
+
+// findCallTarget locates the target of the member call to `function` whose '(' begins at the offset.
+func findCallTarget(parsed *ast.AST, lparenOffset int32, function string) ast.Expr {
+	info := parsed.SourceInfo()
+	matches := ast.MatchDescendants(ast.NavigateAST(parsed), func(e ast.NavigableExpr) bool {
+		if e.Kind() != ast.CallKind || !e.AsCall().IsMemberFunction() {
+			return false
+		}
+		r, found := info.GetOffsetRange(e.ID())
+		return found && r.Start == lparenOffset && e.AsCall().FunctionName() == function
+	})
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[0].AsCall().Target()
+}
+
+func isIdentLike(kind tokenKind) bool {
+	switch kind {
+	case tokIdent, tokReservedWord, tokIn, tokNull, tokTrue, tokFalse:
+		return true
+	}
+	return false
+}
+
+// isUnterminatedLiteral indicates whether an error token is the result of an unterminated string or
+// bytes literal which extends to the end of the input.
+func isUnterminatedLiteral(buf runes.Buffer, tok token) bool {
+	if tok.kind != tokError || tok.end != int32(buf.Len()) {
+		return false
+	}
+	text := buf.Slice(int(tok.start), int(tok.end))
+	return strings.ContainsAny(text, `'"`)
+}
+
+func isLiteralOrComment(kind tokenKind) bool {
+	switch kind {
+	case tokString, tokBytes, tokInt, tokUint, tokFloat, tokComment:
+		return true
+	}
+	return false
+}
+
+// endsOperand indicates whether a token of the given kind may end an operand expression.
+func endsOperand(kind tokenKind) bool {
+	switch kind {
+	case tokIdent, tokReservedWord, tokNull, tokTrue, tokFalse,
+		tokInt, tokUint, tokFloat, tokString, tokBytes,
+		tokRightParen, tokRightBracket, tokRightBrace:
+		return true
+	}
+	return false
+}
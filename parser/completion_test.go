@@ -0,0 +1,264 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/debug"
+)
+
+func TestPrattParserErrorTolerant(t *testing.T) {
+	tests := []struct {
+		expr string
+		out  string
+	}{
+		{
+			expr: `request.user.`,
+			out:  `request^#1:*expr.Expr_IdentExpr#.user^#2:*expr.Expr_SelectExpr#.^#4:*expr.Expr_SelectExpr#`,
+		},
+		{
+			expr: `a. && b`,
+			out: `_&&_(
+  a^#1:*expr.Expr_IdentExpr#.^#3:*expr.Expr_SelectExpr#,
+  b^#5:*expr.Expr_IdentExpr#
+)^#4:*expr.Expr_CallExpr#`,
+		},
+		{
+			expr: `f(a, `,
+			out: `f(
+  a^#2:*expr.Expr_IdentExpr#,
+  ^#4:#
+)^#1:*expr.Expr_CallExpr#`,
+		},
+		{
+			expr: `x.contains(y.)`,
+			out: `x^#1:*expr.Expr_IdentExpr#.contains(
+  y^#3:*expr.Expr_IdentExpr#.^#5:*expr.Expr_SelectExpr#
+)^#2:*expr.Expr_CallExpr#`,
+		},
+	}
+	p, err := NewPrattParser(Macros(AllMacros...), ErrorTolerant(true))
+	if err != nil {
+		t.Fatalf("NewPrattParser() failed: %v", err)
+	}
+	strict, err := NewPrattParser(Macros(AllMacros...))
+	if err != nil {
+		t.Fatalf("NewPrattParser() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			src := common.NewTextSource(tc.expr)
+			parsed, errs := p.Parse(src)
+			if len(errs.GetErrors()) == 0 {
+				t.Fatalf("Parse(%q) succeeded, wanted errors", tc.expr)
+			}
+			if parsed == nil {
+				t.Fatalf("Parse(%q) returned a nil AST", tc.expr)
+			}
+			out := debug.ToAdornedDebugString(parsed.Expr(), &kindAndIDAdorner{parsed.SourceInfo()})
+			if out != tc.out {
+				t.Errorf("Parse(%q) got %s, wanted %s", tc.expr, out, tc.out)
+			}
+			strictParsed, strictErrs := strict.Parse(src)
+			if strictParsed != nil || len(strictErrs.GetErrors()) == 0 {
+				t.Errorf("strict Parse(%q) got %v, %v, wanted errors and a nil AST", tc.expr, strictParsed, strictErrs)
+			}
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		expr     string
+		offset   int
+		kind     CompletionKind
+		prefix   string
+		operand  string
+		function string
+		argIndex int
+		target   string
+		member   bool
+	}{
+		{
+			expr: ``,
+			kind: IdentifierCompletion,
+		},
+		{
+			expr:   `req`,
+			kind:   IdentifierCompletion,
+			prefix: "req",
+		},
+		{
+			expr:   `request`,
+			offset: 3,
+			kind:   IdentifierCompletion,
+			prefix: "req",
+		},
+		{
+			expr:    `request.user.`,
+			kind:    MemberCompletion,
+			operand: "request.user",
+		},
+		{
+			expr:    `request.user.na`,
+			kind:    MemberCompletion,
+			prefix:  "na",
+			operand: "request.user",
+		},
+		{
+			expr:    `request.?us`,
+			kind:    MemberCompletion,
+			prefix:  "us",
+			operand: "request",
+		},
+		{
+			expr:    `a.b.sta(x)`,
+			offset:  7,
+			kind:    MemberCompletion,
+			prefix:  "sta",
+			operand: "a.b",
+		},
+		{
+			expr:    `a.b.startsWith(x)`,
+			offset:  6,
+			kind:    MemberCompletion,
+			prefix:  "st",
+			operand: "a.b",
+		},
+		{
+			expr:     `f(`,
+			kind:     CallArgumentCompletion,
+			function: "f",
+		},
+		{
+			expr:     `f(a, g(b), `,
+			kind:     CallArgumentCompletion,
+			function: "f",
+			argIndex: 2,
+		},
+		{
+			expr:     `f(a, [b, c`,
+			kind:     IdentifierCompletion,
+			prefix:   "c",
+			function: "",
+		},
+		{
+			expr:     `f(a, x.`,
+			kind:     MemberCompletion,
+			operand:  "x",
+			function: "f",
+			argIndex: 1,
+		},
+		{
+			expr:     `items.exists(i, i.`,
+			kind:     MemberCompletion,
+			operand:  "i",
+			function: "exists",
+			argIndex: 1,
+			member:   true,
+		},
+		{
+			expr:     `name.startsWith(pre`,
+			kind:     IdentifierCompletion,
+			prefix:   "pre",
+			function: "startsWith",
+			target:   "name",
+			member:   true,
+		},
+		{
+			expr:     `.f(`,
+			kind:     CallArgumentCompletion,
+			function: ".f",
+		},
+		{
+			expr: `a + `,
+			kind: IdentifierCompletion,
+		},
+	}
+	p, err := NewPrattParser(Macros(AllMacros...), EnableOptionalSyntax(true), ErrorTolerant(true))
+	if err != nil {
+		t.Fatalf("NewPrattParser() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			src := common.NewTextSource(tc.expr)
+			parsed, _ := p.Parse(src)
+			offset := tc.offset
+			if offset == 0 {
+				offset = len(tc.expr)
+			}
+			cc := Completion(parsed, src, offset)
+			if cc == nil {
+				t.Fatalf("Completion(%q, %d) returned nil", tc.expr, offset)
+			}
+			if cc.Kind != tc.kind {
+				t.Errorf("Completion(%q, %d).Kind got %v, wanted %v", tc.expr, offset, cc.Kind, tc.kind)
+			}
+			if cc.Prefix != tc.prefix {
+				t.Errorf("Completion(%q, %d).Prefix got %q, wanted %q", tc.expr, offset, cc.Prefix, tc.prefix)
+			}
+			operand := ""
+			if cc.Operand != nil {
+				operand = debug.ToDebugString(cc.Operand)
+			}
+			if operand != tc.operand {
+				t.Errorf("Completion(%q, %d).Operand got %q, wanted %q", tc.expr, offset, operand, tc.operand)
+			}
+			if tc.function == "" {
+				if cc.Call != nil {
+					t.Errorf("Completion(%q, %d).Call got %v, wanted nil", tc.expr, offset, cc.Call)
+				}
+				return
+			}
+			if cc.Call == nil {
+				t.Fatalf("Completion(%q, %d).Call got nil, wanted %s", tc.expr, offset, tc.function)
+			}
+			if cc.Call.Function != tc.function || cc.Call.ArgIndex != tc.argIndex || cc.Call.IsMemberFunction != tc.member {
+				t.Errorf("Completion(%q, %d).Call got %v, wanted function %s, arg %d, member %t",
+					tc.expr, offset, cc.Call, tc.function, tc.argIndex, tc.member)
+			}
+			target := ""
+			if cc.Call.Target != nil {
+				target = debug.ToDebugString(cc.Call.Target)
+			}
+			if target != tc.target {
+				t.Errorf("Completion(%q, %d).Call.Target got %q, wanted %q", tc.expr, offset, target, tc.target)
+			}
+		})
+	}
+}
+
+func TestCompletionWithinLiterals(t *testing.T) {
+	tests := []struct {
+		expr   string
+		offset int
+	}{
+		{expr: `'abc`, offset: 4},
+		{expr: `"abc" + x`, offset: 2},
+		{expr: `b'abc'`, offset: 3},
+		{expr: `12345`, offset: 2},
+		{expr: "x // comment", offset: 8},
+	}
+	for _, tc := range tests {
+		src := common.NewTextSource(tc.expr)
+		if cc := Completion(nil, src, tc.offset); cc != nil {
+			t.Errorf("Completion(%q, %d) got %v, wanted nil", tc.expr, tc.offset, cc)
+		}
+	}
+}
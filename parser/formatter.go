@@ -0,0 +1,708 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/runes"
+	"cel.dev/cel-go/common/types"
+)
+
+// FormatOption is a functional option for configuring the output of the Format function.
+type FormatOption func(*formatOption) error
+
+type formatOption struct {
+	lineWidth   int
+	indentWidth int
+}
+
+// FormatLineWidth sets the column at which the formatter attempts to break long expressions across
+// multiple lines. The default is 80.
+func FormatLineWidth(width int) FormatOption {
+	return func(opt *formatOption) error {
+		if width < 1 {
+			return fmt.Errorf("format line width must be greater than or equal to 1: %d", width)
+		}
+		opt.lineWidth = width
+		return nil
+	}
+}
+
+// FormatIndentWidth sets the number of spaces used for each level of indentation. The default is 2.
+func FormatIndentWidth(width int) FormatOption {
+	return func(opt *formatOption) error {
+		if width < 1 {
+			return fmt.Errorf("format indent width must be greater than or equal to 1: %d", width)
+		}
+		opt.indentWidth = width
+		return nil
+	}
+}
+
+// Format parses the source and produces a consistently styled rendition of the expression.
+//
+// Unlike Unparse, the formatter operates on the unexpanded source so that macros, literal spellings,
+// and `//` comments are preserved. The formatter applies the following style:
+//
+// - Binary operators are surrounded by single spaces, and parentheses only appear where they affect
+// the order of operations.
+// - Expressions which fit within the line width are written on a single line.
+// - Longer logical chains place one operand per line with the operator at the end of the line.
+// - Longer call chains with more than one member call place each call on its own indented line.
+// - Longer argument lists, list, map, and message literals place one element per indented line,
+// while the iteration variables of comprehension macros remain on the line of the call.
+// - Comments are attached to the nearest expression and written before or after it.
+//
+// Formatting is idempotent: formatting the output again yields the same text.
+func Format(source common.Source, opts ...FormatOption) (string, error) {
+	fmtOpts := &formatOption{lineWidth: 80, indentWidth: 2}
+	for _, opt := range opts {
+		if err := opt(fmtOpts); err != nil {
+			return "", err
+		}
+	}
+	p, err := NewPrattParser(
+		EnableOptionalSyntax(true),
+		PopulateComments(true),
+		ErrorRecoveryLimit(0),
+	)
+	if err != nil {
+		return "", err
+	}
+	parsed, errs := p.Parse(source)
+	if len(errs.GetErrors()) > 0 {
+		return "", errors.New(errs.ToDisplayString())
+	}
+	buf, ok := source.(runes.Buffer)
+	if !ok {
+		buf = runes.NewBuffer(source.Content())
+	}
+	f := &formatter{
+		formatOption: fmtOpts,
+		info:         parsed.SourceInfo(),
+		extents:      newExtents(parsed.Expr(), parsed.SourceInfo(), newSourceTokens(buf)),
+		commented:    make(map[int64]bool),
+		lineStart:    true,
+	}
+	f.markComments(parsed.Expr())
+	f.write(parsed.Expr())
+	f.flush()
+	return f.out.String(), nil
+}
+
+type footer struct {
+	indent int
+	text   string
+}
+
+// formatter writes an unexpanded expression AST according to the formatting style.
+type formatter struct {
+	*formatOption
+	*extents
+	info *ast.SourceInfo
+	// commented records whether a node or any of its descendants has an attached comment.
+	commented map[int64]bool
+
+	out       strings.Builder
+	col       int
+	indent    int
+	lineStart bool
+	trailing  []string
+	footers   []footer
+}
+
+func (f *formatter) markComments(e ast.Expr) bool {
+	has := len(f.info.GetComments(e.ID())) > 0
+	for _, child := range exprChildren(e) {
+		if f.markComments(child) {
+			has = true
+		}
+	}
+	f.commented[e.ID()] = has
+	return has
+}
+
+// hasInnerComments indicates whether any descendant of the node has an attached comment.
+func (f *formatter) hasInnerComments(e ast.Expr) bool {
+	for _, child := range exprChildren(e) {
+		if f.commented[child.ID()] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formatter) emit(s string) {
+	if s == "" {
+		return
+	}
+	f.out.WriteString(s)
+	if i := strings.LastIndexAny(s, "\n"); i >= 0 {
+		f.col = utf8.RuneCountInString(s[i+1:])
+	} else {
+		f.col += utf8.RuneCountInString(s)
+	}
+	f.lineStart = false
+}
+
+// newline ends the current line, writing any pending trailing and footer comments first.
+func (f *formatter) newline() {
+	f.flush()
+	f.out.WriteString("\n")
+	f.out.WriteString(strings.Repeat(" ", f.indent))
+	f.col = f.indent
+	f.lineStart = true
+}
+
+func (f *formatter) flush() {
+	for _, t := range f.trailing {
+		f.out.WriteString(" ")
+		f.out.WriteString(t)
+	}
+	f.trailing = nil
+	for _, ft := range f.footers {
+		f.out.WriteString("\n")
+		f.out.WriteString(strings.Repeat(" ", ft.indent))
+		f.out.WriteString(ft.text)
+	}
+	f.footers = nil
+}
+
+func (f *formatter) fits(s string) bool {
+	return !strings.Contains(s, "\n") && f.col+utf8.RuneCountInString(s) <= f.lineWidth
+}
+
+// write emits the node along with its comments, breaking it across lines if necessary.
+func (f *formatter) write(e ast.Expr) {
+	comments := f.info.GetComments(e.ID())
+	for _, c := range comments {
+		if c.Kind != ast.LeadingComment {
+			continue
+		}
+		if !f.lineStart {
+			f.newline()
+		}
+		f.emit(c.Text)
+		f.newline()
+	}
+	if f.hasInnerComments(e) {
+		f.writeBroken(e)
+	} else if flat := f.flat(e); f.fits(flat) {
+		f.emit(flat)
+	} else {
+		f.writeBroken(e)
+	}
+	for _, c := range comments {
+		switch c.Kind {
+		case ast.TrailingComment:
+			f.trailing = append(f.trailing, c.Text)
+		case ast.FooterComment:
+			f.footers = append(f.footers, footer{indent: f.indent, text: c.Text})
+		}
+	}
+}
+
+// writeNested writes a subexpression, wrapping it in parentheses when required.
+func (f *formatter) writeNested(e ast.Expr, nested bool) {
+	if !nested {
+		f.write(e)
+		return
+	}
+	if !f.commented[e.ID()] {
+		if flat := "(" + f.flat(e) + ")"; f.fits(flat) {
+			f.emit(flat)
+			return
+		}
+	}
+	f.emit("(")
+	f.indent += f.indentWidth
+	f.newline()
+	f.write(e)
+	f.indent -= f.indentWidth
+	f.newline()
+	f.emit(")")
+}
+
+func (f *formatter) flatNested(e ast.Expr, nested bool) string {
+	if nested {
+		return "(" + f.flat(e) + ")"
+	}
+	return f.flat(e)
+}
+
+// flat renders the node on a single line.
+func (f *formatter) flat(e ast.Expr) string {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent()
+	case ast.LiteralKind:
+		return f.literal(e)
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		return f.flatNested(sel.Operand(), isOperatorExpr(sel.Operand())) + "." + maybeQuoteField(sel.FieldName())
+	case ast.ListKind:
+		l := e.AsList()
+		optIndices := make(map[int]bool)
+		for _, idx := range l.OptionalIndices() {
+			optIndices[int(idx)] = true
+		}
+		elems := make([]string, len(l.Elements()))
+		for i, elem := range l.Elements() {
+			elems[i] = f.flat(elem)
+			if optIndices[i] {
+				elems[i] = "?" + elems[i]
+			}
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case ast.MapKind:
+		entries := make([]string, len(e.AsMap().Entries()))
+		for i, entry := range e.AsMap().Entries() {
+			entries[i] = f.flatMapEntry(entry.AsMapEntry())
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case ast.StructKind:
+		s := e.AsStruct()
+		fields := make([]string, len(s.Fields()))
+		for i, field := range s.Fields() {
+			fields[i] = f.flatStructField(field.AsStructField())
+		}
+		return s.TypeName() + "{" + strings.Join(fields, ", ") + "}"
+	case ast.CallKind:
+		return f.flatCall(e)
+	}
+	return ""
+}
+
+func (f *formatter) flatMapEntry(entry ast.MapEntry) string {
+	opt := ""
+	if entry.IsOptional() {
+		opt = "?"
+	}
+	return opt + f.flat(entry.Key()) + ": " + f.flat(entry.Value())
+}
+
+func (f *formatter) flatStructField(field ast.StructField) string {
+	opt := ""
+	if field.IsOptional() {
+		opt = "?"
+	}
+	return opt + maybeQuoteField(field.Name()) + ": " + f.flat(field.Value())
+}
+
+func (f *formatter) flatCall(e ast.Expr) string {
+	call := e.AsCall()
+	fn := call.FunctionName()
+	args := call.Args()
+	switch fn {
+	case operators.Conditional:
+		return f.flatNested(args[0], isConditionalOperand(args[0])) + " ? " +
+			f.flatNested(args[1], isConditionalOperand(args[1])) + " : " +
+			f.flat(args[2])
+	case operators.OptSelect:
+		return f.flatNested(args[0], isOperatorExpr(args[0])) + ".?" +
+			maybeQuoteField(string(args[1].AsLiteral().(types.String)))
+	case operators.Index, operators.OptIndex:
+		op := "["
+		if fn == operators.OptIndex {
+			op = "[?"
+		}
+		return f.flatNested(args[0], isOperatorExpr(args[0])) + op + f.flat(args[1]) + "]"
+	case operators.LogicalNot, operators.Negate:
+		unmangled, _ := operators.FindReverse(fn)
+		return unmangled + f.flatNested(args[0], f.unaryOperandParens(args[0]))
+	}
+	if unmangled, found := operators.FindReverseBinaryOperator(fn); found {
+		lhsParen, rhsParen := binaryOperandParens(fn, args[0], args[1])
+		return f.flatNested(args[0], lhsParen) + " " + unmangled + " " + f.flatNested(args[1], rhsParen)
+	}
+	var sb strings.Builder
+	if call.IsMemberFunction() {
+		sb.WriteString(f.flatNested(call.Target(), isOperatorExpr(call.Target())))
+		sb.WriteString(".")
+	}
+	sb.WriteString(fn)
+	sb.WriteString("(")
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(f.flat(arg))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// isOperatorExpr returns whether the expression is a unary, binary, or ternary operator, which must
+// be parenthesized when it is the operand of a field selection, index, or member call.
+func isOperatorExpr(e ast.Expr) bool {
+	return isBinaryOrTernaryOperator(e) || isUnaryOperator(e)
+}
+
+func isUnaryOperator(e ast.Expr) bool {
+	if e.Kind() != ast.CallKind || len(e.AsCall().Args()) != 1 {
+		return false
+	}
+	fn := e.AsCall().FunctionName()
+	return fn == operators.LogicalNot || fn == operators.Negate
+}
+
+// unaryOperandParens returns whether the operand of a unary operator must be parenthesized.
+//
+// Besides binary and ternary operators, this includes unary operators and negative literals, since
+// repeated operators such as `!!a` and `--a` are folded away by the parser.
+func (f *formatter) unaryOperandParens(operand ast.Expr) bool {
+	if isComplexOperator(operand) || isUnaryOperator(operand) {
+		return true
+	}
+	return operand.Kind() == ast.LiteralKind && strings.HasPrefix(f.literal(operand), "-")
+}
+
+// literal returns the source spelling of a literal so that quoting and numeric formats are preserved.
+func (f *formatter) literal(e ast.Expr) string {
+	r := f.ranges[e.ID()]
+	if r.Start < 0 {
+		return ""
+	}
+	text := f.text(r.Start, r.Stop)
+	if strings.HasPrefix(text, "-") {
+		return "-" + strings.TrimLeft(text[1:], " \t\r\n")
+	}
+	return text
+}
+
+// writeBroken writes the node across multiple lines according to its kind.
+func (f *formatter) writeBroken(e ast.Expr) {
+	switch e.Kind() {
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		f.writeNested(sel.Operand(), isOperatorExpr(sel.Operand()))
+		f.emit("." + maybeQuoteField(sel.FieldName()))
+	case ast.ListKind:
+		l := e.AsList()
+		optIndices := make(map[int]bool)
+		for _, idx := range l.OptionalIndices() {
+			optIndices[int(idx)] = true
+		}
+		f.writeElements("[", "]", len(l.Elements()), func(i int) {
+			if optIndices[i] {
+				f.emit("?")
+			}
+			f.write(l.Elements()[i])
+		})
+	case ast.MapKind:
+		entries := e.AsMap().Entries()
+		f.writeElements("{", "}", len(entries), func(i int) {
+			entry := entries[i].AsMapEntry()
+			if entry.IsOptional() {
+				f.emit("?")
+			}
+			f.write(entry.Key())
+			f.emit(": ")
+			f.write(entry.Value())
+		})
+	case ast.StructKind:
+		s := e.AsStruct()
+		fields := s.Fields()
+		f.writeElements(s.TypeName()+"{", "}", len(fields), func(i int) {
+			field := fields[i].AsStructField()
+			if field.IsOptional() {
+				f.emit("?")
+			}
+			f.emit(maybeQuoteField(field.Name()) + ": ")
+			f.write(field.Value())
+		})
+	case ast.CallKind:
+		f.writeBrokenCall(e)
+	default:
+		f.emit(f.flat(e))
+	}
+}
+
+// writeElements writes a delimited sequence with one element per indented line.
+func (f *formatter) writeElements(open, close string, count int, elem func(int)) {
+	f.emit(open)
+	if count == 0 {
+		f.emit(close)
+		return
+	}
+	f.indent += f.indentWidth
+	for i := 0; i < count; i++ {
+		f.newline()
+		elem(i)
+		if i < count-1 {
+			f.emit(",")
+		}
+	}
+	f.indent -= f.indentWidth
+	f.newline()
+	f.emit(close)
+}
+
+func (f *formatter) writeBrokenCall(e ast.Expr) {
+	call := e.AsCall()
+	fn := call.FunctionName()
+	args := call.Args()
+	switch fn {
+	case operators.Conditional:
+		f.writeNested(args[0], isConditionalOperand(args[0]))
+		f.indent += f.indentWidth
+		f.newline()
+		f.emit("? ")
+		f.writeNested(args[1], isConditionalOperand(args[1]))
+		f.newline()
+		f.emit(": ")
+		f.write(args[2])
+		f.indent -= f.indentWidth
+		return
+	case operators.OptSelect:
+		f.writeNested(args[0], isOperatorExpr(args[0]))
+		f.emit(".?" + maybeQuoteField(string(args[1].AsLiteral().(types.String))))
+		return
+	case operators.Index, operators.OptIndex:
+		f.writeNested(args[0], isOperatorExpr(args[0]))
+		if fn == operators.OptIndex {
+			f.emit("[?")
+		} else {
+			f.emit("[")
+		}
+		f.write(args[1])
+		f.emit("]")
+		return
+	case operators.LogicalNot, operators.Negate:
+		unmangled, _ := operators.FindReverse(fn)
+		f.emit(unmangled)
+		f.writeNested(args[0], f.unaryOperandParens(args[0]))
+		return
+	case operators.LogicalAnd, operators.LogicalOr:
+		unmangled, _ := operators.FindReverseBinaryOperator(fn)
+		terms := f.logicalTerms(e, fn)
+		for i, term := range terms {
+			if i > 0 {
+				f.newline()
+			}
+			f.writeNested(term, isComplexOperatorWithRespectTo(fn, term))
+			if i < len(terms)-1 {
+				f.emit(" " + unmangled)
+			}
+		}
+		return
+	}
+	if unmangled, found := operators.FindReverseBinaryOperator(fn); found {
+		lhsParen, rhsParen := binaryOperandParens(fn, args[0], args[1])
+		f.writeNested(args[0], lhsParen)
+		f.emit(" " + unmangled)
+		f.indent += f.indentWidth
+		f.newline()
+		f.writeNested(args[1], rhsParen)
+		f.indent -= f.indentWidth
+		return
+	}
+	if call.IsMemberFunction() && f.memberCallCount(e) > 1 {
+		f.writeCallChain(e)
+		return
+	}
+	if call.IsMemberFunction() {
+		f.writeNested(call.Target(), isOperatorExpr(call.Target()))
+		f.emit(".")
+	}
+	f.writeArgs(e)
+}
+
+// writeArgs writes the function name and argument list of a call, placing each argument on its
+// own indented line when the arguments do not fit on the current line.
+func (f *formatter) writeArgs(e ast.Expr) {
+	call := e.AsCall()
+	args := call.Args()
+	f.emit(call.FunctionName() + "(")
+	if !f.hasArgComments(e) {
+		flat := make([]string, len(args))
+		for i, arg := range args {
+			flat[i] = f.flat(arg)
+		}
+		if s := strings.Join(flat, ", ") + ")"; f.fits(s) {
+			f.emit(s)
+			return
+		}
+	}
+	// Keep the iteration variables of comprehension macros on the line of the call.
+	inline := 0
+	if call.IsMemberFunction() && len(args) > 1 {
+		for inline < len(args)-1 && args[inline].Kind() == ast.IdentKind && !f.commented[args[inline].ID()] {
+			inline++
+		}
+	}
+	for i := 0; i < inline; i++ {
+		f.emit(args[i].AsIdent() + ",")
+		if i < inline-1 {
+			f.emit(" ")
+		}
+	}
+	f.indent += f.indentWidth
+	for i := inline; i < len(args); i++ {
+		f.newline()
+		f.write(args[i])
+		if i < len(args)-1 {
+			f.emit(",")
+		}
+	}
+	f.indent -= f.indentWidth
+	if len(args) > 0 {
+		f.newline()
+	}
+	f.emit(")")
+}
+
+func (f *formatter) hasArgComments(e ast.Expr) bool {
+	for _, arg := range e.AsCall().Args() {
+		if f.commented[arg.ID()] {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCallChain writes a chain of member calls with each call on its own indented line.
+func (f *formatter) writeCallChain(e ast.Expr) {
+	var links []ast.Expr
+	base := e
+	for isChainLink(base) {
+		links = append(links, base)
+		base = chainOperand(base)
+	}
+	f.writeNested(base, isOperatorExpr(base))
+	f.indent += f.indentWidth
+	for i := len(links) - 1; i >= 0; i-- {
+		link := links[i]
+		// The comments of the outermost link are written by the caller.
+		var comments []ast.Comment
+		if i > 0 {
+			comments = f.info.GetComments(link.ID())
+		}
+		for _, c := range comments {
+			if c.Kind == ast.LeadingComment {
+				f.newline()
+				f.emit(c.Text)
+			}
+		}
+		switch link.Kind() {
+		case ast.SelectKind:
+			f.emit("." + maybeQuoteField(link.AsSelect().FieldName()))
+		case ast.CallKind:
+			call := link.AsCall()
+			switch call.FunctionName() {
+			case operators.OptSelect:
+				f.emit(".?" + maybeQuoteField(string(call.Args()[1].AsLiteral().(types.String))))
+			case operators.Index, operators.OptIndex:
+				if call.FunctionName() == operators.OptIndex {
+					f.emit("[?")
+				} else {
+					f.emit("[")
+				}
+				f.write(call.Args()[1])
+				f.emit("]")
+			default:
+				f.newline()
+				f.emit(".")
+				f.writeArgs(link)
+			}
+		}
+		for _, c := range comments {
+			switch c.Kind {
+			case ast.TrailingComment:
+				f.trailing = append(f.trailing, c.Text)
+			case ast.FooterComment:
+				f.footers = append(f.footers, footer{indent: f.indent, text: c.Text})
+			}
+		}
+	}
+	f.indent -= f.indentWidth
+}
+
+// memberCallCount returns the number of member calls in the chain of member calls, field selections,
+// and index operations rooted at the node.
+func (f *formatter) memberCallCount(e ast.Expr) int {
+	count := 0
+	for isChainLink(e) {
+		if e.Kind() == ast.CallKind && e.AsCall().IsMemberFunction() {
+			count++
+		}
+		e = chainOperand(e)
+	}
+	return count
+}
+
+func isChainLink(e ast.Expr) bool {
+	switch e.Kind() {
+	case ast.SelectKind:
+		return true
+	case ast.CallKind:
+		call := e.AsCall()
+		switch call.FunctionName() {
+		case operators.OptSelect, operators.Index, operators.OptIndex:
+			return true
+		}
+		return call.IsMemberFunction()
+	}
+	return false
+}
+
+func chainOperand(e ast.Expr) ast.Expr {
+	if e.Kind() == ast.SelectKind {
+		return e.AsSelect().Operand()
+	}
+	call := e.AsCall()
+	if call.IsMemberFunction() {
+		return call.Target()
+	}
+	return call.Args()[0]
+}
+
+// logicalTerms flattens a chain of like logical operators into its terms, keeping any nested
+// operator which carries its own comments intact.
+func (f *formatter) logicalTerms(e ast.Expr, fn string) []ast.Expr {
+	var terms []ast.Expr
+	for _, arg := range e.AsCall().Args() {
+		if arg.Kind() == ast.CallKind && arg.AsCall().FunctionName() == fn &&
+			len(f.info.GetComments(arg.ID())) == 0 {
+			terms = append(terms, f.logicalTerms(arg, fn)...)
+			continue
+		}
+		terms = append(terms, arg)
+	}
+	return terms
+}
+
+// binaryOperandParens determines whether the operands of a binary operator require parentheses.
+func binaryOperandParens(fn string, lhs, rhs ast.Expr) (bool, bool) {
+	lhsParen := isComplexOperatorWithRespectTo(fn, lhs)
+	rhsParen := isComplexOperatorWithRespectTo(fn, rhs)
+	if !rhsParen && isLeftRecursive(fn) {
+		rhsParen = isSamePrecedence(fn, rhs)
+	}
+	return lhsParen, rhsParen
+}
+
+// isConditionalOperand indicates whether the condition or first branch of a conditional requires
+// parentheses. The conditional is right-associative, so the final branch never does.
+func isConditionalOperand(e ast.Expr) bool {
+	return isSamePrecedence(operators.Conditional, e)
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"testing"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/debug"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		out  string
+		opts []FormatOption
+	}{
+		{in: `a+b*c`, out: `a + b * c`},
+		{in: `(a+b)*c`, out: `(a + b) * c`},
+		{in: `((a))`, out: `a`},
+		{in: `a - (b - c)`, out: `a - (b - c)`},
+		{in: `!(a&&b)`, out: `!(a && b)`},
+		{in: `-1+x`, out: `-1 + x`},
+		{in: `a?b:c?d:e`, out: `a ? b : c ? d : e`},
+		{in: `(a?b:c)?d:e`, out: `(a ? b : c) ? d : e`},
+		{in: `0x1F+1u+2.5e3+b'abc'+r'raw'+"dq"`, out: `0x1F + 1u + 2.5e3 + b'abc' + r'raw' + "dq"`},
+		{in: `x[0].y[?1].?z`, out: `x[0].y[?1].?z`},
+		{in: `{'a':1,?'b':x}`, out: `{'a': 1, ?'b': x}`},
+		{in: `[1,?x,3]`, out: `[1, ?x, 3]`},
+		{in: `google.expr.Msg{f:1,?g:x}`, out: `google.expr.Msg{f: 1, ?g: x}`},
+		{in: `.pkg.f(x)`, out: `.pkg.f(x)`},
+		{in: `has(a.b)&&a.b.size()>0`, out: `has(a.b) && a.b.size() > 0`},
+		{in: `(-a).b`, out: `(-a).b`},
+		{in: `(!a).b()`, out: `(!a).b()`},
+		{in: `(-a)[0].?b`, out: `(-a)[0].?b`},
+		{in: `-(-a)`, out: `-(-a)`},
+		{in: `!(!a)`, out: `!(!a)`},
+		{in: `!(-a)`, out: `!(-a)`},
+		{in: `-(-1)`, out: `-(-1)`},
+		{
+			in: `(!items).filter(i, i.price > 100 && i.category == 'electronics').map(i, i.name.upperAscii())`,
+			out: `(!items)
+  .filter(i, i.price > 100 && i.category == 'electronics')
+  .map(i, i.name.upperAscii())`,
+		},
+		{
+			in: `request.auth.claims.exists(c, c.startsWith('admin')) && request.time < timestamp('2024-01-01T00:00:00Z') && resource.name.startsWith('/projects/')`,
+			out: `request.auth.claims.exists(c, c.startsWith('admin')) &&
+request.time < timestamp('2024-01-01T00:00:00Z') &&
+resource.name.startsWith('/projects/')`,
+		},
+		{
+			in: `items.filter(i, i.price > 100 && i.category == 'electronics').map(i, i.name.upperAscii()).sortBy(n, n)`,
+			out: `items
+  .filter(i, i.price > 100 && i.category == 'electronics')
+  .map(i, i.name.upperAscii())
+  .sortBy(n, n)`,
+		},
+		{
+			in: `items.exists(i, i.tags.exists(t, t == 'very-long-tag-name-number-one' || t == 'another-long-tag'))`,
+			out: `items.exists(i,
+  i.tags.exists(t,
+    t == 'very-long-tag-name-number-one' ||
+    t == 'another-long-tag'
+  )
+)`,
+			opts: []FormatOption{FormatLineWidth(60)},
+		},
+		{
+			in: `f(first_argument, second_argument)`,
+			out: `f(
+    first_argument,
+    second_argument
+)`,
+			opts: []FormatOption{FormatLineWidth(20), FormatIndentWidth(4)},
+		},
+		{
+			in: "// leading\na && // trailing a\n b // trailing b\n// footer",
+			out: `// leading
+a && // trailing a
+b // trailing b
+// footer`,
+		},
+		{
+			in: "a\n&& b // about b\n&& c // about c\n&& d",
+			out: `a &&
+b && // about b
+c && // about c
+d`,
+		},
+		{
+			in: "a ||\nb.exists(x, x > 0) // any b\n|| c",
+			out: `a ||
+b.exists(x, x > 0) || // any b
+c`,
+		},
+		{
+			in: "[1,   // one\n 2]",
+			out: `[
+  1, // one
+  2
+]`,
+		},
+		{
+			in: "f(\n // lead arg\n a)",
+			out: `f(
+  // lead arg
+  a
+)`,
+		},
+		{
+			in: "x.filter(a,a>1) // keep\n// then double\n.map(a,a*2).exists(a,a==4) // done",
+			out: `x
+  .filter(a, a > 1) // keep
+  // then double
+  .map(a, a * 2)
+  .exists(a, a == 4) // done`,
+		},
+		{
+			in: "cond // why\n ? x : y",
+			out: `cond // why
+  ? x
+  : y`,
+		},
+	}
+	p, err := NewPrattParser(EnableOptionalSyntax(true))
+	if err != nil {
+		t.Fatalf("NewPrattParser() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.in, func(t *testing.T) {
+			out, err := Format(common.NewTextSource(tc.in), tc.opts...)
+			if err != nil {
+				t.Fatalf("Format(%q) failed: %v", tc.in, err)
+			}
+			if out != tc.out {
+				t.Errorf("Format(%q) got:\n%s\nwanted:\n%s", tc.in, out, tc.out)
+			}
+			// Formatting must not change the parsed expression.
+			in, iss := p.Parse(common.NewTextSource(tc.in))
+			if len(iss.GetErrors()) != 0 {
+				t.Fatalf("Parse(%q) failed: %v", tc.in, iss.ToDisplayString())
+			}
+			formatted, iss := p.Parse(common.NewTextSource(out))
+			if len(iss.GetErrors()) != 0 {
+				t.Fatalf("Parse(%q) failed: %v", out, iss.ToDisplayString())
+			}
+			if got, want := debug.ToDebugString(formatted.Expr()), debug.ToDebugString(in.Expr()); got != want {
+				t.Errorf("Parse(Format(%q)) got:\n%s\nwanted:\n%s", tc.in, got, want)
+			}
+			again, err := Format(common.NewTextSource(out), tc.opts...)
+			if err != nil {
+				t.Fatalf("Format(%q) failed: %v", out, err)
+			}
+			if again != out {
+				t.Errorf("Format() is not idempotent, got:\n%s\nwanted:\n%s", again, out)
+			}
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	if _, err := Format(common.NewTextSource(`a +`)); err == nil {
+		t.Error("Format() with a syntax error succeeded, wanted error")
+	}
+	if _, err := Format(common.NewTextSource(`a`), FormatLineWidth(0)); err == nil {
+		t.Error("Format() with an invalid line width succeeded, wanted error")
+	}
+	if _, err := Format(common.NewTextSource(`a`), FormatIndentWidth(-1)); err == nil {
+		t.Error("Format() with an invalid indent width succeeded, wanted error")
+	}
+}
@@ -29,6 +29,8 @@ type options struct {
 	enableVariadicOperatorASTs       bool
 	enableIdentEscapeSyntax          bool
 	enableHiddenAccumulatorName      bool
+	errorTolerant                    bool
+	populateComments                 bool
 }
 
 // Option configures the behavior of the parser.
@@ -134,6 +136,18 @@ func PopulateMacroCalls(populateMacroCalls bool) Option {
 	}
 }
 
+// PopulateComments attaches the `//` comments within the source to the nearest expression nodes
+// and records them in the `SourceInfo` of the parse result.
+//
+// Comments are only meaningful for expressions parsed without macros, since macro expansion
+// replaces the expressions to which the comments would otherwise be attached.
+func PopulateComments(populateComments bool) Option {
+	return func(opts *options) error {
+		opts.populateComments = populateComments
+		return nil
+	}
+}
+
 // EnableOptionalSyntax enables syntax for optional field and index selection.
 func EnableOptionalSyntax(optionalSyntax bool) Option {
 	return func(opts *options) error {
@@ -174,3 +188,19 @@ func EnableVariadicOperatorASTs(varArgASTs bool) Option {
 		return nil
 	}
 }
+
+// ErrorTolerant configures the PrattParser to return a best-effort AST alongside any syntax errors
+// rather than discarding the parse result.
+//
+// Incomplete portions of the expression are represented by unspecified expression nodes located at
+// the point of failure, and member accesses missing a field name, e.g. `request.user.`, are preserved
+// as select expressions with an empty field. The resulting AST is not suitable for evaluation, but
+// may be type-checked to support authoring aids such as code completion.
+//
+// The ANTLR-based Parser always returns its partial AST, so this option has no effect on it.
+func ErrorTolerant(enabled bool) Option {
+	return func(opts *options) error {
+		opts.errorTolerant = enabled
+		return nil
+	}
+}
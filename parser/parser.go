@@ -129,6 +129,9 @@ func (p *Parser) Parse(source common.Source) (*ast.AST, *common.Errors) {
 	} else {
 		out = impl.parse(buf, source.Description())
 	}
+	if p.populateComments && out != nil && len(errs.GetErrors()) == 0 {
+		attachComments(out, impl.helper.getSourceInfo(), buf)
+	}
 	return ast.NewAST(out, impl.helper.getSourceInfo()), errs
 }
 
@@ -107,6 +107,7 @@ type prattParser struct {
 	enableOptionalSyntax       bool
 	enableVariadicOperatorASTs bool
 	enableIdentEscapeSyntax    bool
+	errorTolerant              bool
 }
 
 // PrattParser encapsulates the context necessary to perform Pratt parsing for different expressions.
@@ -188,12 +189,16 @@ func (p *PrattParser) Parse(source common.Source) (*ast.AST, *common.Errors) {
 		enableOptionalSyntax:       p.enableOptionalSyntax,
 		enableVariadicOperatorASTs: p.enableVariadicOperatorASTs,
 		enableIdentEscapeSyntax:    p.enableIdentEscapeSyntax,
+		errorTolerant:              p.errorTolerant,
 	}
 	pratt.initTokenStream()
 	out := pratt.parse()
-	if len(errs.GetErrors()) > 0 {
+	if len(errs.GetErrors()) > 0 && !p.errorTolerant {
 		return nil, errs
 	}
+	if p.populateComments && len(errs.GetErrors()) == 0 {
+		attachComments(out, pratt.helper.getSourceInfo(), buf)
+	}
 	return ast.NewAST(out, pratt.helper.getSourceInfo()), errs
 }
 
@@ -506,6 +511,14 @@ func (p *prattParser) parseSelectorChainTail(lhs ast.Expr) ast.Expr {
 					p.reportError(dotTok, "unsupported syntax '.?'")
 				}
 			}
+			if p.errorTolerant && p.peekTok.kind != tokIdent && p.peekTok.kind != tokReservedWord {
+				// Preserve the operand of the incomplete member access within the partial AST
+				// without consuming the offending token.
+				if p.peekTok.kind != tokError {
+					p.reportError(p.peekTok, "expected identifier after '.'")
+				}
+				return p.helper.newSelect(p.nextID(dotTok), lhs, "")
+			}
 			fieldTok := p.nextToken()
 			if fieldTok.kind != tokIdent && fieldTok.kind != tokReservedWord {
 				if fieldTok.kind != tokError {
@@ -0,0 +1,502 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/env"
+
+	"go.yaml.in/yaml/v3"
+
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	descpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Bundle is a versioned collection of compiled policies along with the environment configuration
+// and protobuf type descriptors required to evaluate them.
+//
+// Bundles are serialized with WriteBundle as a gzip-compressed tar archive containing:
+//
+//   - manifest.yaml: the bundle name, version, policy names, and the SHA-256 hash of every other
+//     file in the archive.
+//   - manifest.sig: an optional detached ed25519 signature of the manifest.
+//   - env.yaml: the environment configuration.
+//   - descriptors.binpb: the file descriptor set, if present.
+//   - policies/<name>.binpb: the checked expression of each policy.
+//
+// Since the manifest covers the hash of every file, the signature attests to the contents of the
+// whole bundle.
+type Bundle struct {
+	// Name is the name of the bundle.
+	Name string
+
+	// Version is the semantic version of the bundle, e.g. `1.2.0`.
+	Version string
+
+	// Config is the environment configuration against which the policies were compiled.
+	Config *env.Config
+
+	// FileDescriptorSet contains the protobuf types referenced by the policies, if any.
+	FileDescriptorSet *descpb.FileDescriptorSet
+
+	// Policies are the compiled policies within the bundle.
+	Policies []*BundlePolicy
+
+	// Signature is the detached signature of the bundle manifest. It is populated by ReadBundle when
+	// the bundle is signed.
+	Signature []byte
+}
+
+// BundlePolicy is a named, checked policy expression within a bundle.
+type BundlePolicy struct {
+	Name string
+	Ast  *cel.Ast
+}
+
+// AddPolicy adds a checked policy expression to the bundle.
+func (b *Bundle) AddPolicy(name string, a *cel.Ast) error {
+	if !bundleNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid bundle policy name: %q", name)
+	}
+	if a == nil || !a.IsChecked() {
+		return fmt.Errorf("bundle policy %s must be a checked expression", name)
+	}
+	for _, p := range b.Policies {
+		if p.Name == name {
+			return fmt.Errorf("duplicate bundle policy name: %s", name)
+		}
+	}
+	b.Policies = append(b.Policies, &BundlePolicy{Name: name, Ast: a})
+	return nil
+}
+
+// Env creates a CEL environment from the bundle configuration and file descriptor set.
+//
+// The configuration only declares functions, so the environment options must include the
+// implementations of any custom functions referenced by the policies.
+func (b *Bundle) Env(opts ...cel.EnvOption) (*cel.Env, error) {
+	var envOpts []cel.EnvOption
+	if b.FileDescriptorSet != nil {
+		envOpts = append(envOpts, cel.TypeDescs(b.FileDescriptorSet))
+	}
+	config := b.Config
+	if config == nil {
+		config = &env.Config{}
+	}
+	envOpts = append(envOpts, FromConfig(config))
+	envOpts = append(envOpts, opts...)
+	// The configuration includes the standard library unless it declares a subset.
+	return cel.NewCustomEnv(envOpts...)
+}
+
+// Programs creates a program for each policy in the bundle, keyed by policy name.
+//
+// The checked expressions within the bundle are planned directly without being parsed or
+// type-checked again.
+func (b *Bundle) Programs(e *cel.Env, opts ...cel.ProgramOption) (map[string]cel.Program, error) {
+	prgs := make(map[string]cel.Program, len(b.Policies))
+	for _, p := range b.Policies {
+		prg, err := e.Program(p.Ast, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating program for bundle policy %s: %w", p.Name, err)
+		}
+		prgs[p.Name] = prg
+	}
+	return prgs, nil
+}
+
+// BundleOption configures how a bundle is written or read.
+type BundleOption func(*bundleOptions) (*bundleOptions, error)
+
+type bundleOptions struct {
+	signingKey      ed25519.PrivateKey
+	verificationKey ed25519.PublicKey
+	maxFileSize     int64
+	maxTotalSize    int64
+}
+
+// SignBundle signs the bundle manifest with the given ed25519 private key when writing a bundle.
+func SignBundle(key ed25519.PrivateKey) BundleOption {
+	return func(o *bundleOptions) (*bundleOptions, error) {
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, errors.New("invalid ed25519 private key")
+		}
+		o.signingKey = key
+		return o, nil
+	}
+}
+
+// VerifyBundleSignature requires that a bundle is signed by the holder of the private key
+// corresponding to the given ed25519 public key when reading a bundle.
+//
+// Without this option, a signature present within the bundle is not verified, though the content
+// hashes within the manifest are always verified.
+func VerifyBundleSignature(key ed25519.PublicKey) BundleOption {
+	return func(o *bundleOptions) (*bundleOptions, error) {
+		if len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 public key")
+		}
+		o.verificationKey = key
+		return o, nil
+	}
+}
+
+// BundleSizeLimits limits the size of each file within a bundle and the combined size of the files
+// when reading a bundle.
+//
+// The sizes apply to the decompressed contents and default to 64 MiB per file and 256 MiB in total.
+func BundleSizeLimits(maxFileSize, maxTotalSize int64) BundleOption {
+	return func(o *bundleOptions) (*bundleOptions, error) {
+		if maxFileSize <= 0 || maxTotalSize <= 0 {
+			return nil, errors.New("bundle size limits must be positive")
+		}
+		o.maxFileSize = maxFileSize
+		o.maxTotalSize = maxTotalSize
+		return o, nil
+	}
+}
+
+// WriteBundle serializes the bundle to the writer, optionally signing it.
+//
+// The output is deterministic for a given bundle and signing key.
+func WriteBundle(w io.Writer, b *Bundle, opts ...BundleOption) error {
+	o, err := newBundleOptions(opts)
+	if err != nil {
+		return err
+	}
+	if !bundleNamePattern.MatchString(b.Name) {
+		return fmt.Errorf("invalid bundle name: %q", b.Name)
+	}
+	if !semverPattern.MatchString(b.Version) {
+		return fmt.Errorf("invalid bundle version, wanted a semantic version: %q", b.Version)
+	}
+	if len(b.Policies) == 0 {
+		return errors.New("bundle must contain at least one policy")
+	}
+	var files []*bundleEntry
+	config := b.Config
+	if config == nil {
+		config = &env.Config{}
+	}
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("error serializing bundle config: %w", err)
+	}
+	files = append(files, &bundleEntry{path: bundleConfigPath, data: configData})
+	marshaler := proto.MarshalOptions{Deterministic: true}
+	if b.FileDescriptorSet != nil {
+		data, err := marshaler.Marshal(b.FileDescriptorSet)
+		if err != nil {
+			return fmt.Errorf("error serializing bundle descriptors: %w", err)
+		}
+		files = append(files, &bundleEntry{path: bundleDescriptorsPath, data: data})
+	}
+	m := &bundleManifest{Name: b.Name, Version: b.Version}
+	for _, p := range b.Policies {
+		checked, err := cel.AstToCheckedExpr(p.Ast)
+		if err != nil {
+			return fmt.Errorf("error serializing bundle policy %s: %w", p.Name, err)
+		}
+		data, err := marshaler.Marshal(checked)
+		if err != nil {
+			return fmt.Errorf("error serializing bundle policy %s: %w", p.Name, err)
+		}
+		path := bundlePolicyPath(p.Name)
+		files = append(files, &bundleEntry{path: path, data: data})
+		m.Policies = append(m.Policies, &bundleManifestPolicy{Name: p.Name, Path: path})
+	}
+	for _, f := range files {
+		m.Files = append(m.Files, &bundleManifestFile{Path: f.path, SHA256: contentHash(f.data)})
+	}
+	manifest, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error serializing bundle manifest: %w", err)
+	}
+	entries := []*bundleEntry{{path: bundleManifestPath, data: manifest}}
+	if o.signingKey != nil {
+		entries = append(entries, &bundleEntry{path: bundleSignaturePath, data: ed25519.Sign(o.signingKey, manifest)})
+	}
+	entries = append(entries, files...)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:    e.path,
+			Mode:    0644,
+			Size:    int64(len(e.data)),
+			ModTime: time.Unix(0, 0),
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadBundle deserializes a bundle from the reader, verifying the content hashes within the bundle
+// manifest and, if configured, the bundle signature.
+//
+// The manifest and its signature must be the first entries of the archive, as written by
+// WriteBundle, so that the manifest is verified before any other file is read. Files which are not
+// listed in the manifest are rejected as soon as they are encountered, and the size of every file
+// is limited as configured with BundleSizeLimits.
+func ReadBundle(r io.Reader, opts ...BundleOption) (*Bundle, error) {
+	o, err := newBundleOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle archive: %w", err)
+	}
+	br := &bundleReader{tr: tar.NewReader(gz), maxFileSize: o.maxFileSize, remaining: o.maxTotalSize}
+
+	hdr, err := br.next()
+	if err == io.EOF || (err == nil && hdr.Name != bundleManifestPath) {
+		return nil, fmt.Errorf("invalid bundle: missing manifest, wanted %s as the first entry", bundleManifestPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := br.read(hdr)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bundle{}
+	hdr, err = br.next()
+	if err == nil && hdr.Name == bundleSignaturePath {
+		if b.Signature, err = br.read(hdr); err != nil {
+			return nil, err
+		}
+		hdr, err = br.next()
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if o.verificationKey != nil {
+		if b.Signature == nil {
+			return nil, errors.New("bundle is not signed")
+		}
+		if !ed25519.Verify(o.verificationKey, manifest, b.Signature) {
+			return nil, errors.New("invalid bundle signature")
+		}
+	}
+	m := &bundleManifest{}
+	if err := yaml.Unmarshal(manifest, m); err != nil {
+		return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+	}
+	if !bundleNamePattern.MatchString(m.Name) {
+		return nil, fmt.Errorf("invalid bundle manifest: invalid bundle name: %q", m.Name)
+	}
+	if !semverPattern.MatchString(m.Version) {
+		return nil, fmt.Errorf("invalid bundle manifest: invalid bundle version, wanted a semantic version: %q", m.Version)
+	}
+	b.Name = m.Name
+	b.Version = m.Version
+
+	// Read the remaining files, each of which must be listed in the manifest.
+	hashes := make(map[string]string, len(m.Files))
+	for _, f := range m.Files {
+		hashes[f.Path] = f.SHA256
+	}
+	contents := map[string][]byte{}
+	for ; err != io.EOF; hdr, err = br.next() {
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name == bundleManifestPath || hdr.Name == bundleSignaturePath {
+			return nil, fmt.Errorf("invalid bundle archive: entry %s must precede the bundle files", hdr.Name)
+		}
+		if _, found := contents[hdr.Name]; found {
+			return nil, fmt.Errorf("invalid bundle archive: duplicate entry %s", hdr.Name)
+		}
+		hash, listed := hashes[hdr.Name]
+		if !listed {
+			return nil, fmt.Errorf("invalid bundle: unexpected file %s", hdr.Name)
+		}
+		data, err := br.read(hdr)
+		if err != nil {
+			return nil, err
+		}
+		if contentHash(data) != hash {
+			return nil, fmt.Errorf("invalid bundle: content hash mismatch for %s", hdr.Name)
+		}
+		contents[hdr.Name] = data
+	}
+	for _, f := range m.Files {
+		if _, found := contents[f.Path]; !found {
+			return nil, fmt.Errorf("invalid bundle: missing file %s", f.Path)
+		}
+	}
+
+	if data, found := contents[bundleConfigPath]; found {
+		b.Config = &env.Config{}
+		if err := yaml.Unmarshal(data, b.Config); err != nil {
+			return nil, fmt.Errorf("invalid bundle config: %w", err)
+		}
+	}
+	if data, found := contents[bundleDescriptorsPath]; found {
+		b.FileDescriptorSet = &descpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, b.FileDescriptorSet); err != nil {
+			return nil, fmt.Errorf("invalid bundle descriptors: %w", err)
+		}
+	}
+	for _, p := range m.Policies {
+		data, found := contents[p.Path]
+		if !found {
+			return nil, fmt.Errorf("invalid bundle: missing policy %s", p.Name)
+		}
+		checked := &exprpb.CheckedExpr{}
+		if err := proto.Unmarshal(data, checked); err != nil {
+			return nil, fmt.Errorf("invalid bundle policy %s: %w", p.Name, err)
+		}
+		a, err := cel.CheckedExprToAstWithSource(checked, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle policy %s: %w", p.Name, err)
+		}
+		if err := b.AddPolicy(p.Name, a); err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+	}
+	return b, nil
+}
+
+func newBundleOptions(opts []BundleOption) (*bundleOptions, error) {
+	o := &bundleOptions{
+		maxFileSize:  defaultMaxBundleFileSize,
+		maxTotalSize: defaultMaxBundleTotalSize,
+	}
+	var err error
+	for _, opt := range opts {
+		o, err = opt(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// bundleReader reads the entries of a bundle archive within the configured size limits.
+type bundleReader struct {
+	tr          *tar.Reader
+	maxFileSize int64
+	// remaining is the number of bytes which may still be read from the archive.
+	remaining int64
+}
+
+// next returns the header of the next archive entry, or io.EOF at the end of the archive.
+func (br *bundleReader) next() (*tar.Header, error) {
+	hdr, err := br.tr.Next()
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle archive: %w", err)
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("invalid bundle archive: unsupported entry %s", hdr.Name)
+	}
+	return hdr, nil
+}
+
+// read returns the contents of the current archive entry, failing if the entry exceeds the size
+// limits regardless of the size recorded in its header.
+func (br *bundleReader) read(hdr *tar.Header) ([]byte, error) {
+	limit := min(br.maxFileSize, br.remaining)
+	if hdr.Size > limit {
+		return nil, fmt.Errorf("invalid bundle archive: entry %s exceeds the bundle size limits", hdr.Name)
+	}
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(br.tr, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle archive: %w", err)
+	}
+	if n > limit {
+		return nil, fmt.Errorf("invalid bundle archive: entry %s exceeds the bundle size limits", hdr.Name)
+	}
+	br.remaining -= n
+	return buf.Bytes(), nil
+}
+
+type bundleEntry struct {
+	path string
+	data []byte
+}
+
+type bundleManifest struct {
+	Name     string                  `yaml:"name"`
+	Version  string                  `yaml:"version"`
+	Policies []*bundleManifestPolicy `yaml:"policies"`
+	Files    []*bundleManifestFile   `yaml:"files"`
+}
+
+type bundleManifestPolicy struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+type bundleManifestFile struct {
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+}
+
+func bundlePolicyPath(name string) string {
+	return fmt.Sprintf("policies/%s.binpb", name)
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+const (
+	defaultMaxBundleFileSize  = 64 << 20
+	defaultMaxBundleTotalSize = 256 << 20
+
+	bundleManifestPath    = "manifest.yaml"
+	bundleSignaturePath   = "manifest.sig"
+	bundleConfigPath      = "env.yaml"
+	bundleDescriptorsPath = "descriptors.binpb"
+)
+
+var (
+	bundleNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	// semverPattern matches a semantic version as defined by https://semver.org.
+	semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
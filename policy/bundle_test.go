@@ -0,0 +1,402 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/env"
+
+	proto3pb "cel.dev/cel-go/test/proto3pb"
+	descpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	pub, priv := bundleKeys(t)
+	b := newTestBundle(t)
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b, SignBundle(priv)); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+	// Serialization is deterministic.
+	var buf2 bytes.Buffer
+	if err := WriteBundle(&buf2, b, SignBundle(priv)); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), buf2.Bytes()) {
+		t.Error("WriteBundle() produced different output for the same bundle")
+	}
+
+	got, err := ReadBundle(bytes.NewReader(buf.Bytes()), VerifyBundleSignature(pub))
+	if err != nil {
+		t.Fatalf("ReadBundle() failed: %v", err)
+	}
+	if got.Name != "limits" || got.Version != "1.2.0-rc.1" {
+		t.Errorf("ReadBundle() got name %q version %q, wanted limits 1.2.0-rc.1", got.Name, got.Version)
+	}
+	if len(got.Signature) != ed25519.SignatureSize {
+		t.Errorf("ReadBundle() got signature of length %d, wanted %d", len(got.Signature), ed25519.SignatureSize)
+	}
+	if got.FileDescriptorSet == nil || len(got.FileDescriptorSet.GetFile()) != len(b.FileDescriptorSet.GetFile()) {
+		t.Errorf("ReadBundle() got descriptors %v, wanted %v", got.FileDescriptorSet, b.FileDescriptorSet)
+	}
+	e, err := got.Env()
+	if err != nil {
+		t.Fatalf("Bundle.Env() failed: %v", err)
+	}
+	prgs, err := got.Programs(e)
+	if err != nil {
+		t.Fatalf("Bundle.Programs() failed: %v", err)
+	}
+	prg, found := prgs["limits"]
+	if !found {
+		t.Fatalf("Bundle.Programs() got %v, wanted program for limits", prgs)
+	}
+	now := time.Date(2024, 7, 30, 20, 30, 0, 0, time.UTC)
+	out, _, err := prg.Eval(map[string]any{"now": now})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if out.Value() != "goodbye, me!" {
+		t.Errorf("prg.Eval() got %v, wanted 'goodbye, me!'", out)
+	}
+}
+
+func TestReadBundleUnsigned(t *testing.T) {
+	pub, _ := bundleKeys(t)
+	b := newTestBundle(t)
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+	got, err := ReadBundle(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadBundle() failed: %v", err)
+	}
+	if got.Signature != nil {
+		t.Errorf("ReadBundle() got signature %v, wanted none", got.Signature)
+	}
+	_, err = ReadBundle(bytes.NewReader(buf.Bytes()), VerifyBundleSignature(pub))
+	if err == nil || !strings.Contains(err.Error(), "bundle is not signed") {
+		t.Errorf("ReadBundle() got error %v, wanted unsigned bundle error", err)
+	}
+}
+
+func TestReadBundleErrors(t *testing.T) {
+	pub, priv := bundleKeys(t)
+	otherPub, _ := bundleKeys(t)
+	b := newTestBundle(t)
+	var signed bytes.Buffer
+	if err := WriteBundle(&signed, b, SignBundle(priv)); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+	entries := readBundleEntries(t, signed.Bytes())
+
+	tests := []struct {
+		name    string
+		entries []*bundleEntry
+		opts    []BundleOption
+		err     string
+	}{
+		{
+			name:    "wrong key",
+			entries: entries,
+			opts:    []BundleOption{VerifyBundleSignature(otherPub)},
+			err:     "invalid bundle signature",
+		},
+		{
+			name: "tampered policy",
+			entries: replaceBundleEntry(entries, bundlePolicyPath("limits"), func(data []byte) []byte {
+				return append(data, 0x0)
+			}),
+			err: "content hash mismatch for policies/limits.binpb",
+		},
+		{
+			name: "tampered manifest",
+			entries: replaceBundleEntry(entries, bundleManifestPath, func(data []byte) []byte {
+				return bytes.Replace(data, []byte("1.2.0-rc.1"), []byte("1.2.1"), 1)
+			}),
+			opts: []BundleOption{VerifyBundleSignature(pub)},
+			err:  "invalid bundle signature",
+		},
+		{
+			name:    "unexpected file",
+			entries: append(entries[:len(entries):len(entries)], &bundleEntry{path: "extra.txt", data: []byte("hi")}),
+			err:     "unexpected file extra.txt",
+		},
+		{
+			name:    "missing file",
+			entries: removeBundleEntry(entries, bundleDescriptorsPath),
+			err:     "missing file descriptors.binpb",
+		},
+		{
+			name:    "missing manifest",
+			entries: removeBundleEntry(entries, bundleManifestPath),
+			err:     "missing manifest",
+		},
+		{
+			name:    "duplicate entry",
+			entries: append(entries[:len(entries):len(entries)], entries[len(entries)-1]),
+			err:     "duplicate entry policies/limits.binpb",
+		},
+		{
+			name:    "manifest not first",
+			entries: append(removeBundleEntry(entries, bundleManifestPath), entries[0]),
+			err:     "missing manifest, wanted manifest.yaml as the first entry",
+		},
+		{
+			name:    "signature after files",
+			entries: append(removeBundleEntry(entries, bundleSignaturePath), entries[1]),
+			err:     "entry manifest.sig must precede the bundle files",
+		},
+		{
+			name: "invalid version",
+			entries: replaceBundleEntry(entries, bundleManifestPath, func(data []byte) []byte {
+				return bytes.Replace(data, []byte("1.2.0-rc.1"), []byte("1.2"), 1)
+			}),
+			err: `invalid bundle version, wanted a semantic version: "1.2"`,
+		},
+		{
+			name:    "file too large",
+			entries: entries,
+			opts:    []BundleOption{BundleSizeLimits(int64(len(entries[0].data)), 1<<20)},
+			err:     "exceeds the bundle size limits",
+		},
+		{
+			name:    "bundle too large",
+			entries: entries,
+			opts:    []BundleOption{BundleSizeLimits(1<<20, int64(len(entries[0].data)+len(entries[1].data)+1))},
+			err:     "exceeds the bundle size limits",
+		},
+		{
+			name: "unexpected file before read",
+			entries: append([]*bundleEntry{entries[0], entries[1], {path: "extra.bin", data: make([]byte, 4096)}},
+				entries[2:]...),
+			opts: []BundleOption{BundleSizeLimits(int64(len(entries[0].data)), 1<<20)},
+			err:  "unexpected file extra.bin",
+		},
+		{
+			name:    "invalid size limits",
+			entries: entries,
+			opts:    []BundleOption{BundleSizeLimits(0, 1)},
+			err:     "bundle size limits must be positive",
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadBundle(bytes.NewReader(writeBundleEntries(t, tc.entries)), tc.opts...)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("ReadBundle() got error %v, wanted %s", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestWriteBundleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Bundle)
+		opts   []BundleOption
+		err    string
+	}{
+		{
+			name:   "invalid version",
+			modify: func(b *Bundle) { b.Version = "v1.2" },
+			err:    "invalid bundle version",
+		},
+		{
+			name:   "invalid name",
+			modify: func(b *Bundle) { b.Name = "../limits" },
+			err:    "invalid bundle name",
+		},
+		{
+			name:   "no policies",
+			modify: func(b *Bundle) { b.Policies = nil },
+			err:    "at least one policy",
+		},
+		{
+			name:   "invalid key",
+			modify: func(*Bundle) {},
+			opts:   []BundleOption{SignBundle(ed25519.PrivateKey("short"))},
+			err:    "invalid ed25519 private key",
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			b := newTestBundle(t)
+			tc.modify(b)
+			err := WriteBundle(io.Discard, b, tc.opts...)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("WriteBundle() got error %v, wanted %s", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestBundleAddPolicyErrors(t *testing.T) {
+	b := newTestBundle(t)
+	if err := b.AddPolicy("limits", b.Policies[0].Ast); err == nil {
+		t.Error("AddPolicy() with a duplicate name succeeded, wanted error")
+	}
+	if err := b.AddPolicy("policies/limits", b.Policies[0].Ast); err == nil {
+		t.Error("AddPolicy() with a path name succeeded, wanted error")
+	}
+	e, err := cel.NewEnv()
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	parsed, iss := e.Parse("1 + 1")
+	if iss.Err() != nil {
+		t.Fatalf("e.Parse() failed: %v", iss.Err())
+	}
+	if err := b.AddPolicy("parsed", parsed); err == nil {
+		t.Error("AddPolicy() with an unchecked expression succeeded, wanted error")
+	}
+}
+
+func newTestBundle(t testing.TB) *Bundle {
+	t.Helper()
+	policy := parsePolicy(t, "limits", []ParserOption{})
+	_, ast, iss := compile(t, "limits", policy, []cel.EnvOption{}, []CompilerOption{})
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	// The compilation environment enables the optional and bindings libraries in addition to those
+	// named within the policy configuration.
+	config := readPolicyConfig(t, "testdata/limits/config.yaml")
+	config.AddExtensions(
+		env.NewExtension("optional", math.MaxUint32),
+		env.NewExtension("bindings", math.MaxUint32))
+	b := &Bundle{
+		Name:              "limits",
+		Version:           "1.2.0-rc.1",
+		Config:            config,
+		FileDescriptorSet: fileDescriptorSet((&proto3pb.TestAllTypes{}).ProtoReflect().Descriptor().ParentFile()),
+	}
+	if err := b.AddPolicy("limits", ast); err != nil {
+		t.Fatalf("AddPolicy() failed: %v", err)
+	}
+	return b
+}
+
+// fileDescriptorSet collects the file along with its transitive imports.
+func fileDescriptorSet(fd protoreflect.FileDescriptor) *descpb.FileDescriptorSet {
+	fds := &descpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	var collect func(protoreflect.FileDescriptor)
+	collect = func(f protoreflect.FileDescriptor) {
+		if seen[f.Path()] {
+			return
+		}
+		seen[f.Path()] = true
+		imports := f.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			collect(imports.Get(i).FileDescriptor)
+		}
+		fds.File = append(fds.File, protodesc.ToFileDescriptorProto(f))
+	}
+	collect(fd)
+	return fds
+}
+
+func bundleKeys(t testing.TB) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() failed: %v", err)
+	}
+	return pub, priv
+}
+
+func readBundleEntries(t testing.TB, data []byte) []*bundleEntry {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() failed: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	var entries []*bundleEntry
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("tr.Next() failed: %v", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("io.ReadAll() failed: %v", err)
+		}
+		entries = append(entries, &bundleEntry{path: hdr.Name, data: content})
+	}
+}
+
+func writeBundleEntries(t testing.TB, entries []*bundleEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.path, Mode: 0644, Size: int64(len(e.data))}); err != nil {
+			t.Fatalf("tw.WriteHeader() failed: %v", err)
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			t.Fatalf("tw.Write() failed: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tw.Close() failed: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gz.Close() failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func replaceBundleEntry(entries []*bundleEntry, path string, replace func([]byte) []byte) []*bundleEntry {
+	out := make([]*bundleEntry, len(entries))
+	for i, e := range entries {
+		out[i] = e
+		if e.path == path {
+			out[i] = &bundleEntry{path: e.path, data: replace(bytes.Clone(e.data))}
+		}
+	}
+	return out
+}
+
+func removeBundleEntry(entries []*bundleEntry, path string) []*bundleEntry {
+	var out []*bundleEntry
+	for _, e := range entries {
+		if e.path != path {
+			out = append(out, e)
+		}
+	}
+	return out
+}
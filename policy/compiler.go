@@ -17,7 +17,11 @@
 package policy
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 
 	"cel.dev/cel-go/cel"
 	"cel.dev/cel-go/common"
@@ -35,6 +39,20 @@ type CompiledRule struct {
 	variables []*CompiledVariable
 	matches   []*CompiledMatch
 	semantic  SemanticType
+
+	// env is the environment in which the rule's matches were compiled, including the
+	// declarations of the rule variables.
+	env *cel.Env
+
+	// optionalOutput is set on residual rules to preserve the optional output type of the rule
+	// from which they were derived.
+	optionalOutput bool
+
+	// planOnce guards the programs planned for the rule expressions on first use by Explain and
+	// Coverage, see CompiledRule.plan.
+	planOnce sync.Once
+	planned  *rulePlan
+	planErr  error
 }
 
 // SourceID returns the source metadata identifier associated with the compiled rule.
@@ -65,6 +83,10 @@ func (r *CompiledRule) Semantic() SemanticType {
 // OutputType returns the output type of the first match clause as all match clauses
 // are validated for agreement prior to construction fo the CompiledRule.
 func (r *CompiledRule) OutputType() *cel.Type {
+	// Decision semantics combine the bool outputs of the matches into a single bool.
+	if r.semantic.isDecision() {
+		return cel.BoolType
+	}
 	// It's a compilation error if the output types of the matches don't agree
 	matches := r.Matches()
 	if len(matches) > 0 {
@@ -80,9 +102,21 @@ func (r *CompiledRule) OutputType() *cel.Type {
 // HasOptionalOutput returns whether the rule returns a concrete or optional value.
 // The rule may return an optional value if all match expressions under the rule are conditional.
 func (r *CompiledRule) HasOptionalOutput() bool {
-	if r.semantic == aggregate {
+	if r.semantic == aggregate || r.semantic == allMatch {
 		return false
 	}
+	if r.optionalOutput {
+		return true
+	}
+	if r.semantic.isDecision() {
+		// The override semantics produce a decision whenever at least one match produces one.
+		for _, m := range r.Matches() {
+			if m.ConditionIsLiteral(types.True) && (m.NestedRule() == nil || !m.NestedRule().HasOptionalOutput()) {
+				return false
+			}
+		}
+		return true
+	}
 	optionalOutput := false
 	for _, m := range r.Matches() {
 		if m.NestedRule() != nil && m.NestedRule().HasOptionalOutput() {
@@ -136,6 +170,7 @@ type CompiledMatch struct {
 	cond       *cel.Ast
 	output     *OutputValue
 	nestedRule *CompiledRule
+	priority   int64
 }
 
 // SourceID returns the source identifier associated with the compiled match.
@@ -149,6 +184,11 @@ func (m *CompiledMatch) Condition() *cel.Ast {
 	return m.cond
 }
 
+// Priority returns the priority of the match under the priority semantic.
+func (m *CompiledMatch) Priority() int64 {
+	return m.priority
+}
+
 // ConditionIsLiteral indicates whether the condition for the match is a literal with a given value.
 func (m *CompiledMatch) ConditionIsLiteral(val ref.Val) bool {
 	c := m.cond.NativeRep().Expr()
@@ -208,6 +248,22 @@ func MaxNestedExpressions(limit int) CompilerOption {
 	}
 }
 
+// IncludeLoader configures the SourceLoader used to resolve the policy sources referenced by
+// `include` entries, along with the parser options used to parse the included policies.
+//
+// Without a loader, policies which declare includes fail to compile.
+func IncludeLoader(loader SourceLoader, opts ...ParserOption) CompilerOption {
+	return func(c *compiler) error {
+		parser, err := NewParser(opts...)
+		if err != nil {
+			return err
+		}
+		c.loader = loader
+		c.includeParser = parser
+		return nil
+	}
+}
+
 // MatchCompiler is an interface that provides the necessary functionality for compiling the output
 // of a match.
 type MatchCompiler interface {
@@ -260,6 +316,13 @@ func Compile(env *cel.Env, p *Policy, opts ...CompilerOption) (*cel.Ast, *cel.Is
 	if iss.Err() != nil {
 		return nil, iss
 	}
+	// Parameter references are retained within the composed expression, so the composition is
+	// checked against the parameter declarations.
+	env, err := paramsEnv(env, p)
+	if err != nil {
+		iss.ReportErrorAtID(p.Name().ID, "error configuring params: %s", err)
+		return nil, iss
+	}
 	// An error cannot happen when composing without supplying options
 	composer, _ := NewRuleComposer(env)
 	return composer.Compose(rule)
@@ -291,6 +354,18 @@ func CompileRule(env *cel.Env, p *Policy, opts ...CompilerOption) (*CompiledRule
 		return nil, iss
 	}
 
+	included := c.compileIncludes(p, c.env, iss, nil)
+	c.env = c.policyEnv(p, included, iss)
+	schema := c.resolveOutputType(c.env, p.OutputType(), iss)
+	rule, iss := c.compileRule(p.Rule(), p, c.env, iss, false, schema)
+	rule.variables = append(included, rule.variables...)
+	return rule, iss
+}
+
+// policyEnv extends the environment with the type name imports of the policy and the declarations
+// of the policy parameters and any variables included from other policies.
+func (c *compiler) policyEnv(p *Policy, included []*CompiledVariable, iss *cel.Issues) *cel.Env {
+	policyEnv := c.env
 	importCount := len(p.Imports())
 	if importCount > 0 {
 		importNames := make([]string, 0, importCount)
@@ -303,15 +378,32 @@ func CompileRule(env *cel.Env, p *Policy, opts ...CompilerOption) (*CompiledRule
 				importNames = append(importNames, typeName)
 			}
 		}
-		env, err := c.env.Extend(cel.Abbrevs(importNames...))
+		env, err := policyEnv.Extend(cel.Abbrevs(importNames...))
 		if err != nil {
 			// validation happens earlier in the sequence, so this should be unreachable.
 			iss.ReportErrorAtID(p.Imports()[0].SourceID(), "error configuring imports: %s", err)
 		} else {
-			c.env = env
+			policyEnv = env
+		}
+	}
+	if paramDecls := paramVariables(policyEnv, p, iss); len(paramDecls) != 0 {
+		env, err := policyEnv.Extend(paramDecls...)
+		if err != nil {
+			iss.ReportErrorAtID(p.Params()[0].SourceID(), "error configuring params: %s", err)
+		} else {
+			policyEnv = env
 		}
 	}
-	return c.compileRule(p.Rule(), p, c.env, iss, false)
+	for _, v := range included {
+		env, err := policyEnv.Extend(cel.Variable(v.Declaration().Name(), v.Declaration().Type()))
+		if err != nil {
+			iss.ReportErrorAtID(v.SourceID(), "invalid variable declaration: %s", err.Error())
+		} else {
+			policyEnv = env
+			c.declareVariableAST(v.Declaration().Name(), v.Expr())
+		}
+	}
+	return policyEnv
 }
 
 type compiler struct {
@@ -322,12 +414,45 @@ type compiler struct {
 	compileMatchOutput   CompileMatchOutputFunc
 	maxNestedExpressions int
 	nestedCount          int
+
+	loader        SourceLoader
+	includeParser *Parser
+
+	// variableASTs maps the names of the variables in scope to their compiled expressions, which
+	// permits outputs which reference a variable to be checked against the declared output type.
+	variableASTs map[string]*cel.Ast
+	// checkedOutputVars records the variables whose output keys have been checked against a schema.
+	checkedOutputVars map[outputVariableCheck]bool
 }
 
-func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Issues, hasAggregateAncestor bool) (*CompiledRule, *cel.Issues) {
+// declareVariableAST records the compiled expression of a variable in the current scope.
+func (c *compiler) declareVariableAST(name string, a *cel.Ast) {
+	if a == nil {
+		return
+	}
+	if c.variableASTs == nil {
+		c.variableASTs = map[string]*cel.Ast{}
+	}
+	c.variableASTs[name] = a
+}
+
+// compileRule compiles the variables and matches of a rule.
+//
+// The schema is the declared output type of the nearest enclosing rule or policy, if any, and is
+// overridden by the output type declared on the rule itself.
+func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Issues, hasAggregateAncestor bool, schema *outputSchema) (*CompiledRule, *cel.Issues) {
 	if hasAggregateAncestor && r.semantic == aggregate {
 		iss.ReportErrorAtID(r.SourceID(), "nested aggregate rules are not allowed")
 	}
+	if r.OutputType() != nil {
+		schema = c.resolveOutputType(ruleEnv, r.OutputType(), iss)
+	}
+	// Variables are scoped to the rule in which they are declared.
+	outerVariableASTs := c.variableASTs
+	c.variableASTs = maps.Clone(outerVariableASTs)
+	defer func() {
+		c.variableASTs = outerVariableASTs
+	}()
 	compiledVars := make([]*CompiledVariable, len(r.Variables()))
 	for i, v := range r.Variables() {
 		exprSrc := c.relSource(v.Expression())
@@ -352,6 +477,9 @@ func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Is
 			iss.ReportErrorAtID(v.exprID, "invalid variable declaration: %s", err.Error())
 		} else {
 			ruleEnv = varEnv
+			if exprIss.Err() == nil {
+				c.declareVariableAST(varDecl.Name(), varAST)
+			}
 		}
 		compiledVar := &CompiledVariable{
 			exprID:  v.name.ID,
@@ -381,10 +509,19 @@ func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Is
 			iss.ReportErrorAtID(m.Condition().ID, "either output or rule may be set but not both")
 			continue
 		}
+		if r.semantic == priorityMatch && !m.HasPriority() {
+			iss.ReportErrorAtID(m.exprID, "match must set a priority under the priority semantic")
+		}
+		if r.semantic != priorityMatch && m.HasPriority() {
+			iss.ReportErrorAtID(m.Priority().ID, "priority may only be set on matches under the priority semantic")
+		}
 		if m.HasOutput() {
 			mc := &matchCompilerImpl{env: ruleEnv, c: c, iss: iss}
 			outAST, outIss := c.compileMatchOutput(mc, m, p)
 			iss = iss.Append(outIss)
+			if outIss.Err() == nil {
+				iss = c.checkOutputType(schema, m, outAST, iss)
+			}
 			compiledMatches = append(compiledMatches, &CompiledMatch{
 				exprID: m.exprID,
 				cond:   condAST,
@@ -392,17 +529,19 @@ func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Is
 					exprID: m.Output().ID,
 					expr:   outAST,
 				},
+				priority: m.Priority().Value,
 			})
 			continue
 		}
 		if m.HasRule() {
 			nextHasAggregateAncestor := hasAggregateAncestor || r.semantic == aggregate
-			nestedRule, ruleIss := c.compileRule(m.Rule(), p, ruleEnv, iss, nextHasAggregateAncestor)
+			nestedRule, ruleIss := c.compileRule(m.Rule(), p, ruleEnv, iss, nextHasAggregateAncestor, schema)
 			iss = iss.Append(ruleIss)
 			compiledMatches = append(compiledMatches, &CompiledMatch{
 				exprID:     m.exprID,
 				cond:       condAST,
 				nestedRule: nestedRule,
+				priority:   m.Priority().Value,
 			})
 
 			// Increment the nesting count post-compile.
@@ -413,6 +552,14 @@ func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Is
 		}
 	}
 
+	// Matches under the priority semantic are evaluated in order of decreasing priority, with ties
+	// evaluated in declaration order.
+	if r.semantic == priorityMatch {
+		slices.SortStableFunc(compiledMatches, func(a, b *CompiledMatch) int {
+			return cmp.Compare(b.priority, a.priority)
+		})
+	}
+
 	// Validate that all branches in the rule are reachable
 	rule := &CompiledRule{
 		exprID:    r.exprID,
@@ -420,11 +567,14 @@ func (c *compiler) compileRule(r *Rule, p *Policy, ruleEnv *cel.Env, iss *cel.Is
 		variables: compiledVars,
 		matches:   compiledMatches,
 		semantic:  r.semantic,
+		env:       ruleEnv,
 	}
 
 	// Note: Consider supporting configurable policy validators that take the policy, rule, and issues
 	// Validate type agreement between the different match outputs
 	c.checkMatchOutputTypesAgree(rule, iss)
+	// Validate that decision semantics combine bool outputs
+	c.checkDecisionOutputTypes(rule, iss)
 	// Validate that all branches in the policy are reachable
 	c.checkUnreachableCode(rule, iss)
 
@@ -465,6 +615,25 @@ func (c *compiler) checkMatchOutputTypesAgree(rule *CompiledRule, iss *cel.Issue
 	}
 }
 
+func (c *compiler) checkDecisionOutputTypes(rule *CompiledRule, iss *cel.Issues) {
+	if !rule.semantic.isDecision() {
+		return
+	}
+	for _, m := range rule.Matches() {
+		matchOutputType := m.OutputType()
+		if matchOutputType.TypeName() == "error" || cel.BoolType.IsAssignableType(matchOutputType) {
+			continue
+		}
+		sourceID := m.SourceID()
+		if m.Output() != nil {
+			sourceID = m.Output().SourceID()
+		} else if m.NestedRule() != nil {
+			sourceID = m.NestedRule().SourceID()
+		}
+		iss.ReportErrorAtID(sourceID, "%s rules require bool outputs, got output type %s", rule.semantic, matchOutputType)
+	}
+}
+
 func (c *compiler) checkUnreachableCode(rule *CompiledRule, iss *cel.Issues) {
 	compiledMatches := rule.Matches()
 	matchCount := len(compiledMatches)
@@ -479,7 +648,8 @@ func (c *compiler) checkUnreachableCode(rule *CompiledRule, iss *cel.Issues) {
 		// If the match is a single output or a nested rule that always returns a value, it is
 		// exhaustive. If the condition is trivially true, then all subsequent branches are unreachable.
 		isExhaustive := triviallyTrue && (m.NestedRule() == nil || !m.NestedRule().HasOptionalOutput())
-		if rule.semantic == firstMatch && isExhaustive && i != matchCount-1 {
+		firstMatchWins := rule.semantic == firstMatch || rule.semantic == priorityMatch
+		if firstMatchWins && isExhaustive && i != matchCount-1 {
 			if m.Output() != nil {
 				iss.ReportErrorAtID(m.SourceID(), "match creates unreachable outputs")
 			}
@@ -16,18 +16,22 @@ package policy
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
 	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/analysis"
 	"cel.dev/cel-go/common/types"
 	"cel.dev/cel-go/common/types/ref"
 	"cel.dev/cel-go/ext"
 	"cel.dev/cel-go/interpreter"
+	proto3pb "cel.dev/cel-go/test/proto3pb"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -131,6 +135,192 @@ func TestMaxNestedExpressions_Error(t *testing.T) {
 	}
 }
 
+func TestCompileIncludes(t *testing.T) {
+	policy := parsePolicy(t, "include", []ParserOption{})
+	env, ast, iss := compile(t, "include", policy, []cel.EnvOption{},
+		[]CompilerOption{IncludeLoader(NewFileLoader(os.DirFS(".")))})
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	wantExpr := `
+	cel.@block([
+	  string(request.auth.role),
+	  string(request.auth.user),
+	  @index0 == "admin",
+	  @index1 == request.resource.owner,
+	  @index2 || @index3],
+	  @index4 ? "allowed" : ("denied by " + @index1))`
+	got, err := cel.AstToString(ast)
+	if err != nil {
+		t.Fatalf("cel.AstToString() failed: %v", err)
+	}
+	if normalize(got) != normalize(wantExpr) {
+		t.Errorf("cel.AstToString() got %s, wanted %s", got, wantExpr)
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	out, _, err := prg.Eval(map[string]any{
+		"request": map[string]any{
+			"auth":     map[string]any{"role": "dev", "user": "alice"},
+			"resource": map[string]any{"owner": "bob"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if out.Equal(types.String("denied by alice")) != types.True {
+		t.Errorf("prg.Eval() got %v, wanted 'denied by alice'", out)
+	}
+}
+
+func TestCompileIncludeErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lib.yaml": {Data: []byte(`
+name: lib
+rule:
+  variables:
+    - name: ok
+      expression: "true"`)},
+		"broken.yaml": {Data: []byte(`
+name: broken
+rule:
+  variables:
+    - name: bad
+      expression: "1 + 'a'"`)},
+		"params.yaml": {Data: []byte(`
+name: params
+params:
+  - name: limit
+    type: int
+rule:
+  variables:
+    - name: over
+      expression: "request.n > params.limit"`)},
+		"cycle_a.yaml": {Data: []byte(`
+name: cycle_a
+include:
+  - name: b
+    path: cycle_b.yaml`)},
+		"cycle_b.yaml": {Data: []byte(`
+name: cycle_b
+include:
+  - name: a
+    path: cycle_a.yaml`)},
+	}
+	tests := []struct {
+		name   string
+		policy string
+		opts   []CompilerOption
+		err    string
+	}{
+		{
+			name: "no_loader",
+			policy: `
+include:
+  - name: lib
+    path: lib.yaml
+rule:
+  match:
+    - output: variables.lib.ok`,
+			err: "no loader configured for include: lib",
+		},
+		{
+			name: "missing_file",
+			policy: `
+include:
+  - name: lib
+    path: missing.yaml
+rule:
+  match:
+    - output: "true"`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err:  "error loading include lib: open missing.yaml: file does not exist",
+		},
+		{
+			name: "duplicate_name",
+			policy: `
+include:
+  - name: lib
+    path: lib.yaml
+  - name: lib
+    path: lib.yaml
+rule:
+  match:
+    - output: variables.lib.ok`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err:  "duplicate include name: lib",
+		},
+		{
+			name: "invalid_name",
+			policy: `
+include:
+  - name: lib.common
+    path: lib.yaml
+rule:
+  match:
+    - output: "true"`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err:  `invalid include name: "lib.common"`,
+		},
+		{
+			name: "cycle",
+			policy: `
+include:
+  - name: a
+    path: cycle_a.yaml
+rule:
+  match:
+    - output: "true"`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err:  "include cycle: policy.yaml -> cycle_a.yaml -> cycle_b.yaml -> cycle_a.yaml",
+		},
+		{
+			name: "included_error",
+			policy: `
+include:
+  - name: broken
+    path: broken.yaml
+rule:
+  match:
+    - output: variables.broken.bad`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err: `ERROR: policy.yaml:4:11: error compiling include broken:
+ERROR: broken.yaml:6:22: found no matching overload for '_+_' applied to '(int, string)'`,
+		},
+		{
+			name: "included_params",
+			policy: `
+params:
+  - name: limit
+    type: string
+include:
+  - name: lib
+    path: params.yaml
+rule:
+  match:
+    - output: variables.lib.over`,
+			opts: []CompilerOption{IncludeLoader(NewFileLoader(fsys))},
+			err: `ERROR: policy.yaml:7:11: error compiling include lib:
+ERROR: params.yaml:4:5: params are not supported in included policies`,
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			envOpts := []cel.EnvOption{cel.Variable("request", cel.MapType(cel.StringType, cel.DynType))}
+			_, _, iss := parseAndCompilePolicy(t, "policy.yaml", tc.policy, envOpts, tc.opts)
+			if iss.Err() == nil {
+				t.Fatalf("Compile() succeeded, wanted error containing %q", tc.err)
+			}
+			if !strings.Contains(iss.Err().Error(), tc.err) {
+				t.Errorf("Compile() got error %v, wanted error containing %q", iss.Err(), tc.err)
+			}
+		})
+	}
+}
+
 func TestWhitespaceHanlding(t *testing.T) {
 
 	testCases := []struct {
@@ -244,6 +434,124 @@ func BenchmarkCompile(b *testing.B) {
 	}
 }
 
+func TestCompiledPolicySemanticEquivalence(t *testing.T) {
+	original := `
+name: equivalence
+rule:
+  variables:
+    - name: limit
+      expression: "10 * 10"
+  match:
+    - condition: request.admin && request.region in ['us', 'eu']
+      output: "true"
+    - output: request.size < variables.limit
+`
+	refactored := `
+name: equivalence
+rule:
+  variables:
+    - name: limit
+      expression: "100"
+  match:
+    - condition: >
+        request.region in ['eu', 'us'] &&
+        request.admin
+      output: "true"
+    - output: request.size < variables.limit
+`
+	changed := `
+name: equivalence
+rule:
+  variables:
+    - name: limit
+      expression: "100"
+  match:
+    - condition: request.admin || request.region in ['eu', 'us']
+      output: "true"
+    - output: request.size < variables.limit
+`
+	envOpts := []cel.EnvOption{cel.Variable("request", cel.MapType(cel.StringType, cel.DynType))}
+	env, originalAST, iss := parseAndCompilePolicy(t, "equivalence", original, envOpts, nil)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	_, refactoredAST, iss := parseAndCompilePolicy(t, "equivalence", refactored, envOpts, nil)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	_, changedAST, iss := parseAndCompilePolicy(t, "equivalence", changed, envOpts, nil)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	equiv, err := cel.SemanticallyEquivalent(env, originalAST, refactoredAST)
+	if err != nil {
+		t.Fatalf("SemanticallyEquivalent() failed: %v", err)
+	}
+	if !equiv {
+		t.Error("SemanticallyEquivalent(original, refactored) got false, wanted true")
+	}
+	equiv, err = cel.SemanticallyEquivalent(env, originalAST, changedAST)
+	if err != nil {
+		t.Fatalf("SemanticallyEquivalent() failed: %v", err)
+	}
+	if equiv {
+		t.Error("SemanticallyEquivalent(original, changed) got true, wanted false")
+	}
+}
+
+func TestCompiledPolicyVerification(t *testing.T) {
+	oldPolicy := `
+name: verification
+rule:
+  variables:
+    - name: privileged
+      expression: request.admin || request.group == 'ops'
+  match:
+    - condition: variables.privileged && request.method in ['GET', 'DELETE']
+      output: "true"
+    - output: request.method == 'GET'
+`
+	newPolicy := `
+name: verification
+rule:
+  match:
+    - condition: request.admin && request.method == 'DELETE'
+      output: "true"
+    - output: request.method == 'GET' && request.group != 'guests'
+`
+	envOpts := []cel.EnvOption{cel.Variable("request", cel.MapType(cel.StringType, cel.DynType))}
+	_, oldAST, iss := parseAndCompilePolicy(t, "verification", oldPolicy, envOpts, nil)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	_, newAST, iss := parseAndCompilePolicy(t, "verification", newPolicy, envOpts, nil)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	v, err := analysis.NewVerifier(
+		analysis.BoolVariable("request.admin"),
+		analysis.StringVariable("request.group"),
+		analysis.StringValues("request.method", "GET", "POST", "DELETE"),
+	)
+	if err != nil {
+		t.Fatalf("NewVerifier() failed: %v", err)
+	}
+	res, err := v.Implies(newAST.NativeRep(), oldAST.NativeRep())
+	if err != nil {
+		t.Fatalf("Implies() failed: %v", err)
+	}
+	if res.Verdict != analysis.Proven {
+		t.Errorf("Implies(new, old) got %v with counterexample %v, wanted proven", res.Verdict, res.Counterexample)
+	}
+	res, err = v.Implies(oldAST.NativeRep(), newAST.NativeRep())
+	if err != nil {
+		t.Fatalf("Implies() failed: %v", err)
+	}
+	if res.Verdict != analysis.Refuted {
+		t.Errorf("Implies(old, new) got %v, wanted refuted", res.Verdict)
+	}
+}
+
 func parsePolicySource(t testing.TB, name string, policySource string, parseOpts ...ParserOption) *Policy {
 	t.Helper()
 	p := StringSource(policySource, name)
@@ -832,6 +1140,537 @@ rule:
 	}
 }
 
+func TestCompileDecisionSemantics(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		unparsed  string
+		decisions map[string]ref.Val
+	}{
+		{
+			name: "deny_overrides",
+			policy: `
+name: deny_overrides
+rule:
+  deny_overrides:
+    - condition: request.role == 'admin'
+      output: "true"
+    - condition: request.blocked
+      output: "false"`,
+			unparsed: `cel.@block([((request.role == "admin") ? [true] : []) + (request.blocked ? [false] : [])],
+			  (size(@index0) == 0) ? optional.none() : optional.of(!(false in @index0)))`,
+			decisions: map[string]ref.Val{
+				"admin":         types.OptionalOf(types.True),
+				"admin_blocked": types.OptionalOf(types.False),
+				"user":          types.OptionalNone,
+			},
+		},
+		{
+			name: "permit_overrides",
+			policy: `
+name: permit_overrides
+rule:
+  permit_overrides:
+    - condition: request.role == 'admin'
+      output: "true"
+    - output: "false"`,
+			unparsed: `cel.@block([((request.role == "admin") ? [true] : []) + [false]], true in @index0)`,
+			decisions: map[string]ref.Val{
+				"admin":         types.True,
+				"admin_blocked": types.True,
+				"user":          types.False,
+			},
+		},
+		{
+			name: "all",
+			policy: `
+name: all
+rule:
+  all:
+    - condition: request.role == 'admin'
+      output: "true"
+    - condition: "!request.blocked"
+      output: "true"`,
+			unparsed: `cel.@block([((request.role == "admin") ? [true] : []) + (!request.blocked ? [true] : [])],
+			  size(@index0) == 2 && !(false in @index0))`,
+			decisions: map[string]ref.Val{
+				"admin":         types.True,
+				"admin_blocked": types.False,
+				"user":          types.False,
+			},
+		},
+		{
+			name: "priority",
+			policy: `
+name: priority
+rule:
+  priority:
+    - condition: request.role == 'admin'
+      output: "'admin'"
+      priority: 1
+    - condition: request.blocked
+      output: "'blocked'"
+      priority: 2
+    - output: "'default'"
+      priority: 0`,
+			unparsed: `request.blocked ? "blocked" : ((request.role == "admin") ? "admin" : "default")`,
+			decisions: map[string]ref.Val{
+				"admin":         types.String("admin"),
+				"admin_blocked": types.String("blocked"),
+				"user":          types.String("default"),
+			},
+		},
+		{
+			name: "nested_decision",
+			policy: `
+name: nested_decision
+rule:
+  match:
+    - condition: request.role != 'guest'
+      rule:
+        deny_overrides:
+          - condition: request.blocked
+            output: "false"
+          - output: "true"
+    - output: "false"`,
+			unparsed: `cel.@block([(request.blocked ? [false] : []) + [true]],
+			  (request.role != "guest") ? !(false in @index0) : false)`,
+			decisions: map[string]ref.Val{
+				"admin":         types.True,
+				"admin_blocked": types.False,
+				"user":          types.True,
+			},
+		},
+	}
+	inputs := map[string]map[string]any{
+		"admin":         {"request": map[string]any{"role": "admin", "blocked": false}},
+		"admin_blocked": {"request": map[string]any{"role": "admin", "blocked": true}},
+		"user":          {"request": map[string]any{"role": "user", "blocked": false}},
+	}
+	envOpts := []cel.EnvOption{cel.Variable("request", cel.MapType(cel.StringType, cel.DynType))}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			env, ast, iss := parseAndCompilePolicy(t, tc.name, tc.policy, envOpts, []CompilerOption{})
+			if iss.Err() != nil {
+				t.Fatalf("Compile() failed: %v", iss.Err())
+			}
+			unparsed, err := cel.AstToString(ast)
+			if err != nil {
+				t.Fatalf("cel.AstToString() failed: %v", err)
+			}
+			if normalize(unparsed) != normalize(tc.unparsed) {
+				t.Errorf("cel.AstToString() got %s, wanted %s", unparsed, tc.unparsed)
+			}
+			policy := parsePolicySource(t, tc.name, tc.policy)
+			ruleEnv, err := env.Extend(envOpts...)
+			if err != nil {
+				t.Fatalf("env.Extend() failed: %v", err)
+			}
+			rule, iss := CompileRule(ruleEnv, policy)
+			if iss.Err() != nil {
+				t.Fatalf("CompileRule() failed: %v", iss.Err())
+			}
+			prg, err := env.Program(ast)
+			if err != nil {
+				t.Fatalf("env.Program() failed: %v", err)
+			}
+			for input, want := range tc.decisions {
+				out, _, err := prg.Eval(inputs[input])
+				if err != nil {
+					t.Fatalf("prg.Eval(%s) failed: %v", input, err)
+				}
+				if out.Equal(want) != types.True {
+					t.Errorf("prg.Eval(%s) got %v, wanted %v", input, out, want)
+				}
+				d, err := Explain(policy, rule, inputs[input])
+				if err != nil {
+					t.Fatalf("Explain(%s) failed: %v", input, err)
+				}
+				if d.Output.Equal(want) != types.True {
+					t.Errorf("Explain(%s) got %v, wanted %v", input, d.Output, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileDecisionSemanticsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		err    string
+	}{
+		{
+			name: "non_bool_output",
+			policy: `
+rule:
+  deny_overrides:
+    - condition: request.blocked
+      output: "'deny'"`,
+			err: "deny_overrides rules require bool outputs, got output type string",
+		},
+		{
+			name: "missing_priority",
+			policy: `
+rule:
+  priority:
+    - condition: request.blocked
+      output: "'deny'"
+    - output: "'allow'"
+      priority: 1`,
+			err: "match must set a priority under the priority semantic",
+		},
+		{
+			name: "unexpected_priority",
+			policy: `
+rule:
+  match:
+    - output: "'allow'"
+      priority: 1`,
+			err: "priority may only be set on matches under the priority semantic",
+		},
+		{
+			name: "unreachable_priority",
+			policy: `
+rule:
+  priority:
+    - condition: request.blocked
+      output: "'deny'"
+      priority: 1
+    - output: "'allow'"
+      priority: 2`,
+			err: "match creates unreachable outputs",
+		},
+	}
+	envOpts := []cel.EnvOption{cel.Variable("request", cel.MapType(cel.StringType, cel.DynType))}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, iss := parseAndCompilePolicy(t, tc.name, tc.policy, envOpts, []CompilerOption{})
+			if iss.Err() == nil {
+				t.Fatalf("Compile() succeeded, wanted error containing %q", tc.err)
+			}
+			if !strings.Contains(iss.Err().Error(), tc.err) {
+				t.Errorf("Compile() got error %v, wanted error containing %q", iss.Err(), tc.err)
+			}
+		})
+	}
+}
+
+func TestCompileOutputType(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{
+			name: "scalar",
+			policy: `
+output_type: string
+rule:
+  match:
+    - condition: request.blocked
+      output: "'deny'"
+    - output: "'allow'"`,
+		},
+		{
+			name: "type_desc",
+			policy: `
+output_type:
+  type_name: list
+  params:
+    - type_name: string
+rule:
+  match:
+    - output: "['allow']"`,
+		},
+		{
+			name: "proto_message",
+			policy: `
+output_type: google.expr.proto3.test.TestAllTypes
+rule:
+  match:
+    - output: "google.expr.proto3.test.TestAllTypes{single_int64: 1}"`,
+		},
+		{
+			name: "nested_rule",
+			policy: `
+rule:
+  output_type: int
+  match:
+    - condition: request.blocked
+      rule:
+        match:
+          - output: "1"
+    - output: "2"`,
+		},
+		{
+			name: "fields",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+    - name: reason
+      type_name: string
+      optional: true
+    - name: details
+      fields:
+        - name: code
+          type_name: int
+rule:
+  match:
+    - condition: request.blocked
+      output: |
+        {'allowed': false, 'reason': 'blocked', 'details': {'code': 1}}
+    - output: |
+        {'allowed': true, ?'reason': request.?reason, 'details': {'code': 0}}`,
+		},
+		{
+			name: "fields_conditional",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: "request.b ? {'allowed': true} : {'allowed': false}"`,
+		},
+		{
+			name: "fields_variable",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+    - name: details
+      fields:
+        - name: code
+          type_name: int
+rule:
+  variables:
+    - name: details
+      expression: "{'code': 1}"
+    - name: res
+      expression: "{'allowed': true, 'details': variables.details}"
+  match:
+    - condition: request.blocked
+      output: "{'allowed': false, 'details': variables.details}"
+    - output: variables.res`,
+		},
+		{
+			name: "fields_dyn",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: dyn(request)`,
+		},
+	}
+	envOpts := []cel.EnvOption{
+		cel.Types(&proto3pb.TestAllTypes{}),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, iss := parseAndCompilePolicy(t, tc.name, tc.policy, envOpts, []CompilerOption{})
+			if iss.Err() != nil {
+				t.Fatalf("Compile() failed: %v", iss.Err())
+			}
+		})
+	}
+}
+
+func TestCompileOutputTypeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		err    string
+	}{
+		{
+			name: "not_assignable",
+			policy: `
+output_type: string
+rule:
+  match:
+    - condition: request.blocked
+      output: "false"
+    - output: "'allow'"`,
+			err: `ERROR: not_assignable:6:16: output type bool is not assignable to the declared output type string
+ |       output: "false"
+ | ...............^`,
+		},
+		{
+			name: "nested_rule_not_assignable",
+			policy: `
+rule:
+  output_type: int
+  match:
+    - condition: request.blocked
+      rule:
+        match:
+          - output: "'deny'"
+    - output: "2"`,
+			err: `ERROR: nested_rule_not_assignable:8:22: output type string is not assignable to the declared output type int
+ |           - output: "'deny'"
+ | .....................^`,
+		},
+		{
+			name: "unknown_type",
+			policy: `
+output_type: google.expr.proto3.test.Missing
+rule:
+  match:
+    - output: "'allow'"`,
+			err: `ERROR: unknown_type:2:14: invalid output_type: undefined type name: "google.expr.proto3.test.Missing"
+ | output_type: google.expr.proto3.test.Missing
+ | .............^`,
+		},
+		{
+			name: "unknown_key",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+    - name: reason
+      type_name: string
+      optional: true
+rule:
+  match:
+    - output: |
+        {'allowed': false, 'resaon': 'blocked'}`,
+			err: `ERROR: unknown_key:12:28: unknown output key: resaon
+ |         {'allowed': false, 'resaon': 'blocked'}
+ | ...........................^`,
+		},
+		{
+			name: "missing_key",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: |
+        {'reason': 'blocked'}`,
+			err: `ERROR: missing_key:9:9: missing required output key: allowed
+ |         {'reason': 'blocked'}
+ | ........^`,
+		},
+		{
+			name: "nested_key_type",
+			policy: `
+output_type:
+  fields:
+    - name: details
+      fields:
+        - name: code
+          type_name: int
+rule:
+  match:
+    - output: |
+        {'details': {'code': 'one'}}`,
+			err: `ERROR: nested_key_type:11:30: output key code has type string, but the declared type is int
+ |         {'details': {'code': 'one'}}
+ | .............................^`,
+		},
+		{
+			name: "conditional_unknown_key",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: "request.b ? {'alowed': true} : {'allowed': false}"`,
+			err: `ERROR: conditional_unknown_key:8:29: unknown output key: alowed
+ |     - output: "request.b ? {'alowed': true} : {'allowed': false}"
+ | ............................^`,
+		},
+		{
+			name: "variable_unknown_key",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  variables:
+    - name: res
+      expression: "{'allwed': true}"
+  match:
+    - output: variables.res`,
+			err: `ERROR: variable_unknown_key:9:21: unknown output key: allwed
+ |       expression: "{'allwed': true}"
+ | ....................^`,
+		},
+		{
+			name: "non_literal",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: request`,
+			err: `ERROR: non_literal:8:15: output keys of type map(string, dyn) cannot be checked against the declared output_type, use a map literal
+ |     - output: request
+ | ..............^`,
+		},
+		{
+			name: "non_literal_key",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: "{string(request.key): true}"`,
+			err: "output keys must be string literals to be checked against the declared output_type",
+		},
+		{
+			name: "duplicate_field",
+			policy: `
+output_type:
+  fields:
+    - name: allowed
+      type_name: bool
+    - name: allowed
+      type_name: bool
+rule:
+  match:
+    - output: "{'allowed': true}"`,
+			err: "duplicate output_type field: allowed",
+		},
+	}
+	envOpts := []cel.EnvOption{
+		cel.Types(&proto3pb.TestAllTypes{}),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, iss := parseAndCompilePolicy(t, tc.name, tc.policy, envOpts, []CompilerOption{})
+			if iss.Err() == nil {
+				t.Fatalf("Compile() succeeded, wanted error containing %q", tc.err)
+			}
+			if !strings.Contains(iss.Err().Error(), tc.err) {
+				t.Errorf("Compile() got error %v, wanted error containing %q", iss.Err(), tc.err)
+			}
+		})
+	}
+}
+
 func TestCompileYAMLPolicy_ConditionAlwaysFalse(t *testing.T) {
 	policySource := `name: condition_always_false
 rule:
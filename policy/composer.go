@@ -189,7 +189,9 @@ func (opt *ruleComposerImpl) optimizeRule(ctx *cel.OptimizerContext, r *Compiled
 		opt.registerVariable(ctx, v)
 	}
 
-	isAggregate := r.semantic == aggregate
+	// Decision semantics collect the outputs of the matching matches into a list in the same manner
+	// as the aggregate semantic, and then combine the list into a single decision.
+	isAggregate := r.semantic == aggregate || r.semantic.isDecision()
 	returnList := isAggregate || asList
 
 	matches := r.Matches()
@@ -245,6 +247,13 @@ func (opt *ruleComposerImpl) optimizeRule(ctx *cel.OptimizerContext, r *Compiled
 	}
 
 	matchExpr := output.expr()
+	if r.semantic.isDecision() {
+		matchExpr = opt.decide(ctx, r, matchExpr, asList)
+	} else if !returnList && r.HasOptionalOutput() && !output.isOptional() {
+		// Residual rules may always produce a value while preserving the optional output type of
+		// the rule from which they were derived.
+		matchExpr = ctx.NewCall("optional.of", matchExpr)
+	}
 	identVisitor := opt.rewriteVariableName(ctx)
 	ast.PostOrderVisit(matchExpr, identVisitor)
 
@@ -314,6 +323,62 @@ func (opt *ruleComposerImpl) registerVariable(ctx *cel.OptimizerContext, v *Comp
 	opt.nextVarIndex++
 }
 
+// decide combines the list of bool outputs produced by the matches of a rule with a decision
+// semantic into the rule output.
+//
+// The list is declared as a cel.@block variable so that it is only evaluated once:
+//
+// - deny_overrides: `!(false in decisions)`
+// - permit_overrides: `true in decisions`
+// - all: `size(decisions) == <match count> && !(false in decisions)`
+//
+// The override semantics produce no value when no match applies, unless a match is unconditional.
+func (opt *ruleComposerImpl) decide(ctx *cel.OptimizerContext, r *CompiledRule, decisions ast.Expr, asList bool) ast.Expr {
+	ast.PostOrderVisit(decisions, opt.rewriteVariableName(ctx))
+	indexVar := fmt.Sprintf("@index%d", opt.nextVarIndex)
+	opt.varIndices = append(opt.varIndices, varIndex{
+		index:    opt.nextVarIndex,
+		indexVar: indexVar,
+		expr:     decisions,
+		celType:  types.NewListType(types.BoolType),
+	})
+	opt.nextVarIndex++
+
+	var decision ast.Expr
+	switch r.semantic {
+	case denyOverrides:
+		decision = ctx.NewCall(operators.LogicalNot,
+			ctx.NewCall(operators.In, ctx.NewLiteral(types.False), ctx.NewIdent(indexVar)))
+	case permitOverrides:
+		decision = ctx.NewCall(operators.In, ctx.NewLiteral(types.True), ctx.NewIdent(indexVar))
+	default:
+		decision = ctx.NewCall(operators.LogicalAnd,
+			ctx.NewCall(operators.Equals,
+				ctx.NewCall("size", ctx.NewIdent(indexVar)),
+				ctx.NewLiteral(types.Int(len(r.Matches())))),
+			ctx.NewCall(operators.LogicalNot,
+				ctx.NewCall(operators.In, ctx.NewLiteral(types.False), ctx.NewIdent(indexVar))))
+	}
+	var none, some ast.Expr
+	if asList {
+		none = ctx.NewList([]ast.Expr{}, []int32{})
+		some = ctx.NewList([]ast.Expr{decision}, []int32{})
+	} else {
+		none = ctx.NewCall("optional.none")
+		some = ctx.NewCall("optional.of", decision)
+	}
+	if !r.HasOptionalOutput() {
+		if asList {
+			return some
+		}
+		return decision
+	}
+	isEmpty := ctx.NewCall(operators.Equals,
+		ctx.NewCall("size", ctx.NewIdent(indexVar)),
+		ctx.NewLiteral(types.Int(0)))
+	return ctx.NewCall(operators.Conditional, isEmpty, none, some)
+}
+
 type ruleUnnesterImpl struct {
 	nextVarIndex     int
 	varIndices       []varIndex
@@ -0,0 +1,192 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"errors"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/interpreter"
+)
+
+// Coverage accumulates the matches, conditions, and variables of a compiled rule which are
+// exercised across a series of evaluations.
+//
+// Evaluation follows the semantics of the composed policy expression, so conditions which follow
+// a matching output under the first-match semantic are not observed, and variables are only
+// observed when they are read.
+type Coverage struct {
+	policy    *Policy
+	rule      *CompiledRule
+	matches   []*MatchCoverage
+	variables []*VariableCoverage
+
+	matchIndex    map[*CompiledMatch]*MatchCoverage
+	variableIndex map[*CompiledVariable]*VariableCoverage
+}
+
+// MatchCoverage records the outcomes observed for the condition of a match.
+type MatchCoverage struct {
+	// SourceID is the source metadata identifier of the match within the policy.
+	SourceID int64
+
+	// RuleID is the id of the innermost rule containing the match, if set.
+	RuleID string
+
+	// Location is the line and column of the match within the policy source.
+	Location common.Location
+
+	// Conditional indicates whether the match has a condition other than the literal `true`.
+	Conditional bool
+
+	// TrueCount is the number of evaluations in which the condition held and the match fired.
+	TrueCount int64
+
+	// FalseCount is the number of evaluations in which the condition did not hold.
+	FalseCount int64
+}
+
+// Fired indicates whether the match produced an output, or entered its nested rule, during any
+// of the recorded evaluations.
+func (m *MatchCoverage) Fired() bool {
+	return m.TrueCount > 0
+}
+
+// VariableCoverage records the number of times a variable was evaluated.
+type VariableCoverage struct {
+	// SourceID is the source metadata identifier of the variable within the policy.
+	SourceID int64
+
+	// Name is the name of the variable, e.g. `variables.is_admin`.
+	Name string
+
+	// Location is the line and column of the variable within the policy source.
+	Location common.Location
+
+	// Evaluations is the number of evaluations in which the variable was read.
+	Evaluations int64
+}
+
+// NewCoverage creates a coverage accumulator for a rule compiled from the given policy.
+func NewCoverage(p *Policy, rule *CompiledRule) (*Coverage, error) {
+	if rule == nil || rule.env == nil {
+		return nil, errors.New("coverage requires a rule produced by CompileRule")
+	}
+	if p == nil {
+		return nil, errors.New("coverage requires the policy from which the rule was compiled")
+	}
+	c := &Coverage{
+		policy:        p,
+		rule:          rule,
+		matchIndex:    map[*CompiledMatch]*MatchCoverage{},
+		variableIndex: map[*CompiledVariable]*VariableCoverage{},
+	}
+	c.index(rule)
+	return c, nil
+}
+
+// Record evaluates the rule against the input activation and accumulates the matches, conditions,
+// and variables which were exercised.
+//
+// Observations made prior to an evaluation error are retained, and the error is returned.
+func (c *Coverage) Record(vars any) error {
+	act, err := interpreter.NewActivation(vars)
+	if err != nil {
+		return err
+	}
+	ex := &explainer{policy: c.policy, cov: c}
+	_, err = ex.evalRule(c.rule, act, nil)
+	return err
+}
+
+// Matches returns the coverage of each match in the rule and its nested rules, in evaluation order.
+func (c *Coverage) Matches() []*MatchCoverage {
+	return c.matches
+}
+
+// Variables returns the coverage of each variable in the rule and its nested rules, in declaration
+// order.
+func (c *Coverage) Variables() []*VariableCoverage {
+	return c.variables
+}
+
+// Source returns the source of the policy from which the rule was compiled.
+func (c *Coverage) Source() *Source {
+	return c.policy.Source()
+}
+
+func (c *Coverage) index(rule *CompiledRule) {
+	ruleID := ""
+	if rule.ID() != nil {
+		ruleID = rule.ID().Value
+	}
+	for _, v := range rule.Variables() {
+		vc := &VariableCoverage{
+			SourceID: v.SourceID(),
+			Name:     v.Declaration().Name(),
+			Location: c.location(v.SourceID()),
+		}
+		c.variables = append(c.variables, vc)
+		c.variableIndex[v] = vc
+	}
+	for _, m := range rule.Matches() {
+		mc := &MatchCoverage{
+			SourceID:    m.SourceID(),
+			RuleID:      ruleID,
+			Location:    c.location(m.SourceID()),
+			Conditional: !m.ConditionIsLiteral(types.True),
+		}
+		c.matches = append(c.matches, mc)
+		c.matchIndex[m] = mc
+		if m.NestedRule() != nil {
+			c.index(m.NestedRule())
+		}
+	}
+}
+
+func (c *Coverage) location(id int64) common.Location {
+	if offset, found := c.policy.SourceInfo().GetOffsetRange(id); found {
+		if loc, found := c.policy.Source().OffsetLocation(offset.Start); found {
+			return loc
+		}
+	}
+	return common.NoLocation
+}
+
+func (c *Coverage) observeCondition(m *CompiledMatch, cond ref.Val) {
+	if c == nil {
+		return
+	}
+	mc, found := c.matchIndex[m]
+	if !found {
+		return
+	}
+	if cond == types.True {
+		mc.TrueCount++
+	} else {
+		mc.FalseCount++
+	}
+}
+
+func (c *Coverage) observeVariable(v *CompiledVariable) {
+	if c == nil {
+		return
+	}
+	if vc, found := c.variableIndex[v]; found {
+		vc.Evaluations++
+	}
+}
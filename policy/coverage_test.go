@@ -0,0 +1,97 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	_, policy, rule := compileExplainPolicy(t, explainPolicy)
+	cov, err := NewCoverage(policy, rule)
+	if err != nil {
+		t.Fatalf("NewCoverage() failed: %v", err)
+	}
+	inputs := []map[string]any{
+		{"request": map[string]any{"role": "admin"}, "resource": map[string]any{}},
+		{"request": map[string]any{"role": "dev", "size": 20}, "resource": map[string]any{"env": "prod"}},
+		{"request": map[string]any{"role": "dev", "size": 5}, "resource": map[string]any{"env": "prod"}},
+	}
+	for _, in := range inputs {
+		if err := cov.Record(in); err != nil {
+			t.Fatalf("Record(%v) failed: %v", in, err)
+		}
+	}
+	type matchCov struct {
+		line        int
+		conditional bool
+		t, f        int64
+	}
+	gotMatches := []matchCov{}
+	for _, m := range cov.Matches() {
+		gotMatches = append(gotMatches, matchCov{m.Location.Line(), m.Conditional, m.TrueCount, m.FalseCount})
+	}
+	wantMatches := []matchCov{
+		{line: 11, conditional: true, t: 1, f: 2},
+		{line: 13, conditional: true, t: 2, f: 0},
+		{line: 20, conditional: true, t: 1, f: 1},
+		{line: 22, conditional: false, t: 0, f: 0},
+	}
+	if !reflect.DeepEqual(gotMatches, wantMatches) {
+		t.Errorf("Matches() got %v, wanted %v", gotMatches, wantMatches)
+	}
+	if cov.Matches()[3].Fired() {
+		t.Error("Matches()[3].Fired() got true, wanted the default match to be unexercised")
+	}
+	gotVars := map[string]int64{}
+	for _, v := range cov.Variables() {
+		gotVars[v.Name] = v.Evaluations
+	}
+	wantVars := map[string]int64{
+		"variables.is_admin": 3,
+		"variables.is_prod":  2,
+		"variables.limit":    2,
+	}
+	if !reflect.DeepEqual(gotVars, wantVars) {
+		t.Errorf("Variables() got %v, wanted %v", gotVars, wantVars)
+	}
+}
+
+func TestCoverageErrors(t *testing.T) {
+	_, policy, rule := compileExplainPolicy(t, explainPolicy)
+	cov, err := NewCoverage(policy, rule)
+	if err != nil {
+		t.Fatalf("NewCoverage() failed: %v", err)
+	}
+	err = cov.Record(map[string]any{
+		"request":  map[string]any{"role": "dev"},
+		"resource": map[string]any{"env": "prod"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no such key: size") {
+		t.Errorf("Record() got error %v, wanted evaluation error", err)
+	}
+	// Observations made prior to the error are retained.
+	if cov.Matches()[1].TrueCount != 1 {
+		t.Errorf("Record() got nested rule match count %d, wanted 1", cov.Matches()[1].TrueCount)
+	}
+	if _, err := NewCoverage(policy, &CompiledRule{}); err == nil {
+		t.Error("NewCoverage() with uncompiled rule succeeded, wanted error")
+	}
+	if _, err := NewCoverage(nil, rule); err == nil {
+		t.Error("NewCoverage() without a policy succeeded, wanted error")
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/interpreter"
+)
+
+// Decision records the output of a compiled rule along with the matches which produced it.
+type Decision struct {
+	// Output is the value produced by the rule, which is the same value produced by evaluating the
+	// composed policy expression.
+	Output ref.Val
+
+	// Matches contains the output matches which produced the rule output. Under the first-match
+	// and priority semantics there is at most one entry, and under the aggregate semantic there is
+	// one entry for each element of the output list, in order. Under the override semantics the
+	// entries are the matches whose outputs agree with the decision, and under the all semantic
+	// the entries are the matches whose conditions held.
+	Matches []*MatchDecision
+}
+
+// MatchDecision describes an output match which contributed to the output of a rule.
+type MatchDecision struct {
+	// SourceID is the source metadata identifier of the match within the policy.
+	SourceID int64
+
+	// RuleID is the id of the innermost rule containing the match, if set.
+	RuleID string
+
+	// Location is the line and column of the match within the policy source.
+	Location common.Location
+
+	// Span is the range of offsets within the policy source from the start of the match through
+	// the end of its output expression.
+	Span ast.OffsetRange
+
+	// Output is the value of the match output expression.
+	Output ref.Val
+
+	// Variables contains the values of the policy variables read while evaluating the match
+	// output and the conditions which led to it, keyed by variable name, e.g. `variables.is_admin`.
+	Variables map[string]ref.Val
+}
+
+// VariableNames returns the names of the variables recorded in the match decision in sorted order.
+func (d *MatchDecision) VariableNames() []string {
+	names := make([]string, 0, len(d.Variables))
+	for name := range d.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Explain evaluates a compiled rule against the input activation and returns a decision record
+// identifying the matches which produced the output.
+//
+// The policy must be the one from which the rule was compiled, and is used to map the matches to
+// their positions within the policy source. Variables are evaluated lazily, as they are in the
+// composed expression, so only the variables which were read are recorded. An error is returned if
+// a condition or output of the rule evaluates to an error.
+//
+// The programs for the rule expressions are planned on the first explanation and retained by the
+// rule, so repeated explanations of the same rule only incur the cost of evaluation.
+func Explain(p *Policy, rule *CompiledRule, vars any) (*Decision, error) {
+	if rule == nil || rule.env == nil {
+		return nil, errors.New("explanation requires a rule produced by CompileRule")
+	}
+	if p == nil {
+		return nil, errors.New("explanation requires the policy from which the rule was compiled")
+	}
+	act, err := interpreter.NewActivation(vars)
+	if err != nil {
+		return nil, err
+	}
+	ex := &explainer{policy: p}
+	res, err := ex.evalRule(rule, act, nil)
+	if err != nil {
+		return nil, err
+	}
+	d := &Decision{}
+	for _, matches := range res.matches {
+		d.Matches = append(d.Matches, matches...)
+	}
+	switch {
+	case rule.semantic == aggregate:
+		d.Output = types.NewRefValList(types.DefaultTypeAdapter, res.outputs)
+	case !rule.HasOptionalOutput():
+		if len(res.outputs) == 0 {
+			return nil, errors.New("rule produced no output")
+		}
+		d.Output = res.outputs[0]
+	case len(res.outputs) == 0:
+		d.Output = types.OptionalNone
+	default:
+		d.Output = types.OptionalOf(res.outputs[0])
+	}
+	return d, nil
+}
+
+type explainer struct {
+	policy *Policy
+	// cov, if set, records the conditions and variables observed during evaluation.
+	cov *Coverage
+}
+
+// ruleResult contains the outputs which make up the rule output, along with the matches which
+// produced each output.
+type ruleResult struct {
+	outputs []ref.Val
+	matches [][]*MatchDecision
+}
+
+func (res *ruleResult) add(out ref.Val, matches ...*MatchDecision) {
+	res.outputs = append(res.outputs, out)
+	res.matches = append(res.matches, matches)
+}
+
+// rulePlan contains the programs planned for the expressions of a rule, indexed in the same order
+// as the rule matches and variables. Matches with a nested rule have no output program.
+type rulePlan struct {
+	conditions []cel.Program
+	outputs    []cel.Program
+	variables  []cel.Program
+}
+
+// plan returns the programs for the expressions of the rule, planning them on first use.
+//
+// The condition and output programs track evaluation state so that the values of the variables
+// read by each match are taken from the evaluation which produced the match result.
+func (r *CompiledRule) plan() (*rulePlan, error) {
+	r.planOnce.Do(func() {
+		r.planned, r.planErr = newRulePlan(r)
+	})
+	return r.planned, r.planErr
+}
+
+func newRulePlan(r *CompiledRule) (*rulePlan, error) {
+	plan := &rulePlan{
+		conditions: make([]cel.Program, len(r.matches)),
+		outputs:    make([]cel.Program, len(r.matches)),
+		variables:  make([]cel.Program, len(r.variables)),
+	}
+	var err error
+	for i, v := range r.variables {
+		if plan.variables[i], err = r.env.Program(v.Expr()); err != nil {
+			return nil, err
+		}
+	}
+	tracked := cel.EvalOptions(cel.OptTrackState)
+	for i, m := range r.matches {
+		if plan.conditions[i], err = r.env.Program(m.Condition(), tracked); err != nil {
+			return nil, err
+		}
+		if m.Output() == nil {
+			continue
+		}
+		if plan.outputs[i], err = r.env.Program(m.Output().Expr(), tracked); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// evalRule evaluates the matches of a rule following the semantics of the rule composer.
+//
+// The trail contains the variables read by the conditions of the enclosing matches.
+func (ex *explainer) evalRule(rule *CompiledRule, vars interpreter.Activation, trail map[string]ref.Val) (*ruleResult, error) {
+	plan, err := rule.plan()
+	if err != nil {
+		return nil, err
+	}
+	act, err := lazyRuleActivation(rule, plan, vars, ex.cov.observeVariable)
+	if err != nil {
+		return nil, err
+	}
+	collect := rule.semantic == aggregate || rule.semantic.isDecision()
+	res := &ruleResult{}
+	for i, m := range rule.Matches() {
+		read := make(map[string]ref.Val, len(trail))
+		for k, v := range trail {
+			read[k] = v
+		}
+		cond, err := ex.eval(plan.conditions[i], m.Condition(), act, read)
+		if err != nil {
+			return nil, ex.matchError(m, err)
+		}
+		ex.cov.observeCondition(m, cond)
+		if cond != types.True {
+			continue
+		}
+		if m.Output() != nil {
+			out, err := ex.eval(plan.outputs[i], m.Output().Expr(), act, read)
+			if err != nil {
+				return nil, ex.matchError(m, err)
+			}
+			res.add(out, ex.matchDecision(rule, m, out, read))
+			if !collect {
+				return res, nil
+			}
+			continue
+		}
+		nested, err := ex.evalRule(m.NestedRule(), act, read)
+		if err != nil {
+			return nil, err
+		}
+		for i, out := range nested.outputs {
+			res.add(out, nested.matches[i]...)
+		}
+		if collect {
+			continue
+		}
+		// A nested rule which produces no value falls through to the next match only if its
+		// condition is unconditionally true; otherwise the rule produces no value.
+		if len(nested.outputs) != 0 || !m.ConditionIsLiteral(types.True) {
+			return res, nil
+		}
+	}
+	if rule.semantic.isDecision() {
+		return combineDecisions(rule, res), nil
+	}
+	return res, nil
+}
+
+// combineDecisions combines the bool outputs of a rule with a decision semantic into a single output, and
+// retains the matches which determined it.
+func combineDecisions(rule *CompiledRule, res *ruleResult) *ruleResult {
+	var permits, denies []*MatchDecision
+	for i, out := range res.outputs {
+		if out == types.False {
+			denies = append(denies, res.matches[i]...)
+		} else {
+			permits = append(permits, res.matches[i]...)
+		}
+	}
+	decision := &ruleResult{}
+	switch rule.semantic {
+	case denyOverrides:
+		if len(denies) != 0 {
+			decision.add(types.False, denies...)
+		} else if len(permits) != 0 {
+			decision.add(types.True, permits...)
+		}
+	case permitOverrides:
+		if len(permits) != 0 {
+			decision.add(types.True, permits...)
+		} else if len(denies) != 0 {
+			decision.add(types.False, denies...)
+		}
+	default:
+		holds := len(res.outputs) == len(rule.Matches()) && len(denies) == 0
+		var matches []*MatchDecision
+		for _, m := range res.matches {
+			matches = append(matches, m...)
+		}
+		decision.add(types.Bool(holds), matches...)
+	}
+	return decision
+}
+
+// eval evaluates the planned program for an expression and returns the value of the expression
+// root along with the values of the policy variables read by it, as recorded in the evaluation
+// state.
+func (ex *explainer) eval(prg cel.Program, a *cel.Ast, vars interpreter.Activation, read map[string]ref.Val) (ref.Val, error) {
+	out, det, err := prg.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	state := det.State()
+	root := a.NativeRep().Expr()
+	if val, found := state.Value(root.ID()); found {
+		out = val
+	}
+	ast.PostOrderVisit(root, ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.IdentKind || !strings.HasPrefix(e.AsIdent(), variablePrefix+".") {
+			return
+		}
+		if val, found := state.Value(e.ID()); found {
+			read[e.AsIdent()] = val
+		}
+	}))
+	return out, nil
+}
+
+func (ex *explainer) matchDecision(rule *CompiledRule, m *CompiledMatch, out ref.Val, read map[string]ref.Val) *MatchDecision {
+	d := &MatchDecision{
+		SourceID:  m.SourceID(),
+		Location:  common.NoLocation,
+		Span:      ast.OffsetRange{Start: -1, Stop: -1},
+		Output:    out,
+		Variables: read,
+	}
+	if rule.ID() != nil {
+		d.RuleID = rule.ID().Value
+	}
+	info := ex.policy.SourceInfo()
+	start, found := info.GetOffsetRange(m.SourceID())
+	if !found {
+		return d
+	}
+	d.Span = ast.OffsetRange{Start: start.Start, Stop: start.Stop}
+	if loc, found := ex.policy.Source().OffsetLocation(start.Start); found {
+		d.Location = loc
+	}
+	if end, found := info.GetOffsetRange(m.Output().SourceID()); found {
+		d.Span.Stop = end.Start + int32(len(m.Output().Expr().Source().Content()))
+	}
+	return d
+}
+
+func (ex *explainer) matchError(m *CompiledMatch, err error) error {
+	info := ex.policy.SourceInfo()
+	if offset, found := info.GetOffsetRange(m.SourceID()); found {
+		if loc, found := ex.policy.Source().OffsetLocation(offset.Start); found {
+			return fmt.Errorf("%s:%d:%d: %w", ex.policy.Source().Description(), loc.Line(), loc.Column()+1, err)
+		}
+	}
+	return err
+}
+
+// lazyRuleActivation layers the rule variables over the input activation. Each variable is
+// evaluated with its planned program on first use, at which point the optional observer is
+// notified.
+func lazyRuleActivation(rule *CompiledRule, plan *rulePlan, vars interpreter.Activation, observe func(*CompiledVariable)) (interpreter.Activation, error) {
+	var act interpreter.Activation
+	bindings := make(map[string]any, len(rule.Variables()))
+	for i, v := range rule.Variables() {
+		v, prg := v, plan.variables[i]
+		var val ref.Val
+		bindings[v.Declaration().Name()] = func() ref.Val {
+			if val != nil {
+				return val
+			}
+			if observe != nil {
+				observe(v)
+			}
+			out, _, err := prg.Eval(act)
+			if out == nil {
+				out = types.WrapErr(err)
+			}
+			val = out
+			return val
+		}
+	}
+	local, err := interpreter.NewActivation(bindings)
+	if err != nil {
+		return nil, err
+	}
+	act = interpreter.NewHierarchicalActivation(vars, local)
+	return act, nil
+}
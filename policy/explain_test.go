@@ -0,0 +1,238 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/ext"
+)
+
+const explainPolicy = `
+name: explain
+rule:
+  id: root
+  variables:
+    - name: is_admin
+      expression: request.role == 'admin'
+    - name: is_prod
+      expression: resource.env == 'prod'
+  match:
+    - condition: variables.is_admin
+      output: "'allow'"
+    - condition: variables.is_prod
+      rule:
+        id: prod
+        variables:
+          - name: limit
+            expression: "10"
+        match:
+          - condition: request.size > variables.limit
+            output: "'deny: too large'"
+    - output: "'allow-default'"
+`
+
+const explainAggregatePolicy = `
+name: explain_aggregate
+rule:
+  variables:
+    - name: is_prod
+      expression: resource.env == 'prod'
+  aggregate:
+    - condition: variables.is_prod
+      output: "'prod'"
+    - condition: request.size > 10
+      output: "'large'"
+    - condition: request.role == 'admin'
+      output: "'admin'"
+`
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		in        map[string]any
+		wantLines []int
+		wantRules []string
+		wantVars  [][]string
+	}{
+		{
+			name:      "first_match",
+			policy:    explainPolicy,
+			in:        map[string]any{"request": map[string]any{"role": "admin"}, "resource": map[string]any{}},
+			wantLines: []int{11},
+			wantRules: []string{"root"},
+			wantVars:  [][]string{{"variables.is_admin"}},
+		},
+		{
+			name:      "nested",
+			policy:    explainPolicy,
+			in:        map[string]any{"request": map[string]any{"role": "dev", "size": 20}, "resource": map[string]any{"env": "prod"}},
+			wantLines: []int{20},
+			wantRules: []string{"prod"},
+			wantVars:  [][]string{{"variables.is_prod", "variables.limit"}},
+		},
+		{
+			name:      "nested_no_output",
+			policy:    explainPolicy,
+			in:        map[string]any{"request": map[string]any{"role": "dev", "size": 5}, "resource": map[string]any{"env": "prod"}},
+			wantLines: []int{},
+			wantRules: []string{},
+			wantVars:  [][]string{},
+		},
+		{
+			name:      "default",
+			policy:    explainPolicy,
+			in:        map[string]any{"request": map[string]any{"role": "dev"}, "resource": map[string]any{"env": "dev"}},
+			wantLines: []int{22},
+			wantRules: []string{"root"},
+			wantVars:  [][]string{{}},
+		},
+		{
+			name:      "aggregate",
+			policy:    explainAggregatePolicy,
+			in:        map[string]any{"request": map[string]any{"role": "admin", "size": 5}, "resource": map[string]any{"env": "prod"}},
+			wantLines: []int{8, 12},
+			wantRules: []string{"", ""},
+			wantVars:  [][]string{{"variables.is_prod"}, {}},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			env, policy, rule := compileExplainPolicy(t, tc.policy)
+			d, err := Explain(policy, rule, tc.in)
+			if err != nil {
+				t.Fatalf("Explain() failed: %v", err)
+			}
+			composer, err := NewRuleComposer(env)
+			if err != nil {
+				t.Fatalf("NewRuleComposer() failed: %v", err)
+			}
+			a, iss := composer.Compose(rule)
+			if iss.Err() != nil {
+				t.Fatalf("Compose() failed: %v", iss.Err())
+			}
+			want := evalResidualTest(t, env, a, tc.in)
+			if d.Output.Equal(want) != types.True {
+				t.Errorf("Explain() got output %v, wanted %v", d.Output, want)
+			}
+			lines := []int{}
+			rules := []string{}
+			vars := [][]string{}
+			for _, m := range d.Matches {
+				lines = append(lines, m.Location.Line())
+				rules = append(rules, m.RuleID)
+				vars = append(vars, m.VariableNames())
+				if m.Span.Start < 0 || m.Span.Stop <= m.Span.Start {
+					t.Errorf("Explain() got span %v, wanted a non-empty span", m.Span)
+				}
+			}
+			if !reflect.DeepEqual(lines, tc.wantLines) {
+				t.Errorf("Explain() got match lines %v, wanted %v", lines, tc.wantLines)
+			}
+			if !reflect.DeepEqual(rules, tc.wantRules) {
+				t.Errorf("Explain() got rule ids %v, wanted %v", rules, tc.wantRules)
+			}
+			if !reflect.DeepEqual(vars, tc.wantVars) {
+				t.Errorf("Explain() got variables %v, wanted %v", vars, tc.wantVars)
+			}
+		})
+	}
+}
+
+func TestExplainSpan(t *testing.T) {
+	_, policy, rule := compileExplainPolicy(t, explainPolicy)
+	d, err := Explain(policy, rule, map[string]any{
+		"request":  map[string]any{"role": "admin"},
+		"resource": map[string]any{},
+	})
+	if err != nil {
+		t.Fatalf("Explain() failed: %v", err)
+	}
+	span := d.Matches[0].Span
+	got := policy.Source().Content()[span.Start:span.Stop]
+	want := "condition: variables.is_admin\n      output: \"'allow'"
+	if got != want {
+		t.Errorf("Explain() got span text %q, wanted %q", got, want)
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	_, policy, rule := compileExplainPolicy(t, explainPolicy)
+	_, err := Explain(policy, rule, map[string]any{
+		"request":  map[string]any{"role": "dev"},
+		"resource": map[string]any{"env": "prod"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "explain:20:13: no such key: size") {
+		t.Errorf("Explain() got error %v, wanted error at the failing match", err)
+	}
+	if _, err := Explain(policy, &CompiledRule{}, map[string]any{}); err == nil {
+		t.Error("Explain() with uncompiled rule succeeded, wanted error")
+	}
+	if _, err := Explain(nil, rule, map[string]any{}); err == nil {
+		t.Error("Explain() without a policy succeeded, wanted error")
+	}
+}
+
+func TestExplainReusesPlan(t *testing.T) {
+	_, policy, rule := compileExplainPolicy(t, explainPolicy)
+	in := map[string]any{
+		"request":  map[string]any{"role": "dev", "size": 20},
+		"resource": map[string]any{"env": "prod"},
+	}
+	if _, err := Explain(policy, rule, in); err != nil {
+		t.Fatalf("Explain() failed: %v", err)
+	}
+	plan, nested := rule.planned, rule.Matches()[1].NestedRule().planned
+	if plan == nil || nested == nil {
+		t.Fatal("Explain() did not retain the planned programs")
+	}
+	for i := 0; i < 2; i++ {
+		d, err := Explain(policy, rule, in)
+		if err != nil {
+			t.Fatalf("Explain() failed: %v", err)
+		}
+		if len(d.Matches) != 1 || d.Matches[0].Output != types.String("deny: too large") {
+			t.Errorf("Explain() got matches %v, wanted the 'deny: too large' match", d.Matches)
+		}
+	}
+	if rule.planned != plan || rule.Matches()[1].NestedRule().planned != nested {
+		t.Error("Explain() planned the rule programs again, wanted the retained programs")
+	}
+}
+
+func compileExplainPolicy(t *testing.T, src string) (*cel.Env, *Policy, *CompiledRule) {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	policy := parsePolicySource(t, "explain", src)
+	rule, iss := CompileRule(env, policy)
+	if iss.Err() != nil {
+		t.Fatalf("CompileRule() failed: %v", iss.Err())
+	}
+	return env, policy, rule
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/decls"
+)
+
+// compileIncludes resolves, parses, and compiles the policies included by the given policy, and
+// returns their top-level rule variables qualified by the include names.
+//
+// The stack contains the descriptions of the sources being included, and is used to detect cycles.
+// Errors within an included policy are reported at the include entry with the diagnostics of the
+// included source embedded in the message, so positions are reported relative to the file in which
+// they occur.
+func (c *compiler) compileIncludes(p *Policy, env *cel.Env, iss *cel.Issues, stack []string) []*CompiledVariable {
+	if len(p.Includes()) == 0 {
+		return nil
+	}
+	if len(stack) == 0 {
+		stack = []string{p.Source().Description()}
+	}
+	var included []*CompiledVariable
+	names := map[string]bool{}
+	for _, inc := range p.Includes() {
+		ns := inc.Name().Value
+		if !isIncludeName(ns) {
+			iss.ReportErrorAtID(inc.Name().ID, "invalid include name: %q", ns)
+			continue
+		}
+		if names[ns] {
+			iss.ReportErrorAtID(inc.Name().ID, "duplicate include name: %s", ns)
+			continue
+		}
+		names[ns] = true
+		vars, found := c.compileInclude(p, inc, env, iss, stack)
+		if !found {
+			continue
+		}
+		for _, v := range vars {
+			included = append(included, qualifyVariable(inc, v))
+		}
+	}
+	return included
+}
+
+func (c *compiler) compileInclude(p *Policy, inc *Include, env *cel.Env, iss *cel.Issues, stack []string) ([]*CompiledVariable, bool) {
+	ns := inc.Name().Value
+	if c.loader == nil {
+		iss.ReportErrorAtID(inc.SourceID(), "no loader configured for include: %s", ns)
+		return nil, false
+	}
+	src, err := c.loader.Load(inc.Path().Value, p.Source())
+	if err != nil {
+		iss.ReportErrorAtID(inc.Path().ID, "error loading include %s: %s", ns, err)
+		return nil, false
+	}
+	if slices.Contains(stack, src.Description()) {
+		cycle := append(stack[:len(stack):len(stack)], src.Description())
+		iss.ReportErrorAtID(inc.Path().ID, "include cycle: %s", strings.Join(cycle, " -> "))
+		return nil, false
+	}
+	lib, libIss := c.includeParser.Parse(src)
+	if libIss.Err() != nil {
+		iss.ReportErrorAtID(inc.Path().ID, "error parsing include %s:\n%s", ns, libIss)
+		return nil, false
+	}
+	vars, libIss := c.compileLibrary(lib, env, append(stack[:len(stack):len(stack)], src.Description()))
+	if libIss.Err() != nil {
+		iss.ReportErrorAtID(inc.Path().ID, "error compiling include %s:\n%s", ns, libIss)
+	}
+	// The variables are returned even on error so that references to them from the including
+	// policy do not produce additional errors.
+	return vars, true
+}
+
+// compileLibrary compiles the included policies and top-level rule variables of an included policy.
+//
+// The matches of the included policy's rule are type-checked, but otherwise ignored. Included
+// policies may not declare params.
+func (c *compiler) compileLibrary(lib *Policy, env *cel.Env, stack []string) ([]*CompiledVariable, *cel.Issues) {
+	sub := &compiler{
+		env:                  env,
+		info:                 lib.SourceInfo(),
+		src:                  lib.Source(),
+		compileMatchOutput:   c.compileMatchOutput,
+		maxNestedExpressions: c.maxNestedExpressions,
+		nestedCount:          c.nestedCount,
+		loader:               c.loader,
+		includeParser:        c.includeParser,
+	}
+	defer func() {
+		c.nestedCount = sub.nestedCount
+	}()
+	iss := cel.NewIssuesWithSourceInfo(common.NewErrors(sub.src), sub.info)
+	// Parameters are bound by the caller of the root policy, so an included policy would share the
+	// params namespace of the root policy without any way to bind its own declarations.
+	if len(lib.Params()) != 0 {
+		iss.ReportErrorAtID(lib.Params()[0].SourceID(), "params are not supported in included policies")
+	}
+	included := sub.compileIncludes(lib, env, iss, stack)
+	libEnv := sub.policyEnv(lib, included, iss)
+	if lib.Rule() == nil {
+		return included, iss
+	}
+	schema := sub.resolveOutputType(libEnv, lib.OutputType(), iss)
+	rule, iss := sub.compileRule(lib.Rule(), lib, libEnv, iss, false, schema)
+	return append(included, rule.Variables()...), iss
+}
+
+// qualifyVariable declares an included variable within the namespace of the include, and rewrites
+// the references to other variables of the included policy within its expression accordingly.
+//
+// The source id of the qualified variable refers to the include entry, while the expression retains
+// the source of the included policy.
+func qualifyVariable(inc *Include, v *CompiledVariable) *CompiledVariable {
+	ns := inc.Name().Value
+	if v.Expr() != nil {
+		native := v.Expr().NativeRep()
+		fac := ast.NewExprFactory()
+		ast.PostOrderVisit(native.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+			if e.Kind() != ast.IdentKind || !strings.HasPrefix(e.AsIdent(), variablePrefix+".") {
+				return
+			}
+			name := fmt.Sprintf("%s.%s.%s", variablePrefix, ns, strings.TrimPrefix(e.AsIdent(), variablePrefix+"."))
+			e.SetKindCase(fac.NewIdent(e.ID(), name))
+			if r, found := native.ReferenceMap()[e.ID()]; found {
+				r.Name = name
+			}
+		}))
+	}
+	name := fmt.Sprintf("%s.%s", ns, v.Name())
+	return &CompiledVariable{
+		exprID:  inc.Name().ID,
+		name:    name,
+		expr:    v.Expr(),
+		varDecl: decls.NewVariable(fmt.Sprintf("%s.%s", variablePrefix, name), v.Declaration().Type()),
+	}
+}
+
+// isIncludeName indicates whether the name is a simple identifier which may be used to qualify
+// included variable names.
+func isIncludeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
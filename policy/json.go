@@ -0,0 +1,225 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
+
+	"cel.dev/cel-go/cel"
+
+	structpb "google.golang.org/protobuf/types/known/structpb"
+)
+
+// ParseJSON generates an internal parsed policy representation from a JSON document.
+//
+// The document has the same structure as the YAML form of the policy, and positions within the
+// document are tracked in the same manner, so diagnostics refer to lines and columns of the JSON
+// text. Custom fields are handled by the configured TagVisitor.
+func (parser *Parser) ParseJSON(src *Source) (*Policy, *cel.Issues) {
+	return parser.parse(src, decodeJSON)
+}
+
+// ParseMap generates an internal parsed policy representation from a policy value, such as the
+// result of decoding a JSON document into a `map[string]any`.
+//
+// The value is rendered as an indented JSON document with sorted keys which serves as the policy
+// source, so diagnostics refer to synthetic locations within the rendered document. The rendered
+// document may be retrieved from the Source of the returned policy.
+func (parser *Parser) ParseMap(policy map[string]any, description string) (*Policy, *cel.Issues) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// HTML escaping would obscure common CEL operators such as `&&` and `<`.
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(policy); err != nil {
+		return parser.parse(StringSource("", description), func(*Source) (*yaml.Node, int32, error) {
+			return nil, 0, fmt.Errorf("invalid policy value: %w", err)
+		})
+	}
+	return parser.ParseJSON(ByteSource(buf.Bytes(), description))
+}
+
+// ParseStruct generates an internal parsed policy representation from a protobuf Struct in the same
+// manner as ParseMap.
+func (parser *Parser) ParseStruct(policy *structpb.Struct, description string) (*Policy, *cel.Issues) {
+	return parser.ParseMap(policy.AsMap(), description)
+}
+
+// decodeJSON decodes the source, which must be a single JSON object, into the YAML node tree used
+// to build the policy.
+//
+// The nodes are built from the JSON token stream rather than by parsing the source as YAML, since
+// YAML does not accept all JSON string escapes. The line and column of each node refer to the
+// start of the corresponding JSON value. The returned offset is the code point offset at which an
+// error was detected.
+func decodeJSON(src *Source) (*yaml.Node, int32, error) {
+	if offset, err := validateJSON(src); err != nil {
+		return nil, offset, err
+	}
+	content := src.Content()
+	d := &jsonDecoder{content: content, dec: json.NewDecoder(strings.NewReader(content)), line: 1}
+	d.dec.UseNumber()
+	node, err := d.value()
+	if err != nil {
+		return nil, codePointOffset(content, d.start), err
+	}
+	return node, 0, nil
+}
+
+// validateJSON ensures that the source is a single, well-formed JSON object, reporting syntax
+// errors at the offsets detected by a full decode of the document.
+//
+// The returned offset is the code point offset at which the error was detected.
+func validateJSON(src *Source) (int32, error) {
+	content := src.Content()
+	dec := json.NewDecoder(strings.NewReader(content))
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return codePointOffset(content, syntaxErr.Offset-1), fmt.Errorf("invalid JSON: %w", err)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return codePointOffset(content, int64(len(content))), errors.New("invalid JSON: unexpected end of input")
+		}
+		return 0, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, isObject := doc.(map[string]any); !isObject {
+		return 0, errors.New("invalid JSON: policy must be an object")
+	}
+	end := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		rest := content[end:]
+		offset := end + int64(len(rest)-len(strings.TrimLeft(rest, " \t\r\n")))
+		return codePointOffset(content, offset), errors.New("invalid JSON: unexpected content after the policy object")
+	}
+	return 0, nil
+}
+
+// jsonDecoder builds YAML nodes from the token stream of a valid JSON document while tracking the
+// source position of each token.
+type jsonDecoder struct {
+	content string
+	dec     *json.Decoder
+	// start is the byte offset of the most recently read token.
+	start int64
+
+	// line is the line number of the byte offset pos, and lineStart the byte offset of the line.
+	line      int
+	lineStart int64
+	pos       int64
+}
+
+// value decodes the next JSON value into a YAML node.
+func (d *jsonDecoder) value() (*yaml.Node, error) {
+	// Commas and colons between tokens are consumed by the decoder.
+	offset := d.dec.InputOffset()
+	for offset < int64(len(d.content)) && strings.IndexByte(" \t\r\n,:", d.content[offset]) >= 0 {
+		offset++
+	}
+	d.start = offset
+	tok, err := d.dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	node := d.node()
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			return node, d.object(node)
+		}
+		return node, d.array(node)
+	case string:
+		node.Tag = "!!str"
+		node.Style = yaml.DoubleQuotedStyle
+		node.Value = tok
+	case json.Number:
+		node.Tag = "!!float"
+		if _, err := tok.Int64(); err == nil {
+			node.Tag = "!!int"
+		}
+		node.Value = tok.String()
+	case bool:
+		node.Tag = "!!bool"
+		node.Value = fmt.Sprintf("%t", tok)
+	case nil:
+		node.Tag = "!!null"
+		node.Value = "null"
+	}
+	return node, nil
+}
+
+func (d *jsonDecoder) object(node *yaml.Node) error {
+	node.Kind = yaml.MappingNode
+	node.Tag = "!!map"
+	keys := map[string]bool{}
+	for d.dec.More() {
+		key, err := d.value()
+		if err != nil {
+			return err
+		}
+		if keys[key.Value] {
+			return fmt.Errorf("invalid JSON: duplicate key %q", key.Value)
+		}
+		keys[key.Value] = true
+		val, err := d.value()
+		if err != nil {
+			return err
+		}
+		node.Content = append(node.Content, key, val)
+	}
+	_, err := d.dec.Token()
+	return err
+}
+
+func (d *jsonDecoder) array(node *yaml.Node) error {
+	node.Kind = yaml.SequenceNode
+	node.Tag = "!!seq"
+	for d.dec.More() {
+		elem, err := d.value()
+		if err != nil {
+			return err
+		}
+		node.Content = append(node.Content, elem)
+	}
+	_, err := d.dec.Token()
+	return err
+}
+
+// node returns a new scalar node positioned at the start of the most recent token.
+func (d *jsonDecoder) node() *yaml.Node {
+	// Tokens are read in order, so the line is advanced incrementally.
+	for ; d.pos < d.start; d.pos++ {
+		if d.content[d.pos] == '\n' {
+			d.line++
+			d.lineStart = d.pos + 1
+		}
+	}
+	col := utf8.RuneCountInString(d.content[d.lineStart:d.start]) + 1
+	return &yaml.Node{Kind: yaml.ScalarNode, Line: d.line, Column: col}
+}
+
+func codePointOffset(content string, byteOffset int64) int32 {
+	byteOffset = max(0, min(byteOffset, int64(len(content))))
+	return int32(utf8.RuneCountInString(content[:byteOffset]))
+}
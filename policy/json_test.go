@@ -0,0 +1,292 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/ext"
+
+	structpb "google.golang.org/protobuf/types/known/structpb"
+)
+
+const jsonPolicy = `{
+  "name": "size",
+  "owner": "storage",
+  "rule": {
+    "variables": [
+      {"name": "big", "expression": "request.size > 10"}
+    ],
+    "match": [
+      {"condition": "variables.big && request.role != 'admin'", "output": "'deny'"},
+      {"output": "'allow'"}
+    ]
+  }
+}`
+
+func TestParseJSON(t *testing.T) {
+	parser := newJSONTestParser(t)
+	policy, iss := parser.ParseJSON(StringSource(jsonPolicy, "size.json"))
+	if iss.Err() != nil {
+		t.Fatalf("ParseJSON() failed: %v", iss.Err())
+	}
+	if owner, _ := policy.Metadata("owner"); owner != "storage" {
+		t.Errorf("Metadata(owner) got %v, wanted storage", owner)
+	}
+	evalJSONTestPolicy(t, policy)
+}
+
+func TestParseJSONEscapes(t *testing.T) {
+	src := `{
+  "name": "size\/v1",
+  "owner": "\ud83d\ude00",
+  "rule": {
+    "match": [
+      {"condition": "request.path.startsWith('\/')", "output": "'\ud83d\ude00'"},
+      {"output": "''"}
+    ]
+  }
+}`
+	parser := newJSONTestParser(t)
+	policy, iss := parser.ParseJSON(StringSource(src, "escapes.json"))
+	if iss.Err() != nil {
+		t.Fatalf("ParseJSON() failed: %v", iss.Err())
+	}
+	if name := policy.Name().Value; name != "size/v1" {
+		t.Errorf("Name() got %q, wanted size/v1", name)
+	}
+	if owner, _ := policy.Metadata("owner"); owner != "\U0001F600" {
+		t.Errorf("Metadata(owner) got %q, wanted \U0001F600", owner)
+	}
+	env := newJSONTestEnv(t)
+	ast, iss := Compile(env, policy)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	out, _, err := prg.Eval(map[string]any{"request": map[string]any{"path": "/a"}})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if out.Value() != "\U0001F600" {
+		t.Errorf("prg.Eval() got %v, wanted \U0001F600", out)
+	}
+}
+
+func TestParseMap(t *testing.T) {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(jsonPolicy), &doc); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	parser := newJSONTestParser(t)
+	policy, iss := parser.ParseMap(doc, "size")
+	if iss.Err() != nil {
+		t.Fatalf("ParseMap() failed: %v", iss.Err())
+	}
+	if owner, _ := policy.Metadata("owner"); owner != "storage" {
+		t.Errorf("Metadata(owner) got %v, wanted storage", owner)
+	}
+	// The CEL operators are preserved verbatim within the rendered source.
+	if !strings.Contains(policy.Source().Content(), "variables.big && request.role != 'admin'") {
+		t.Errorf("ParseMap() rendered source %s, wanted unescaped expressions", policy.Source().Content())
+	}
+	evalJSONTestPolicy(t, policy)
+
+	pb, err := structpb.NewStruct(doc)
+	if err != nil {
+		t.Fatalf("structpb.NewStruct() failed: %v", err)
+	}
+	policy, iss = parser.ParseStruct(pb, "size")
+	if iss.Err() != nil {
+		t.Fatalf("ParseStruct() failed: %v", iss.Err())
+	}
+	evalJSONTestPolicy(t, policy)
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{
+			src: "{\n  \"name\": \"size\",\n  \"rule\": {,}\n}",
+			err: `ERROR: error.json:3:12: invalid JSON: invalid character ',' looking for beginning of object key string
+ |   "rule": {,}
+ | ...........^`,
+		},
+		{
+			src: `{"name": "size"} {}`,
+			err: `ERROR: error.json:1:18: invalid JSON: unexpected content after the policy object
+ | {"name": "size"} {}
+ | .................^`,
+		},
+		{
+			src: `{"name": "size"`,
+			err: `ERROR: error.json:1:16: invalid JSON: unexpected end of input
+ | {"name": "size"
+ | ...............^`,
+		},
+		{
+			src: `["name", "size"]`,
+			err: `ERROR: error.json:1:1: invalid JSON: policy must be an object
+ | ["name", "size"]
+ | ^`,
+		},
+		{
+			src: "{\n  \"name\": \"size\",\n  \"name\": \"\\ud83d\\ude00\"\n}",
+			err: `ERROR: error.json:3:3: invalid JSON: duplicate key "name"
+ |   "name": "\ud83d\ude00"
+ | ..^`,
+		},
+		{
+			src: "{\n  \"name\": \"\\ud83d\\ude00\", \"unknown\": true\n}",
+			err: `ERROR: error.json:2:28: unsupported policy tag: unknown
+ |   "name": "\ud83d\ude00", "unknown": true
+ | ...........................^`,
+		},
+		{
+			src: "{\n  \"name\": \"size\",\n  \"unknown\": true\n}",
+			err: `ERROR: error.json:3:4: unsupported policy tag: unknown
+ |   "unknown": true
+ | ...^`,
+		},
+	}
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("NewParser() failed: %v", err)
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.src, func(t *testing.T) {
+			_, iss := parser.ParseJSON(StringSource(tc.src, "error.json"))
+			if iss.Err() == nil {
+				t.Fatalf("ParseJSON() succeeded, wanted error %s", tc.err)
+			}
+			if iss.Err().Error() != tc.err {
+				t.Errorf("ParseJSON() got error %s, wanted %s", iss.Err(), tc.err)
+			}
+		})
+	}
+}
+
+func TestParseJSONCompileError(t *testing.T) {
+	src := `{
+  "name": "size",
+  "rule": {
+    "match": [
+      {"condition": "req.size > 10", "output": "'deny'"}
+    ]
+  }
+}`
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("NewParser() failed: %v", err)
+	}
+	policy, iss := parser.ParseJSON(StringSource(src, "size.json"))
+	if iss.Err() != nil {
+		t.Fatalf("ParseJSON() failed: %v", iss.Err())
+	}
+	_, iss = Compile(newJSONTestEnv(t), policy)
+	want := `ERROR: size.json:5:22: undeclared reference to 'req' (in container '')
+ |       {"condition": "req.size > 10", "output": "'deny'"}
+ | .....................^`
+	if iss.Err() == nil || iss.Err().Error() != want {
+		t.Errorf("Compile() got error %v, wanted %s", iss.Err(), want)
+	}
+}
+
+func TestParseMapError(t *testing.T) {
+	parser, err := NewParser()
+	if err != nil {
+		t.Fatalf("NewParser() failed: %v", err)
+	}
+	_, iss := parser.ParseMap(map[string]any{"name": make(chan int)}, "invalid")
+	if iss.Err() == nil || !strings.Contains(iss.Err().Error(), "invalid policy value") {
+		t.Errorf("ParseMap() got error %v, wanted invalid policy value", iss.Err())
+	}
+}
+
+func newJSONTestParser(t testing.TB) *Parser {
+	t.Helper()
+	parser, err := NewParser(CustomTagVisitor(ownerTagVisitor{DefaultTagVisitor()}))
+	if err != nil {
+		t.Fatalf("NewParser() failed: %v", err)
+	}
+	return parser
+}
+
+func newJSONTestEnv(t testing.TB) *cel.Env {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	return env
+}
+
+func evalJSONTestPolicy(t testing.TB, policy *Policy) {
+	t.Helper()
+	env := newJSONTestEnv(t)
+	ast, iss := Compile(env, policy)
+	if iss.Err() != nil {
+		t.Fatalf("Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	tests := []struct {
+		request map[string]any
+		want    string
+	}{
+		{request: map[string]any{"size": 20, "role": "dev"}, want: "deny"},
+		{request: map[string]any{"size": 20, "role": "admin"}, want: "allow"},
+		{request: map[string]any{"size": 5, "role": "dev"}, want: "allow"},
+	}
+	for _, tc := range tests {
+		out, _, err := prg.Eval(map[string]any{"request": tc.request})
+		if err != nil {
+			t.Fatalf("prg.Eval() failed: %v", err)
+		}
+		if out.Value() != tc.want {
+			t.Errorf("prg.Eval(%v) got %v, wanted %s", tc.request, out, tc.want)
+		}
+	}
+}
+
+// ownerTagVisitor records the top-level `owner` tag as policy metadata.
+type ownerTagVisitor struct {
+	TagVisitor
+}
+
+func (v ownerTagVisitor) PolicyTag(ctx ParserContext, id int64, tagName string, node *yaml.Node, p *Policy) {
+	if tagName != "owner" {
+		v.TagVisitor.PolicyTag(ctx, id, tagName, node, p)
+		return
+	}
+	p.SetMetadata(tagName, ctx.NewString(node).Value)
+}
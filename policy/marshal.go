@@ -0,0 +1,806 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"cel.dev/cel-go/common/env"
+)
+
+var (
+	policyFields = []string{"name", "description", "imports", "include", "params", "output_type", "rule"}
+	paramFields  = []string{"name", "description", "type", "type_name", "params", "is_type_param", "values"}
+	ruleFields   = []string{"id", "description", "variables", "output_type",
+		"match", "aggregate", "deny_overrides", "permit_overrides", "priority", "all"}
+	matchFields = []string{"condition", "output", "explanation", "priority",
+		"rule", "match", "aggregate", "deny_overrides", "permit_overrides", "all"}
+	matchRuleKeys  = []string{"rule", "match", "aggregate", "deny_overrides", "permit_overrides", "all"}
+	variableFields = []string{"name", "expression"}
+)
+
+// TagMarshaler is the counterpart of a TagVisitor which produces the custom tags written for each
+// element of a policy.
+//
+// Tag values may be any value which can be encoded as YAML, including a *yaml.Node.
+type TagMarshaler interface {
+	// PolicyTags returns the custom tags written at the top-level of the policy.
+	PolicyTags(p *Policy) []CustomTag
+
+	// RuleTags returns the custom tags written within a rule.
+	RuleTags(p *Policy, r *Rule) []CustomTag
+
+	// MatchTags returns the custom tags written within a match.
+	MatchTags(p *Policy, m *Match) []CustomTag
+
+	// VariableTags returns the custom tags written within a variable.
+	VariableTags(p *Policy, v *Variable) []CustomTag
+}
+
+// CustomTag is a named value written alongside the standard fields of a policy element.
+type CustomTag struct {
+	Name  string
+	Value any
+}
+
+// DefaultTagMarshaler returns a TagMarshaler which writes no custom tags.
+func DefaultTagMarshaler() TagMarshaler {
+	return defaultTagMarshaler{}
+}
+
+type defaultTagMarshaler struct{}
+
+func (defaultTagMarshaler) PolicyTags(*Policy) []CustomTag {
+	return nil
+}
+
+func (defaultTagMarshaler) RuleTags(*Policy, *Rule) []CustomTag {
+	return nil
+}
+
+func (defaultTagMarshaler) MatchTags(*Policy, *Match) []CustomTag {
+	return nil
+}
+
+func (defaultTagMarshaler) VariableTags(*Policy, *Variable) []CustomTag {
+	return nil
+}
+
+// MetadataTagMarshaler returns a TagMarshaler which writes each policy metadata entry as a
+// top-level policy tag, the inverse of a TagVisitor which records top-level tags with
+// Policy.SetMetadata.
+func MetadataTagMarshaler() TagMarshaler {
+	return metadataTagMarshaler{}
+}
+
+type metadataTagMarshaler struct {
+	defaultTagMarshaler
+}
+
+func (metadataTagMarshaler) PolicyTags(p *Policy) []CustomTag {
+	keys := p.MetadataKeys()
+	sort.Strings(keys)
+	tags := make([]CustomTag, 0, len(keys))
+	for _, k := range keys {
+		v, _ := p.Metadata(k)
+		tags = append(tags, CustomTag{Name: k, Value: v})
+	}
+	return tags
+}
+
+// MarshalOption configures the YAML output of Marshal.
+type MarshalOption func(*marshaler) (*marshaler, error)
+
+// MarshalCustomTags configures the TagMarshaler used to write custom policy tags.
+func MarshalCustomTags(tags TagMarshaler) MarshalOption {
+	return func(m *marshaler) (*marshaler, error) {
+		m.tags = tags
+		return m, nil
+	}
+}
+
+// MarshalSimpleVariables writes variables in the inline `name: expression` form accepted by the
+// SimpleVariables parser option.
+func MarshalSimpleVariables() MarshalOption {
+	return func(m *marshaler) (*marshaler, error) {
+		m.inlineVariables = true
+		return m, nil
+	}
+}
+
+// Marshal writes the policy as a YAML document.
+//
+// When the policy was parsed from a YAML or JSON source, the original document serves as a
+// template: comments, key order, scalar styles, and unrecognized tags are retained, and only the
+// values which differ from the parsed policy are rewritten. Policy elements are associated with
+// the original document by their source IDs, so elements which are added programmatically should
+// be created with an ID which does not refer to the parsed source, such as zero.
+//
+// Custom tags which were interpreted by a TagVisitor are written by the TagMarshaler configured
+// with MarshalCustomTags. Tags in the original document which the TagMarshaler does not produce
+// are written as they appeared in the source.
+func Marshal(p *Policy, opts ...MarshalOption) ([]byte, error) {
+	m := &marshaler{tags: DefaultTagMarshaler(), policy: p}
+	var err error
+	for _, opt := range opts {
+		m, err = opt(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	doc := m.original()
+	root, err := m.policyNode()
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = &yaml.Node{Kind: yaml.DocumentNode}
+	}
+	doc.Content = []*yaml.Node{root}
+	if m.blockStyle {
+		clearFlowStyle(doc)
+	}
+	foldedToLiteral(doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.ReplaceAll(buf.Bytes(), []byte(foldedTag+" |"), []byte(">")), nil
+}
+
+// foldedTag marks folded block scalars whose raw text is retained by the marshaler.
+//
+// The encoder can only write the raw lines of a block scalar in literal style, since it folds the
+// value of a folded scalar at its own line breaks and separates a clipped folded scalar from the
+// next key with a blank line. The parser reads the raw lines of both literal
+// and folded scalars, so such scalars are encoded in literal style under this tag, and the tagged
+// literal indicator is replaced with the folded indicator in the encoded document.
+const foldedTag = "!policy.folded"
+
+type marshaler struct {
+	tags            TagMarshaler
+	inlineVariables bool
+
+	policy *Policy
+	src    *Source
+	root   *yaml.Node
+	// nodes indexes the mapping nodes of the original document by source offset.
+	nodes map[int32]*yaml.Node
+	// indent is the indentation step of the original document.
+	indent int
+	// blockStyle indicates that the original document was written in flow style, e.g. as JSON,
+	// and should be converted to block style.
+	blockStyle bool
+}
+
+// original parses the policy source, returning nil if the policy has no source or if the source
+// is not a YAML document.
+func (m *marshaler) original() *yaml.Node {
+	src := m.policy.Source()
+	info := m.policy.SourceInfo()
+	if src == nil || info == nil {
+		return nil
+	}
+	var doc yaml.Node
+	if err := sourceToYAML(src, &doc); err != nil || len(doc.Content) == 0 ||
+		doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	m.src = src
+	m.root = doc.Content[0]
+	m.blockStyle = m.root.Style&yaml.FlowStyle != 0
+	m.indent = 2
+	for i := 1; i < len(m.root.Content); i += 2 {
+		if val := m.root.Content[i]; val.Kind == yaml.MappingNode && val.Style&yaml.FlowStyle == 0 {
+			if step := val.Column - m.root.Column; step > 0 {
+				m.indent = step
+			}
+			break
+		}
+	}
+	m.nodes = map[int32]*yaml.Node{}
+	m.indexNodes(info.LineOffsets(), m.root)
+	return &doc
+}
+
+// indexNodes records the offset of each mapping node using the same computation as
+// CollectMetadata, so that nodes may be found by the offset ranges of policy elements.
+func (m *marshaler) indexNodes(lineOffsets []int32, node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		offset := int32(node.Column) - 1
+		if node.Line > 1 && node.Line-2 < len(lineOffsets) {
+			offset += lineOffsets[node.Line-2]
+		}
+		if _, found := m.nodes[offset]; !found {
+			m.nodes[offset] = node
+		}
+	}
+	for _, n := range node.Content {
+		m.indexNodes(lineOffsets, n)
+	}
+}
+
+// node returns the original mapping node of the policy element with the given source ID, if any.
+func (m *marshaler) node(id int64) *yaml.Node {
+	if m.nodes == nil || id == 0 {
+		return nil
+	}
+	r, found := m.policy.SourceInfo().OffsetRanges()[id]
+	if !found {
+		return nil
+	}
+	return m.nodes[r.Start]
+}
+
+func (m *marshaler) policyNode() (*yaml.Node, error) {
+	p := m.policy
+	mp := newMapping(m.root, policyFields)
+	if p.Name().Value != "" || mp.has("name") {
+		m.setString(mp, "name", p.Name().Value, false)
+	}
+	if p.Description().Value != "" || mp.has("description") {
+		m.setString(mp, "description", p.Description().Value, true)
+	}
+	if len(p.Imports()) != 0 || mp.has("imports") {
+		items := make([]*yaml.Node, 0, len(p.Imports()))
+		for _, imp := range p.Imports() {
+			ip := newMapping(m.node(imp.SourceID()), []string{"name"})
+			m.setString(ip, "name", imp.Name().Value, false)
+			items = append(items, ip.node())
+		}
+		_, orig := mp.get("imports")
+		mp.set("imports", sequence(orig, items))
+	}
+	if len(p.Includes()) != 0 || mp.has("include") {
+		items := make([]*yaml.Node, 0, len(p.Includes()))
+		for _, inc := range p.Includes() {
+			ip := newMapping(m.node(inc.SourceID()), []string{"name", "path"})
+			m.setString(ip, "name", inc.Name().Value, false)
+			m.setString(ip, "path", inc.Path().Value, false)
+			items = append(items, ip.node())
+		}
+		_, orig := mp.get("include")
+		mp.set("include", sequence(orig, items))
+	}
+	if len(p.Params()) != 0 || mp.has("params") {
+		items := make([]*yaml.Node, 0, len(p.Params()))
+		for _, param := range p.Params() {
+			n, err := m.paramNode(param)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, n)
+		}
+		_, orig := mp.get("params")
+		mp.set("params", sequence(orig, items))
+	}
+	if p.OutputType() != nil {
+		if err := m.setOutputType(mp, p.OutputType()); err != nil {
+			return nil, err
+		}
+	}
+	if p.Rule() != nil {
+		r, err := m.ruleNode(p.Rule())
+		if err != nil {
+			return nil, err
+		}
+		mp.set("rule", r)
+	}
+	if err := mp.setTags(m.tags.PolicyTags(p)); err != nil {
+		return nil, err
+	}
+	return mp.node(), nil
+}
+
+func (m *marshaler) paramNode(param *Param) (*yaml.Node, error) {
+	mp := newMapping(m.node(param.SourceID()), paramFields)
+	m.setString(mp, "name", param.Name().Value, false)
+	if param.Description().Value != "" || mp.has("description") {
+		m.setString(mp, "description", param.Description().Value, true)
+	}
+	td := param.TypeDesc()
+	if td == nil {
+		return mp.node(), nil
+	}
+	// The `type` shorthand is retained as written while the type is unchanged, and the type is
+	// otherwise written with the inline type description fields.
+	if _, orig := mp.get("type"); orig != nil {
+		var origType env.TypeDesc
+		if err := orig.Decode(&origType); err == nil && reflect.DeepEqual(&origType, td) {
+			mp.set("type", orig)
+			return mp.node(), nil
+		}
+	}
+	gen := &yaml.Node{}
+	if err := gen.Encode(td); err != nil {
+		return nil, err
+	}
+	for i := 0; i+1 < len(gen.Content); i += 2 {
+		key := gen.Content[i].Value
+		_, orig := mp.get(key)
+		mp.set(key, mergeNode(orig, gen.Content[i+1]))
+	}
+	return mp.node(), nil
+}
+
+func (m *marshaler) ruleNode(r *Rule) (*yaml.Node, error) {
+	mp := newMapping(m.node(r.SourceID()), ruleFields)
+	if r.id != nil {
+		m.setString(mp, "id", r.id.Value, false)
+	}
+	if r.description != nil {
+		m.setString(mp, "description", r.description.Value, false)
+	}
+	if r.OutputType() != nil {
+		if err := m.setOutputType(mp, r.OutputType()); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Variables()) != 0 || mp.has("variables") {
+		items := make([]*yaml.Node, 0, len(r.Variables()))
+		for _, v := range r.Variables() {
+			n, err := m.variableNode(v)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, n)
+		}
+		_, orig := mp.get("variables")
+		mp.set("variables", sequence(orig, items))
+	}
+	key := r.Semantic().String()
+	if len(r.Matches()) != 0 || r.semantic != unspecified || mp.has(key) {
+		items := make([]*yaml.Node, 0, len(r.Matches()))
+		for _, match := range r.Matches() {
+			n, err := m.matchNode(match)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, n)
+		}
+		_, orig := mp.get(key)
+		mp.set(key, sequence(orig, items))
+	}
+	if err := mp.setTags(m.tags.RuleTags(m.policy, r)); err != nil {
+		return nil, err
+	}
+	return mp.node(), nil
+}
+
+func (m *marshaler) variableNode(v *Variable) (*yaml.Node, error) {
+	tags := m.tags.VariableTags(m.policy, v)
+	orig := m.node(v.exprID)
+	if !m.inlineVariables {
+		mp := newMapping(orig, variableFields)
+		m.setString(mp, "name", v.Name().Value, false)
+		m.setString(mp, "expression", v.Expression().Value, false)
+		if err := mp.setTags(tags); err != nil {
+			return nil, err
+		}
+		return mp.node(), nil
+	}
+	if len(tags) != 0 {
+		return nil, fmt.Errorf("variable %s: custom tags may not be set on inline variables", v.Name().Value)
+	}
+	out := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	var origKey, origVal *yaml.Node
+	if orig != nil && orig.Kind == yaml.MappingNode && len(orig.Content) == 2 {
+		*out = *orig
+		origKey, origVal = orig.Content[0], orig.Content[1]
+	}
+	key := m.scalar(v.Name().Value, nil, origKey, true)
+	val := m.scalar(v.Expression().Value, origKey, origVal, false)
+	out.Content = []*yaml.Node{key, val}
+	return out, nil
+}
+
+func (m *marshaler) matchNode(match *Match) (*yaml.Node, error) {
+	mp := newMapping(m.node(match.SourceID()), matchFields)
+	// The parser defaults the condition to `true` when it is absent.
+	cond := match.Condition().Value
+	if (cond != "" && cond != "true") || mp.has("condition") {
+		m.setString(mp, "condition", cond, false)
+	}
+	if match.HasOutput() {
+		m.setString(mp, "output", match.Output().Value, false)
+	}
+	if match.HasExplanation() {
+		m.setString(mp, "explanation", match.Explanation().Value, false)
+	}
+	if match.HasPriority() {
+		priority := match.Priority().Value
+		_, orig := mp.get("priority")
+		if orig == nil || orig.Kind != yaml.ScalarNode || !intEquals(orig.Value, priority) {
+			n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(priority, 10)}
+			copyComments(n, orig)
+			orig = n
+		}
+		mp.set("priority", orig)
+	}
+	if match.HasRule() {
+		key := "rule"
+		for _, k := range matchRuleKeys {
+			if mp.has(k) {
+				key = k
+				break
+			}
+		}
+		r, err := m.ruleNode(match.Rule())
+		if err != nil {
+			return nil, err
+		}
+		mp.set(key, r)
+	}
+	if err := mp.setTags(m.tags.MatchTags(m.policy, match)); err != nil {
+		return nil, err
+	}
+	return mp.node(), nil
+}
+
+func (m *marshaler) setOutputType(mp *mapping, t *OutputType) error {
+	_, orig := mp.get("output_type")
+	gen, err := outputTypeNode(t, orig != nil && orig.Kind == yaml.MappingNode)
+	if err != nil {
+		return err
+	}
+	mp.set("output_type", mergeNode(orig, gen))
+	return nil
+}
+
+// outputTypeNode renders an output type, using the scalar type name form for simple types unless
+// a mapping is requested.
+func outputTypeNode(t *OutputType, asMap bool) (*yaml.Node, error) {
+	if td := t.TypeDesc(); td != nil {
+		if !asMap && len(td.Params) == 0 && !td.IsTypeParam && len(td.Values) == 0 {
+			return stringNode(td.TypeName), nil
+		}
+		n := &yaml.Node{}
+		if err := n.Encode(td); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	fields := make([]*yaml.Node, 0, len(t.Fields()))
+	for _, f := range t.Fields() {
+		fn := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		fn.Content = append(fn.Content, stringNode("name"), stringNode(f.Name().Value))
+		if f.IsOptional() {
+			fn.Content = append(fn.Content, stringNode("optional"),
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+		if f.Type() != nil {
+			tn, err := outputTypeNode(f.Type(), true)
+			if err != nil {
+				return nil, err
+			}
+			fn.Content = append(fn.Content, tn.Content...)
+		}
+		fields = append(fields, fn)
+	}
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			stringNode("fields"),
+			{Kind: yaml.SequenceNode, Tag: "!!seq", Content: fields},
+		},
+	}, nil
+}
+
+// setString sets a string field within the mapping, reusing the original value node when the
+// value is unchanged.
+func (m *marshaler) setString(mp *mapping, key, value string, strict bool) {
+	origKey, orig := mp.get(key)
+	mp.set(key, m.scalar(value, origKey, orig, strict))
+}
+
+// scalar returns the original node if its parsed value matches the given value, or a new scalar
+// node carrying the comments of the original node otherwise.
+//
+// Strict values are compared with the decoded YAML value, while other values are compared with
+// the raw block text used by the parser for literal and folded scalars.
+func (m *marshaler) scalar(value string, key, orig *yaml.Node, strict bool) *yaml.Node {
+	if orig == nil || orig.Kind != yaml.ScalarNode {
+		n := stringNode(value)
+		copyComments(n, orig)
+		return n
+	}
+	block := orig.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0
+	if strict || !block || key == nil {
+		if orig.Value == value {
+			return orig
+		}
+	} else if blockScalarText(m.src, orig.Line+1, key.Column+1) == value {
+		// The parser uses the raw source lines of block scalars, so folding must not be applied
+		// when the text is written back, and indentation beyond the natural indentation of the
+		// block is retained.
+		text := trimIndent(value, key.Column-1+m.indent)
+		if orig.Style&yaml.FoldedStyle != 0 {
+			orig.Style = orig.Style&^yaml.FoldedStyle | yaml.LiteralStyle
+			orig.Tag = foldedTag
+		}
+		// Retain the clip chomping indicator of the block.
+		if strings.HasSuffix(orig.Value, "\n") {
+			text += "\n"
+		}
+		orig.Value = text
+		return orig
+	}
+	n := stringNode(value)
+	copyComments(n, orig)
+	return n
+}
+
+// stringNode returns a string scalar, using the literal style for multi-line values.
+func stringNode(value string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: quoteStyle(value)}
+	if strings.Contains(value, "\n") {
+		n.Value = trimIndent(value, -1)
+		n.Style = yaml.LiteralStyle
+	}
+	return n
+}
+
+// trimIndent removes up to limit spaces of the indentation common to all non-blank lines of the
+// text, or all of the common indentation if the limit is negative.
+func trimIndent(text string, limit int) string {
+	lines := strings.Split(text, "\n")
+	indent := -1
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if limit >= 0 {
+		indent = min(indent, limit)
+	}
+	if indent <= 0 {
+		return text
+	}
+	for i, line := range lines {
+		if len(line) >= indent {
+			lines[i] = line[indent:]
+		} else {
+			lines[i] = strings.TrimLeft(line, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func intEquals(text string, value int64) bool {
+	i, err := strconv.ParseInt(text, 0, 64)
+	return err == nil && i == value
+}
+
+// sequence returns a sequence of the given items which retains the style and comments of the
+// original sequence, if any.
+func sequence(orig *yaml.Node, items []*yaml.Node) *yaml.Node {
+	out := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if orig != nil && orig.Kind == yaml.SequenceNode {
+		*out = *orig
+	}
+	out.Content = items
+	return out
+}
+
+// mergeNode returns the generated node, reusing the original nodes and their comments wherever
+// the original and generated values agree.
+func mergeNode(orig, gen *yaml.Node) *yaml.Node {
+	if orig == nil {
+		return gen
+	}
+	if orig.Kind != gen.Kind {
+		copyComments(gen, orig)
+		return gen
+	}
+	switch gen.Kind {
+	case yaml.ScalarNode:
+		if orig.Value == gen.Value && orig.ShortTag() == gen.ShortTag() {
+			return orig
+		}
+		copyComments(gen, orig)
+		return gen
+	case yaml.MappingNode:
+		genIndex := map[string]int{}
+		for i := 0; i+1 < len(gen.Content); i += 2 {
+			genIndex[gen.Content[i].Value] = i
+		}
+		out := *orig
+		out.Content = make([]*yaml.Node, 0, len(gen.Content))
+		merged := map[string]bool{}
+		for i := 0; i+1 < len(orig.Content); i += 2 {
+			k := orig.Content[i]
+			if j, found := genIndex[k.Value]; found && !merged[k.Value] {
+				out.Content = append(out.Content, k, mergeNode(orig.Content[i+1], gen.Content[j+1]))
+				merged[k.Value] = true
+			}
+		}
+		for i := 0; i+1 < len(gen.Content); i += 2 {
+			if !merged[gen.Content[i].Value] {
+				out.Content = append(out.Content, gen.Content[i], gen.Content[i+1])
+			}
+		}
+		return &out
+	case yaml.SequenceNode:
+		out := *orig
+		out.Content = make([]*yaml.Node, len(gen.Content))
+		for i, item := range gen.Content {
+			if i < len(orig.Content) {
+				out.Content[i] = mergeNode(orig.Content[i], item)
+			} else {
+				out.Content[i] = item
+			}
+		}
+		return &out
+	}
+	return gen
+}
+
+func copyComments(dst, src *yaml.Node) {
+	if src == nil {
+		return
+	}
+	dst.HeadComment = src.HeadComment
+	dst.LineComment = src.LineComment
+	dst.FootComment = src.FootComment
+}
+
+// quoteStyle returns the style of a scalar with the given value, preferring double quotes for
+// values which begin with a CEL string literal in keeping with the style of most policies. Other
+// values are quoted by the encoder only when necessary.
+func quoteStyle(value string) yaml.Style {
+	if strings.HasPrefix(value, "'") {
+		return yaml.DoubleQuotedStyle
+	}
+	return 0
+}
+
+// clearFlowStyle converts flow collections and quoted scalars to block style.
+func clearFlowStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	if node.Kind == yaml.ScalarNode && node.Style == 0 {
+		node.Style = quoteStyle(node.Value)
+	}
+	for _, n := range node.Content {
+		clearFlowStyle(n)
+	}
+}
+
+// foldedToLiteral converts folded scalars which contain line breaks to literal style, since the
+// encoder separates such lines with blank lines which would end the raw text of the block.
+func foldedToLiteral(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Style&yaml.FoldedStyle != 0 &&
+		strings.Contains(strings.TrimRight(node.Value, "\n"), "\n") {
+		node.Style = node.Style&^yaml.FoldedStyle | yaml.LiteralStyle
+	}
+	for _, n := range node.Content {
+		foldedToLiteral(n)
+	}
+}
+
+// mapping assembles a YAML mapping, retaining the key order, comments, and unrecognized keys of
+// an original mapping node when one is present.
+type mapping struct {
+	orig   *yaml.Node
+	fields map[string]bool
+	keys   []string
+	values map[string]*yaml.Node
+}
+
+func newMapping(orig *yaml.Node, fields []string) *mapping {
+	if orig != nil && orig.Kind != yaml.MappingNode {
+		orig = nil
+	}
+	mp := &mapping{
+		orig:   orig,
+		fields: make(map[string]bool, len(fields)),
+		values: map[string]*yaml.Node{},
+	}
+	for _, f := range fields {
+		mp.fields[f] = true
+	}
+	return mp
+}
+
+// has indicates whether the original mapping contains the key.
+func (mp *mapping) has(key string) bool {
+	k, _ := mp.get(key)
+	return k != nil
+}
+
+// get returns the original key and value nodes for the key, if present.
+func (mp *mapping) get(key string) (*yaml.Node, *yaml.Node) {
+	if mp.orig == nil {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mp.orig.Content); i += 2 {
+		if mp.orig.Content[i].Value == key {
+			return mp.orig.Content[i], mp.orig.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func (mp *mapping) set(key string, val *yaml.Node) {
+	if _, found := mp.values[key]; !found {
+		mp.keys = append(mp.keys, key)
+	}
+	mp.values[key] = val
+}
+
+func (mp *mapping) setTags(tags []CustomTag) error {
+	for _, tag := range tags {
+		if mp.fields[tag.Name] {
+			return fmt.Errorf("custom tag %s conflicts with a policy field", tag.Name)
+		}
+		gen, isNode := tag.Value.(*yaml.Node)
+		if !isNode {
+			gen = &yaml.Node{}
+			if err := gen.Encode(tag.Value); err != nil {
+				return fmt.Errorf("custom tag %s: %w", tag.Name, err)
+			}
+		}
+		_, orig := mp.get(tag.Name)
+		mp.set(tag.Name, mergeNode(orig, gen))
+	}
+	return nil
+}
+
+// node returns the assembled mapping. Original keys are written in their original order, followed
+// by new keys in the order in which they were set. Original policy fields which were not set are
+// dropped, while original custom tags are retained.
+func (mp *mapping) node() *yaml.Node {
+	out := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	written := map[string]bool{}
+	if mp.orig != nil {
+		*out = *mp.orig
+		out.Content = make([]*yaml.Node, 0, len(mp.orig.Content))
+		for i := 0; i+1 < len(mp.orig.Content); i += 2 {
+			k := mp.orig.Content[i]
+			if written[k.Value] {
+				continue
+			}
+			if val, found := mp.values[k.Value]; found {
+				out.Content = append(out.Content, k, val)
+				written[k.Value] = true
+			} else if !mp.fields[k.Value] {
+				out.Content = append(out.Content, k, mp.orig.Content[i+1])
+				written[k.Value] = true
+			}
+		}
+	}
+	for _, k := range mp.keys {
+		if !written[k] {
+			out.Content = append(out.Content, stringNode(k), mp.values[k])
+		}
+	}
+	return out
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/env"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, tst := range policyTests {
+		tc := tst
+		// The k8s policy rule is derived from custom tags rather than the standard policy fields.
+		if tc.name == "k8s" {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			policy := parsePolicy(t, tc.name, tc.parseOpts)
+			out, err := Marshal(policy)
+			if err != nil {
+				t.Fatalf("Marshal() failed: %v", err)
+			}
+			parser, err := NewParser(tc.parseOpts...)
+			if err != nil {
+				t.Fatalf("NewParser() failed: %v", err)
+			}
+			reparsed, iss := parser.Parse(ByteSource(out, tc.name))
+			if iss.Err() != nil {
+				t.Fatalf("Parse() of the marshaled policy failed: %v\n%s", iss.Err(), out)
+			}
+			_, want, iss := compile(t, tc.name, policy, tc.envOpts, []CompilerOption{})
+			if iss.Err() != nil {
+				t.Fatalf("Compile() failed: %v", iss.Err())
+			}
+			_, got, iss := compile(t, tc.name, reparsed, tc.envOpts, []CompilerOption{})
+			if iss.Err() != nil {
+				t.Fatalf("Compile() of the marshaled policy failed: %v\n%s", iss.Err(), out)
+			}
+			wantExpr, _ := cel.AstToString(want)
+			gotExpr, _ := cel.AstToString(got)
+			if gotExpr != wantExpr {
+				t.Errorf("marshaled policy compiled to %s, wanted %s", gotExpr, wantExpr)
+			}
+			again, err := Marshal(reparsed)
+			if err != nil {
+				t.Fatalf("Marshal() of the marshaled policy failed: %v", err)
+			}
+			if string(again) != string(out) {
+				t.Errorf("Marshal() is not stable, got:\n%s\nwanted:\n%s", again, out)
+			}
+		})
+	}
+}
+
+func TestMarshalPreservesComments(t *testing.T) {
+	src := `# Size limits for storage requests.
+name: size
+owner: storage # the owning team
+rule:
+  variables:
+    # Requests over the limit.
+    - name: big
+      expression: request.size > 10
+    - name: admin
+      expression: request.role == 'admin'
+  match:
+    # Administrators are exempt.
+    - condition: variables.admin
+      output: "'allow'"
+    - condition: |
+        variables.big &&
+          request.tier != 'gold'
+      output: "'deny'" # over the limit
+    - output: "'allow'"
+`
+	policy := parsePolicySource(t, "size.celpolicy", src,
+		CustomTagVisitor(ownerTagVisitor{DefaultTagVisitor()}))
+	r := policy.Rule()
+	// Raise the limit, drop the admin exemption, and add a new variable and match.
+	r.Variables()[0].SetExpression(ValueString{Value: "request.size > 100"})
+	r.variables = r.variables[:1]
+	small := NewVariable(0)
+	small.SetName(ValueString{Value: "small"})
+	small.SetExpression(ValueString{Value: "request.size < 5"})
+	r.AddVariable(small)
+	r.matches = r.matches[1:]
+	m := NewMatch(0)
+	m.SetCondition(ValueString{Value: "variables.small"})
+	m.SetOutput(ValueString{Value: "'allow'"})
+	r.matches = append([]*Match{m}, r.matches...)
+
+	out, err := Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	want := `# Size limits for storage requests.
+name: size
+owner: storage # the owning team
+rule:
+  variables:
+    # Requests over the limit.
+    - name: big
+      expression: request.size > 100
+    - name: small
+      expression: request.size < 5
+  match:
+    - condition: variables.small
+      output: "'allow'"
+    - condition: |
+        variables.big &&
+          request.tier != 'gold'
+      output: "'deny'" # over the limit
+    - output: "'allow'"
+`
+	if string(out) != want {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, want)
+	}
+}
+
+func TestMarshalFoldedScalars(t *testing.T) {
+	src := `name: folded
+rule:
+  variables:
+    - name: big
+      expression: >-
+        request.size > 10
+  match:
+    - condition: >
+        variables.big &&
+          request.tier != 'gold'
+      output: "'deny'"
+    - output: >
+        'allow'
+`
+	policy := parsePolicySource(t, "folded.celpolicy", src)
+	out, err := Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if string(out) != src {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, src)
+	}
+	reparsed := parsePolicySource(t, "folded.celpolicy", string(out))
+	got := reparsed.Rule().Matches()[0].Condition().Value
+	want := policy.Rule().Matches()[0].Condition().Value
+	if got != want {
+		t.Errorf("Marshal() round trip got condition %q, wanted %q", got, want)
+	}
+}
+
+func TestMarshalNewPolicy(t *testing.T) {
+	policy := NewPolicy(nil, nil)
+	policy.SetName(ValueString{Value: "size"})
+	policy.SetDescription(ValueString{Value: "Size limits for storage requests."})
+	policy.AddImport(newTestImport("google.expr.proto3.test.TestAllTypes"))
+	outputType := NewOutputType(0)
+	outputType.SetTypeDesc(&env.TypeDesc{TypeName: "string"})
+	policy.SetOutputType(outputType)
+	r := NewRule(0)
+	r.SetID(ValueString{Value: "size"})
+	v := NewVariable(0)
+	v.SetName(ValueString{Value: "big"})
+	v.SetExpression(ValueString{Value: "request.size > 10"})
+	r.AddVariable(v)
+	nested := NewRule(0)
+	deny := NewMatch(0)
+	deny.SetCondition(ValueString{Value: "request.role != 'admin'"})
+	deny.SetOutput(ValueString{Value: "'deny'"})
+	nested.AddMatch(deny)
+	m := NewMatch(0)
+	m.SetCondition(ValueString{Value: "variables.big"})
+	m.SetRule(nested)
+	r.AddMatch(m)
+	allow := NewMatch(0)
+	allow.SetCondition(ValueString{Value: "true"})
+	allow.SetOutput(ValueString{Value: "'allow'"})
+	allow.SetPriority(ValueInt{Value: 2})
+	r.AddMatch(allow)
+	policy.SetRule(r)
+	policy.SetMetadata("owner", "storage")
+
+	out, err := Marshal(policy, MarshalCustomTags(MetadataTagMarshaler()))
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	want := `name: size
+description: Size limits for storage requests.
+imports:
+  - name: google.expr.proto3.test.TestAllTypes
+output_type: string
+rule:
+  id: size
+  variables:
+    - name: big
+      expression: request.size > 10
+  match:
+    - condition: variables.big
+      rule:
+        match:
+          - condition: request.role != 'admin'
+            output: "'deny'"
+    - output: "'allow'"
+      priority: 2
+owner: storage
+`
+	if string(out) != want {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, want)
+	}
+	reparsed := parsePolicySource(t, "size.celpolicy", string(out),
+		CustomTagVisitor(ownerTagVisitor{DefaultTagVisitor()}))
+	if owner, _ := reparsed.Metadata("owner"); owner != "storage" {
+		t.Errorf("Metadata(owner) got %v, wanted storage", owner)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	policy, iss := newJSONTestParser(t).ParseJSON(StringSource(jsonPolicy, "size.json"))
+	if iss.Err() != nil {
+		t.Fatalf("ParseJSON() failed: %v", iss.Err())
+	}
+	policy.SetMetadata("owner", "compute")
+	out, err := Marshal(policy, MarshalCustomTags(MetadataTagMarshaler()))
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	want := `name: size
+owner: compute
+rule:
+  variables:
+    - name: big
+      expression: request.size > 10
+  match:
+    - condition: variables.big && request.role != 'admin'
+      output: "'deny'"
+    - output: "'allow'"
+`
+	if string(out) != want {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, want)
+	}
+	reparsed := parsePolicySource(t, "size.celpolicy", string(out),
+		CustomTagVisitor(ownerTagVisitor{DefaultTagVisitor()}))
+	evalJSONTestPolicy(t, reparsed)
+}
+
+func TestMarshalOutputType(t *testing.T) {
+	src := `name: decision
+output_type:
+  fields:
+    - name: allowed # whether the request is allowed
+      type_name: bool
+    - name: reasons
+      optional: true
+      type_name: list
+      params:
+        - type_name: string
+rule:
+  match:
+    - output: "{'allowed': true}"
+`
+	policy := parsePolicySource(t, "decision.celpolicy", src)
+	out, err := Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if string(out) != src {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, src)
+	}
+
+	// Replace the reasons field with a required string.
+	f := NewOutputField(0)
+	f.SetName(ValueString{Value: "reason"})
+	ft := NewOutputType(0)
+	ft.SetTypeDesc(&env.TypeDesc{TypeName: "string"})
+	f.SetType(ft)
+	policy.OutputType().fields[1] = f
+	out, err = Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	want := `name: decision
+output_type:
+  fields:
+    - name: allowed # whether the request is allowed
+      type_name: bool
+    - name: reason
+      type_name: string
+rule:
+  match:
+    - output: "{'allowed': true}"
+`
+	if string(out) != want {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, want)
+	}
+}
+
+func TestMarshalSimpleVariables(t *testing.T) {
+	src := `name: size
+rule:
+  variables:
+    - big: request.size > 10 # the limit
+  match:
+    - condition: variables.big
+      output: "'deny'"
+`
+	policy := parsePolicySource(t, "size.celpolicy", src, SimpleVariables())
+	policy.Rule().Variables()[0].SetExpression(ValueString{Value: "request.size > 20"})
+	out, err := Marshal(policy, MarshalSimpleVariables())
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	want := strings.Replace(src, "> 10", "> 20", 1)
+	if string(out) != want {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, want)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	policy := parsePolicySource(t, "size.celpolicy", `name: size
+rule:
+  variables:
+    - name: big
+      expression: request.size > 10
+  match:
+    - output: "'allow'"
+`)
+	_, err := Marshal(policy, MarshalCustomTags(conflictingTagMarshaler{DefaultTagMarshaler()}))
+	if err == nil || !strings.Contains(err.Error(), "custom tag output conflicts with a policy field") {
+		t.Errorf("Marshal() got error %v, wanted custom tag conflict", err)
+	}
+	_, err = Marshal(policy, MarshalSimpleVariables(), MarshalCustomTags(conflictingTagMarshaler{DefaultTagMarshaler()}))
+	if err == nil || !strings.Contains(err.Error(), "custom tags may not be set on inline variables") {
+		t.Errorf("Marshal() got error %v, wanted inline variable tag error", err)
+	}
+}
+
+func newTestImport(name string) *Import {
+	imp := NewImport(0)
+	imp.SetName(ValueString{Value: name})
+	return imp
+}
+
+// conflictingTagMarshaler writes custom tags which collide with standard policy fields.
+type conflictingTagMarshaler struct {
+	TagMarshaler
+}
+
+func (conflictingTagMarshaler) MatchTags(*Policy, *Match) []CustomTag {
+	return []CustomTag{{Name: "output", Value: "'deny'"}}
+}
+
+func (conflictingTagMarshaler) VariableTags(*Policy, *Variable) []CustomTag {
+	return []CustomTag{{Name: "type", Value: "bool"}}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/types"
+)
+
+// outputSchema is the resolved form of an OutputType declaration.
+type outputSchema struct {
+	celType *types.Type
+	// fields is non-nil when the output type declares the fields of a map-literal output.
+	fields []*outputFieldSchema
+}
+
+type outputFieldSchema struct {
+	name     string
+	optional bool
+	schema   *outputSchema
+}
+
+func (s *outputSchema) field(name string) (*outputFieldSchema, bool) {
+	for _, f := range s.fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// resolveOutputType resolves a declared output type to a CEL type using the type provider of the
+// environment. Resolution errors are reported at the output type declaration and result in a nil
+// schema so that the outputs are not checked against it.
+func (c *compiler) resolveOutputType(env *cel.Env, t *OutputType, iss *cel.Issues) *outputSchema {
+	if t == nil {
+		return nil
+	}
+	if t.TypeDesc() != nil {
+		celType, err := t.TypeDesc().AsCELType(env.CELTypeProvider())
+		if err != nil {
+			iss.ReportErrorAtID(t.SourceID(), "invalid output_type: %s", err)
+			return nil
+		}
+		return &outputSchema{celType: celType}
+	}
+	if len(t.Fields()) == 0 {
+		return nil
+	}
+	s := &outputSchema{
+		celType: types.NewMapType(types.StringType, types.DynType),
+		fields:  make([]*outputFieldSchema, 0, len(t.Fields())),
+	}
+	for _, f := range t.Fields() {
+		name := f.Name().Value
+		if _, found := s.field(name); found {
+			iss.ReportErrorAtID(f.SourceID(), "duplicate output_type field: %s", name)
+			continue
+		}
+		fieldSchema := c.resolveOutputType(env, f.Type(), iss)
+		if fieldSchema == nil {
+			fieldSchema = &outputSchema{celType: types.DynType}
+		}
+		s.fields = append(s.fields, &outputFieldSchema{
+			name:     name,
+			optional: f.IsOptional(),
+			schema:   fieldSchema,
+		})
+	}
+	return s
+}
+
+// checkOutputType validates that a match output is assignable to the declared output type.
+//
+// When the declared type is a field schema, the keys of the map literals which make up the output
+// are checked against the schema. Map literals are found through the branches of conditionals and
+// through references to variables, and errors are reported at the offending expressions, which
+// may be within a variable. Map-typed outputs whose keys cannot be determined are reported as
+// errors.
+func (c *compiler) checkOutputType(s *outputSchema, m *Match, outAST *cel.Ast, iss *cel.Issues) *cel.Issues {
+	if s == nil || outAST == nil {
+		return iss
+	}
+	outType := outAST.OutputType()
+	if !isAssignableOutput(s.celType, outType) {
+		iss.ReportErrorAtID(m.Output().ID, "output type %s is not assignable to the declared output type %s", outType, s.celType)
+		return iss
+	}
+	if s.fields == nil {
+		return iss
+	}
+	oc := &outputFieldChecker{c: c}
+	oc.check(s, outAST, outAST.NativeRep().Expr())
+	for _, outIss := range oc.issues {
+		iss = iss.Append(outIss)
+	}
+	return iss
+}
+
+// outputVariableCheck identifies the check of a variable expression against an output schema.
+type outputVariableCheck struct {
+	schema *outputSchema
+	expr   *cel.Ast
+}
+
+// outputFieldChecker checks the keys of map literal outputs against an output schema, where the
+// outputs may span the expressions of several variables.
+type outputFieldChecker struct {
+	c      *compiler
+	issues []*cel.Issues
+	// current is the expression being checked and iss the issues reported against it.
+	current *cel.Ast
+	iss     *cel.Issues
+}
+
+func (oc *outputFieldChecker) check(s *outputSchema, a *cel.Ast, e ast.Expr) {
+	if oc.current != a {
+		prevAST, prevIss := oc.current, oc.iss
+		oc.current = a
+		oc.iss = cel.NewIssuesWithSourceInfo(common.NewErrors(a.Source()), a.NativeRep().SourceInfo())
+		oc.issues = append(oc.issues, oc.iss)
+		defer func() {
+			oc.current, oc.iss = prevAST, prevIss
+		}()
+	}
+	native := a.NativeRep()
+	switch e.Kind() {
+	case ast.MapKind:
+		oc.checkMapLiteral(s, a, e)
+		return
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() == operators.Conditional {
+			oc.check(s, a, call.Args()[1])
+			oc.check(s, a, call.Args()[2])
+			return
+		}
+	case ast.IdentKind, ast.SelectKind:
+		if ref, found := native.ReferenceMap()[e.ID()]; found && ref.Value == nil {
+			if varAST, found := oc.c.variableASTs[ref.Name]; found {
+				key := outputVariableCheck{schema: s, expr: varAST}
+				if oc.c.checkedOutputVars[key] {
+					return
+				}
+				if oc.c.checkedOutputVars == nil {
+					oc.c.checkedOutputVars = map[outputVariableCheck]bool{}
+				}
+				oc.c.checkedOutputVars[key] = true
+				oc.check(s, varAST, varAST.NativeRep().Expr())
+				return
+			}
+		}
+	}
+	if t := native.GetType(e.ID()); t.Kind() == types.MapKind {
+		oc.iss.ReportErrorAtID(e.ID(), "output keys of type %s cannot be checked against the declared output_type, use a map literal", t)
+	}
+}
+
+func (oc *outputFieldChecker) checkMapLiteral(s *outputSchema, a *cel.Ast, e ast.Expr) {
+	native := a.NativeRep()
+	present := map[string]bool{}
+	for _, entry := range e.AsMap().Entries() {
+		me := entry.AsMapEntry()
+		key := me.Key()
+		if key.Kind() != ast.LiteralKind {
+			oc.iss.ReportErrorAtID(key.ID(), "output keys must be string literals to be checked against the declared output_type")
+			continue
+		}
+		name, isString := key.AsLiteral().(types.String)
+		if !isString {
+			continue
+		}
+		present[string(name)] = true
+		f, found := s.field(string(name))
+		if !found {
+			oc.iss.ReportErrorAtID(key.ID(), "unknown output key: %s", name)
+			continue
+		}
+		valType := native.GetType(me.Value().ID())
+		if me.IsOptional() && valType.TypeName() == "optional_type" {
+			valType = valType.Parameters()[0]
+		}
+		if !isAssignableOutput(f.schema.celType, valType) {
+			oc.iss.ReportErrorAtID(me.Value().ID(), "output key %s has type %s, but the declared type is %s", name, valType, f.schema.celType)
+			continue
+		}
+		if f.schema.fields != nil {
+			oc.check(f.schema, a, me.Value())
+		}
+	}
+	for _, f := range s.fields {
+		if !f.optional && !present[f.name] {
+			oc.iss.ReportErrorAtID(e.ID(), "missing required output key: %s", f.name)
+		}
+	}
+}
+
+// isAssignableOutput indicates whether an output of the given type may be assigned to the declared
+// type. Dynamically typed outputs cannot be checked at compile time and are accepted.
+func isAssignableOutput(declared, t *types.Type) bool {
+	return t.Kind() == types.DynKind || declared.IsAssignableType(t)
+}
@@ -0,0 +1,299 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/common/types/traits"
+	"cel.dev/cel-go/interpreter"
+)
+
+// paramPrefix is the namespace under which policy parameters are declared.
+const paramPrefix = "params."
+
+var paramNamePattern = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// Template is a compiled policy whose parameters are bound to values when the template is
+// instantiated, so that a single compilation may be shared by many instances of the policy.
+type Template struct {
+	env    *cel.Env
+	ast    *cel.Ast
+	params map[string]*types.Type
+}
+
+// CompileTemplate compiles a policy which declares parameters into a Template.
+func CompileTemplate(env *cel.Env, p *Policy, opts ...CompilerOption) (*Template, *cel.Issues) {
+	ast, iss := Compile(env, p, opts...)
+	if iss.Err() != nil {
+		return nil, iss
+	}
+	tmplEnv, err := paramsEnv(env, p)
+	if err != nil {
+		// Invalid parameters are reported during compilation, so this should be unreachable.
+		iss.ReportErrorAtID(p.Name().ID, "error configuring params: %s", err)
+		return nil, iss
+	}
+	params := make(map[string]*types.Type, len(p.Params()))
+	for _, param := range p.Params() {
+		params[param.Name().Value], _ = param.TypeDesc().AsCELType(tmplEnv.CELTypeProvider())
+	}
+	return &Template{env: tmplEnv, ast: ast, params: params}, iss
+}
+
+// Env returns the environment of the template, which declares the policy parameters.
+func (t *Template) Env() *cel.Env {
+	return t.env
+}
+
+// Ast returns the compiled policy expression in which the parameters are unbound.
+func (t *Template) Ast() *cel.Ast {
+	return t.ast
+}
+
+// Params returns the sorted names of the template parameters.
+func (t *Template) Params() []string {
+	names := make([]string, 0, len(t.params))
+	for name := range t.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Instantiate binds a value to each of the template parameters and returns a program for the
+// template expression. The bound values are supplied to the program alongside the evaluation
+// input, so the template is neither recompiled nor optimized. The bound values take precedence
+// over any input variable of the same name, so the input cannot override a parameter.
+func (t *Template) Instantiate(bindings map[string]any, opts ...cel.ProgramOption) (cel.Program, error) {
+	vars, err := t.bind(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return newParamProgram(t.env, t.ast, vars, opts...)
+}
+
+// Specialize binds a value to each of the template parameters and returns a program for the
+// specialized template expression, see SpecializedAst.
+func (t *Template) Specialize(bindings map[string]any, opts ...cel.ProgramOption) (cel.Program, error) {
+	vars, err := t.bind(bindings)
+	if err != nil {
+		return nil, err
+	}
+	ast, err := t.specialize(vars)
+	if err != nil {
+		return nil, err
+	}
+	// The bound values remain available to any reference which could not be folded.
+	return newParamProgram(t.env, ast, vars, opts...)
+}
+
+// SpecializedAst binds a value to each of the template parameters and folds the values into the
+// template expression with the constant folding optimizer, simplifying any expressions which
+// depend only on the parameters.
+func (t *Template) SpecializedAst(bindings map[string]any) (*cel.Ast, error) {
+	vars, err := t.bind(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return t.specialize(vars)
+}
+
+func (t *Template) specialize(vars map[string]any) (*cel.Ast, error) {
+	known, err := cel.NewActivation(vars)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := cel.NewConstantFoldingOptimizer(cel.FoldKnownValues(known))
+	if err != nil {
+		return nil, err
+	}
+	opt, err := cel.NewStaticOptimizer(folder)
+	if err != nil {
+		return nil, err
+	}
+	// Composed rules are wrapped in a cel.@block whose index variables must be declared in order to
+	// check the optimized expression.
+	env, err := t.env.Extend(cel.BlockIndexVariables(t.ast)...)
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := opt.Optimize(env, t.ast)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	return ast, nil
+}
+
+// paramProgram is a program whose parameter values are layered over the evaluation input.
+//
+// Parameters supplied with cel.Globals would be shadowed by input variables of the same name, so
+// the parameters are instead resolved ahead of the input on every evaluation.
+type paramProgram struct {
+	cel.Program
+	params cel.Activation
+}
+
+func newParamProgram(env *cel.Env, ast *cel.Ast, vars map[string]any, opts ...cel.ProgramOption) (cel.Program, error) {
+	params, err := cel.NewActivation(vars)
+	if err != nil {
+		return nil, err
+	}
+	prg, err := env.Program(ast, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &paramProgram{Program: prg, params: params}, nil
+}
+
+// Eval implements the cel.Program interface method.
+func (p *paramProgram) Eval(input any) (ref.Val, *cel.EvalDetails, error) {
+	return p.Program.Eval(p.activation(input))
+}
+
+// ContextEval implements the cel.Program interface method.
+func (p *paramProgram) ContextEval(ctx context.Context, input any) (ref.Val, *cel.EvalDetails, error) {
+	return p.Program.ContextEval(ctx, p.activation(input))
+}
+
+// ConcurrentEval implements the cel.Program interface method.
+func (p *paramProgram) ConcurrentEval(ctx context.Context, input any) <-chan cel.EvalResult {
+	return p.Program.ConcurrentEval(ctx, p.activation(input))
+}
+
+// EvalBatch implements the cel.Program interface method.
+func (p *paramProgram) EvalBatch(ctx context.Context, inputs []any) []cel.EvalResult {
+	layered := make([]any, len(inputs))
+	for i, input := range inputs {
+		layered[i] = p.activation(input)
+	}
+	return p.Program.EvalBatch(ctx, layered)
+}
+
+// activation returns an activation which resolves the parameters ahead of the input variables.
+//
+// An input which is not a valid activation is returned as-is so that the program reports the
+// error.
+func (p *paramProgram) activation(input any) any {
+	in, err := cel.NewActivation(input)
+	if err != nil {
+		return input
+	}
+	return interpreter.NewHierarchicalActivation(in, p.params)
+}
+
+// bind validates the parameter bindings and returns them as variables keyed by their qualified
+// names.
+func (t *Template) bind(bindings map[string]any) (map[string]any, error) {
+	for name := range bindings {
+		if _, found := t.params[name]; !found {
+			return nil, fmt.Errorf("unknown param: %s", name)
+		}
+	}
+	vars := make(map[string]any, len(t.params))
+	for _, name := range t.Params() {
+		value, found := bindings[name]
+		if !found {
+			return nil, fmt.Errorf("missing binding for param: %s", name)
+		}
+		val := t.env.CELTypeAdapter().NativeToValue(value)
+		if types.IsError(val) || !isAssignableValue(t.params[name], val) {
+			return nil, fmt.Errorf("param %s: got value of type %s, wanted %s", name, val.Type(), t.params[name])
+		}
+		vars[paramPrefix+name] = val
+	}
+	return vars, nil
+}
+
+// isAssignableValue determines whether the value is assignable to the type, including the
+// elements of lists and maps.
+func isAssignableValue(t *types.Type, val ref.Val) bool {
+	switch t.Kind() {
+	case types.DynKind, types.AnyKind, types.TypeParamKind:
+		return true
+	}
+	if !t.IsAssignableRuntimeType(val) {
+		return false
+	}
+	switch t.Kind() {
+	case types.ListKind:
+		elemType := t.Parameters()[0]
+		for it := val.(traits.Lister).Iterator(); it.HasNext() == types.True; {
+			if !isAssignableValue(elemType, it.Next()) {
+				return false
+			}
+		}
+	case types.MapKind:
+		m := val.(traits.Mapper)
+		keyType, valType := t.Parameters()[0], t.Parameters()[1]
+		for it := m.Iterator(); it.HasNext() == types.True; {
+			k := it.Next()
+			if !isAssignableValue(keyType, k) || !isAssignableValue(valType, m.Get(k)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// paramsEnv extends the environment with the declarations of the policy parameters.
+func paramsEnv(env *cel.Env, p *Policy) (*cel.Env, error) {
+	if len(p.Params()) == 0 {
+		return env, nil
+	}
+	iss := cel.NewIssues(common.NewErrors(p.Source()))
+	paramDecls := paramVariables(env, p, iss)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	return env.Extend(paramDecls...)
+}
+
+// paramVariables returns the variable declarations of the policy parameters, reporting any invalid
+// parameter declarations.
+func paramVariables(env *cel.Env, p *Policy, iss *cel.Issues) []cel.EnvOption {
+	declared := map[string]bool{}
+	paramDecls := make([]cel.EnvOption, 0, len(p.Params()))
+	for _, param := range p.Params() {
+		name := param.Name().Value
+		if !paramNamePattern.MatchString(name) {
+			iss.ReportErrorAtID(param.Name().ID, "invalid param name: %q", name)
+			continue
+		}
+		if declared[name] {
+			iss.ReportErrorAtID(param.Name().ID, "duplicate param: %s", name)
+			continue
+		}
+		declared[name] = true
+		if param.TypeDesc() == nil {
+			iss.ReportErrorAtID(param.SourceID(), "param %s must specify a type", name)
+			continue
+		}
+		t, err := param.TypeDesc().AsCELType(env.CELTypeProvider())
+		if err != nil {
+			iss.ReportErrorAtID(param.SourceID(), "invalid param type: %s", err)
+			continue
+		}
+		paramDecls = append(paramDecls, cel.Variable(paramPrefix+name, t))
+	}
+	return paramDecls
+}
@@ -0,0 +1,306 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	celast "cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/ext"
+)
+
+const registriesPolicy = `name: registries
+params:
+  - name: allowed_registries
+    description: The registries from which images may be pulled.
+    type: list<string>
+  - name: max_containers
+    type_name: int
+rule:
+  variables:
+    - name: disallowed
+      expression: |
+        resource.containers.filter(c,
+          !params.allowed_registries.exists(r, c.startsWith(r + '/')))
+  match:
+    - condition: resource.containers.size() > params.max_containers
+      output: "'too many containers'"
+    - condition: variables.disallowed.size() > 0
+      output: "'disallowed image: ' + variables.disallowed[0]"
+`
+
+func TestTemplateInstantiate(t *testing.T) {
+	tmpl := compileRegistriesTemplate(t)
+	if got := tmpl.Params(); !reflect.DeepEqual(got, []string{"allowed_registries", "max_containers"}) {
+		t.Errorf("Params() got %v, wanted [allowed_registries max_containers]", got)
+	}
+	internal, err := tmpl.Instantiate(map[string]any{
+		"allowed_registries": []string{"registry.internal"},
+		"max_containers":     2,
+	})
+	if err != nil {
+		t.Fatalf("Instantiate() failed: %v", err)
+	}
+	public, err := tmpl.Instantiate(map[string]any{
+		"allowed_registries": []string{"registry.internal", "docker.io"},
+		"max_containers":     1,
+	})
+	if err != nil {
+		t.Fatalf("Instantiate() failed: %v", err)
+	}
+	tests := []struct {
+		prg        cel.Program
+		containers []string
+		want       any
+	}{
+		{prg: internal, containers: []string{"registry.internal/app"}, want: types.OptionalNone},
+		{prg: internal, containers: []string{"docker.io/nginx"}, want: "disallowed image: docker.io/nginx"},
+		{prg: public, containers: []string{"docker.io/nginx"}, want: types.OptionalNone},
+		{prg: public, containers: []string{"docker.io/nginx", "docker.io/redis"}, want: "too many containers"},
+	}
+	for _, tc := range tests {
+		evalRegistries(t, tc.prg, tc.containers, tc.want)
+	}
+}
+
+func TestTemplateSpecialize(t *testing.T) {
+	tmpl := compileRegistriesTemplate(t)
+	bindings := map[string]any{
+		"allowed_registries": []string{"registry.internal"},
+		"max_containers":     2,
+	}
+	ast, err := tmpl.SpecializedAst(bindings)
+	if err != nil {
+		t.Fatalf("SpecializedAst() failed: %v", err)
+	}
+	folded := false
+	celast.PreOrderVisit(ast.NativeRep().Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		switch e.Kind() {
+		case celast.IdentKind:
+			if strings.HasPrefix(e.AsIdent(), paramPrefix) {
+				t.Errorf("SpecializedAst() got unfolded reference to %s", e.AsIdent())
+			}
+		case celast.LiteralKind:
+			folded = folded || e.AsLiteral() == types.String("registry.internal")
+		}
+	}))
+	if !folded {
+		t.Error("SpecializedAst() got no literal for the allowed_registries binding")
+	}
+	prg, err := tmpl.Specialize(bindings)
+	if err != nil {
+		t.Fatalf("Specialize() failed: %v", err)
+	}
+	evalRegistries(t, prg, []string{"registry.internal/app"}, types.OptionalNone)
+	evalRegistries(t, prg, []string{"a/b", "c/d", "e/f"}, "too many containers")
+	evalRegistries(t, prg, []string{"docker.io/nginx"}, "disallowed image: docker.io/nginx")
+}
+
+func TestTemplateParamsShadowing(t *testing.T) {
+	tmpl := compileRegistriesTemplate(t)
+	bindings := map[string]any{
+		"allowed_registries": []string{"registry.internal"},
+		"max_containers":     1,
+	}
+	instance, err := tmpl.Instantiate(bindings)
+	if err != nil {
+		t.Fatalf("Instantiate() failed: %v", err)
+	}
+	specialized, err := tmpl.Specialize(bindings)
+	if err != nil {
+		t.Fatalf("Specialize() failed: %v", err)
+	}
+	resource := map[string]any{"containers": []string{"docker.io/nginx", "docker.io/redis"}}
+	inputs := []map[string]any{
+		{
+			"resource":                  resource,
+			"params.max_containers":     10,
+			"params.allowed_registries": []string{"docker.io"},
+		},
+		{
+			"resource": resource,
+			"params": map[string]any{
+				"max_containers":     10,
+				"allowed_registries": []string{"docker.io"},
+			},
+		},
+	}
+	for _, prg := range []cel.Program{instance, specialized} {
+		for _, input := range inputs {
+			out, _, err := prg.Eval(input)
+			if err != nil {
+				t.Fatalf("prg.Eval(%v) failed: %v", input, err)
+			}
+			opt, isOpt := out.(*types.Optional)
+			if !isOpt || !opt.HasValue() || opt.GetValue().Value() != "too many containers" {
+				t.Errorf("prg.Eval(%v) got %v, wanted optional.of(too many containers)", input, out)
+			}
+		}
+	}
+}
+
+func TestTemplateBindErrors(t *testing.T) {
+	tmpl := compileRegistriesTemplate(t)
+	tests := []struct {
+		bindings map[string]any
+		err      string
+	}{
+		{
+			bindings: map[string]any{"allowed_registries": []string{}},
+			err:      "missing binding for param: max_containers",
+		},
+		{
+			bindings: map[string]any{"allowed_registries": []string{}, "max_containers": 1, "max_images": 2},
+			err:      "unknown param: max_images",
+		},
+		{
+			bindings: map[string]any{"allowed_registries": []int{1}, "max_containers": 1},
+			err:      "param allowed_registries: got value of type list(dyn), wanted list(string)",
+		},
+		{
+			bindings: map[string]any{"allowed_registries": []string{}, "max_containers": "1"},
+			err:      "param max_containers: got value of type string, wanted int",
+		},
+	}
+	for _, tc := range tests {
+		if _, err := tmpl.Instantiate(tc.bindings); err == nil || err.Error() != tc.err {
+			t.Errorf("Instantiate(%v) got error %v, wanted %s", tc.bindings, err, tc.err)
+		}
+		if _, err := tmpl.Specialize(tc.bindings); err == nil || err.Error() != tc.err {
+			t.Errorf("Specialize(%v) got error %v, wanted %s", tc.bindings, err, tc.err)
+		}
+	}
+}
+
+func TestCompileParamErrors(t *testing.T) {
+	tests := []struct {
+		policy string
+		err    string
+	}{
+		{
+			policy: `name: registries
+params:
+  - name: allowed
+    type: lisst<string>
+rule:
+  match:
+    - output: "1"`,
+			err: `ERROR: registries.celpolicy:3:5: invalid param type: undefined type name: "lisst"
+ |   - name: allowed
+ | ....^`,
+		},
+		{
+			policy: `name: registries
+params:
+  - name: allowed
+    type: int
+  - name: allowed
+    type: int
+rule:
+  match:
+    - output: "params.allowed"`,
+			err: `ERROR: registries.celpolicy:5:11: duplicate param: allowed
+ |   - name: allowed
+ | ..........^`,
+		},
+		{
+			policy: `name: registries
+params:
+  - name: max-containers
+    type: int
+rule:
+  match:
+    - output: "1"`,
+			err: `ERROR: registries.celpolicy:3:11: invalid param name: "max-containers"
+ |   - name: max-containers
+ | ..........^`,
+		},
+		{
+			policy: `name: registries
+params:
+  - name: allowed
+    type: list<string>
+rule:
+  match:
+    - output: "params.denied.size()"`,
+			err: `ERROR: registries.celpolicy:7:16: undeclared reference to 'params' (in container '')
+ |     - output: "params.denied.size()"
+ | ...............^`,
+		},
+	}
+	for _, tc := range tests {
+		policy := parsePolicySource(t, "registries.celpolicy", tc.policy)
+		_, iss := CompileTemplate(newRegistriesEnv(t), policy)
+		if iss.Err() == nil || iss.Err().Error() != tc.err {
+			t.Errorf("CompileTemplate() got error %v, wanted %s", iss.Err(), tc.err)
+		}
+	}
+}
+
+func TestMarshalParams(t *testing.T) {
+	policy := parsePolicySource(t, "registries.celpolicy", registriesPolicy)
+	out, err := Marshal(policy)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if string(out) != registriesPolicy {
+		t.Errorf("Marshal() got:\n%s\nwanted:\n%s", out, registriesPolicy)
+	}
+}
+
+func compileRegistriesTemplate(t testing.TB) *Template {
+	t.Helper()
+	policy := parsePolicySource(t, "registries.celpolicy", registriesPolicy)
+	tmpl, iss := CompileTemplate(newRegistriesEnv(t), policy)
+	if iss.Err() != nil {
+		t.Fatalf("CompileTemplate() failed: %v", iss.Err())
+	}
+	return tmpl
+}
+
+func newRegistriesEnv(t testing.TB) *cel.Env {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		cel.Variable("resource", cel.ObjectType("google.protobuf.Struct")),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	return env
+}
+
+func evalRegistries(t testing.TB, prg cel.Program, containers []string, want any) {
+	t.Helper()
+	out, _, err := prg.Eval(map[string]any{"resource": map[string]any{"containers": containers}})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	if want == types.OptionalNone {
+		if out != types.OptionalNone {
+			t.Errorf("prg.Eval(%v) got %v, wanted optional.none()", containers, out)
+		}
+		return
+	}
+	opt, isOpt := out.(*types.Optional)
+	if !isOpt || !opt.HasValue() || opt.GetValue().Value() != want {
+		t.Errorf("prg.Eval(%v) got %v, wanted optional.of(%v)", containers, out, want)
+	}
+}
@@ -16,6 +16,7 @@ package policy
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"go.yaml.in/yaml/v3"
@@ -23,6 +24,7 @@ import (
 	"cel.dev/cel-go/cel"
 	"cel.dev/cel-go/common"
 	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/env"
 )
 
 // SemanticType describes the evaluation semantic for a given policy block.
@@ -32,8 +34,38 @@ const (
 	unspecified SemanticType = iota
 	firstMatch
 	aggregate
+	denyOverrides
+	permitOverrides
+	priorityMatch
+	allMatch
 )
 
+// semanticKeys maps the YAML keys which declare the matches of a rule to their semantic.
+var semanticKeys = map[string]SemanticType{
+	"match":            firstMatch,
+	"aggregate":        aggregate,
+	"deny_overrides":   denyOverrides,
+	"permit_overrides": permitOverrides,
+	"priority":         priorityMatch,
+	"all":              allMatch,
+}
+
+// String returns the YAML key used to declare matches with the semantic.
+func (s SemanticType) String() string {
+	for key, sem := range semanticKeys {
+		if sem == s {
+			return key
+		}
+	}
+	return "match"
+}
+
+// isDecision indicates whether the semantic combines the bool outputs of all matching matches into
+// a single decision.
+func (s SemanticType) isDecision() bool {
+	return s == denyOverrides || s == permitOverrides || s == allMatch
+}
+
 // NewPolicy creates a policy object which references a policy source and source information.
 func NewPolicy(src *Source, info *ast.SourceInfo) *Policy {
 	return &Policy{
@@ -42,6 +74,8 @@ func NewPolicy(src *Source, info *ast.SourceInfo) *Policy {
 		info:     info,
 		semantic: unspecified,
 		imports:  []*Import{},
+		includes: []*Include{},
+		params:   []*Param{},
 	}
 }
 
@@ -50,6 +84,9 @@ type Policy struct {
 	name        ValueString
 	description ValueString
 	imports     []*Import
+	includes    []*Include
+	params      []*Param
+	outputType  *OutputType
 	rule        *Rule
 	semantic    SemanticType
 	info        *ast.SourceInfo
@@ -89,6 +126,21 @@ func (p *Policy) Imports() []*Import {
 	return p.imports
 }
 
+// Includes returns the list of policy sources included by the policy.
+func (p *Policy) Includes() []*Include {
+	return p.includes
+}
+
+// Params returns the list of parameters declared by the policy.
+func (p *Policy) Params() []*Param {
+	return p.params
+}
+
+// OutputType returns the declared type of the policy outputs, or nil if not set.
+func (p *Policy) OutputType() *OutputType {
+	return p.outputType
+}
+
 // Name returns the name of the policy.
 func (p *Policy) Name() ValueString {
 	return p.name
@@ -124,6 +176,16 @@ func (p *Policy) AddImport(i *Import) {
 	p.imports = append(p.imports, i)
 }
 
+// AddInclude adds an include to the policy.
+func (p *Policy) AddInclude(i *Include) {
+	p.includes = append(p.includes, i)
+}
+
+// AddParam adds a parameter declaration to the policy.
+func (p *Policy) AddParam(param *Param) {
+	p.params = append(p.params, param)
+}
+
 // SetName configures the policy name.
 func (p *Policy) SetName(name ValueString) {
 	p.name = name
@@ -134,6 +196,11 @@ func (p *Policy) SetDescription(description ValueString) {
 	p.description = description
 }
 
+// SetOutputType configures the declared type of the policy outputs.
+func (p *Policy) SetOutputType(t *OutputType) {
+	p.outputType = t
+}
+
 // SetRule configures the policy rule entry point.
 func (p *Policy) SetRule(r *Rule) {
 	p.rule = r
@@ -154,6 +221,7 @@ func (p *Policy) ClearMetadata(name string) {
 func (p *Policy) GetExplanationOutputPolicy() *Policy {
 	ep := Policy{
 		name:     p.name,
+		includes: p.includes,
 		semantic: p.semantic,
 		info:     p.info,
 		metadata: p.metadata,
@@ -191,6 +259,197 @@ func (i *Import) SetName(name ValueString) {
 	i.name = name
 }
 
+// NewInclude creates a new policy include node.
+func NewInclude(exprID int64) *Include {
+	return &Include{exprID: exprID}
+}
+
+// Include represents a reference to another policy source whose top-level rule variables are made
+// available within the including policy under the include name, e.g. `variables.lib.is_admin`.
+type Include struct {
+	exprID int64
+	name   ValueString
+	path   ValueString
+}
+
+// SourceID returns the source identifier associated with the include.
+func (i *Include) SourceID() int64 {
+	return i.exprID
+}
+
+// Name returns the namespace under which the included variables are declared.
+func (i *Include) Name() ValueString {
+	return i.name
+}
+
+// Path returns the reference to the included policy source, resolved by a SourceLoader.
+func (i *Include) Path() ValueString {
+	return i.path
+}
+
+// SetName updates the namespace for the include.
+func (i *Include) SetName(name ValueString) {
+	i.name = name
+}
+
+// SetPath updates the reference to the included policy source.
+func (i *Include) SetPath(path ValueString) {
+	i.path = path
+}
+
+// NewParam creates a new policy parameter declaration.
+func NewParam(exprID int64) *Param {
+	return &Param{exprID: exprID}
+}
+
+// Param declares a typed policy parameter which is referenced within CEL expressions as
+// `params.<name>` and bound to a value when the compiled policy is instantiated.
+//
+// The parameter type uses the syntax of the environment configuration, e.g. `type: list<string>`
+// or `type_name: list` with `params: [{type_name: string}]`.
+type Param struct {
+	exprID      int64
+	name        ValueString
+	description ValueString
+	typeDesc    *env.TypeDesc
+}
+
+// SourceID returns the source identifier associated with the parameter.
+func (p *Param) SourceID() int64 {
+	return p.exprID
+}
+
+// Name returns the name of the parameter.
+func (p *Param) Name() ValueString {
+	return p.name
+}
+
+// Description returns the description of the parameter.
+func (p *Param) Description() ValueString {
+	return p.description
+}
+
+// TypeDesc returns the declared type of the parameter, or nil if not set.
+func (p *Param) TypeDesc() *env.TypeDesc {
+	return p.typeDesc
+}
+
+// SetName updates the name of the parameter.
+func (p *Param) SetName(name ValueString) {
+	p.name = name
+}
+
+// SetDescription updates the description of the parameter.
+func (p *Param) SetDescription(description ValueString) {
+	p.description = description
+}
+
+// SetTypeDesc updates the declared type of the parameter.
+func (p *Param) SetTypeDesc(td *env.TypeDesc) {
+	p.typeDesc = td
+}
+
+// NewOutputType creates a new output type declaration.
+func NewOutputType(exprID int64) *OutputType {
+	return &OutputType{exprID: exprID}
+}
+
+// OutputType declares the type of the outputs of a policy or rule, either as a type description
+// or as a schema of the fields of a map-literal output.
+//
+// A type description uses the syntax of the environment configuration, e.g. `string`,
+// `google.expr.proto3.test.TestAllTypes`, or `{type_name: list, params: [{type_name: string}]}`.
+// A schema declares the fields of a `map(string, dyn)` output:
+//
+//	output_type:
+//	  fields:
+//	    - name: allowed
+//	      type_name: bool
+//	    - name: reason
+//	      type_name: string
+//	      optional: true
+//
+// Outputs checked against a schema must be map literals, possibly reached through conditionals or
+// variables, so that their keys can be validated.
+type OutputType struct {
+	exprID   int64
+	typeDesc *env.TypeDesc
+	fields   []*OutputField
+}
+
+// SourceID returns the source identifier associated with the output type.
+func (t *OutputType) SourceID() int64 {
+	return t.exprID
+}
+
+// TypeDesc returns the type description, or nil if the output type is a field schema.
+func (t *OutputType) TypeDesc() *env.TypeDesc {
+	return t.typeDesc
+}
+
+// Fields returns the fields of a map-literal output schema.
+func (t *OutputType) Fields() []*OutputField {
+	return t.fields
+}
+
+// SetTypeDesc sets the type description of the output type.
+func (t *OutputType) SetTypeDesc(td *env.TypeDesc) {
+	t.typeDesc = td
+}
+
+// AddField adds a field to the output schema.
+func (t *OutputType) AddField(f *OutputField) {
+	t.fields = append(t.fields, f)
+}
+
+// NewOutputField creates a new output schema field.
+func NewOutputField(exprID int64) *OutputField {
+	return &OutputField{exprID: exprID}
+}
+
+// OutputField declares a named field within a map-literal output schema.
+type OutputField struct {
+	exprID     int64
+	name       ValueString
+	optional   bool
+	outputType *OutputType
+}
+
+// SourceID returns the source identifier associated with the field.
+func (f *OutputField) SourceID() int64 {
+	return f.exprID
+}
+
+// Name returns the field name.
+func (f *OutputField) Name() ValueString {
+	return f.name
+}
+
+// IsOptional indicates whether the field may be omitted from the output.
+func (f *OutputField) IsOptional() bool {
+	return f.optional
+}
+
+// Type returns the type of the field value.
+func (f *OutputField) Type() *OutputType {
+	return f.outputType
+}
+
+// SetName sets the field name.
+func (f *OutputField) SetName(name ValueString) {
+	f.name = name
+}
+
+// SetOptional sets whether the field may be omitted from the output.
+func (f *OutputField) SetOptional(optional bool) {
+	f.optional = optional
+}
+
+// SetType sets the type of the field value.
+func (f *OutputField) SetType(t *OutputType) {
+	f.outputType = t
+}
+
 // NewRule creates a Rule instance.
 func NewRule(exprID int64) *Rule {
 	return &Rule{
@@ -209,6 +468,7 @@ type Rule struct {
 	variables   []*Variable
 	matches     []*Match
 	semantic    SemanticType
+	outputType  *OutputType
 }
 
 // Semantic returns the evaluation semantic for the rule.
@@ -248,6 +508,11 @@ func (r *Rule) Description() ValueString {
 	return ValueString{}
 }
 
+// OutputType returns the declared type of the rule outputs, or nil if not set.
+func (r *Rule) OutputType() *OutputType {
+	return r.outputType
+}
+
 // Matches returns the ordered set of Match declarations.
 func (r *Rule) Matches() []*Match {
 	return r.matches[:]
@@ -268,6 +533,11 @@ func (r *Rule) SetDescription(desc ValueString) {
 	r.description = &desc
 }
 
+// SetOutputType configures the declared type of the rule outputs.
+func (r *Rule) SetOutputType(t *OutputType) {
+	r.outputType = t
+}
+
 // AddMatch addes a Match to the rule.
 func (r *Rule) AddMatch(m *Match) {
 	r.matches = append(r.matches, m)
@@ -297,6 +567,7 @@ func (r *Rule) getExplanationOutputRule() *Rule {
 		em := Match{
 			condition: match.condition,
 			output:    match.explanation,
+			priority:  match.priority,
 			rule:      match.rule.getExplanationOutputRule(),
 		}
 		er.matches = append(er.matches, &em)
@@ -348,6 +619,7 @@ type Match struct {
 	condition   ValueString
 	output      *ValueString
 	explanation *ValueString
+	priority    *ValueInt
 	rule        *Rule
 }
 
@@ -387,6 +659,20 @@ func (m *Match) Explanation() ValueString {
 	return ValueString{}
 }
 
+// HasPriority indicates whether the priority field is set on the match.
+func (m *Match) HasPriority() bool {
+	return m.priority != nil
+}
+
+// Priority returns the priority of the match under the priority semantic, or a zero value if the
+// priority is not set.
+func (m *Match) Priority() ValueInt {
+	if m.HasPriority() {
+		return *m.priority
+	}
+	return ValueInt{}
+}
+
 // HasRule indicates whether the rule field is set on a match.
 func (m *Match) HasRule() bool {
 	return m.rule != nil
@@ -412,6 +698,11 @@ func (m *Match) SetExplanation(e ValueString) {
 	m.explanation = &e
 }
 
+// SetPriority sets the priority of the match.
+func (m *Match) SetPriority(p ValueInt) {
+	m.priority = &p
+}
+
 // SetRule sets the rule for the match.
 func (m *Match) SetRule(r *Rule) {
 	m.rule = r
@@ -423,6 +714,12 @@ type ValueString struct {
 	Value string
 }
 
+// ValueInt contains an identifier corresponding to source metadata and an integer value.
+type ValueInt struct {
+	ID    int64
+	Value int64
+}
+
 // ParserContext declares a set of interfaces for creating and managing metadata for parsed policies.
 type ParserContext interface {
 	// NextID returns a monotonically increasing identifier for a source fragment.
@@ -570,11 +867,30 @@ func NewParser(opts ...ParserOption) (*Parser, error) {
 // where they occur within the file, thus making error messages relative to the whole
 // file rather than the individual expression.
 func (parser *Parser) Parse(src *Source) (*Policy, *cel.Issues) {
+	return parser.parse(src, nil)
+}
+
+// parse generates a policy from the source. The source is parsed as YAML unless a decode function
+// is provided, in which case the decoded document node is used instead. Errors returned by the
+// decode function are reported at the given code point offset.
+func (parser *Parser) parse(src *Source, decode func(*Source) (*yaml.Node, int32, error)) (*Policy, *cel.Issues) {
 	info := ast.NewSourceInfo(src)
 	errs := common.NewErrors(src)
 	iss := cel.NewIssuesWithSourceInfo(errs, info)
 	p := newParserImpl(parser.TagVisitor, info, src, iss, parser.inlineStyleVariables)
-	policy := p.parseYAML(src)
+	var policy *Policy
+	if decode == nil {
+		policy = p.parseYAML(src)
+	} else {
+		node, offset, err := decode(src)
+		if err != nil {
+			id := p.NextID()
+			info.SetOffsetRange(id, ast.OffsetRange{Start: offset, Stop: offset})
+			iss.ReportErrorAtID(id, "%s", err.Error())
+			return nil, iss
+		}
+		policy = p.ParsePolicy(p, node)
+	}
 	if iss.Err() != nil {
 		return nil, iss
 	}
@@ -683,30 +999,35 @@ func (p *parserImpl) NewString(node *yaml.Node) ValueString {
 		return ValueString{ID: id, Value: node.Value}
 	}
 	if node.Style == yaml.FoldedStyle || node.Style == yaml.LiteralStyle {
-		col := node.Column
-		line := node.Line
-		txt, found := p.src.Snippet(line)
-		indent := ""
-		for len(indent) < col-1 {
-			indent += " "
-		}
-		var raw strings.Builder
-		for found && strings.HasPrefix(txt, indent) {
-			line++
-			raw.WriteString(txt)
-			txt, found = p.src.Snippet(line)
-			if found && strings.HasPrefix(txt, indent) {
-				raw.WriteString("\n")
-			}
-		}
+		raw := blockScalarText(p.src, node.Line, node.Column)
 		offset := p.info.OffsetRanges()[p.id]
 		offsetStart := offset.Start - (int32(node.Column) - 1)
 		p.info.SetOffsetRange(p.id, ast.OffsetRange{Start: offsetStart, Stop: offsetStart})
-		return ValueString{ID: id, Value: raw.String()}
+		return ValueString{ID: id, Value: raw}
 	}
 	return ValueString{ID: id, Value: node.Value}
 }
 
+// blockScalarText returns the raw source text of a literal or folded block scalar, starting at the
+// given line and including all subsequent lines indented to at least the given column.
+//
+// The raw text is used in place of the YAML value so that expression offsets line up with the
+// source.
+func blockScalarText(src *Source, line, col int) string {
+	txt, found := src.Snippet(line)
+	indent := strings.Repeat(" ", max(col-1, 0))
+	var raw strings.Builder
+	for found && strings.HasPrefix(txt, indent) {
+		line++
+		raw.WriteString(txt)
+		txt, found = src.Snippet(line)
+		if found && strings.HasPrefix(txt, indent) {
+			raw.WriteString("\n")
+		}
+	}
+	return raw.String()
+}
+
 // newStrictString creates a new ValueString from the YAML node, but as a string with no special
 // source position information. Intended for use in descriptions, where the string is just
 // a human-readable string for presentation.
@@ -750,6 +1071,12 @@ func (p *parserImpl) ParsePolicy(ctx ParserContext, node *yaml.Node) *Policy {
 		switch fieldName {
 		case "imports":
 			p.parseImports(ctx, policy, val)
+		case "include":
+			p.parseIncludes(ctx, policy, val)
+		case "params":
+			p.parseParams(ctx, policy, val)
+		case "output_type":
+			policy.SetOutputType(p.parseOutputType(ctx, val))
 		case "name":
 			policy.SetName(ctx.NewString(val))
 		case "description":
@@ -795,6 +1122,178 @@ func (p *parserImpl) parseImport(ctx ParserContext, _ *Policy, node *yaml.Node)
 	return imp
 }
 
+func (p *parserImpl) parseIncludes(ctx ParserContext, policy *Policy, node *yaml.Node) {
+	id := ctx.CollectMetadata(node)
+	if p.assertYAMLType(id, node, yamlList) == nil {
+		return
+	}
+	for _, val := range node.Content {
+		policy.AddInclude(p.parseInclude(ctx, policy, val))
+	}
+}
+
+func (p *parserImpl) parseInclude(ctx ParserContext, _ *Policy, node *yaml.Node) *Include {
+	id := ctx.CollectMetadata(node)
+	inc := NewInclude(id)
+	if p.assertYAMLType(id, node, yamlMap) == nil || !p.checkMapValid(ctx, id, node) {
+		return inc
+	}
+	p.RangeMap(node, func(key *yaml.Node, val *yaml.Node) bool {
+		keyID := ctx.CollectMetadata(key)
+		fieldName := key.Value
+		switch fieldName {
+		case "name":
+			inc.SetName(ctx.NewString(val))
+		case "path":
+			inc.SetPath(ctx.NewString(val))
+		default:
+			p.ReportErrorAtID(keyID, "unsupported include tag: %s", fieldName)
+		}
+		return true
+	})
+	if inc.Name().Value == "" || inc.Path().Value == "" {
+		p.ReportErrorAtID(id, "include must specify a name and a path")
+	}
+	return inc
+}
+
+func (p *parserImpl) parseParams(ctx ParserContext, policy *Policy, node *yaml.Node) {
+	id := ctx.CollectMetadata(node)
+	if p.assertYAMLType(id, node, yamlList) == nil {
+		return
+	}
+	for _, val := range node.Content {
+		policy.AddParam(p.parseParam(ctx, policy, val))
+	}
+}
+
+func (p *parserImpl) parseParam(ctx ParserContext, _ *Policy, node *yaml.Node) *Param {
+	id := ctx.CollectMetadata(node)
+	param := NewParam(id)
+	if p.assertYAMLType(id, node, yamlMap) == nil || !p.checkMapValid(ctx, id, node) {
+		return param
+	}
+	p.RangeMap(node, func(key, val *yaml.Node) bool {
+		keyID := ctx.CollectMetadata(key)
+		switch key.Value {
+		case "name":
+			param.SetName(ctx.NewString(val))
+		case "description":
+			param.SetDescription(p.newStrictString(val))
+		case "type", "type_name", "params", "is_type_param", "values":
+			// The parameter type is decoded below.
+		default:
+			p.ReportErrorAtID(keyID, "unsupported param tag: %s", key.Value)
+		}
+		return true
+	})
+	var decl env.Variable
+	if err := node.Decode(&decl); err != nil {
+		p.ReportErrorAtID(id, "invalid param: %s", err)
+		return param
+	}
+	td := decl.GetType()
+	if param.Name().Value == "" || td == nil {
+		p.ReportErrorAtID(id, "param must specify a name and a type")
+		return param
+	}
+	if err := td.Validate(); err != nil {
+		p.ReportErrorAtID(id, "invalid param type: %s", err)
+		return param
+	}
+	param.SetTypeDesc(td)
+	return param
+}
+
+func (p *parserImpl) parseOutputType(ctx ParserContext, node *yaml.Node) *OutputType {
+	id := ctx.CollectMetadata(node)
+	t := NewOutputType(id)
+	nodeType := p.assertYAMLType(id, node, yamlString, yamlMap)
+	if nodeType == nil {
+		return t
+	}
+	if *nodeType == yamlString {
+		t.SetTypeDesc(&env.TypeDesc{TypeName: node.Value})
+		return t
+	}
+	if !p.checkMapValid(ctx, id, node) {
+		return t
+	}
+	hasTypeName := false
+	p.RangeMap(node, func(key, val *yaml.Node) bool {
+		keyID := ctx.CollectMetadata(key)
+		switch key.Value {
+		case "type_name":
+			hasTypeName = true
+		case "fields":
+			p.parseOutputFields(ctx, t, val)
+		case "params", "is_type_param", "values", "name", "optional":
+			// Type description fields are decoded below, and field attributes are handled by
+			// parseOutputField.
+		default:
+			p.ReportErrorAtID(keyID, "unsupported output_type tag: %s", key.Value)
+		}
+		return true
+	})
+	if !hasTypeName {
+		if len(t.Fields()) == 0 {
+			p.ReportErrorAtID(id, "output_type must specify a type_name or fields")
+		}
+		return t
+	}
+	if len(t.Fields()) != 0 {
+		p.ReportErrorAtID(id, "output_type may specify either a type_name or fields, but not both")
+		return t
+	}
+	td := &env.TypeDesc{}
+	if err := node.Decode(td); err != nil {
+		p.ReportErrorAtID(id, "invalid output_type: %s", err)
+		return t
+	}
+	if err := td.Validate(); err != nil {
+		p.ReportErrorAtID(id, "invalid output_type: %s", err)
+		return t
+	}
+	t.SetTypeDesc(td)
+	return t
+}
+
+func (p *parserImpl) parseOutputFields(ctx ParserContext, t *OutputType, node *yaml.Node) {
+	id := ctx.CollectMetadata(node)
+	if p.assertYAMLType(id, node, yamlList) == nil {
+		return
+	}
+	for _, val := range node.Content {
+		t.AddField(p.parseOutputField(ctx, val))
+	}
+}
+
+func (p *parserImpl) parseOutputField(ctx ParserContext, node *yaml.Node) *OutputField {
+	id := ctx.CollectMetadata(node)
+	f := NewOutputField(id)
+	if p.assertYAMLType(id, node, yamlMap) == nil || !p.checkMapValid(ctx, id, node) {
+		return f
+	}
+	p.RangeMap(node, func(key, val *yaml.Node) bool {
+		ctx.CollectMetadata(key)
+		switch key.Value {
+		case "name":
+			f.SetName(ctx.NewString(val))
+		case "optional":
+			valID := ctx.CollectMetadata(val)
+			if p.assertYAMLType(valID, val, yamlBool) != nil {
+				f.SetOptional(val.Value == "true")
+			}
+		}
+		return true
+	})
+	if f.Name().Value == "" {
+		p.ReportErrorAtID(id, "output_type field must specify a name")
+	}
+	f.SetType(p.parseOutputType(ctx, node))
+	return f
+}
+
 // ParseRule will parse the current yaml node as though it is the entry point to a rule.
 func (p *parserImpl) ParseRule(ctx ParserContext, policy *Policy, node *yaml.Node) *Rule {
 	r, id := ctx.NewRule(node)
@@ -811,13 +1310,13 @@ func (p *parserImpl) ParseRule(ctx ParserContext, policy *Policy, node *yaml.Nod
 			r.SetDescription(ctx.NewString(val))
 		case "variables":
 			p.parseVariables(ctx, policy, r, val)
-		case "match", "aggregate":
-			sem := firstMatch
-			if fieldName == "aggregate" {
-				sem = aggregate
-			}
+		case "output_type":
+			r.SetOutputType(p.parseOutputType(ctx, val))
+		case "match", "aggregate", "deny_overrides", "permit_overrides", "priority", "all":
+			sem := semanticKeys[fieldName]
 			if r.semantic != unspecified && r.semantic != sem {
-				p.ReportErrorAtID(tagID, "Only one of 'match' or 'aggregate' may be set in a rule")
+				p.ReportErrorAtID(tagID,
+					"Only one of 'match', 'aggregate', 'deny_overrides', 'permit_overrides', 'priority', or 'all' may be set in a rule")
 			} else {
 				r.SetSemantic(sem)
 				policy.SetSemantic(sem)
@@ -924,7 +1423,9 @@ func (p *parserImpl) parseMatchInternal(ctx ParserContext, policy *Policy, r *Ru
 				p.ReportErrorAtID(keyID, "explanation can only be set on output match cases, not nested rules")
 			}
 			m.SetExplanation(ctx.NewString(val))
-		case "rule", "match", "aggregate":
+		case "priority":
+			p.parseMatchPriority(ctx, m, val)
+		case "rule", "match", "aggregate", "deny_overrides", "permit_overrides", "all":
 			if m.HasOutput() {
 				p.ReportErrorAtID(keyID, "only the rule or the output may be set")
 			}
@@ -943,6 +1444,19 @@ func (p *parserImpl) parseMatchInternal(ctx ParserContext, policy *Policy, r *Ru
 	return m
 }
 
+func (p *parserImpl) parseMatchPriority(ctx ParserContext, m *Match, node *yaml.Node) {
+	id := ctx.CollectMetadata(node)
+	if p.assertYAMLType(id, node, yamlInt) == nil {
+		return
+	}
+	priority, err := strconv.ParseInt(node.Value, 0, 64)
+	if err != nil {
+		p.ReportErrorAtID(id, "invalid priority: %s", node.Value)
+		return
+	}
+	m.SetPriority(ValueInt{ID: id, Value: priority})
+}
+
 func (p *parserImpl) assertYAMLType(id int64, node *yaml.Node, nodeTypes ...yamlNodeType) *yamlNodeType {
 	nt, found := yamlTypes[node.LongTag()]
 	if !found {
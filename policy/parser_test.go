@@ -65,6 +65,70 @@ rule:
 		},
 		{
 			txt: `
+rule:
+  priority:
+    - output: "true"
+      priority: high`,
+			err: `ERROR: <input>:5:17: got yaml node type tag:yaml.org,2002:str, wanted type(s) [tag:yaml.org,2002:int]
+ |       priority: high
+ | ................^`,
+		},
+		{
+			txt: `
+output_type:
+  type_name: list
+  fields:
+    - type_name: string
+      optional: maybe
+  param: string`,
+			err: `ERROR: <input>:3:3: output_type may specify either a type_name or fields, but not both
+ |   type_name: list
+ | ..^
+ERROR: <input>:5:7: output_type field must specify a name
+ |     - type_name: string
+ | ......^
+ERROR: <input>:6:17: got yaml node type tag:yaml.org,2002:str, wanted type(s) [tag:yaml.org,2002:bool]
+ |       optional: maybe
+ | ................^
+ERROR: <input>:7:3: unsupported output_type tag: param
+ |   param: string
+ | ..^`,
+		},
+		{
+			txt: `
+include:
+  - name: lib
+    source: lib.yaml`,
+			err: `ERROR: <input>:3:5: include must specify a name and a path
+ |   - name: lib
+ | ....^
+ERROR: <input>:4:5: unsupported include tag: source
+ |     source: lib.yaml
+ | ....^`,
+		},
+		{
+			txt: `
+params:
+  - name: registries
+    kind: list`,
+			err: `ERROR: <input>:3:5: param must specify a name and a type
+ |   - name: registries
+ | ....^
+ERROR: <input>:4:5: unsupported param tag: kind
+ |     kind: list
+ | ....^`,
+		},
+		{
+			txt: `
+params:
+  - name: registries
+    type: list(`,
+			err: `ERROR: <input>:3:5: invalid param: unexpected character '(' at position 4 in "list("
+ |   - name: registries
+ | ....^`,
+		},
+		{
+			txt: `
 inputs:
   - name: a
   - name: b`,
@@ -154,7 +218,7 @@ rule:
   aggregate:
     - condition: "true"
       output: "'bar'"`,
-			err: `ERROR: <input>:6:3: Only one of 'match' or 'aggregate' may be set in a rule
+			err: `ERROR: <input>:6:3: Only one of 'match', 'aggregate', 'deny_overrides', 'permit_overrides', 'priority', or 'all' may be set in a rule
  |   aggregate:
  | ..^`,
 		},
@@ -238,7 +302,7 @@ rule:
     - output: 'true'
   aggregate:
     - output: 'true'`,
-			err: `ERROR: <input>:6:3: Only one of 'match' or 'aggregate' may be set in a rule
+			err: `ERROR: <input>:6:3: Only one of 'match', 'aggregate', 'deny_overrides', 'permit_overrides', 'priority', or 'all' may be set in a rule
  |   aggregate:
  | ..^`,
 		},
@@ -250,7 +314,7 @@ rule:
     - output: 'true'
   match:
     - output: 'true'`,
-			err: `ERROR: <input>:6:3: Only one of 'match' or 'aggregate' may be set in a rule
+			err: `ERROR: <input>:6:3: Only one of 'match', 'aggregate', 'deny_overrides', 'permit_overrides', 'priority', or 'all' may be set in a rule
  |   match:
  | ..^`,
 		},
@@ -0,0 +1,420 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry manages a set of named policy programs which may be reloaded while they are
+// being evaluated.
+//
+// A Registry compiles every policy provided by a Loader into an immutable Snapshot. Reloading
+// compiles a new snapshot and swaps it in atomically, so evaluations observe either the old or the
+// new set of policies, but never a mix of the two. When any policy fails to compile, the reload is
+// rejected and the previous snapshot remains in service.
+//
+// A new snapshot may also be staged rather than swapped in. While a snapshot is staged, each
+// evaluation is also performed against the staged version of the policy and any difference in the
+// decisions is reported to the configured ShadowReporter, so the new version can be validated
+// against live traffic before it is promoted.
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/policy"
+)
+
+// Loader provides the policy sources managed by a Registry.
+type Loader interface {
+	// Load returns the current set of policy sources.
+	//
+	// Load is called once for each reload, so the sources should reflect the latest state of the
+	// underlying storage.
+	Load() ([]*policy.Source, error)
+}
+
+// NewFileLoader creates a Loader which reads the policy files matching the glob pattern, e.g.
+// `*.celpolicy` or `*/policy.yaml`, from the file system.
+//
+// The pattern is evaluated on every load, so added and removed files are picked up on reload.
+func NewFileLoader(fsys fs.FS, pattern string) Loader {
+	return &fileLoader{fsys: fsys, pattern: pattern}
+}
+
+type fileLoader struct {
+	fsys    fs.FS
+	pattern string
+}
+
+// Load implements the Loader interface method.
+func (l *fileLoader) Load() ([]*policy.Source, error) {
+	paths, err := fs.Glob(l.fsys, l.pattern)
+	if err != nil {
+		return nil, err
+	}
+	srcs := make([]*policy.Source, 0, len(paths))
+	for _, path := range paths {
+		data, err := fs.ReadFile(l.fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, policy.ByteSource(data, path))
+	}
+	return srcs, nil
+}
+
+// MapLoader is an in-memory Loader of policy sources keyed by their description.
+type MapLoader map[string]string
+
+// Load implements the Loader interface method.
+//
+// Sources are returned in the order of their descriptions.
+func (l MapLoader) Load() ([]*policy.Source, error) {
+	descs := make([]string, 0, len(l))
+	for desc := range l {
+		descs = append(descs, desc)
+	}
+	sort.Strings(descs)
+	srcs := make([]*policy.Source, 0, len(l))
+	for _, desc := range descs {
+		srcs = append(srcs, policy.StringSource(l[desc], desc))
+	}
+	return srcs, nil
+}
+
+// Entry is a compiled policy within a Snapshot.
+type Entry struct {
+	// Name is the name of the policy.
+	Name string
+
+	// Policy is the parsed policy.
+	Policy *policy.Policy
+
+	// Ast is the checked expression composed from the policy.
+	Ast *cel.Ast
+
+	// Program is the program planned from the Ast.
+	Program cel.Program
+
+	// Metadata contains the metadata declared within the policy, if any.
+	Metadata map[string]any
+}
+
+// Snapshot is an immutable set of compiled policies, keyed by policy name.
+type Snapshot struct {
+	version int64
+	entries map[string]*Entry
+	names   []string
+}
+
+// Version returns the version of the snapshot. Versions increase with each successful compilation.
+func (s *Snapshot) Version() int64 {
+	return s.version
+}
+
+// Get returns the policy entry with the given name, if present.
+func (s *Snapshot) Get(name string) (*Entry, bool) {
+	e, found := s.entries[name]
+	return e, found
+}
+
+// Names returns the names of the policies within the snapshot in sorted order.
+func (s *Snapshot) Names() []string {
+	return s.names
+}
+
+// Diff describes an evaluation in which the staged version of a policy produced a different
+// decision than the current version.
+type Diff struct {
+	// Name is the name of the policy.
+	Name string
+
+	// Input is the activation against which the policy was evaluated.
+	Input any
+
+	// Version is the version of the current snapshot.
+	Version int64
+
+	// Result and Err are the outcome of the current version of the policy.
+	Result ref.Val
+	Err    error
+
+	// StagedVersion is the version of the staged snapshot.
+	StagedVersion int64
+
+	// StagedResult and StagedErr are the outcome of the staged version of the policy, or nil when
+	// the policy is absent from the staged snapshot.
+	StagedResult ref.Val
+	StagedErr    error
+}
+
+// ShadowReporter receives the decision differences observed while a snapshot is staged.
+//
+// The reporter is called synchronously from Eval, and may be called concurrently.
+type ShadowReporter func(*Diff)
+
+// Option is a functional option for configuring a Registry.
+type Option func(*Registry) (*Registry, error)
+
+// ParserOptions configures the options used to parse each policy.
+func ParserOptions(opts ...policy.ParserOption) Option {
+	return func(r *Registry) (*Registry, error) {
+		r.parserOpts = append(r.parserOpts, opts...)
+		return r, nil
+	}
+}
+
+// CompilerOptions configures the options used to compile each policy, e.g. policy.IncludeLoader.
+func CompilerOptions(opts ...policy.CompilerOption) Option {
+	return func(r *Registry) (*Registry, error) {
+		r.compilerOpts = append(r.compilerOpts, opts...)
+		return r, nil
+	}
+}
+
+// ProgramOptions configures the options used to plan each policy program.
+func ProgramOptions(opts ...cel.ProgramOption) Option {
+	return func(r *Registry) (*Registry, error) {
+		r.programOpts = append(r.programOpts, opts...)
+		return r, nil
+	}
+}
+
+// Shadow configures the reporter which receives the decision differences between the current and
+// staged versions of a policy.
+func Shadow(reporter ShadowReporter) Option {
+	return func(r *Registry) (*Registry, error) {
+		if reporter == nil {
+			return nil, errors.New("shadow reporter must not be nil")
+		}
+		r.reporter = reporter
+		return r, nil
+	}
+}
+
+// Registry is a hot-reloadable set of named policy programs.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	env          *cel.Env
+	loader       Loader
+	parserOpts   []policy.ParserOption
+	compilerOpts []policy.CompilerOption
+	programOpts  []cel.ProgramOption
+	reporter     ShadowReporter
+
+	// mu serializes compilation and the transitions between the current and staged snapshots.
+	mu          sync.Mutex
+	nextVersion int64
+	current     atomic.Pointer[Snapshot]
+	staged      atomic.Pointer[Snapshot]
+}
+
+// NewRegistry creates a Registry which compiles the policies provided by the loader against the
+// environment.
+//
+// The initial load must succeed, so the registry always has a snapshot in service.
+func NewRegistry(env *cel.Env, loader Loader, opts ...Option) (*Registry, error) {
+	r := &Registry{env: env, loader: loader}
+	var err error
+	for _, opt := range opts {
+		r, err = opt(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Current returns the snapshot in service.
+func (r *Registry) Current() *Snapshot {
+	return r.current.Load()
+}
+
+// Staged returns the staged snapshot, or nil if no snapshot is staged.
+func (r *Registry) Staged() *Snapshot {
+	return r.staged.Load()
+}
+
+// Reload compiles the policies from the loader and swaps them into service.
+//
+// On error, the snapshot in service is retained. A staged snapshot is discarded on success.
+func (r *Registry) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, err := r.compile()
+	if err != nil {
+		return err
+	}
+	r.current.Store(s)
+	r.staged.Store(nil)
+	return nil
+}
+
+// Stage compiles the policies from the loader and stages them for shadow evaluation alongside the
+// snapshot in service. Any previously staged snapshot is replaced.
+//
+// On error, the staged snapshot, if any, is retained.
+func (r *Registry) Stage() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, err := r.compile()
+	if err != nil {
+		return err
+	}
+	r.staged.Store(s)
+	return nil
+}
+
+// Promote swaps the staged snapshot into service.
+func (r *Registry) Promote() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.staged.Load()
+	if s == nil {
+		return errors.New("no snapshot is staged")
+	}
+	r.current.Store(s)
+	r.staged.Store(nil)
+	return nil
+}
+
+// Discard drops the staged snapshot, if any.
+func (r *Registry) Discard() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.staged.Store(nil)
+}
+
+// Eval evaluates the named policy from the snapshot in service against the input.
+//
+// When a snapshot is staged and a ShadowReporter is configured, the staged version of the policy
+// is evaluated against the same input and any difference in the decisions is reported. The staged
+// outcome never affects the returned result.
+func (r *Registry) Eval(name string, vars any) (ref.Val, *cel.EvalDetails, error) {
+	cur := r.current.Load()
+	e, found := cur.Get(name)
+	if !found {
+		return nil, nil, fmt.Errorf("no such policy: %s", name)
+	}
+	act, err := cel.NewActivation(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, det, err := e.Program.Eval(act)
+	if staged := r.staged.Load(); staged != nil && r.reporter != nil {
+		r.shadow(cur, staged, name, vars, act, out, err)
+	}
+	return out, det, err
+}
+
+func (r *Registry) shadow(cur, staged *Snapshot, name string, vars any, act cel.Activation, out ref.Val, err error) {
+	d := &Diff{
+		Name:          name,
+		Input:         vars,
+		Version:       cur.Version(),
+		Result:        out,
+		Err:           err,
+		StagedVersion: staged.Version(),
+	}
+	if e, found := staged.Get(name); found {
+		d.StagedResult, _, d.StagedErr = e.Program.Eval(act)
+	}
+	if !sameDecision(d) {
+		r.reporter(d)
+	}
+}
+
+// sameDecision indicates whether the current and staged versions of a policy agree. Errors are
+// compared by message.
+func sameDecision(d *Diff) bool {
+	if d.Err != nil || d.StagedErr != nil {
+		return d.Err != nil && d.StagedErr != nil && d.Err.Error() == d.StagedErr.Error()
+	}
+	if d.Result == nil || d.StagedResult == nil {
+		return d.Result == d.StagedResult
+	}
+	return d.Result.Equal(d.StagedResult) == types.True
+}
+
+func (r *Registry) compile() (*Snapshot, error) {
+	srcs, err := r.loader.Load()
+	if err != nil {
+		return nil, err
+	}
+	parser, err := policy.NewParser(r.parserOpts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{entries: make(map[string]*Entry, len(srcs))}
+	var errs []error
+	for _, src := range srcs {
+		e, err := r.compileEntry(parser, src)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, found := s.entries[e.Name]; found {
+			errs = append(errs, fmt.Errorf("%s: duplicate policy name: %s", src.Description(), e.Name))
+			continue
+		}
+		s.entries[e.Name] = e
+		s.names = append(s.names, e.Name)
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	sort.Strings(s.names)
+	r.nextVersion++
+	s.version = r.nextVersion
+	return s, nil
+}
+
+func (r *Registry) compileEntry(parser *policy.Parser, src *policy.Source) (*Entry, error) {
+	p, iss := parser.Parse(src)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	name := p.Name().Value
+	if name == "" {
+		return nil, fmt.Errorf("%s: policy must declare a name", src.Description())
+	}
+	ast, iss := policy.Compile(r.env, p, r.compilerOpts...)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	prg, err := r.env.Program(ast, r.programOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Description(), err)
+	}
+	metadata := make(map[string]any, len(p.MetadataKeys()))
+	for _, key := range p.MetadataKeys() {
+		metadata[key], _ = p.Metadata(key)
+	}
+	return &Entry{
+		Name:     name,
+		Policy:   p,
+		Ast:      ast,
+		Program:  prg,
+		Metadata: metadata,
+	}, nil
+}
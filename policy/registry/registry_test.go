@@ -0,0 +1,276 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"go.yaml.in/yaml/v3"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/ext"
+	"cel.dev/cel-go/policy"
+)
+
+const (
+	sizePolicy = `
+name: size
+owner: storage
+rule:
+  match:
+    - condition: request.size > 10
+      output: "'deny'"
+    - output: "'allow'"
+`
+	sizePolicyV2 = `
+name: size
+owner: storage
+rule:
+  match:
+    - condition: request.size > 20
+      output: "'deny'"
+    - output: "'allow'"
+`
+	rolePolicy = `
+name: role
+rule:
+  match:
+    - condition: request.role == 'admin'
+      output: "'allow'"
+    - output: "'deny'"
+`
+	brokenPolicy = `
+name: size
+rule:
+  match:
+    - condition: req.size > 10
+      output: "'deny'"
+`
+)
+
+func TestRegistryEval(t *testing.T) {
+	r := newTestRegistry(t, MapLoader{"size.celpolicy": sizePolicy, "role.celpolicy": rolePolicy})
+	if got := r.Current().Names(); !reflect.DeepEqual(got, []string{"role", "size"}) {
+		t.Errorf("Names() got %v, wanted [role size]", got)
+	}
+	out, _, err := r.Eval("size", map[string]any{"request": map[string]any{"size": 15}})
+	if err != nil {
+		t.Fatalf("Eval() failed: %v", err)
+	}
+	if out.Value() != "deny" {
+		t.Errorf("Eval() got %v, wanted deny", out)
+	}
+	if _, _, err := r.Eval("missing", map[string]any{}); err == nil {
+		t.Error("Eval() of an unknown policy succeeded, wanted error")
+	}
+	e, found := r.Current().Get("size")
+	if !found {
+		t.Fatal("Get(size) not found")
+	}
+	if !reflect.DeepEqual(e.Metadata, map[string]any{"owner": "storage"}) {
+		t.Errorf("Metadata got %v, wanted owner: storage", e.Metadata)
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	loader := MapLoader{"size.celpolicy": sizePolicy}
+	r := newTestRegistry(t, loader)
+	v1 := r.Current()
+
+	// A failed reload retains the snapshot in service.
+	loader["size.celpolicy"] = brokenPolicy
+	err := r.Reload()
+	if err == nil || !strings.Contains(err.Error(), "undeclared reference to 'req'") {
+		t.Fatalf("Reload() got error %v, wanted compile error", err)
+	}
+	if r.Current() != v1 {
+		t.Error("Reload() replaced the current snapshot after a compile error")
+	}
+
+	loader["size.celpolicy"] = sizePolicyV2
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+	if r.Current().Version() <= v1.Version() {
+		t.Errorf("Reload() got version %d, wanted greater than %d", r.Current().Version(), v1.Version())
+	}
+	out, _, err := r.Eval("size", map[string]any{"request": map[string]any{"size": 15}})
+	if err != nil {
+		t.Fatalf("Eval() failed: %v", err)
+	}
+	if out.Value() != "allow" {
+		t.Errorf("Eval() got %v, wanted allow", out)
+	}
+
+	// Duplicate policy names are rejected.
+	loader["other.celpolicy"] = sizePolicy
+	err = r.Reload()
+	if err == nil || !strings.Contains(err.Error(), "duplicate policy name: size") {
+		t.Errorf("Reload() got error %v, wanted duplicate name error", err)
+	}
+}
+
+func TestRegistryConcurrentReload(t *testing.T) {
+	loader := MapLoader{"size.celpolicy": sizePolicy}
+	r := newTestRegistry(t, loader)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				out, _, err := r.Eval("size", map[string]any{"request": map[string]any{"size": 15}})
+				if err != nil {
+					t.Errorf("Eval() failed: %v", err)
+					return
+				}
+				if out.Value() != "deny" && out.Value() != "allow" {
+					t.Errorf("Eval() got %v, wanted deny or allow", out)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := r.Reload(); err != nil {
+			t.Errorf("Reload() failed: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestRegistryShadow(t *testing.T) {
+	var mu sync.Mutex
+	var diffs []*Diff
+	loader := MapLoader{"size.celpolicy": sizePolicy}
+	r := newTestRegistry(t, loader, Shadow(func(d *Diff) {
+		mu.Lock()
+		defer mu.Unlock()
+		diffs = append(diffs, d)
+	}))
+	if err := r.Promote(); err == nil {
+		t.Error("Promote() without a staged snapshot succeeded, wanted error")
+	}
+	loader["size.celpolicy"] = sizePolicyV2
+	if err := r.Stage(); err != nil {
+		t.Fatalf("Stage() failed: %v", err)
+	}
+	for _, size := range []int{5, 15, 25} {
+		out, _, err := r.Eval("size", map[string]any{"request": map[string]any{"size": size}})
+		if err != nil {
+			t.Fatalf("Eval() failed: %v", err)
+		}
+		// The current version remains in service while staged.
+		want := "allow"
+		if size > 10 {
+			want = "deny"
+		}
+		if out.Value() != want {
+			t.Errorf("Eval(size: %d) got %v, wanted %s", size, out, want)
+		}
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("Shadow() got %d diffs, wanted 1", len(diffs))
+	}
+	d := diffs[0]
+	if d.Result.Value() != "deny" || d.StagedResult.Value() != "allow" || d.StagedVersion != r.Staged().Version() {
+		t.Errorf("Shadow() got diff %v -> %v at version %d, wanted deny -> allow", d.Result, d.StagedResult, d.StagedVersion)
+	}
+
+	staged := r.Staged()
+	if err := r.Promote(); err != nil {
+		t.Fatalf("Promote() failed: %v", err)
+	}
+	if r.Current() != staged || r.Staged() != nil {
+		t.Error("Promote() did not swap the staged snapshot into service")
+	}
+	loader["size.celpolicy"] = sizePolicy
+	if err := r.Stage(); err != nil {
+		t.Fatalf("Stage() failed: %v", err)
+	}
+	r.Discard()
+	if r.Staged() != nil {
+		t.Error("Discard() retained the staged snapshot")
+	}
+}
+
+func TestRegistryFileLoader(t *testing.T) {
+	fsys := fstest.MapFS{
+		"policies/size.celpolicy": {Data: []byte(sizePolicy)},
+		"policies/role.celpolicy": {Data: []byte(rolePolicy)},
+		"policies/config.yaml":    {Data: []byte("name: config")},
+	}
+	r := newTestRegistry(t, NewFileLoader(fsys, "policies/*.celpolicy"))
+	if got := r.Current().Names(); !reflect.DeepEqual(got, []string{"role", "size"}) {
+		t.Errorf("Names() got %v, wanted [role size]", got)
+	}
+	delete(fsys, "policies/role.celpolicy")
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+	if got := r.Current().Names(); !reflect.DeepEqual(got, []string{"size"}) {
+		t.Errorf("Names() got %v, wanted [size]", got)
+	}
+}
+
+func TestNewRegistryError(t *testing.T) {
+	env := newTestEnv(t)
+	_, err := NewRegistry(env, MapLoader{"size.celpolicy": brokenPolicy})
+	if err == nil || !strings.Contains(err.Error(), "size.celpolicy") {
+		t.Errorf("NewRegistry() got error %v, wanted compile error", err)
+	}
+	if _, err := NewRegistry(env, MapLoader{}, Shadow(nil)); err == nil {
+		t.Error("NewRegistry() with a nil shadow reporter succeeded, wanted error")
+	}
+}
+
+func newTestRegistry(t testing.TB, loader Loader, opts ...Option) *Registry {
+	t.Helper()
+	opts = append([]Option{ParserOptions(policy.CustomTagVisitor(ownerTagVisitor{policy.DefaultTagVisitor()}))}, opts...)
+	r, err := NewRegistry(newTestEnv(t), loader, opts...)
+	if err != nil {
+		t.Fatalf("NewRegistry() failed: %v", err)
+	}
+	return r
+}
+
+func newTestEnv(t testing.TB) *cel.Env {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	return env
+}
+
+// ownerTagVisitor records the top-level `owner` tag as policy metadata.
+type ownerTagVisitor struct {
+	policy.TagVisitor
+}
+
+func (v ownerTagVisitor) PolicyTag(ctx policy.ParserContext, id int64, tagName string, node *yaml.Node, p *policy.Policy) {
+	if tagName != "owner" {
+		v.TagVisitor.PolicyTag(ctx, id, tagName, node, p)
+		return
+	}
+	p.SetMetadata(tagName, ctx.NewString(node).Value)
+}
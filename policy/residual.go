@@ -0,0 +1,370 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/interpreter"
+)
+
+// ResidualRule is the result of partially evaluating a CompiledRule.
+//
+// The residual rule retains only the variables and matches whose values could not be determined
+// from the known inputs, and is available as a CompiledRule, as a single composed CEL expression,
+// and as policy YAML.
+type ResidualRule struct {
+	rule *CompiledRule
+	ast  *cel.Ast
+	yaml string
+}
+
+// Rule returns the simplified CompiledRule.
+func (r *ResidualRule) Rule() *CompiledRule {
+	return r.rule
+}
+
+// Ast returns the residual rule composed into a single CEL expression.
+func (r *ResidualRule) Ast() *cel.Ast {
+	return r.ast
+}
+
+// YAML returns the residual rule as a policy document with a single top-level `rule`.
+//
+// Policy level fields such as the name, imports, and descriptions are not part of the compiled
+// rule and are omitted from the output. When the residual rule always produces a value, the
+// policy document produces a concrete value even if the original rule had an optional output.
+func (r *ResidualRule) YAML() string {
+	return r.yaml
+}
+
+// Residual partially evaluates a compiled rule against the known inputs within a partial
+// activation and returns the simplified rule.
+//
+// Matches whose conditions evaluate to false are removed, conditions which evaluate to true are
+// replaced with the literal `true`, and, under the first-match semantic, any matches which follow
+// a match whose condition is true are removed as unreachable. Matches whose conditions depend on attributes
+// marked as unknown within the activation are retained with their conditions and outputs
+// simplified as far as the known inputs allow. Variables which are no longer referenced by the
+// retained expressions are removed.
+//
+// Conditions which evaluate to an error are retained unchanged so that the error is reported when
+// the residual rule is evaluated against the complete input.
+//
+// Rules with the deny_overrides, permit_overrides, or all semantics are not supported.
+func Residual(rule *CompiledRule, vars cel.PartialActivation) (*ResidualRule, error) {
+	if rule == nil || rule.env == nil {
+		return nil, errors.New("residual computation requires a rule produced by CompileRule")
+	}
+	if vars == nil {
+		return nil, errors.New("residual computation requires a non-nil partial activation")
+	}
+	residual, err := residualRule(rule, vars)
+	if err != nil {
+		return nil, err
+	}
+	a, err := composeResidual(residual)
+	if err != nil {
+		return nil, err
+	}
+	node, err := residualRuleNode(residual)
+	if err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{newYAMLString("rule"), node}}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return &ResidualRule{rule: residual, ast: a, yaml: buf.String()}, nil
+}
+
+// residualRule computes the residual of the rule given the variable bindings in scope.
+func residualRule(rule *CompiledRule, vars interpreter.Activation) (*CompiledRule, error) {
+	if rule.semantic.isDecision() {
+		return nil, fmt.Errorf("residual computation is not supported for %s rules", rule.semantic)
+	}
+	// The matches of a rule with the priority semantic are ordered by priority, so the residual
+	// is expressed with the first-match semantic.
+	semantic := rule.semantic
+	if semantic == priorityMatch {
+		semantic = firstMatch
+	}
+	env := rule.env
+	locals := map[string]any{}
+	act := vars
+	compiledVars := make([]*CompiledVariable, 0, len(rule.Variables()))
+	for _, v := range rule.Variables() {
+		if v.Expr() == nil {
+			return nil, fmt.Errorf("variable %s was not compiled successfully", v.Name())
+		}
+		val, expr, err := residualExpr(env, v.Expr(), act)
+		if err != nil {
+			return nil, err
+		}
+		locals[v.Declaration().Name()] = val
+		act, err = localActivation(vars, locals)
+		if err != nil {
+			return nil, err
+		}
+		compiledVars = append(compiledVars, &CompiledVariable{
+			exprID:  v.exprID,
+			name:    v.name,
+			expr:    expr,
+			varDecl: v.varDecl,
+		})
+	}
+
+	matches := []*CompiledMatch{}
+	for _, m := range rule.Matches() {
+		condVal, cond, err := residualExpr(env, m.Condition(), act)
+		if err != nil {
+			return nil, err
+		}
+		if condVal == types.False {
+			continue
+		}
+		match := &CompiledMatch{exprID: m.exprID, cond: cond}
+		if m.Output() != nil {
+			_, out, err := residualExpr(env, m.Output().Expr(), act)
+			if err != nil {
+				return nil, err
+			}
+			match.output = &OutputValue{exprID: m.Output().SourceID(), expr: out}
+		}
+		// Matches whose condition is known to be true end the evaluation of a first-match rule.
+		terminal := condVal == types.True
+		if m.NestedRule() != nil {
+			nested, err := residualRule(m.NestedRule(), act)
+			if err != nil {
+				return nil, err
+			}
+			// A nested rule with an optional output falls through to the next match when it
+			// produces no value only if its condition is unconditionally true; otherwise the
+			// rule evaluates to optional.none().
+			unconditional := m.ConditionIsLiteral(types.True)
+			if len(nested.Matches()) == 0 {
+				if rule.semantic == aggregate || unconditional {
+					continue
+				}
+				if terminal {
+					break
+				}
+				// Retain the original nested rule so the residual does not fall through.
+				nested = m.NestedRule()
+			}
+			match.nestedRule = nested
+			terminal = terminal && !(unconditional && nested.HasOptionalOutput())
+		}
+		matches = append(matches, match)
+		if semantic == firstMatch && terminal {
+			break
+		}
+	}
+
+	residual := &CompiledRule{
+		exprID:   rule.exprID,
+		id:       rule.id,
+		matches:  matches,
+		semantic: semantic,
+		env:      env,
+
+		optionalOutput: rule.HasOptionalOutput(),
+	}
+	residual.variables = pruneUnusedVariables(compiledVars, referencedVariables(residual))
+	return residual, nil
+}
+
+// localActivation layers the values of the rule variables over the input activation.
+func localActivation(vars interpreter.Activation, locals map[string]any) (interpreter.Activation, error) {
+	bindings := make(map[string]any, len(locals))
+	for k, v := range locals {
+		bindings[k] = v
+	}
+	act, err := interpreter.NewActivation(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return interpreter.NewHierarchicalActivation(vars, act), nil
+}
+
+// residualExpr evaluates the expression against the activation and returns the evaluation result
+// together with the residual expression.
+//
+// When the expression evaluates to an error, the original expression is returned unchanged.
+func residualExpr(env *cel.Env, a *cel.Ast, vars interpreter.Activation) (ref.Val, *cel.Ast, error) {
+	prg, err := env.Program(a, cel.EvalOptions(cel.OptTrackState, cel.OptPartialEval))
+	if err != nil {
+		return nil, nil, err
+	}
+	out, det, err := prg.Eval(vars)
+	if out == nil {
+		return nil, nil, err
+	}
+	if types.IsError(out) {
+		return out, a, nil
+	}
+	residual, err := env.ResidualAst(a, det)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, residual, nil
+}
+
+// referencedVariables returns the set of rule variables referenced by the matches of a rule,
+// including the variables and matches of nested rules.
+func referencedVariables(rule *CompiledRule) map[string]bool {
+	refs := map[string]bool{}
+	for _, m := range rule.Matches() {
+		collectVariableRefs(m.Condition(), refs)
+		if m.Output() != nil {
+			collectVariableRefs(m.Output().Expr(), refs)
+		}
+		if m.NestedRule() != nil {
+			for _, v := range m.NestedRule().Variables() {
+				collectVariableRefs(v.Expr(), refs)
+			}
+			for name := range referencedVariables(m.NestedRule()) {
+				refs[name] = true
+			}
+		}
+	}
+	return refs
+}
+
+// pruneUnusedVariables removes the variables which are not referenced by the rule, either directly
+// or through other referenced variables.
+func pruneUnusedVariables(vars []*CompiledVariable, refs map[string]bool) []*CompiledVariable {
+	keep := make([]bool, len(vars))
+	for i := len(vars) - 1; i >= 0; i-- {
+		v := vars[i]
+		if !refs[v.Declaration().Name()] {
+			continue
+		}
+		keep[i] = true
+		collectVariableRefs(v.Expr(), refs)
+	}
+	pruned := []*CompiledVariable{}
+	for i, v := range vars {
+		if keep[i] {
+			pruned = append(pruned, v)
+		}
+	}
+	return pruned
+}
+
+func collectVariableRefs(a *cel.Ast, refs map[string]bool) {
+	ast.PostOrderVisit(a.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() == ast.IdentKind && strings.HasPrefix(e.AsIdent(), variablePrefix+".") {
+			refs[e.AsIdent()] = true
+		}
+	}))
+}
+
+// composeResidual composes the residual rule into a single expression.
+func composeResidual(rule *CompiledRule) (*cel.Ast, error) {
+	if len(rule.Matches()) == 0 {
+		// The rule never produces a value.
+		empty := "optional.none()"
+		if rule.semantic == aggregate {
+			empty = "[]"
+		}
+		a, iss := rule.env.Compile(empty)
+		if iss.Err() != nil {
+			return nil, iss.Err()
+		}
+		return a, nil
+	}
+	composer, err := NewRuleComposer(rule.env)
+	if err != nil {
+		return nil, err
+	}
+	a, iss := composer.Compose(rule)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	return a, nil
+}
+
+// residualRuleNode renders the residual rule as a YAML mapping node.
+func residualRuleNode(rule *CompiledRule) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	if rule.ID() != nil && rule.ID().Value != "" {
+		node.Content = append(node.Content, newYAMLString("id"), newYAMLString(rule.ID().Value))
+	}
+	if len(rule.Variables()) != 0 {
+		varsNode := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range rule.Variables() {
+			expr, err := cel.AstToString(v.Expr())
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %w", v.Name(), err)
+			}
+			varsNode.Content = append(varsNode.Content, &yaml.Node{
+				Kind: yaml.MappingNode,
+				Content: []*yaml.Node{
+					newYAMLString("name"), newYAMLString(v.Name()),
+					newYAMLString("expression"), newYAMLString(expr),
+				},
+			})
+		}
+		node.Content = append(node.Content, newYAMLString("variables"), varsNode)
+	}
+	matchesNode := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, m := range rule.Matches() {
+		matchNode := &yaml.Node{Kind: yaml.MappingNode}
+		if !m.ConditionIsLiteral(types.True) {
+			cond, err := cel.AstToString(m.Condition())
+			if err != nil {
+				return nil, err
+			}
+			matchNode.Content = append(matchNode.Content, newYAMLString("condition"), newYAMLString(cond))
+		}
+		if m.Output() != nil {
+			out, err := cel.AstToString(m.Output().Expr())
+			if err != nil {
+				return nil, err
+			}
+			matchNode.Content = append(matchNode.Content, newYAMLString("output"), newYAMLString(out))
+		}
+		if m.NestedRule() != nil {
+			nested, err := residualRuleNode(m.NestedRule())
+			if err != nil {
+				return nil, err
+			}
+			matchNode.Content = append(matchNode.Content, newYAMLString("rule"), nested)
+		}
+		matchesNode.Content = append(matchesNode.Content, matchNode)
+	}
+	node.Content = append(node.Content, newYAMLString(rule.semantic.String()), matchesNode)
+	return node, nil
+}
+
+func newYAMLString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
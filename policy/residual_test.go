@@ -0,0 +1,290 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+	"cel.dev/cel-go/ext"
+)
+
+const residualPolicy = `
+name: residual
+rule:
+  variables:
+    - name: is_admin
+      expression: request.role == 'admin'
+    - name: is_prod
+      expression: resource.env == 'prod'
+    - name: limit
+      expression: "variables.is_prod ? 10 : 100"
+  match:
+    - condition: variables.is_admin
+      output: "'allow'"
+    - condition: variables.is_prod && request.size > variables.limit
+      output: "'deny: ' + resource.env"
+    - condition: request.size > 1000
+      rule:
+        match:
+          - condition: resource.owner == request.user
+            output: "'allow-owner'"
+    - output: "'allow-default'"
+`
+
+func TestResidual(t *testing.T) {
+	env, rule := compileResidualPolicy(t, residualPolicy)
+	tests := []struct {
+		name     string
+		known    map[string]any
+		unknowns []*cel.AttributePatternType
+		wantYAML string
+		inputs   []map[string]any
+	}{
+		{
+			name: "known_admin",
+			known: map[string]any{
+				"request": map[string]any{"role": "admin"},
+			},
+			unknowns: []*cel.AttributePatternType{cel.AttributePattern("resource")},
+			wantYAML: `rule:
+  match:
+    - output: '"allow"'
+`,
+			inputs: []map[string]any{
+				{"request": map[string]any{"role": "admin", "size": 1}, "resource": map[string]any{"env": "prod"}},
+			},
+		},
+		{
+			name: "known_resource",
+			known: map[string]any{
+				"resource": map[string]any{"env": "prod", "owner": "alice"},
+			},
+			unknowns: []*cel.AttributePatternType{cel.AttributePattern("request")},
+			wantYAML: `rule:
+  variables:
+    - name: is_admin
+      expression: request.role == "admin"
+  match:
+    - condition: variables.is_admin
+      output: '"allow"'
+    - condition: request.size > 10
+      output: '"deny: prod"'
+    - condition: request.size > 1000
+      rule:
+        match:
+          - condition: '"alice" == request.user'
+            output: '"allow-owner"'
+    - output: '"allow-default"'
+`,
+			inputs: []map[string]any{
+				{"request": map[string]any{"role": "admin", "size": 1, "user": "bob"}, "resource": map[string]any{"env": "prod", "owner": "alice"}},
+				{"request": map[string]any{"role": "user", "size": 20, "user": "bob"}, "resource": map[string]any{"env": "prod", "owner": "alice"}},
+				{"request": map[string]any{"role": "user", "size": 5, "user": "bob"}, "resource": map[string]any{"env": "prod", "owner": "alice"}},
+			},
+		},
+		{
+			name: "known_non_admin_dev",
+			known: map[string]any{
+				"request":  map[string]any{"role": "user", "size": 2000},
+				"resource": map[string]any{"env": "dev"},
+			},
+			unknowns: []*cel.AttributePatternType{
+				cel.AttributePattern("request").QualString("user"),
+				cel.AttributePattern("resource").QualString("owner"),
+			},
+			wantYAML: `rule:
+  match:
+    - rule:
+        match:
+          - condition: resource.owner == request.user
+            output: '"allow-owner"'
+`,
+			inputs: []map[string]any{
+				{"request": map[string]any{"role": "user", "size": 2000, "user": "bob"}, "resource": map[string]any{"env": "dev", "owner": "bob"}},
+				{"request": map[string]any{"role": "user", "size": 2000, "user": "bob"}, "resource": map[string]any{"env": "dev", "owner": "alice"}},
+			},
+		},
+		{
+			name: "nested_without_matches",
+			known: map[string]any{
+				"request":  map[string]any{"role": "user", "user": "bob"},
+				"resource": map[string]any{"env": "dev", "owner": "alice"},
+			},
+			unknowns: []*cel.AttributePatternType{
+				cel.AttributePattern("request").QualString("size"),
+			},
+			wantYAML: `rule:
+  match:
+    - condition: request.size > 1000
+      rule:
+        match:
+          - condition: resource.owner == request.user
+            output: '"allow-owner"'
+    - output: '"allow-default"'
+`,
+			inputs: []map[string]any{
+				{"request": map[string]any{"role": "user", "size": 2000, "user": "bob"}, "resource": map[string]any{"env": "dev", "owner": "alice"}},
+				{"request": map[string]any{"role": "user", "size": 20, "user": "bob"}, "resource": map[string]any{"env": "dev", "owner": "alice"}},
+			},
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			vars, err := cel.PartialVars(tc.known, tc.unknowns...)
+			if err != nil {
+				t.Fatalf("cel.PartialVars() failed: %v", err)
+			}
+			residual, err := Residual(rule, vars)
+			if err != nil {
+				t.Fatalf("Residual() failed: %v", err)
+			}
+			if residual.YAML() != tc.wantYAML {
+				t.Errorf("Residual().YAML() got:\n%s\nwanted:\n%s", residual.YAML(), tc.wantYAML)
+			}
+			// The residual YAML must itself be a valid policy.
+			reparsed := parsePolicySource(t, tc.name, residual.YAML())
+			reparsedAST, iss := Compile(env, reparsed)
+			if iss.Err() != nil {
+				t.Fatalf("Compile(residual YAML) failed: %v", iss.Err())
+			}
+			origAST, iss := Compile(env, parsePolicySource(t, "residual", residualPolicy))
+			if iss.Err() != nil {
+				t.Fatalf("Compile() failed: %v", iss.Err())
+			}
+			for _, in := range tc.inputs {
+				want := evalResidualTest(t, env, origAST, in)
+				if got := evalResidualTest(t, env, residual.Ast(), in); got.Equal(want) != types.True {
+					t.Errorf("residual.Ast() eval(%v) got %v, wanted %v", in, got, want)
+				}
+				// A residual which always produces a value is written as a non-optional policy.
+				got := evalResidualTest(t, env, reparsedAST, in)
+				if opt, ok := want.(*types.Optional); ok && opt.HasValue() && got.Type() != types.OptionalType {
+					want = opt.GetValue()
+				}
+				if got.Equal(want) != types.True {
+					t.Errorf("residual YAML eval(%v) got %v, wanted %v", in, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResidualAggregate(t *testing.T) {
+	env, rule := compileResidualPolicy(t, `
+name: residual_aggregate
+rule:
+  aggregate:
+    - condition: request.size > 10
+      output: "'large'"
+    - condition: request.role == 'admin'
+      output: "'admin'"
+    - condition: request.user == 'bob'
+      output: "'bob'"
+`)
+	vars, err := cel.PartialVars(
+		map[string]any{"request": map[string]any{"size": 20, "role": "user"}},
+		cel.AttributePattern("request").QualString("user"))
+	if err != nil {
+		t.Fatalf("cel.PartialVars() failed: %v", err)
+	}
+	residual, err := Residual(rule, vars)
+	if err != nil {
+		t.Fatalf("Residual() failed: %v", err)
+	}
+	want := `rule:
+  aggregate:
+    - output: '"large"'
+    - condition: request.user == "bob"
+      output: '"bob"'
+`
+	if residual.YAML() != want {
+		t.Errorf("Residual().YAML() got:\n%s\nwanted:\n%s", residual.YAML(), want)
+	}
+	out := evalResidualTest(t, env, residual.Ast(),
+		map[string]any{"request": map[string]any{"size": 20, "role": "user", "user": "bob"}})
+	if out.Equal(types.DefaultTypeAdapter.NativeToValue([]string{"large", "bob"})) != types.True {
+		t.Errorf("residual.Ast() eval got %v, wanted [large, bob]", out)
+	}
+}
+
+func TestResidualNoMatches(t *testing.T) {
+	env, rule := compileResidualPolicy(t, `
+name: residual_none
+rule:
+  match:
+    - condition: request.size > 10
+      output: "'large'"
+`)
+	vars, err := cel.PartialVars(map[string]any{"request": map[string]any{"size": 1}})
+	if err != nil {
+		t.Fatalf("cel.PartialVars() failed: %v", err)
+	}
+	residual, err := Residual(rule, vars)
+	if err != nil {
+		t.Fatalf("Residual() failed: %v", err)
+	}
+	if len(residual.Rule().Matches()) != 0 {
+		t.Errorf("Residual() got %d matches, wanted 0", len(residual.Rule().Matches()))
+	}
+	out := evalResidualTest(t, env, residual.Ast(), map[string]any{})
+	if out.Equal(types.OptionalNone) != types.True {
+		t.Errorf("residual.Ast() eval got %v, wanted optional.none()", out)
+	}
+}
+
+func TestResidualErrors(t *testing.T) {
+	_, rule := compileResidualPolicy(t, residualPolicy)
+	if _, err := Residual(rule, nil); err == nil {
+		t.Error("Residual() with nil activation succeeded, wanted error")
+	}
+	if _, err := Residual(&CompiledRule{}, nil); err == nil {
+		t.Error("Residual() with uncompiled rule succeeded, wanted error")
+	}
+}
+
+func compileResidualPolicy(t *testing.T, src string) (*cel.Env, *CompiledRule) {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("resource", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	rule, iss := CompileRule(env, parsePolicySource(t, "residual", src))
+	if iss.Err() != nil {
+		t.Fatalf("CompileRule() failed: %v", iss.Err())
+	}
+	return env, rule
+}
+
+func evalResidualTest(t *testing.T, env *cel.Env, a *cel.Ast, in map[string]any) ref.Val {
+	t.Helper()
+	prg, err := env.Program(a)
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	out, _, err := prg.Eval(in)
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	return out
+}
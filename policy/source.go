@@ -15,6 +15,10 @@
 package policy
 
 import (
+	"io/fs"
+	"path"
+	"strings"
+
 	"cel.dev/cel-go/common"
 )
 
@@ -65,3 +69,39 @@ func (rel *RelativeSource) OffsetLocation(offset int32) (common.Location, bool)
 	}
 	return rel.Source.OffsetLocation(absOffset + offset)
 }
+
+// SourceLoader resolves a policy include reference into a Source.
+type SourceLoader interface {
+	// Load returns the Source for the reference as written within the including source.
+	//
+	// The Description of the returned Source must uniquely identify it, as it is used to report
+	// diagnostics and to detect include cycles.
+	Load(ref string, from *Source) (*Source, error)
+}
+
+// NewFileLoader creates a SourceLoader which reads policy files from a file system.
+//
+// References are slash-separated paths which are resolved relative to the directory of the
+// including source's description, so the root policy should be read from the same file system
+// with its path as the description.
+func NewFileLoader(fsys fs.FS) SourceLoader {
+	return &fileLoader{fsys: fsys}
+}
+
+type fileLoader struct {
+	fsys fs.FS
+}
+
+// Load implements the SourceLoader interface method.
+func (l *fileLoader) Load(ref string, from *Source) (*Source, error) {
+	// File system paths are unrooted, so absolute references resolve from the file system root.
+	name := strings.TrimPrefix(path.Clean(ref), "/")
+	if from != nil && !path.IsAbs(ref) {
+		name = path.Join(path.Dir(from.Description()), ref)
+	}
+	contents, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return ByteSource(contents, name), nil
+}
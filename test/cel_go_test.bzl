@@ -32,6 +32,7 @@ def cel_go_test(
         file_descriptor_set = "",
         filegroup = "",
         enable_coverage = False,
+        enable_mutation_testing = False,
         deps = [],
         data = [],
         **kwargs):
@@ -61,6 +62,8 @@ def cel_go_test(
           to support a textformat file_descriptor_set, embed it in the environment file. (default None)
       filegroup: str label of a filegroup containing the test suite, config, and cel_expr.
       enable_coverage: boolean indicating if coverage should be enabled for the test.
+      enable_mutation_testing: boolean indicating if surviving mutants of the tested expression
+          should be reported.
       deps: list of dependencies for the go_test rule
       data: list of data dependencies for the go_test rule
       **kwargs: additional arguments to pass to the go_test rule
@@ -107,6 +110,7 @@ def cel_go_test(
         "--config_path=%s" % config,
         "--base_config_path=%s" % base_config,
         "--enable_coverage=%s" % enable_coverage,
+        "--enable_mutation_testing=%s" % enable_mutation_testing,
     ]
 
     if cel_expr_format == ".cel" or cel_expr_format == ".celpolicy" or cel_expr_format == ".yaml":
@@ -0,0 +1,190 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package celbundle builds and verifies policy bundles, see policy.Bundle.
+//
+// Policies are compiled against an environment constructed solely from the configuration and
+// type descriptors stored within the bundle, so a bundle which builds successfully may be loaded
+// with policy.ReadBundle and planned without access to the original policy sources.
+package celbundle
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/env"
+	"cel.dev/cel-go/policy"
+
+	"go.yaml.in/yaml/v3"
+
+	descpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Build compiles the policy files against the environment configuration and optional file
+// descriptor set and packages the results into a bundle.
+//
+// The optional and bindings extensions, which are used by composed policies, are added to the
+// configuration when not already present. Each policy is stored under its `name`.
+func Build(name, version, configPath, descriptorsPath string, policyPaths ...string) (*policy.Bundle, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	config := &env.Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid environment config %s: %w", configPath, err)
+	}
+	for _, extName := range []string{"optional", "bindings"} {
+		if !slices.ContainsFunc(config.Extensions, func(e *env.Extension) bool { return e.Name == extName }) {
+			config.AddExtensions(&env.Extension{Name: extName, Version: "latest"})
+		}
+	}
+	b := &policy.Bundle{Name: name, Version: version, Config: config}
+	if descriptorsPath != "" {
+		data, err := os.ReadFile(descriptorsPath)
+		if err != nil {
+			return nil, err
+		}
+		b.FileDescriptorSet = &descpb.FileDescriptorSet{}
+		if err := proto.Unmarshal(data, b.FileDescriptorSet); err != nil {
+			return nil, fmt.Errorf("invalid file descriptor set %s: %w", descriptorsPath, err)
+		}
+	}
+	e, err := b.Env()
+	if err != nil {
+		return nil, err
+	}
+	parser, err := policy.NewParser()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range policyPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p, iss := parser.Parse(policy.ByteSource(data, path))
+		if iss.Err() != nil {
+			return nil, iss.Err()
+		}
+		ast, iss := policy.Compile(e, p)
+		if iss.Err() != nil {
+			return nil, iss.Err()
+		}
+		if err := b.AddPolicy(p.Name().Value, ast); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return b, nil
+}
+
+// Verify reads a bundle, verifying its content hashes and, when a public key is provided, its
+// signature. The bundle environment is then constructed and a program is planned for each policy.
+//
+// The environment options must include the implementations of any custom functions referenced by
+// the policies.
+func Verify(r io.Reader, key ed25519.PublicKey, opts ...cel.EnvOption) (*policy.Bundle, error) {
+	var readOpts []policy.BundleOption
+	if key != nil {
+		readOpts = append(readOpts, policy.VerifyBundleSignature(key))
+	}
+	b, err := policy.ReadBundle(r, readOpts...)
+	if err != nil {
+		return nil, err
+	}
+	e, err := b.Env(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.Programs(e); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GenerateKeys generates an ed25519 key pair for signing bundles and returns the PEM encoded
+// PKCS #8 private key and PKIX public key.
+func GenerateKeys() (privateKey, publicKey []byte, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 ed25519 private key.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	der, err := decodePEM(data, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+	return priv, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX ed25519 public key.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	der, err := decodePEM(data, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type: %T", key)
+	}
+	return pub, nil
+}
+
+func decodePEM(data []byte, blockType string) ([]byte, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(data))
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("unexpected PEM block %q, wanted %q", block.Type, blockType)
+	}
+	return block.Bytes, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celbundle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cel.dev/cel-go/policy"
+
+	proto3pb "cel.dev/cel-go/test/proto3pb"
+	descpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestBuildAndVerify(t *testing.T) {
+	privPEM, pubPEM, err := GenerateKeys()
+	if err != nil {
+		t.Fatalf("GenerateKeys() failed: %v", err)
+	}
+	priv, err := ParsePrivateKey(privPEM)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() failed: %v", err)
+	}
+	pub, err := ParsePublicKey(pubPEM)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() failed: %v", err)
+	}
+
+	b, err := Build("protos", "0.1.0", "../../policy/testdata/pb/config.yaml", writeDescriptors(t),
+		"../../policy/testdata/pb/policy.yaml")
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := policy.WriteBundle(&buf, b, policy.SignBundle(priv)); err != nil {
+		t.Fatalf("policy.WriteBundle() failed: %v", err)
+	}
+	got, err := Verify(bytes.NewReader(buf.Bytes()), pub)
+	if err != nil {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	e, err := got.Env()
+	if err != nil {
+		t.Fatalf("Bundle.Env() failed: %v", err)
+	}
+	prgs, err := got.Programs(e)
+	if err != nil {
+		t.Fatalf("Bundle.Programs() failed: %v", err)
+	}
+	out, _, err := prgs["pb"].Eval(map[string]any{"spec": &proto3pb.TestAllTypes{SingleInt32: 11}})
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	want := "invalid spec, got single_int32=11, wanted <= 10"
+	if out.Value() != want {
+		t.Errorf("prg.Eval() got %v, wanted %s", out, want)
+	}
+
+	_, otherPubPEM, err := GenerateKeys()
+	if err != nil {
+		t.Fatalf("GenerateKeys() failed: %v", err)
+	}
+	otherPub, err := ParsePublicKey(otherPubPEM)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() failed: %v", err)
+	}
+	if _, err := Verify(bytes.NewReader(buf.Bytes()), otherPub); err == nil {
+		t.Error("Verify() with the wrong public key succeeded, wanted error")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		policies []string
+		err      string
+	}{
+		{
+			name:     "missing descriptors",
+			config:   "../../policy/testdata/pb/config.yaml",
+			policies: []string{"../../policy/testdata/pb/policy.yaml"},
+			err:      "undefined type",
+		},
+		{
+			name:   "duplicate policy",
+			config: "../../policy/testdata/limits/config.yaml",
+			policies: []string{
+				"../../policy/testdata/limits/policy.yaml",
+				"../../policy/testdata/limits/policy.yaml",
+			},
+			err: "duplicate bundle policy name: limits",
+		},
+	}
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Build("test", "1.0.0", tc.config, "", tc.policies...)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Build() got error %v, wanted %s", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestParseKeyErrors(t *testing.T) {
+	privPEM, pubPEM, err := GenerateKeys()
+	if err != nil {
+		t.Fatalf("GenerateKeys() failed: %v", err)
+	}
+	if _, err := ParsePublicKey(privPEM); err == nil {
+		t.Error("ParsePublicKey() with a private key succeeded, wanted error")
+	}
+	if _, err := ParsePrivateKey(pubPEM); err == nil {
+		t.Error("ParsePrivateKey() with a public key succeeded, wanted error")
+	}
+	if _, err := ParsePublicKey([]byte("not a key")); err == nil {
+		t.Error("ParsePublicKey() with invalid data succeeded, wanted error")
+	}
+}
+
+// writeDescriptors writes the file descriptor set for the proto3 test types to a temporary file.
+func writeDescriptors(t testing.TB) string {
+	t.Helper()
+	fds := &descpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	var collect func(protoreflect.FileDescriptor)
+	collect = func(f protoreflect.FileDescriptor) {
+		if seen[f.Path()] {
+			return
+		}
+		seen[f.Path()] = true
+		imports := f.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			collect(imports.Get(i).FileDescriptor)
+		}
+		fds.File = append(fds.File, protodesc.ToFileDescriptorProto(f))
+	}
+	collect((&proto3pb.TestAllTypes{}).ProtoReflect().Descriptor().ParentFile())
+	data, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatalf("proto.Marshal() failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "descriptors.binpb")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main provides the celbundle command which builds, signs, and verifies policy bundles.
+//
+// usage:
+//
+// ```
+// $ celbundle build -name limits -version 1.0.0 -config config.yaml [-descriptors types.binpb] [-sign_key private.pem] -o bundle.tar.gz policy.celpolicy ...
+// $ celbundle verify [-key public.pem] bundle.tar.gz
+// $ celbundle keygen -private private.pem -public public.pem
+// ```
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+
+	"cel.dev/cel-go/policy"
+	"cel.dev/cel-go/tools/celbundle"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "build":
+		err = build(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	case "keygen":
+		err = keygen(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "celbundle: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: celbundle build|verify|keygen [flags]")
+	os.Exit(2)
+}
+
+func build(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	name := fs.String("name", "", "the name of the bundle")
+	version := fs.String("version", "", "the semantic version of the bundle")
+	config := fs.String("config", "", "the YAML environment config against which policies are compiled")
+	descriptors := fs.String("descriptors", "", "an optional binary file descriptor set of the types used by the policies")
+	signKey := fs.String("sign_key", "", "an optional PEM encoded ed25519 private key with which to sign the bundle")
+	out := fs.String("o", "", "the output bundle path")
+	fs.Parse(args)
+	if *config == "" || *out == "" || fs.NArg() == 0 {
+		return fmt.Errorf("build requires -config, -o, and at least one policy file")
+	}
+	b, err := celbundle.Build(*name, *version, *config, *descriptors, fs.Args()...)
+	if err != nil {
+		return err
+	}
+	var opts []policy.BundleOption
+	if *signKey != "" {
+		data, err := os.ReadFile(*signKey)
+		if err != nil {
+			return err
+		}
+		key, err := celbundle.ParsePrivateKey(data)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, policy.SignBundle(key))
+	}
+	var buf bytes.Buffer
+	if err := policy.WriteBundle(&buf, b, opts...); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0644)
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	key := fs.String("key", "", "an optional PEM encoded ed25519 public key with which to verify the bundle signature")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("verify requires a single bundle file")
+	}
+	var pub ed25519.PublicKey
+	if *key != "" {
+		data, err := os.ReadFile(*key)
+		if err != nil {
+			return err
+		}
+		pub, err = celbundle.ParsePublicKey(data)
+		if err != nil {
+			return err
+		}
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	b, err := celbundle.Verify(f, pub)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s@%s: %d policies verified\n", b.Name, b.Version, len(b.Policies))
+	return nil
+}
+
+func keygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	privPath := fs.String("private", "", "the output path of the PEM encoded private key")
+	pubPath := fs.String("public", "", "the output path of the PEM encoded public key")
+	fs.Parse(args)
+	if *privPath == "" || *pubPath == "" {
+		return fmt.Errorf("keygen requires -private and -public")
+	}
+	priv, pub, err := celbundle.GenerateKeys()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*privPath, priv, 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(*pubPath, pub, 0644)
+}
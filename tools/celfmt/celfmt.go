@@ -0,0 +1,162 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package celfmt formats CEL expressions stored within .cel files and embedded within .celpolicy
+// YAML documents.
+//
+// The expression style is determined by parser.Format, which preserves `//` comments.
+package celfmt
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cel.dev/cel-go/common"
+	"cel.dev/cel-go/parser"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Formatter rewrites CEL sources according to a consistent style.
+type Formatter struct {
+	fmtOpts  []parser.FormatOption
+	exprKeys []string
+}
+
+// FormatterOption is a functional option for configuring a Formatter.
+type FormatterOption func(*Formatter) (*Formatter, error)
+
+// LineWidth sets the column at which long expressions are broken across multiple lines.
+func LineWidth(width int) FormatterOption {
+	return func(f *Formatter) (*Formatter, error) {
+		f.fmtOpts = append(f.fmtOpts, parser.FormatLineWidth(width))
+		return f, nil
+	}
+}
+
+// ExpressionKeys sets the YAML mapping keys whose values are formatted as CEL expressions within
+// policy documents.
+//
+// By default, the values of the `expression`, `condition`, `output`, and `explanation` keys are
+// formatted.
+func ExpressionKeys(keys ...string) FormatterOption {
+	return func(f *Formatter) (*Formatter, error) {
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("at least one expression key must be specified")
+		}
+		f.exprKeys = keys
+		return f, nil
+	}
+}
+
+// NewFormatter creates a Formatter with the given options.
+func NewFormatter(opts ...FormatterOption) (*Formatter, error) {
+	f := &Formatter{
+		exprKeys: []string{"expression", "condition", "output", "explanation"},
+	}
+	var err error
+	for _, opt := range opts {
+		f, err = opt(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// FormatFile formats the contents of a file according to its extension.
+//
+// Files with a .cel extension contain a single expression. Files with a .celpolicy, .yaml, or .yml
+// extension are treated as policy documents.
+func (f *Formatter) FormatFile(path string, data []byte) ([]byte, error) {
+	switch filepath.Ext(path) {
+	case ".cel":
+		return f.FormatExpression(path, data)
+	case ".celpolicy", ".yaml", ".yml":
+		return f.FormatPolicy(path, data)
+	default:
+		return nil, fmt.Errorf("invalid file extension wanted: .cel, .celpolicy, .yaml, or .yml found: %v", path)
+	}
+}
+
+// FormatExpression formats the contents of a .cel file. The result ends with a newline.
+func (f *Formatter) FormatExpression(path string, data []byte) ([]byte, error) {
+	src := common.NewStringSource(string(data), path)
+	out, err := parser.Format(src, f.fmtOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out + "\n"), nil
+}
+
+// FormatPolicy formats the expressions embedded within a YAML policy document.
+//
+// Scalar values of the configured expression keys are formatted, and values which span multiple
+// lines after formatting are written as literal block scalars. The remainder of the document,
+// including YAML comments, is preserved, though the document is re-encoded with a two space indent.
+func (f *Formatter) FormatPolicy(path string, data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: yaml.Unmarshal failed: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		return data, nil
+	}
+	var errs []string
+	f.formatNode(path, &doc, &errs)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: yaml.Encode failed: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *Formatter) formatNode(path string, node *yaml.Node, errs *[]string) {
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			f.formatNode(path, child, errs)
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		if val.Kind != yaml.ScalarNode || val.Tag != "!!str" || !slices.Contains(f.exprKeys, key.Value) {
+			f.formatNode(path, val, errs)
+			continue
+		}
+		src := common.NewStringSource(val.Value, fmt.Sprintf("%s:%d", path, val.Line))
+		out, err := parser.Format(src, f.fmtOpts...)
+		if err != nil {
+			*errs = append(*errs, err.Error())
+			continue
+		}
+		val.Value = out
+		if strings.Contains(out, "\n") {
+			val.Style = yaml.LiteralStyle
+		} else if val.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			val.Style = 0
+		}
+	}
+}
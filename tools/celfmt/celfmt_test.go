@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celfmt
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFormatExpression(t *testing.T) {
+	f, err := NewFormatter(LineWidth(40))
+	if err != nil {
+		t.Fatalf("NewFormatter() failed: %v", err)
+	}
+	in := "// check the size\nsize(x)>0&&x.all(i,i<10) // bounded\n"
+	want := "// check the size\nsize(x) > 0 && x.all(i, i < 10) // bounded\n"
+	out, err := f.FormatFile("expr.cel", []byte(in))
+	if err != nil {
+		t.Fatalf("FormatFile() failed: %v", err)
+	}
+	if string(out) != want {
+		t.Errorf("FormatFile() got %q, wanted %q", out, want)
+	}
+	if _, err := f.FormatFile("expr.cel", []byte("a +")); err == nil {
+		t.Error("FormatFile() with a syntax error succeeded, wanted error")
+	}
+	if _, err := f.FormatFile("expr.txt", []byte("a")); err == nil {
+		t.Error("FormatFile() with an unsupported extension succeeded, wanted error")
+	}
+}
+
+func TestFormatPolicy(t *testing.T) {
+	f, err := NewFormatter(LineWidth(60))
+	if err != nil {
+		t.Fatalf("NewFormatter() failed: %v", err)
+	}
+	in, err := os.ReadFile("testdata/policy.celpolicy")
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	want, err := os.ReadFile("testdata/policy.golden")
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	out, err := f.FormatFile("testdata/policy.celpolicy", in)
+	if err != nil {
+		t.Fatalf("FormatFile() failed: %v", err)
+	}
+	if string(out) != string(want) {
+		t.Errorf("FormatFile() got:\n%s\nwanted:\n%s", out, want)
+	}
+	again, err := f.FormatFile("testdata/policy.celpolicy", out)
+	if err != nil {
+		t.Fatalf("FormatFile() failed: %v", err)
+	}
+	if string(again) != string(out) {
+		t.Errorf("FormatFile() is not idempotent, got:\n%s\nwanted:\n%s", again, out)
+	}
+}
+
+func TestFormatPolicyErrors(t *testing.T) {
+	f, err := NewFormatter()
+	if err != nil {
+		t.Fatalf("NewFormatter() failed: %v", err)
+	}
+	if _, err := f.FormatPolicy("p.celpolicy", []byte("rule:\n  match:\n    - output: a +\n")); err == nil {
+		t.Error("FormatPolicy() with an invalid expression succeeded, wanted error")
+	}
+	if _, err := NewFormatter(ExpressionKeys()); err == nil {
+		t.Error("NewFormatter() with no expression keys succeeded, wanted error")
+	}
+}
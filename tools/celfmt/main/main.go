@@ -0,0 +1,107 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main provides the celfmt command which formats .cel files and the expressions embedded
+// within .celpolicy files.
+//
+// usage:
+//
+// ```
+// $ celfmt [-w] [-l] [-width 80] [-keys expression,condition,output,explanation] [path ...]
+// ```
+//
+// With no paths, celfmt formats a single expression read from standard input.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cel.dev/cel-go/tools/celfmt"
+)
+
+var (
+	write = flag.Bool("w", false, "write the result to the source file instead of standard output")
+	list  = flag.Bool("l", false, "list files whose formatting differs from celfmt's")
+	width = flag.Int("width", 80, "the line width at which long expressions are broken")
+	keys  = flag.String("keys", "", "comma-separated YAML keys whose values are CEL expressions")
+)
+
+func main() {
+	flag.Parse()
+	opts := []celfmt.FormatterOption{celfmt.LineWidth(*width)}
+	if *keys != "" {
+		opts = append(opts, celfmt.ExpressionKeys(strings.Split(*keys, ",")...))
+	}
+	f, err := celfmt.NewFormatter(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "celfmt: %v\n", err)
+		os.Exit(2)
+	}
+	if flag.NArg() == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "celfmt: %v\n", err)
+			os.Exit(2)
+		}
+		out, err := f.FormatExpression("<stdin>", data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+	exitCode := 0
+	for _, path := range flag.Args() {
+		if err := formatFile(f, path); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func formatFile(f *celfmt.Formatter, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	out, err := f.FormatFile(path, data)
+	if err != nil {
+		return err
+	}
+	changed := !bytes.Equal(data, out)
+	if *list && changed {
+		fmt.Println(path)
+	}
+	if *write {
+		if !changed {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, out, info.Mode().Perm())
+	}
+	if !*list {
+		os.Stdout.Write(out)
+	}
+	return nil
+}
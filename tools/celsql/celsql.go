@@ -0,0 +1,527 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package celsql translates boolean CEL expressions into parameterized SQL predicates.
+//
+// The translator is intended to be used with the residual expressions produced by partial
+// evaluation, where the attributes which correspond to the columns of a table are marked as
+// unknown and all other inputs are known. The resulting predicate can be used within the WHERE
+// clause of a query so that row-level policies are enforced within the database.
+package celsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/overloads"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/common/types/ref"
+)
+
+// Column describes the table column which corresponds to a CEL field path.
+type Column struct {
+	// Name is the column name, optionally qualified by the table name, e.g. `docs.owner`.
+	Name string
+
+	// Nullable indicates whether the column may contain NULL values.
+	//
+	// A NULL value corresponds to an unset field within CEL. Comparisons against nullable columns
+	// evaluate to false when the column is NULL, and `has()` tests are translated to an
+	// `IS NOT NULL` check.
+	Nullable bool
+}
+
+// TranslatorOption is a functional option for configuring a Translator.
+type TranslatorOption func(*Translator) (*Translator, error)
+
+// Columns maps CEL field paths, e.g. `resource.owner`, to table columns.
+//
+// The option may be specified more than once. Later mappings for the same field path replace
+// earlier ones.
+func Columns(mapping map[string]Column) TranslatorOption {
+	return func(t *Translator) (*Translator, error) {
+		for path, col := range mapping {
+			if path == "" {
+				return nil, errors.New("invalid column mapping: empty field path")
+			}
+			if col.Name == "" {
+				return nil, fmt.Errorf("invalid column mapping for %s: empty column name", path)
+			}
+			t.columns[path] = col
+		}
+		return t, nil
+	}
+}
+
+// Translator converts the supported subset of CEL into SQL predicates.
+//
+// The supported subset consists of:
+//
+//   - comparisons (`==`, `!=`, `<`, `<=`, `>`, `>=`) between columns and literals, including
+//     comparisons against `null`
+//   - the logical operators `&&`, `||`, and `!`
+//   - `in` tests of a column against a list of literals
+//   - the string functions `startsWith`, `endsWith`, `contains`, and `matches` with a literal
+//     argument
+//   - `has()` tests on nullable columns
+//
+// Regular expressions are passed to the database unchanged, so only patterns whose semantics
+// agree between RE2 and the dialect's regular expression engine should be used with `matches`.
+type Translator struct {
+	env     *cel.Env
+	dialect Dialect
+	columns map[string]Column
+}
+
+// NewTranslator creates a Translator for the given environment and dialect.
+//
+// The environment is used to type-check the residual expressions returned for the portions of
+// an expression which cannot be translated. Residual expressions which contain macros can only be
+// produced when the environment is configured with cel.EnableMacroCallTracking().
+func NewTranslator(env *cel.Env, dialect Dialect, opts ...TranslatorOption) (*Translator, error) {
+	if env == nil {
+		return nil, errors.New("translator requires a non-nil environment")
+	}
+	if dialect == nil {
+		return nil, errors.New("translator requires a non-nil dialect")
+	}
+	t := &Translator{
+		env:     env,
+		dialect: dialect,
+		columns: map[string]Column{},
+	}
+	var err error
+	for _, opt := range opts {
+		t, err = opt(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Query is the result of translating a CEL expression.
+type Query struct {
+	// Where is the SQL predicate, or the empty string if no part of the expression could be
+	// translated.
+	Where string
+
+	// Args contains the values for the bind parameters referenced in Where, in order.
+	//
+	// Values are converted to their Go equivalents: bool, []byte, float64, int64, string,
+	// time.Time, or uint64.
+	Args []any
+
+	// Residual contains the top-level conjuncts of the expression which could not be translated,
+	// or nil if the entire expression was translated.
+	//
+	// Rows selected by the Where predicate must also satisfy the residual expression in order to
+	// satisfy the original expression.
+	Residual *cel.Ast
+}
+
+// Translate converts the expression into a SQL predicate.
+//
+// The expression is split into its top-level conjuncts, and each conjunct is either translated
+// in its entirety or retained within the residual expression of the query.
+func (t *Translator) Translate(a *cel.Ast) (*Query, error) {
+	if a == nil {
+		return nil, errors.New("cannot translate a nil ast")
+	}
+	if a.IsChecked() && !a.OutputType().IsExactType(types.BoolType) && a.OutputType() != types.DynType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, got: %s", a.OutputType())
+	}
+	info := a.NativeRep().SourceInfo()
+	b := &sqlBuilder{Translator: t, checked: a.NativeRep()}
+	var where []string
+	var residuals []string
+	for _, e := range conjuncts(a.NativeRep().Expr()) {
+		if isBoolLiteral(e, true) {
+			continue
+		}
+		argCount := len(b.args)
+		if sql, ok := b.predicate(e); ok {
+			where = append(where, sql)
+			continue
+		}
+		b.args = b.args[:argCount]
+		residual, err := cel.ExprToString(e, info)
+		if err != nil {
+			return nil, fmt.Errorf("residual expression: %w", err)
+		}
+		residuals = append(residuals, residual)
+	}
+	q := &Query{Where: strings.Join(where, " AND "), Args: b.args}
+	if len(residuals) == 0 {
+		return q, nil
+	}
+	if len(residuals) > 1 {
+		for i, r := range residuals {
+			residuals[i] = "(" + r + ")"
+		}
+	}
+	residual, iss := t.env.Compile(strings.Join(residuals, " && "))
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	q.Residual = residual
+	return q, nil
+}
+
+// conjuncts flattens the top-level logical-and expressions into a list of operands.
+func conjuncts(e ast.Expr) []ast.Expr {
+	if e.Kind() != ast.CallKind || e.AsCall().FunctionName() != operators.LogicalAnd {
+		return []ast.Expr{e}
+	}
+	var out []ast.Expr
+	for _, arg := range e.AsCall().Args() {
+		out = append(out, conjuncts(arg)...)
+	}
+	return out
+}
+
+// sqlBuilder accumulates the bind parameters for a predicate.
+type sqlBuilder struct {
+	*Translator
+	checked *ast.AST
+	args    []any
+}
+
+// predicate translates a boolean expression, returning false if the expression is not supported.
+func (b *sqlBuilder) predicate(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.LiteralKind:
+		if isBoolLiteral(e, true) {
+			return "1 = 1", true
+		}
+		if isBoolLiteral(e, false) {
+			return "1 = 0", true
+		}
+	case ast.SelectKind:
+		if e.AsSelect().IsTestOnly() {
+			col, found := b.column(e)
+			if !found || !col.Nullable {
+				return "", false
+			}
+			return b.dialect.QuoteIdentifier(col.Name) + " IS NOT NULL", true
+		}
+		return b.boolColumn(e)
+	case ast.IdentKind:
+		return b.boolColumn(e)
+	case ast.CallKind:
+		if sql, ok := b.call(e); ok {
+			return sql, true
+		}
+		return b.boolColumn(e)
+	}
+	return "", false
+}
+
+// boolColumn translates a reference to a boolean column into an equality test against true.
+func (b *sqlBuilder) boolColumn(e ast.Expr) (string, bool) {
+	col, found := b.column(e)
+	if !found {
+		return "", false
+	}
+	p, _ := b.bind(types.True)
+	return b.guardNulls(fmt.Sprintf("%s = %s", b.dialect.QuoteIdentifier(col.Name), p), col), true
+}
+
+func (b *sqlBuilder) call(e ast.Expr) (string, bool) {
+	call := e.AsCall()
+	args := call.Args()
+	switch fn := call.FunctionName(); fn {
+	case operators.LogicalAnd, operators.LogicalOr:
+		sep := " AND "
+		if fn == operators.LogicalOr {
+			sep = " OR "
+		}
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			sql, ok := b.predicate(arg)
+			if !ok {
+				return "", false
+			}
+			parts[i] = sql
+		}
+		return "(" + strings.Join(parts, sep) + ")", true
+	case operators.LogicalNot:
+		sql, ok := b.predicate(args[0])
+		if !ok {
+			return "", false
+		}
+		return "NOT (" + sql + ")", true
+	case operators.Equals, operators.NotEquals, operators.Less, operators.LessEquals,
+		operators.Greater, operators.GreaterEquals:
+		return b.comparison(fn, args[0], args[1])
+	case operators.In, operators.OldIn:
+		return b.in(args[0], args[1])
+	case overloads.StartsWith, overloads.EndsWith, overloads.Contains, overloads.Matches:
+		target, arg, ok := stringCallArgs(e)
+		if !ok {
+			return "", false
+		}
+		return b.stringMatch(fn, target, arg)
+	}
+	return "", false
+}
+
+var comparisonOperators = map[string]string{
+	operators.Equals:        "=",
+	operators.NotEquals:     "<>",
+	operators.Less:          "<",
+	operators.LessEquals:    "<=",
+	operators.Greater:       ">",
+	operators.GreaterEquals: ">=",
+}
+
+func (b *sqlBuilder) comparison(fn string, lhs, rhs ast.Expr) (string, bool) {
+	lhsCol, lhsIsCol := b.column(lhs)
+	rhsCol, rhsIsCol := b.column(rhs)
+	if !lhsIsCol && !rhsIsCol {
+		return "", false
+	}
+	// Comparisons against null are translated into IS [NOT] NULL checks.
+	if lhsIsCol && isNullLiteral(rhs) || rhsIsCol && isNullLiteral(lhs) {
+		col := lhsCol
+		if rhsIsCol {
+			col = rhsCol
+		}
+		switch fn {
+		case operators.Equals:
+			return b.dialect.QuoteIdentifier(col.Name) + " IS NULL", true
+		case operators.NotEquals:
+			return b.dialect.QuoteIdentifier(col.Name) + " IS NOT NULL", true
+		}
+		return "", false
+	}
+	isString := b.isString(lhs) || b.isString(rhs)
+	lhsSQL, ok := b.operand(lhs, lhsCol, lhsIsCol, isString)
+	if !ok {
+		return "", false
+	}
+	rhsSQL, ok := b.operand(rhs, rhsCol, rhsIsCol, isString)
+	if !ok {
+		return "", false
+	}
+	var cols []Column
+	if lhsIsCol {
+		cols = append(cols, lhsCol)
+	}
+	if rhsIsCol {
+		cols = append(cols, rhsCol)
+	}
+	return b.guardNulls(fmt.Sprintf("%s %s %s", lhsSQL, comparisonOperators[fn], rhsSQL), cols...), true
+}
+
+func (b *sqlBuilder) in(elem, list ast.Expr) (string, bool) {
+	col, found := b.column(elem)
+	if !found {
+		return "", false
+	}
+	if list.Kind() != ast.ListKind || len(list.AsList().OptionalIndices()) != 0 {
+		return "", false
+	}
+	var values []ref.Val
+	for _, v := range list.AsList().Elements() {
+		if v.Kind() != ast.LiteralKind {
+			return "", false
+		}
+		values = append(values, v.AsLiteral())
+	}
+	if len(values) == 0 {
+		return "1 = 0", true
+	}
+	placeholders := make([]string, len(values))
+	isString := b.isString(elem)
+	for i, v := range values {
+		p, ok := b.bind(v)
+		if !ok {
+			return "", false
+		}
+		placeholders[i] = p
+		isString = isString || v.Type() == types.StringType
+	}
+	colSQL := b.dialect.QuoteIdentifier(col.Name)
+	if isString {
+		colSQL = b.dialect.StringOperand(colSQL)
+	}
+	sql := fmt.Sprintf("%s IN (%s)", colSQL, strings.Join(placeholders, ", "))
+	return b.guardNulls(sql, col), true
+}
+
+// likeEscape is the escape character used within LIKE patterns. The character is chosen so that
+// it does not require escaping within a SQL string literal in any supported dialect.
+const likeEscape = "!"
+
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+func (b *sqlBuilder) stringMatch(fn string, target, arg ast.Expr) (string, bool) {
+	col, found := b.column(target)
+	if !found || arg.Kind() != ast.LiteralKind {
+		return "", false
+	}
+	str, isStr := arg.AsLiteral().(types.String)
+	if !isStr {
+		return "", false
+	}
+	colSQL := b.dialect.QuoteIdentifier(col.Name)
+	if fn == overloads.Matches {
+		p, _ := b.bind(str)
+		return b.guardNulls(b.dialect.RegexMatch(colSQL, p), col), true
+	}
+	pattern := likeEscaper.Replace(string(str))
+	switch fn {
+	case overloads.StartsWith:
+		pattern = pattern + "%"
+	case overloads.EndsWith:
+		pattern = "%" + pattern
+	case overloads.Contains:
+		pattern = "%" + pattern + "%"
+	}
+	p, _ := b.bind(types.String(pattern))
+	sql := fmt.Sprintf("%s LIKE %s ESCAPE '%s'", b.dialect.StringOperand(colSQL), p, likeEscape)
+	return b.guardNulls(sql, col), true
+}
+
+// guardNulls ensures the predicate evaluates to false rather than NULL when any of the nullable
+// columns is NULL, so that the negation of the predicate matches the CEL semantics.
+func (b *sqlBuilder) guardNulls(sql string, cols ...Column) string {
+	var guards []string
+	for _, col := range cols {
+		if col.Nullable {
+			guards = append(guards, b.dialect.QuoteIdentifier(col.Name)+" IS NOT NULL")
+		}
+	}
+	if len(guards) == 0 {
+		return sql
+	}
+	return "(" + strings.Join(append(guards, sql), " AND ") + ")"
+}
+
+func (b *sqlBuilder) operand(e ast.Expr, col Column, isCol, isString bool) (string, bool) {
+	if isCol {
+		colSQL := b.dialect.QuoteIdentifier(col.Name)
+		if isString {
+			return b.dialect.StringOperand(colSQL), true
+		}
+		return colSQL, true
+	}
+	if e.Kind() != ast.LiteralKind {
+		return "", false
+	}
+	return b.bind(e.AsLiteral())
+}
+
+// isString returns whether the expression is a string literal or has a string type.
+func (b *sqlBuilder) isString(e ast.Expr) bool {
+	if e.Kind() == ast.LiteralKind {
+		return e.AsLiteral().Type() == types.StringType
+	}
+	return b.checked.GetType(e.ID()).IsExactType(types.StringType)
+}
+
+// bind records the native value of a literal as a bind parameter and returns its placeholder.
+func (b *sqlBuilder) bind(v ref.Val) (string, bool) {
+	var arg any
+	switch v := v.(type) {
+	case types.Bool:
+		arg = bool(v)
+	case types.Bytes:
+		arg = []byte(v)
+	case types.Double:
+		arg = float64(v)
+	case types.Int:
+		arg = int64(v)
+	case types.String:
+		arg = string(v)
+	case types.Timestamp:
+		arg = v.Time
+	case types.Uint:
+		arg = uint64(v)
+	default:
+		return "", false
+	}
+	b.args = append(b.args, arg)
+	return b.dialect.Placeholder(len(b.args)), true
+}
+
+// column resolves a field selection or string-keyed index expression to a mapped column.
+func (b *sqlBuilder) column(e ast.Expr) (Column, bool) {
+	path, ok := fieldPath(e)
+	if !ok {
+		return Column{}, false
+	}
+	col, found := b.columns[path]
+	return col, found
+}
+
+func fieldPath(e ast.Expr) (string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return e.AsIdent(), true
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		operand, ok := fieldPath(sel.Operand())
+		if !ok {
+			return "", false
+		}
+		return operand + "." + sel.FieldName(), true
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.Index || len(call.Args()) != 2 {
+			return "", false
+		}
+		key := call.Args()[1]
+		if key.Kind() != ast.LiteralKind {
+			return "", false
+		}
+		field, isStr := key.AsLiteral().(types.String)
+		if !isStr {
+			return "", false
+		}
+		operand, ok := fieldPath(call.Args()[0])
+		if !ok {
+			return "", false
+		}
+		return operand + "." + string(field), true
+	}
+	return "", false
+}
+
+// stringCallArgs returns the target and argument of a string function in either its receiver or
+// global call form.
+func stringCallArgs(e ast.Expr) (ast.Expr, ast.Expr, bool) {
+	call := e.AsCall()
+	args := call.Args()
+	if call.IsMemberFunction() && len(args) == 1 {
+		return call.Target(), args[0], true
+	}
+	if !call.IsMemberFunction() && len(args) == 2 {
+		return args[0], args[1], true
+	}
+	return nil, nil, false
+}
+
+func isBoolLiteral(e ast.Expr, val bool) bool {
+	return e.Kind() == ast.LiteralKind && e.AsLiteral() == types.Bool(val)
+}
+
+func isNullLiteral(e ast.Expr) bool {
+	return e.Kind() == ast.LiteralKind && e.AsLiteral() == types.NullValue
+}
@@ -0,0 +1,311 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celsql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		dialect  Dialect
+		where    string
+		args     []any
+		residual string
+	}{
+		{
+			expr:    `row.owner == 'alice' && row.size > 10`,
+			dialect: PostgreSQL(),
+			where:   `"docs"."owner" = $1 AND "docs"."size" > $2`,
+			args:    []any{"alice", int64(10)},
+		},
+		{
+			expr:    `row.owner == 'alice' && row.size > 10`,
+			dialect: MySQL(),
+			where:   "CAST(`docs`.`owner` AS BINARY) = ? AND `docs`.`size` > ?",
+			args:    []any{"alice", int64(10)},
+		},
+		{
+			expr:    `'Bob' != row.owner && row.label < 'b' && row.owner in ['alice', 'bob']`,
+			dialect: MySQL(),
+			where: "? <> CAST(`docs`.`owner` AS BINARY) AND (`label` IS NOT NULL AND CAST(`label` AS BINARY) < ?) " +
+				"AND CAST(`docs`.`owner` AS BINARY) IN (?, ?)",
+			args: []any{"Bob", "b", "alice", "bob"},
+		},
+		{
+			expr:    `row.owner.startsWith('a_b') && row.size in [1, 2]`,
+			dialect: MySQL(),
+			where:   "CAST(`docs`.`owner` AS BINARY) LIKE ? ESCAPE '!' AND `docs`.`size` IN (?, ?)",
+			args:    []any{"a!_b%", int64(1), int64(2)},
+		},
+		{
+			expr:    `row.owner != 'alice' || !(row.size <= 10u)`,
+			dialect: SQLite(),
+			where:   `("docs"."owner" <> ? OR NOT ("docs"."size" <= ?))`,
+			args:    []any{"alice", uint64(10)},
+		},
+		{
+			expr:    `row.label == null || row.label != null`,
+			dialect: PostgreSQL(),
+			where:   `("label" IS NULL OR "label" IS NOT NULL)`,
+		},
+		{
+			expr:    `row.label == 'x' && 2.5 < row.score`,
+			dialect: PostgreSQL(),
+			where:   `("label" IS NOT NULL AND "label" = $1) AND ("score" IS NOT NULL AND $2 < "score")`,
+			args:    []any{"x", 2.5},
+		},
+		{
+			expr:    `row.owner in ['alice', 'bob'] && !(row.label in [])`,
+			dialect: PostgreSQL(),
+			where:   `"docs"."owner" IN ($1, $2) AND NOT (1 = 0)`,
+			args:    []any{"alice", "bob"},
+		},
+		{
+			expr:    `row.owner.startsWith('a_b') && row.owner.endsWith('50%') && row.owner.contains('x!')`,
+			dialect: PostgreSQL(),
+			where: `"docs"."owner" LIKE $1 ESCAPE '!' AND "docs"."owner" LIKE $2 ESCAPE '!' ` +
+				`AND "docs"."owner" LIKE $3 ESCAPE '!'`,
+			args: []any{"a!_b%", "%50!%", "%x!!%"},
+		},
+		{
+			expr:    `row.owner.matches('^a.*') && matches(row.owner, 'z$')`,
+			dialect: PostgreSQL(),
+			where:   `"docs"."owner" ~ $1 AND "docs"."owner" ~ $2`,
+			args:    []any{"^a.*", "z$"},
+		},
+		{
+			expr:    `row.owner.matches('^a.*')`,
+			dialect: MySQL(),
+			where:   "REGEXP_LIKE(`docs`.`owner`, ?, 'c')",
+			args:    []any{"^a.*"},
+		},
+		{
+			expr:    `has(row.label) && !has(row.score)`,
+			dialect: PostgreSQL(),
+			where:   `"label" IS NOT NULL AND NOT ("score" IS NOT NULL)`,
+		},
+		{
+			expr:    `row.active && row['owner'] == 'alice'`,
+			dialect: PostgreSQL(),
+			where:   `"active" = $1 AND "docs"."owner" = $2`,
+			args:    []any{true, "alice"},
+		},
+		{
+			expr:     `row.owner == 'alice' && row.size + 1 > 10 && row.size < 100`,
+			dialect:  PostgreSQL(),
+			where:    `"docs"."owner" = $1 AND "docs"."size" < $2`,
+			args:     []any{"alice", int64(100)},
+			residual: `row.size + 1 > 10`,
+		},
+		{
+			expr:     `row.owner == 'alice' || row.tags.exists(t, t == 'x')`,
+			dialect:  PostgreSQL(),
+			where:    ``,
+			residual: `row.owner == "alice" || row.tags.exists(t, t == "x")`,
+		},
+		{
+			expr:     `row.unmapped == 1 && has(row.size) && row.size > 1`,
+			dialect:  PostgreSQL(),
+			where:    `"docs"."size" > $1`,
+			args:     []any{int64(1)},
+			residual: `row.unmapped == 1 && has(row.size)`,
+		},
+		{
+			expr:    `true && row.size > 1`,
+			dialect: PostgreSQL(),
+			where:   `"docs"."size" > $1`,
+			args:    []any{int64(1)},
+		},
+		{
+			expr:    `false`,
+			dialect: PostgreSQL(),
+			where:   `1 = 0`,
+		},
+	}
+	env := testEnv(t)
+	for _, tst := range tests {
+		tc := tst
+		t.Run(tc.expr, func(t *testing.T) {
+			tr, err := NewTranslator(env, tc.dialect, Columns(testColumns))
+			if err != nil {
+				t.Fatalf("NewTranslator() failed: %v", err)
+			}
+			a, iss := env.Compile(tc.expr)
+			if iss.Err() != nil {
+				t.Fatalf("env.Compile(%q) failed: %v", tc.expr, iss.Err())
+			}
+			q, err := tr.Translate(a)
+			if err != nil {
+				t.Fatalf("Translate() failed: %v", err)
+			}
+			if q.Where != tc.where {
+				t.Errorf("Translate() got where %q, wanted %q", q.Where, tc.where)
+			}
+			if len(q.Args) != 0 || len(tc.args) != 0 {
+				if !reflect.DeepEqual(q.Args, tc.args) {
+					t.Errorf("Translate() got args %v, wanted %v", q.Args, tc.args)
+				}
+			}
+			if tc.residual == "" {
+				if q.Residual != nil {
+					t.Errorf("Translate() got residual %v, wanted nil", q.Residual)
+				}
+				return
+			}
+			if q.Residual == nil {
+				t.Fatalf("Translate() got nil residual, wanted %q", tc.residual)
+			}
+			residual, err := cel.AstToString(q.Residual)
+			if err != nil {
+				t.Fatalf("cel.AstToString() failed: %v", err)
+			}
+			if residual != tc.residual {
+				t.Errorf("Translate() got residual %q, wanted %q", residual, tc.residual)
+			}
+		})
+	}
+}
+
+func TestTranslatePartialEval(t *testing.T) {
+	env := testEnv(t)
+	a, iss := env.Compile(`user.admin || (row.owner == user.name && row.size <= user.quota)`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	prg, err := env.Program(a, cel.EvalOptions(cel.OptTrackState, cel.OptPartialEval))
+	if err != nil {
+		t.Fatalf("env.Program() failed: %v", err)
+	}
+	vars, err := cel.PartialVars(map[string]any{
+		"user": map[string]any{"admin": false, "name": "alice", "quota": 1024},
+	}, cel.AttributePattern("row"))
+	if err != nil {
+		t.Fatalf("cel.PartialVars() failed: %v", err)
+	}
+	_, det, err := prg.Eval(vars)
+	if err != nil {
+		t.Fatalf("prg.Eval() failed: %v", err)
+	}
+	residual, err := env.ResidualAst(a, det)
+	if err != nil {
+		t.Fatalf("env.ResidualAst() failed: %v", err)
+	}
+	tr, err := NewTranslator(env, PostgreSQL(), Columns(testColumns))
+	if err != nil {
+		t.Fatalf("NewTranslator() failed: %v", err)
+	}
+	q, err := tr.Translate(residual)
+	if err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+	wantWhere := `"docs"."owner" = $1 AND "docs"."size" <= $2`
+	if q.Where != wantWhere {
+		t.Errorf("Translate() got where %q, wanted %q", q.Where, wantWhere)
+	}
+	wantArgs := []any{"alice", int64(1024)}
+	if !reflect.DeepEqual(q.Args, wantArgs) {
+		t.Errorf("Translate() got args %v, wanted %v", q.Args, wantArgs)
+	}
+	if q.Residual != nil {
+		t.Errorf("Translate() got residual %v, wanted nil", q.Residual)
+	}
+}
+
+func TestTranslatorErrors(t *testing.T) {
+	env := testEnv(t)
+	if _, err := NewTranslator(nil, PostgreSQL()); err == nil {
+		t.Error("NewTranslator(nil) succeeded, wanted error")
+	}
+	if _, err := NewTranslator(env, nil); err == nil {
+		t.Error("NewTranslator(env, nil) succeeded, wanted error")
+	}
+	_, err := NewTranslator(env, PostgreSQL(), Columns(map[string]Column{"row.owner": {}}))
+	if err == nil || !strings.Contains(err.Error(), "empty column name") {
+		t.Errorf("NewTranslator() got error %v, wanted empty column name", err)
+	}
+	tr, err := NewTranslator(env, PostgreSQL(), Columns(testColumns))
+	if err != nil {
+		t.Fatalf("NewTranslator() failed: %v", err)
+	}
+	a, iss := env.Compile(`row.size + 1`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	if _, err := tr.Translate(a); err == nil || !strings.Contains(err.Error(), "must evaluate to a bool") {
+		t.Errorf("Translate() got error %v, wanted must evaluate to a bool", err)
+	}
+	if _, err := tr.Translate(nil); err == nil {
+		t.Error("Translate(nil) succeeded, wanted error")
+	}
+}
+
+func TestTranslateTypedStringColumns(t *testing.T) {
+	env, err := cel.NewEnv(cel.Variable("row", cel.MapType(cel.StringType, cel.StringType)))
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	tr, err := NewTranslator(env, MySQL(), Columns(testColumns))
+	if err != nil {
+		t.Fatalf("NewTranslator() failed: %v", err)
+	}
+	a, iss := env.Compile(`row.owner == row.label`)
+	if iss.Err() != nil {
+		t.Fatalf("env.Compile() failed: %v", iss.Err())
+	}
+	q, err := tr.Translate(a)
+	if err != nil {
+		t.Fatalf("Translate() failed: %v", err)
+	}
+	want := "(`label` IS NOT NULL AND CAST(`docs`.`owner` AS BINARY) = CAST(`label` AS BINARY))"
+	if q.Where != want {
+		t.Errorf("Translate() got where %q, wanted %q", q.Where, want)
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	if got := PostgreSQL().QuoteIdentifier(`t."x"`); got != `"t"."""x"""` {
+		t.Errorf("QuoteIdentifier() got %s", got)
+	}
+	if got := MySQL().QuoteIdentifier("a`b"); got != "`a``b`" {
+		t.Errorf("QuoteIdentifier() got %s", got)
+	}
+}
+
+var testColumns = map[string]Column{
+	"row.owner":  {Name: "docs.owner"},
+	"row.size":   {Name: "docs.size"},
+	"row.label":  {Name: "label", Nullable: true},
+	"row.score":  {Name: "score", Nullable: true},
+	"row.active": {Name: "active"},
+}
+
+func testEnv(t *testing.T) *cel.Env {
+	t.Helper()
+	env, err := cel.NewEnv(
+		cel.EnableMacroCallTracking(),
+		cel.Variable("row", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("user", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		t.Fatalf("cel.NewEnv() failed: %v", err)
+	}
+	return env
+}
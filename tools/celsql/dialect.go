@@ -0,0 +1,150 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect describes the syntax differences between SQL database engines which are relevant to
+// predicate generation.
+type Dialect interface {
+	// Name returns the name of the SQL dialect.
+	Name() string
+
+	// Placeholder returns the bind parameter marker for the parameter at the given 1-based
+	// position within the generated predicate.
+	Placeholder(position int) string
+
+	// QuoteIdentifier quotes a possibly table-qualified column name.
+	QuoteIdentifier(name string) string
+
+	// RegexMatch returns a predicate which tests whether the column matches the regular expression
+	// bound to the pattern placeholder.
+	RegexMatch(column, pattern string) string
+
+	// StringOperand returns the operand for a string column within a comparison, an `IN` test, or
+	// a `LIKE` pattern match, such that the operation is case-sensitive and treats trailing spaces
+	// as significant, as in CEL.
+	StringOperand(column string) string
+}
+
+// PostgreSQL returns the dialect for PostgreSQL which uses numbered `$n` placeholders and the
+// POSIX regular expression operator `~`.
+func PostgreSQL() Dialect {
+	return postgresDialect{}
+}
+
+// MySQL returns the dialect for MySQL which uses `?` placeholders, backtick quoted identifiers,
+// and the `REGEXP_LIKE` function available since MySQL 8.0.
+//
+// The default MySQL collations compare strings without regard to case, and most also ignore
+// trailing spaces, so `'Bob' = 'bob '` holds. To preserve the CEL semantics, string columns are
+// cast to binary strings within comparisons and `LIKE` patterns, and regular expressions are
+// matched case-sensitively. A column is treated as a string when it is compared against a string
+// literal or when the type-checked expression gives it a string type. String values are compared
+// by their encoded bytes, so the columns should use the utf8mb4 character set of the connection.
+// Note that the cast prevents the use of an index on the column.
+func MySQL() Dialect {
+	return mysqlDialect{}
+}
+
+// SQLite returns the dialect for SQLite which uses `?` placeholders and the `REGEXP` operator.
+//
+// SQLite does not provide a default implementation of `REGEXP`, so the connection must register
+// a `regexp` function in order to evaluate predicates generated from the CEL `matches` function.
+// The SQLite `LIKE` operator ignores the case of ASCII characters unless the
+// `case_sensitive_like` pragma is enabled, so the connection should enable it in order for the
+// `startsWith`, `endsWith`, and `contains` functions to be case-sensitive as in CEL.
+func SQLite() Dialect {
+	return sqliteDialect{}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgresql"
+}
+
+func (postgresDialect) Placeholder(position int) string {
+	return fmt.Sprintf("$%d", position)
+}
+
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+func (postgresDialect) RegexMatch(column, pattern string) string {
+	return fmt.Sprintf("%s ~ %s", column, pattern)
+}
+
+func (postgresDialect) StringOperand(column string) string {
+	return column
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "`")
+}
+
+func (mysqlDialect) RegexMatch(column, pattern string) string {
+	return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'c')", column, pattern)
+}
+
+func (mysqlDialect) StringOperand(column string) string {
+	return fmt.Sprintf("CAST(%s AS BINARY)", column)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (sqliteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+func (sqliteDialect) RegexMatch(column, pattern string) string {
+	return fmt.Sprintf("%s REGEXP %s", column, pattern)
+}
+
+func (sqliteDialect) StringOperand(column string) string {
+	return column
+}
+
+// quoteIdentifier quotes each dot-separated component of the name, doubling any embedded quote
+// characters.
+func quoteIdentifier(name, quote string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = quote + strings.ReplaceAll(p, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
@@ -17,12 +17,15 @@ package celtest
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
 	"testing"
 
 	"cel.dev/cel-go/common/ast"
 	"cel.dev/cel-go/common/types"
 	"cel.dev/cel-go/parser"
+	"cel.dev/cel-go/policy"
 )
 
 // reportCoverage reports the coverage information for the provided programs.
@@ -151,3 +154,150 @@ func printCoverageReport(t *testing.T, exprString string, cr *coverageReport) {
 	}
 	t.Logf("--- End Coverage Report ---\n")
 }
+
+// reportPolicyCoverage reports the coverage of the policies under test in terms of the policy
+// structure:
+//   - Match coverage is the number of matches which fired, i.e. produced an output or entered a
+//     nested rule, during the test execution.
+//   - Condition coverage treats each match condition other than the literal `true` as a branch with
+//     two outcomes, and reports the number of outcomes observed during the test execution.
+//   - Variable coverage is the number of variables which were evaluated during the test execution.
+func reportPolicyCoverage(t *testing.T, programs []Program) {
+	t.Helper()
+	for _, p := range programs {
+		cov := p.PolicyCoverage
+		if cov == nil {
+			continue
+		}
+		desc := cov.Source().Description()
+		t.Logf("--- Start Policy Coverage Report ---\nPolicy: %s", desc)
+		var fired, conditions, coveredOutcomes int
+		unfiredMatches := []string{}
+		uncoveredConditions := []string{}
+		for _, m := range cov.Matches() {
+			pos := fmt.Sprintf("%s:%d", desc, m.Location.Line())
+			if m.Fired() {
+				fired++
+			} else {
+				unfiredMatches = append(unfiredMatches, pos)
+			}
+			if !m.Conditional {
+				continue
+			}
+			conditions += 2
+			switch {
+			case m.TrueCount > 0 && m.FalseCount > 0:
+				coveredOutcomes += 2
+			case m.TrueCount > 0:
+				coveredOutcomes++
+				uncoveredConditions = append(uncoveredConditions, pos+": lacks 'false' coverage")
+			case m.FalseCount > 0:
+				coveredOutcomes++
+				uncoveredConditions = append(uncoveredConditions, pos+": lacks 'true' coverage")
+			default:
+				uncoveredConditions = append(uncoveredConditions, pos+": No coverage")
+			}
+		}
+		t.Logf("Policy Match Coverage: %.2f%% (%d out of %d matches fired)",
+			percent(fired, len(cov.Matches())), fired, len(cov.Matches()))
+		if len(unfiredMatches) > 0 {
+			t.Logf("Unfired Matches:\n%s", strings.Join(unfiredMatches, "\n"))
+		}
+		t.Logf("Policy Condition Coverage: %.2f%% (%d out of %d condition outcomes covered)",
+			percent(coveredOutcomes, conditions), coveredOutcomes, conditions)
+		if len(uncoveredConditions) > 0 {
+			t.Logf("Uncovered Conditions:\n%s", strings.Join(uncoveredConditions, "\n"))
+		}
+		evaluated := 0
+		unevaluatedVars := []string{}
+		for _, v := range cov.Variables() {
+			if v.Evaluations > 0 {
+				evaluated++
+			} else {
+				unevaluatedVars = append(unevaluatedVars,
+					fmt.Sprintf("%s:%d: %s", desc, v.Location.Line(), v.Name))
+			}
+		}
+		t.Logf("Policy Variable Coverage: %.2f%% (%d out of %d variables evaluated)",
+			percent(evaluated, len(cov.Variables())), evaluated, len(cov.Variables()))
+		if len(unevaluatedVars) > 0 {
+			t.Logf("Unevaluated Variables:\n%s", strings.Join(unevaluatedVars, "\n"))
+		}
+		t.Logf("--- End Policy Coverage Report ---\n")
+	}
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0.0
+	}
+	return float64(covered) / float64(total) * 100.0
+}
+
+// writeLCOV writes the coverage of the policies under test to the file at the given path in the
+// LCOV trace file format.
+func writeLCOV(path string, programs []Program) error {
+	var sb strings.Builder
+	for _, p := range programs {
+		if p.PolicyCoverage != nil {
+			formatLCOV(&sb, p.PolicyCoverage)
+		}
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// formatLCOV formats the policy coverage as an LCOV record.
+//
+// Each match and variable is reported as a line whose hit count is the number of times the match
+// condition or variable was evaluated, and each match condition other than the literal `true` is
+// reported as a branch whose outcomes are the number of times the condition did and did not hold.
+func formatLCOV(sb *strings.Builder, cov *policy.Coverage) {
+	lines := map[int]int64{}
+	addLine := func(line int, hits int64) {
+		if line > 0 {
+			lines[line] += hits
+		}
+	}
+	var branches []string
+	var branchesHit int
+	for _, m := range cov.Matches() {
+		line := m.Location.Line()
+		addLine(line, m.TrueCount+m.FalseCount)
+		if !m.Conditional || line <= 0 {
+			continue
+		}
+		block := len(branches) / 2
+		for i, taken := range []int64{m.TrueCount, m.FalseCount} {
+			count := "-"
+			if m.TrueCount+m.FalseCount > 0 {
+				count = fmt.Sprintf("%d", taken)
+			}
+			if taken > 0 {
+				branchesHit++
+			}
+			branches = append(branches, fmt.Sprintf("BRDA:%d,%d,%d,%s", line, block, i, count))
+		}
+	}
+	for _, v := range cov.Variables() {
+		addLine(v.Location.Line(), v.Evaluations)
+	}
+	lineNums := make([]int, 0, len(lines))
+	for line := range lines {
+		lineNums = append(lineNums, line)
+	}
+	sort.Ints(lineNums)
+
+	fmt.Fprintf(sb, "TN:\nSF:%s\n", cov.Source().Description())
+	for _, branch := range branches {
+		fmt.Fprintf(sb, "%s\n", branch)
+	}
+	fmt.Fprintf(sb, "BRF:%d\nBRH:%d\n", len(branches), branchesHit)
+	linesHit := 0
+	for _, line := range lineNums {
+		if lines[line] > 0 {
+			linesHit++
+		}
+		fmt.Fprintf(sb, "DA:%d,%d\n", line, lines[line])
+	}
+	fmt.Fprintf(sb, "LF:%d\nLH:%d\nend_of_record\n", len(lineNums), linesHit)
+}
@@ -16,6 +16,9 @@
 package celtest
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"cel.dev/cel-go/cel"
@@ -140,3 +143,88 @@ func TestCoverageStats(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyCoverage(t *testing.T) {
+	tr, err := NewTestRunner(
+		TestCompiler(compiler.EnvironmentFile("testdata/access_config.yaml")),
+		TestSuite("testdata/access_policy_tests.yaml"),
+		TestExpression("testdata/access_policy.yaml"),
+		CoverageLCOV(filepath.Join(t.TempDir(), "coverage.lcov")),
+	)
+	if err != nil {
+		t.Fatalf("NewTestRunner() failed: %v", err)
+	}
+	programs := runTests(t, tr)
+	if len(programs) != 1 || programs[0].PolicyCoverage == nil {
+		t.Fatalf("Programs() got %v, wanted a single program with policy coverage", programs)
+	}
+	cov := programs[0].PolicyCoverage
+	fired := []int{}
+	for _, m := range cov.Matches() {
+		if m.Fired() {
+			fired = append(fired, m.Location.Line())
+		}
+	}
+	if want := []int{25, 27}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("Matches() got fired lines %v, wanted %v", fired, want)
+	}
+	unevaluated := []string{}
+	for _, v := range cov.Variables() {
+		if v.Evaluations == 0 {
+			unevaluated = append(unevaluated, v.Name)
+		}
+	}
+	if want := []string{"variables.is_guest"}; !reflect.DeepEqual(unevaluated, want) {
+		t.Errorf("Variables() got unevaluated %v, wanted %v", unevaluated, want)
+	}
+	if err := writeLCOV(tr.CoverageLCOVPath, programs); err != nil {
+		t.Fatalf("writeLCOV() failed: %v", err)
+	}
+	got, err := os.ReadFile(tr.CoverageLCOVPath)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	want := `TN:
+SF:testdata/access_policy.yaml
+BRDA:25,0,0,1
+BRDA:25,0,1,1
+BRDA:27,1,0,1
+BRDA:27,1,1,0
+BRDA:29,2,0,-
+BRDA:29,2,1,-
+BRF:6
+BRH:3
+DA:18,2
+DA:20,1
+DA:22,0
+DA:25,2
+DA:27,1
+DA:29,0
+DA:31,0
+LF:7
+LH:4
+end_of_record
+`
+	if string(got) != want {
+		t.Errorf("writeLCOV() got:\n%s\nwanted:\n%s", got, want)
+	}
+}
+
+// runTests executes the configured tests and returns the programs along with their coverage.
+func runTests(t *testing.T, tr *TestRunner) []Program {
+	t.Helper()
+	programs, err := tr.Programs(t, tr.testProgramOptions...)
+	if err != nil {
+		t.Fatalf("Programs() failed: %v", err)
+	}
+	tests, err := tr.Tests(t)
+	if err != nil {
+		t.Fatalf("Tests() failed: %v", err)
+	}
+	for _, test := range tests {
+		if err := tr.ExecuteTest(t, programs, test); err != nil {
+			t.Fatalf("ExecuteTest() failed: %v", err)
+		}
+	}
+	return programs
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cel.dev/cel-go/cel"
+	"cel.dev/cel-go/common/ast"
+	"cel.dev/cel-go/common/operators"
+	"cel.dev/cel-go/common/types"
+	"cel.dev/cel-go/parser"
+)
+
+// flippedComparisons maps each comparison operator to the operator which yields the opposite
+// result for comparable operands.
+var flippedComparisons = map[string]string{
+	operators.Equals:        operators.NotEquals,
+	operators.NotEquals:     operators.Equals,
+	operators.Less:          operators.GreaterEquals,
+	operators.LessEquals:    operators.Greater,
+	operators.Greater:       operators.LessEquals,
+	operators.GreaterEquals: operators.Less,
+}
+
+// mutant is a variant of a checked expression in which a single comparison has been flipped or a
+// single condition has been negated.
+type mutant struct {
+	// id is the id of the mutated expression within the original AST.
+	id          int64
+	description string
+	ast         *cel.Ast
+}
+
+// mutants generates the mutants of a checked expression.
+//
+// Each comparison operator is replaced with its opposite, e.g. `<` becomes `>=`, and the condition
+// of each conditional expression is negated. The overload references of the mutated calls are
+// removed so that the mutated functions are resolved at evaluation time.
+func mutants(a *cel.Ast) ([]*mutant, error) {
+	native := a.NativeRep()
+	var targets []ast.Expr
+	ast.PostOrderVisit(native.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.CallKind {
+			return
+		}
+		fn := e.AsCall().FunctionName()
+		if _, found := flippedComparisons[fn]; found || fn == operators.Conditional {
+			targets = append(targets, e)
+		}
+	}))
+	muts := make([]*mutant, 0, len(targets))
+	for _, target := range targets {
+		m, err := mutate(a, target)
+		if err != nil {
+			return nil, err
+		}
+		muts = append(muts, m)
+	}
+	return muts, nil
+}
+
+func mutate(a *cel.Ast, target ast.Expr) (*mutant, error) {
+	mutated := ast.Copy(a.NativeRep())
+	fac := ast.NewExprFactory()
+	m := &mutant{id: target.ID()}
+	ast.PostOrderVisit(mutated.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if e.ID() != target.ID() || e.Kind() != ast.CallKind {
+			return
+		}
+		call := e.AsCall()
+		fn := call.FunctionName()
+		if fn == operators.Conditional {
+			cond := call.Args()[0]
+			negatedID := ast.MaxID(mutated) + 1
+			negated := fac.NewCall(negatedID, operators.LogicalNot, cond)
+			mutated.SetType(negatedID, types.BoolType)
+			if offset, found := mutated.SourceInfo().GetOffsetRange(cond.ID()); found {
+				mutated.SourceInfo().SetOffsetRange(negatedID, offset)
+			}
+			e.SetKindCase(fac.NewCall(e.ID(), fn, negated, call.Args()[1], call.Args()[2]))
+			m.description = "negated condition"
+			return
+		}
+		flipped := flippedComparisons[fn]
+		e.SetKindCase(fac.NewCall(e.ID(), flipped, call.Args()...))
+		delete(mutated.ReferenceMap(), e.ID())
+		m.description = fmt.Sprintf("flipped %s to %s", operatorDisplayName(fn), operatorDisplayName(flipped))
+	}))
+	pb, err := ast.ToProto(mutated)
+	if err != nil {
+		return nil, err
+	}
+	m.ast, err = cel.CheckedExprToAstWithSource(pb, a.Source())
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func operatorDisplayName(fn string) string {
+	if name, found := operators.FindReverse(fn); found {
+		return fmt.Sprintf("'%s'", name)
+	}
+	return fn
+}
+
+// reportMutants runs the tests against the mutants of each program and reports the mutants which
+// survived, i.e. for which every test still passed.
+func reportMutants(t *testing.T, tr *TestRunner, programs []Program, tests []*Test) {
+	t.Helper()
+	for _, p := range programs {
+		mr, err := tr.runMutants(p, tests)
+		if err != nil {
+			t.Errorf("error running mutation tests: %v", err)
+			continue
+		}
+		t.Logf("--- Start Mutation Report ---\nExpression: %s", describeSource(p.Ast))
+		t.Logf("Mutation Score: %.2f%% (%d out of %d mutants killed)",
+			percent(mr.killed, mr.mutants), mr.killed, mr.mutants)
+		if len(mr.survivors) > 0 {
+			t.Logf("Surviving Mutants:\n%s", strings.Join(mr.survivors, "\n"))
+		}
+		t.Logf("--- End Mutation Report ---\n")
+	}
+}
+
+type mutationReport struct {
+	mutants   int
+	killed    int
+	survivors []string
+}
+
+// runMutants evaluates the tests against each mutant of the program.
+func (tr *TestRunner) runMutants(p Program, tests []*Test) (*mutationReport, error) {
+	e, err := tr.CreateEnv()
+	if err != nil {
+		return nil, err
+	}
+	muts, err := mutants(p.Ast)
+	if err != nil {
+		return nil, err
+	}
+	mr := &mutationReport{mutants: len(muts)}
+	for _, m := range muts {
+		prg, err := e.Program(m.ast, tr.testProgramOptions...)
+		if err != nil {
+			return nil, err
+		}
+		if mutantKilled(prg, tests) {
+			mr.killed++
+			continue
+		}
+		mr.survivors = append(mr.survivors, describeMutant(p.Ast, m))
+	}
+	return mr, nil
+}
+
+// mutantKilled indicates whether at least one of the tests fails when run against the mutant.
+func mutantKilled(prg cel.Program, tests []*Test) bool {
+	for _, test := range tests {
+		out, _, err := prg.Eval(test.input)
+		if !test.resultMatcher(out, err).Success {
+			return true
+		}
+	}
+	return false
+}
+
+// describeMutant formats the location of a mutant along with the source line in which it occurs.
+//
+// Policy expressions are composed into a single expression in which variables are replaced with
+// generated names, so the original source text is shown rather than the unparsed expression when
+// it is available.
+func describeMutant(a *cel.Ast, m *mutant) string {
+	native := a.NativeRep()
+	var target ast.Expr
+	ast.PostOrderVisit(native.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if e.ID() == m.id {
+			target = e
+		}
+	}))
+	// Conditionals generated when composing policies have no position, so the condition is used.
+	if call := target.AsCall(); call.FunctionName() == operators.Conditional {
+		target = call.Args()[0]
+	}
+	loc := native.SourceInfo().GetStartLocation(target.ID())
+	text := ""
+	if a.Source() != nil {
+		if line, found := a.Source().Snippet(loc.Line()); found {
+			text = strings.TrimSpace(line)
+		}
+	} else if unparsed, err := parser.Unparse(target, native.SourceInfo()); err == nil {
+		text = unparsed
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", describeSource(a), loc.Line(), loc.Column()+1, m.description, text)
+}
+
+func describeSource(a *cel.Ast) string {
+	if a.Source() == nil {
+		return "<input>"
+	}
+	return a.Source().Description()
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package celtest
+
+import (
+	"reflect"
+	"testing"
+
+	"cel.dev/cel-go/tools/compiler"
+)
+
+func TestMutants(t *testing.T) {
+	tr, err := NewTestRunner(
+		TestCompiler(compiler.EnvironmentFile("testdata/access_config.yaml")),
+		TestSuite("testdata/access_policy_tests.yaml"),
+		TestExpression("testdata/access_policy.yaml"),
+		EnableMutationTesting(),
+	)
+	if err != nil {
+		t.Fatalf("NewTestRunner() failed: %v", err)
+	}
+	programs := runTests(t, tr)
+	tests, err := tr.Tests(t)
+	if err != nil {
+		t.Fatalf("Tests() failed: %v", err)
+	}
+	mr, err := tr.runMutants(programs[0], tests)
+	if err != nil {
+		t.Fatalf("runMutants() failed: %v", err)
+	}
+	if mr.mutants != 6 || mr.killed != 4 {
+		t.Errorf("runMutants() got %d of %d mutants killed, wanted 4 of 6", mr.killed, mr.mutants)
+	}
+	want := []string{
+		`testdata/access_policy.yaml:23:33: flipped '==' to '!=': expression: "request.role == 'guest'"`,
+		`testdata/access_policy.yaml:29:28: negated condition: - condition: "variables.is_guest"`,
+	}
+	if !reflect.DeepEqual(mr.survivors, want) {
+		t.Errorf("runMutants() got survivors %v, wanted %v", mr.survivors, want)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"cel.dev/cel-go/common/types"
 	"cel.dev/cel-go/common/types/ref"
 	"cel.dev/cel-go/interpreter"
+	"cel.dev/cel-go/policy"
 	"cel.dev/cel-go/test"
 	"cel.dev/cel-go/tools/compiler"
 	"github.com/google/go-cmp/cmp"
@@ -59,6 +60,8 @@ var (
 	configPath            string
 	baseConfigPath        string
 	enableCoverage        bool
+	coverageLCOVPath      string
+	enableMutationTesting bool
 	enableDebug           bool
 )
 
@@ -69,6 +72,8 @@ func init() {
 	flag.StringVar(&baseConfigPath, "base_config_path", "", "path to a base config file")
 	flag.StringVar(&celExpression, "cel_expr", "", "CEL expression to test")
 	flag.BoolVar(&enableCoverage, "enable_coverage", false, "Enable coverage calculation and reporting.")
+	flag.StringVar(&coverageLCOVPath, "coverage_lcov_path", "", "path to which policy coverage is written in LCOV format")
+	flag.BoolVar(&enableMutationTesting, "enable_mutation_testing", false, "Enable mutation testing and reporting of surviving mutants.")
 	flag.BoolVar(&enableDebug, "celtest_debug", false, "Enables verbose logging of test case execution.")
 }
 
@@ -167,6 +172,15 @@ func TriggerTests(t *testing.T, testRunnerOpts ...TestRunnerOption) {
 	}
 	if tr.EnableCoverage {
 		reportCoverage(t, programs)
+		reportPolicyCoverage(t, programs)
+	}
+	if tr.CoverageLCOVPath != "" {
+		if err := writeLCOV(tr.CoverageLCOVPath, programs); err != nil {
+			t.Errorf("error writing coverage: %v", err)
+		}
+	}
+	if tr.EnableMutationTesting {
+		reportMutants(t, tr, programs, tests)
 	}
 }
 
@@ -181,6 +195,8 @@ func TriggerTests(t *testing.T, testRunnerOpts ...TestRunnerOption) {
 //   - Test expression - The `cel_expr` flag is used to populate the test expressions which need to be
 //     evaluated by the test runner.
 //   - Enable coverage - The `enable_coverage` flag is used to enable coverage calculation and reporting.
+//   - Coverage LCOV path - The `coverage_lcov_path` flag is used to write policy coverage in LCOV format.
+//   - Enable mutation testing - The `enable_mutation_testing` flag is used to enable mutation testing.
 func TestRunnerOptionsFromFlags(testResourcesDir string, testRunnerOpts []TestRunnerOption, testCompilerOpts ...any) TestRunnerOption {
 	if !flag.Parsed() {
 		flag.Parse()
@@ -198,6 +214,12 @@ func TestRunnerOptionsFromFlags(testResourcesDir string, testRunnerOpts []TestRu
 		if enableCoverage {
 			opts = append(opts, EnableCoverage())
 		}
+		if coverageLCOVPath != "" {
+			opts = append(opts, CoverageLCOV(coverageLCOVPath))
+		}
+		if enableMutationTesting {
+			opts = append(opts, EnableMutationTesting())
+		}
 		opts = append(opts, testRunnerOpts...)
 		var err error
 		for _, opt := range opts {
@@ -221,7 +243,7 @@ func testRunnerCompilerFromFlags(testCompilerOpts ...any) TestRunnerOption {
 	if configPath != "" {
 		opts = append(opts, compiler.EnvironmentFile(configPath))
 	}
-	if enableCoverage {
+	if enableCoverage || coverageLCOVPath != "" {
 		opts = append(opts, cel.EnableMacroCallTracking())
 	}
 	opts = append(opts, testCompilerOpts...)
@@ -354,6 +376,8 @@ func DebugAST(ast *cel.Ast) string {
 // - test Suite Parser: A parser for a test suite file serialized in Textproto/YAML format.
 // - test Program Options: A list of options to be used when creating the CEL programs.
 // - EnableCoverage: A boolean to enable coverage calculation.
+// - CoverageLCOVPath: The path to which policy coverage is written in LCOV format.
+// - EnableMutationTesting: A boolean to enable mutation testing.
 //
 // The TestRunner provides the following methods:
 // - Programs: Creates a list of CEL programs from the input expressions.
@@ -365,6 +389,9 @@ type TestRunner struct {
 	TestSuiteFilePath string
 	FileDescriptorSet *descpb.FileDescriptorSet
 	EnableCoverage    bool
+	CoverageLCOVPath  string
+
+	EnableMutationTesting bool
 
 	activationFactory  ActivationFactory
 	testSuiteParser    TestSuiteParser
@@ -550,17 +577,45 @@ func EnableCoverage() TestRunnerOption {
 	}
 }
 
+// CoverageLCOV returns a TestRunnerOption which enables coverage calculation for the test run and
+// writes the coverage of the tested policies to the given path in the LCOV trace file format.
+func CoverageLCOV(path string) TestRunnerOption {
+	return func(tr *TestRunner) (*TestRunner, error) {
+		tr, err := EnableCoverage()(tr)
+		if err != nil {
+			return nil, err
+		}
+		tr.CoverageLCOVPath = path
+		return tr, nil
+	}
+}
+
+// EnableMutationTesting returns a TestRunnerOption which enables mutation testing for the test run.
+//
+// After the tests pass, each comparison in the tested expressions is flipped and each condition is
+// negated in turn, and the tests are run against the mutated expression. Mutants which are not
+// detected by any test are reported as surviving.
+func EnableMutationTesting() TestRunnerOption {
+	return func(tr *TestRunner) (*TestRunner, error) {
+		tr.EnableMutationTesting = true
+		return tr, nil
+	}
+}
+
 // Program represents the result of creating CEL programs for the configured expressions in the
 // test runner. It encompasses the following:
 // - CELProgram - the evaluable CEL program
 // - PolicyMetadata - the metadata map obtained while creating the CEL AST from the expression
 // - Ast - the CEL AST created from the expression
 // - CoverageStats - the coverage report map obtained from calculating the coverage if enabled.
+// - PolicyCoverage - the coverage of the policy matches and variables if the expression is a
+// policy and coverage is enabled.
 type Program struct {
 	cel.Program
 	PolicyMetadata map[string]any
 	Ast            *cel.Ast
 	CoverageStats  map[int64]set
+	PolicyCoverage *policy.Coverage
 }
 
 // set is a generic set implementation using a map where the keys are the elements of the set
@@ -597,15 +652,39 @@ func (tr *TestRunner) Programs(t *testing.T, opts ...cel.ProgramOption) ([]Progr
 		if err != nil {
 			return nil, err
 		}
+		var policyCoverage *policy.Coverage
+		if tr.EnableCoverage {
+			policyCoverage, err = tr.policyCoverage(expr)
+			if err != nil {
+				return nil, err
+			}
+		}
 		programs = append(programs, Program{
 			Program:        prg,
 			PolicyMetadata: policyMetadata,
 			Ast:            ast,
+			PolicyCoverage: policyCoverage,
 		})
 	}
 	return programs, nil
 }
 
+// policyCoverage creates a policy coverage accumulator if the input expression is a policy file.
+func (tr *TestRunner) policyCoverage(expr compiler.InputExpression) (*policy.Coverage, error) {
+	f, ok := expr.(*compiler.FileExpression)
+	if !ok {
+		return nil, nil
+	}
+	if format := compiler.InferFileFormat(f.Path); format != compiler.CELPolicy && format != compiler.TextYAML {
+		return nil, nil
+	}
+	p, rule, err := f.CreatePolicyRule(tr.Compiler)
+	if err != nil {
+		return nil, err
+	}
+	return policy.NewCoverage(p, rule)
+}
+
 // Tests creates a list of tests from the test suite file and test suite parser configured in the
 // test runner.
 //
@@ -1038,6 +1117,13 @@ func (tr *TestRunner) ExecuteTest(t *testing.T, programs []Program, test *Test)
 			collectCoverageStats(details, &pr)
 			programs[i] = pr
 		}
+		if pr.PolicyCoverage != nil {
+			// Evaluation errors are expected by some tests, and the observations made prior to the
+			// error are retained.
+			if err := pr.PolicyCoverage.Record(test.input); err != nil && enableDebug {
+				t.Logf("Policy coverage evaluation error: %v", err)
+			}
+		}
 	}
 	return nil
 }
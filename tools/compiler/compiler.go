@@ -513,30 +513,13 @@ func (f *FileExpression) CreateAST(compiler Compiler) (*cel.Ast, map[string]any,
 		}
 		return ast, nil, nil
 	case CELPolicy, TextYAML:
-		data, err := loadFile(f.Path)
+		e, p, policyMetadata, err := f.parsePolicy(compiler, e)
 		if err != nil {
 			return nil, nil, err
 		}
-		src := policy.ByteSource(data, f.Path)
-		parser, err := compiler.CreatePolicyParser()
-		if err != nil {
-			return nil, nil, err
-		}
-		p, iss := parser.Parse(src)
-		if iss.Err() != nil {
-			return nil, nil, fmt.Errorf("parser.Parse(%q) failed: %w", src.Content(), iss.Err())
-		}
-		policyMetadata := clonePolicyMetadata(p)
-		if meta, ok := compiler.(CustomMetadataCompiler); ok {
-			for _, opt := range meta.PolicyMetadataEnvOptions() {
-				if e, err = e.Extend(opt(policyMetadata)); err != nil {
-					return nil, nil, fmt.Errorf("e.Extend() with metadata option failed: %w", err)
-				}
-			}
-		}
 		ast, iss := policy.Compile(e, p, compiler.PolicyCompilerOptions()...)
 		if iss.Err() != nil {
-			return nil, nil, fmt.Errorf("policy.Compile(%q) failed: %w", src.Content(), iss.Err())
+			return nil, nil, fmt.Errorf("policy.Compile(%q) failed: %w", p.Source().Content(), iss.Err())
 		}
 		return ast, policyMetadata, nil
 	default:
@@ -544,6 +527,58 @@ func (f *FileExpression) CreateAST(compiler Compiler) (*cel.Ast, map[string]any,
 	}
 }
 
+// CreatePolicyRule parses a policy file and compiles it into a policy.CompiledRule using the
+// provided compiler, in the same manner as CreateAST.
+//
+// The parsed policy is returned alongside the compiled rule so that evaluation results may be
+// mapped back to the policy source, e.g. with policy.Explain.
+func (f *FileExpression) CreatePolicyRule(compiler Compiler) (*policy.Policy, *policy.CompiledRule, error) {
+	e, err := compiler.CreateEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	format := InferFileFormat(f.Path)
+	if format != CELPolicy && format != TextYAML {
+		return nil, nil, fmt.Errorf("invalid file extension wanted: .celpolicy or .yaml found: %v", format)
+	}
+	e, p, _, err := f.parsePolicy(compiler, e)
+	if err != nil {
+		return nil, nil, err
+	}
+	rule, iss := policy.CompileRule(e, p, compiler.PolicyCompilerOptions()...)
+	if iss.Err() != nil {
+		return nil, nil, fmt.Errorf("policy.CompileRule(%q) failed: %w", p.Source().Content(), iss.Err())
+	}
+	return p, rule, nil
+}
+
+// parsePolicy parses the policy file and extends the environment with the policy metadata options
+// configured on the compiler.
+func (f *FileExpression) parsePolicy(compiler Compiler, e *cel.Env) (*cel.Env, *policy.Policy, map[string]any, error) {
+	data, err := loadFile(f.Path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	src := policy.ByteSource(data, f.Path)
+	parser, err := compiler.CreatePolicyParser()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p, iss := parser.Parse(src)
+	if iss.Err() != nil {
+		return nil, nil, nil, fmt.Errorf("parser.Parse(%q) failed: %w", src.Content(), iss.Err())
+	}
+	policyMetadata := clonePolicyMetadata(p)
+	if meta, ok := compiler.(CustomMetadataCompiler); ok {
+		for _, opt := range meta.PolicyMetadataEnvOptions() {
+			if e, err = e.Extend(opt(policyMetadata)); err != nil {
+				return nil, nil, nil, fmt.Errorf("e.Extend() with metadata option failed: %w", err)
+			}
+		}
+	}
+	return e, p, policyMetadata, nil
+}
+
 func clonePolicyMetadata(p *policy.Policy) map[string]any {
 	metadataKeys := p.MetadataKeys()
 	metadata := make(map[string]any, len(metadataKeys))
@@ -394,6 +394,41 @@ func TestFileExpressionCustomPolicyParser(t *testing.T) {
 	})
 }
 
+func TestFileExpressionCreatePolicyRule(t *testing.T) {
+	t.Run("test file expression create policy rule", func(t *testing.T) {
+		envOpt := EnvironmentFile("../../policy/testdata/limits/config.yaml")
+		compiler, err := NewCompiler(envOpt)
+		if err != nil {
+			t.Fatalf("NewCompiler() failed: %v", err)
+		}
+		policyFile := &FileExpression{
+			Path: "../../policy/testdata/limits/policy.yaml",
+		}
+		p, rule, err := policyFile.CreatePolicyRule(compiler)
+		if err != nil {
+			t.Fatalf("CreatePolicyRule() failed: %v", err)
+		}
+		if p.Name().Value != "limits" {
+			t.Errorf("CreatePolicyRule() got policy %q, wanted limits", p.Name().Value)
+		}
+		if len(rule.Matches()) == 0 {
+			t.Error("CreatePolicyRule() returned a rule without matches")
+		}
+	})
+	t.Run("test file expression create policy rule from expression file", func(t *testing.T) {
+		compiler, err := NewCompiler()
+		if err != nil {
+			t.Fatalf("NewCompiler() failed: %v", err)
+		}
+		exprFile := &FileExpression{
+			Path: "testdata/expr.cel",
+		}
+		if _, _, err := exprFile.CreatePolicyRule(compiler); err == nil {
+			t.Error("CreatePolicyRule() succeeded for an expression file, wanted error")
+		}
+	})
+}
+
 func TestRawExpressionCreateAst(t *testing.T) {
 	t.Run("test raw expression create ast", func(t *testing.T) {
 		envOpt := EnvironmentFile("testdata/config.yaml")